@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrUnauthenticated can be returned (or wrapped) by an Authorizer to answer
+// with 401 Unauthorized instead of 403 Forbidden
+var ErrUnauthenticated = errors.New("openapi: unauthenticated")
+
+// AuthorizationRequest carries what an Authorizer needs to decide on a request
+type AuthorizationRequest struct {
+	Request *http.Request
+	Route   *RouteMatch
+	// Requirements are the effective security requirements of the operation.
+	// Any one requirement must be satisfied; within a requirement every scheme
+	// must be satisfied with all of its listed scopes.
+	Requirements []SecurityRequirement
+	// Schemes holds the security schemes referenced by the requirements
+	Schemes map[string]SecurityScheme
+}
+
+// Authorizer decides whether a request may proceed. A nil error allows it.
+type Authorizer func(req AuthorizationRequest) error
+
+// AuthorizeOptions configures the authorization middleware
+type AuthorizeOptions struct {
+	// OnDenied writes the response for rejected requests. The default answers
+	// 401 for ErrUnauthenticated and 403 otherwise.
+	OnDenied func(w http.ResponseWriter, r *http.Request, err error)
+	// DenyUnmatched rejects requests that match no operation instead of passing
+	// them through without authorization: with 405 and an Allow header when
+	// the path has operations for other methods, else with 404
+	DenyUnmatched bool
+}
+
+// EffectiveSecurity returns the security requirements applying to an operation:
+// its own when set (an empty, non-nil list disables security), otherwise the
+// document-level requirements
+func (d *Document) EffectiveSecurity(op *Operation) []SecurityRequirement {
+	if op.Security != nil {
+		return op.Security
+	}
+	return d.Security
+}
+
+// Authorize returns middleware that resolves the effective security requirements
+// of the matched operation and asks the authorizer whether the request satisfies
+// them. Operations without requirements are not passed to the authorizer.
+func Authorize(doc *Document, authorizer Authorizer, opts AuthorizeOptions) func(http.Handler) http.Handler {
+	if opts.OnDenied == nil {
+		opts.OnDenied = func(w http.ResponseWriter, r *http.Request, err error) {
+			if errors.Is(err, ErrUnauthenticated) {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		}
+	}
+	router := NewRouter(doc)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			match, r := matchRequest(router, r)
+			if match == nil {
+				if opts.DenyUnmatched {
+					if allowed := router.allowedMethods(r.URL.EscapedPath()); len(allowed) > 0 {
+						w.Header().Set("Allow", strings.Join(allowed, ", "))
+						http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+						return
+					}
+					http.NotFound(w, r)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requirements := doc.EffectiveSecurity(match.Operation)
+			if len(requirements) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			schemes := make(map[string]SecurityScheme)
+			for _, requirement := range requirements {
+				for name := range requirement {
+					if doc.Components != nil {
+						if scheme, ok := doc.Components.SecuritySchemes[name]; ok {
+							schemes[name] = scheme
+						}
+					}
+				}
+			}
+
+			err := authorizer(AuthorizationRequest{
+				Request:      r,
+				Route:        match,
+				Requirements: requirements,
+				Schemes:      schemes,
+			})
+			if err != nil {
+				opts.OnDenied(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BackstageMetadata describes the Backstage catalog entity registering a document
+type BackstageMetadata struct {
+	// Name of the entity; defaults to the sanitized document title
+	Name string
+	// Namespace defaults to the catalog's default namespace
+	Namespace string
+	// Title defaults to the document title
+	Title string
+	// Description defaults to the document description
+	Description string
+	// Owner is the user or group owning the API; required
+	Owner string
+	// Lifecycle defaults to "production"
+	Lifecycle string
+	// System optionally groups the API with the components implementing it
+	System      string
+	Tags        []string
+	Labels      map[string]string
+	Annotations map[string]string
+	// Links are added after the document's external docs
+	Links []BackstageLink
+	// DefinitionURL references the document at a URL instead of inlining it
+	DefinitionURL string
+}
+
+// BackstageLink is a link shown on the entity page
+type BackstageLink struct {
+	URL   string `json:"url" yaml:"url"`
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	Icon  string `json:"icon,omitempty" yaml:"icon,omitempty"`
+}
+
+// Backstage catalog entity types, limited to the fields the export sets
+
+type backstageEntity struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   backstageMetadata `yaml:"metadata"`
+	Spec       backstageSpec     `yaml:"spec"`
+}
+
+type backstageMetadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Title       string            `yaml:"title,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty"`
+	Links       []BackstageLink   `yaml:"links,omitempty"`
+}
+
+type backstageSpec struct {
+	Type       string      `yaml:"type"`
+	Lifecycle  string      `yaml:"lifecycle"`
+	Owner      string      `yaml:"owner"`
+	System     string      `yaml:"system,omitempty"`
+	Definition interface{} `yaml:"definition"`
+}
+
+var backstageName = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ToBackstageAPIEntity renders the catalog-info.yaml API entity registering
+// the document in a Backstage software catalog. The document is inlined as
+// JSON, or referenced with a $text substitution when DefinitionURL is set.
+func (d *Document) ToBackstageAPIEntity(meta BackstageMetadata) ([]byte, error) {
+	if meta.Owner == "" {
+		return nil, errors.New("openapi: Backstage API entities require an owner")
+	}
+	if meta.Name == "" {
+		meta.Name = strings.Trim(backstageName.ReplaceAllString(strings.ToLower(d.Info.Title), "-"), "-")
+	}
+	if meta.Title == "" {
+		meta.Title = d.Info.Title
+	}
+	if meta.Description == "" {
+		meta.Description = d.Info.Description
+	}
+	if meta.Lifecycle == "" {
+		meta.Lifecycle = "production"
+	}
+	var links []BackstageLink
+	if d.ExternalDocs != nil && d.ExternalDocs.URL != "" {
+		links = append(links, BackstageLink{URL: d.ExternalDocs.URL, Title: d.ExternalDocs.Description})
+	}
+	links = append(links, meta.Links...)
+
+	var definition interface{} = map[string]string{"$text": meta.DefinitionURL}
+	if meta.DefinitionURL == "" {
+		spec, err := d.ToJSON()
+		if err != nil {
+			return nil, err
+		}
+		definition = string(spec)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	err := enc.Encode(backstageEntity{
+		APIVersion: "backstage.io/v1alpha1",
+		Kind:       "API",
+		Metadata: backstageMetadata{
+			Name:        meta.Name,
+			Namespace:   meta.Namespace,
+			Title:       meta.Title,
+			Description: meta.Description,
+			Labels:      meta.Labels,
+			Annotations: meta.Annotations,
+			Tags:        meta.Tags,
+			Links:       links,
+		},
+		Spec: backstageSpec{
+			Type:       "openapi",
+			Lifecycle:  meta.Lifecycle,
+			Owner:      meta.Owner,
+			System:     meta.System,
+			Definition: definition,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
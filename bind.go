@@ -0,0 +1,418 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RequestError reports why a request doesn't conform to its operation
+type RequestError struct {
+	Errors []ValidationError
+}
+
+// Error implements the error interface
+func (e *RequestError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "request does not match the operation: " + strings.Join(messages, "; ")
+}
+
+// Binder decodes requests for a single operation according to the document
+type Binder struct {
+	doc    *Document
+	path   string
+	method string
+	op     *Operation
+	params []Parameter
+}
+
+// NewBinder creates a binder for the operation with the given operationId
+func (d *Document) NewBinder(operationID string) (*Binder, error) {
+	path, method, op := d.FindOperation(operationID)
+	if op == nil {
+		return nil, fmt.Errorf("openapi: unknown operation %q", operationID)
+	}
+	return &Binder{
+		doc:    d,
+		path:   path,
+		method: method,
+		op:     op,
+		params: d.OperationParameters(path, op),
+	}, nil
+}
+
+// Bind decodes the path, query, header and cookie parameters and the body of a
+// request into dst, which must be a pointer to a struct. Parameters are bound to
+// fields tagged with their location, e.g. `path:"petId"`, `query:"limit"`,
+// `header:"X-Tenant-ID"` or `cookie:"session"`. The body is decoded into the
+// field tagged `body:""`, or into dst itself when there is no such field.
+//
+// Parameter values are deserialized following their style and explode settings
+// and coerced to the types declared by their schemas (so "?limit=10" becomes an
+// integer), then validated. Problems are reported as a *RequestError.
+func (b *Binder) Bind(r *http.Request, dst interface{}) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.Elem().Kind() != reflect.Struct {
+		return errors.New("openapi: Bind requires a pointer to a struct")
+	}
+	fields := make(map[string]reflect.Value)
+	collectBindFields(target.Elem(), fields)
+
+	pathValues, _ := matchPathTemplate(b.path, r.URL.EscapedPath())
+	query := r.URL.Query()
+
+	var errs []ValidationError
+	for _, p := range b.params {
+		pointer := "/" + p.In + "/" + escapePointer(p.Name)
+		raw, present := b.rawParameter(r, p, pathValues, query)
+		if !present {
+			if p.Required {
+				errs = append(errs, ValidationError{Path: pointer, Message: "missing required parameter", Severity: SeverityError})
+			}
+			continue
+		}
+
+		value, err := b.doc.decodeParameter(p, raw)
+		if err != nil {
+			errs = append(errs, ValidationError{Path: pointer, Message: err.Error(), Severity: SeverityError})
+			continue
+		}
+		for _, verr := range b.doc.ValidateValue(p.Schema, value) {
+			verr.Path = pointer + verr.Path
+			errs = append(errs, verr)
+		}
+
+		if field, ok := fields[p.In+":"+strings.ToLower(p.Name)]; ok {
+			if err := assignValue(field, value); err != nil {
+				errs = append(errs, ValidationError{Path: pointer, Message: err.Error(), Severity: SeverityError})
+			}
+		}
+	}
+
+	bodyTarget, ok := fields["body:"]
+	if !ok {
+		bodyTarget = target.Elem()
+	}
+	errs = append(errs, b.bindBody(r, bodyTarget)...)
+
+	if len(errs) > 0 {
+		return &RequestError{Errors: errs}
+	}
+	return nil
+}
+
+// rawParameter extracts the serialized values of a parameter from the request
+func (b *Binder) rawParameter(r *http.Request, p Parameter, pathValues map[string]string, query url.Values) ([]string, bool) {
+	switch p.In {
+	case "path":
+		if v := r.PathValue(p.Name); v != "" {
+			return []string{v}, true
+		}
+		v, ok := pathValues[p.Name]
+		return []string{v}, ok
+	case "query":
+		if p.Style == "deepObject" || (isObjectSchema(b.doc.resolveSchema(p.Schema)) && explodes(p)) {
+			var pairs []string
+			for key, values := range query {
+				for _, v := range values {
+					pairs = append(pairs, key+"="+v)
+				}
+			}
+			return pairs, len(pairs) > 0
+		}
+		values, ok := query[p.Name]
+		return values, ok
+	case "header":
+		values := r.Header.Values(p.Name)
+		return values, len(values) > 0
+	case "cookie":
+		cookie, err := r.Cookie(p.Name)
+		if err != nil {
+			return nil, false
+		}
+		return []string{cookie.Value}, true
+	}
+	return nil, false
+}
+
+// bindBody validates and decodes the request body into target
+func (b *Binder) bindBody(r *http.Request, target reflect.Value) []ValidationError {
+	body := b.doc.resolveRequestBody(b.op.RequestBody)
+	if body == nil || r.Body == nil {
+		return nil
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return []ValidationError{{Path: "/body", Message: err.Error(), Severity: SeverityError}}
+	}
+	if len(data) == 0 {
+		if body.Required {
+			return []ValidationError{{Path: "/body", Message: "missing required request body", Severity: SeverityError}}
+		}
+		return nil
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	name, mt, ok := matchMediaType(body.Content, contentType)
+	if !ok {
+		return []ValidationError{{Path: "/body", Message: fmt.Sprintf("content type %q is not accepted", contentType), Severity: SeverityError}}
+	}
+
+	var value interface{}
+	switch {
+	case isJSONMediaType(name):
+		if err := json.Unmarshal(data, &value); err != nil {
+			return []ValidationError{{Path: "/body", Message: fmt.Sprintf("invalid JSON: %v", err), Severity: SeverityError}}
+		}
+	case strings.HasPrefix(name, "application/x-www-form-urlencoded"):
+		form, err := url.ParseQuery(string(data))
+		if err != nil {
+			return []ValidationError{{Path: "/body", Message: err.Error(), Severity: SeverityError}}
+		}
+		properties, _ := b.doc.schemaProperties(mt.Schema)
+		obj := make(map[string]interface{}, len(form))
+		for key, values := range form {
+			coerced, err := b.doc.coerceValues(properties[key], values, ",")
+			if err != nil {
+				return []ValidationError{{Path: "/body/" + escapePointer(key), Message: err.Error(), Severity: SeverityError}}
+			}
+			obj[key] = coerced
+		}
+		value = obj
+	default:
+		// Opaque payloads can only be bound to string or []byte targets
+		switch {
+		case target.Kind() == reflect.String:
+			target.SetString(string(data))
+		case target.Kind() == reflect.Slice && target.Type().Elem().Kind() == reflect.Uint8:
+			target.SetBytes(data)
+		default:
+			return []ValidationError{{Path: "/body", Message: fmt.Sprintf("cannot bind %q body to %s", name, target.Type()), Severity: SeverityError}}
+		}
+		return nil
+	}
+
+	var errs []ValidationError
+	for _, err := range b.doc.ValidateValue(mt.Schema, value) {
+		err.Path = "/body" + err.Path
+		errs = append(errs, err)
+	}
+	if err := assignValue(target, value); err != nil {
+		errs = append(errs, ValidationError{Path: "/body", Message: err.Error(), Severity: SeverityError})
+	}
+	return errs
+}
+
+// decodeParameter deserializes raw parameter values according to the
+// parameter's style and explode settings and coerces them to its schema
+func (d *Document) decodeParameter(p Parameter, raw []string) (interface{}, error) {
+	schema := d.resolveSchema(p.Schema)
+	if schema == nil {
+		// Parameters described by content carry a serialized (usually JSON) document
+		var value interface{}
+		if len(p.Content) > 0 && json.Unmarshal([]byte(raw[0]), &value) == nil {
+			return value, nil
+		}
+		return raw[0], nil
+	}
+
+	style := p.Style
+	if style == "" {
+		style = map[string]string{"path": "simple", "header": "simple", "query": "form", "cookie": "form"}[p.In]
+	}
+
+	if p.In == "query" && isObjectSchema(schema) {
+		return d.decodeQueryObject(p, schema, raw)
+	}
+
+	value := strings.Join(raw, ",")
+	separator := ","
+	switch style {
+	case "label":
+		value = strings.TrimPrefix(value, ".")
+		if explodes(p) {
+			separator = "."
+		}
+	case "matrix":
+		value = strings.TrimPrefix(value, ";"+p.Name+"=")
+		if explodes(p) {
+			separator = ";" + p.Name + "="
+		}
+	case "spaceDelimited":
+		separator = " "
+	case "pipeDelimited":
+		separator = "|"
+	}
+
+	if schema.Type.Is("array") && p.In == "query" && explodes(p) && style == "form" {
+		return d.coerceValues(schema, raw, separator)
+	}
+	return d.coerceValues(schema, []string{value}, separator)
+}
+
+// decodeQueryObject rebuilds an object parameter from query pairs, either in
+// deepObject form ("filter[name]=x") or exploded form ("name=x")
+func (d *Document) decodeQueryObject(p Parameter, schema *Schema, pairs []string) (interface{}, error) {
+	properties, _ := d.schemaProperties(schema)
+	obj := make(map[string]interface{})
+	for _, pair := range pairs {
+		key, value, _ := strings.Cut(pair, "=")
+		if p.Style == "deepObject" {
+			inner, ok := strings.CutPrefix(key, p.Name+"[")
+			if !ok || !strings.HasSuffix(inner, "]") {
+				continue
+			}
+			key = strings.TrimSuffix(inner, "]")
+		} else if _, ok := properties[key]; !ok {
+			continue
+		}
+		coerced, err := d.coerceValues(properties[key], []string{value}, ",")
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %v", key, err)
+		}
+		obj[key] = coerced
+	}
+	return obj, nil
+}
+
+// coerceValues converts serialized values into the JSON types declared by a
+// schema. Arrays accept repeated values or a single separator-joined value.
+func (d *Document) coerceValues(s *Schema, values []string, separator string) (interface{}, error) {
+	s = d.resolveSchema(s)
+	if s != nil && s.Type.Is("array") {
+		if len(values) == 1 {
+			values = strings.Split(values[0], separator)
+			if len(values) == 1 && values[0] == "" {
+				values = nil
+			}
+		}
+		items := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			item, err := coerceScalar(d.resolveSchema(s.Items), v)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return coerceScalar(s, values[0])
+}
+
+// coerceScalar converts a single serialized value to its schema's type
+func coerceScalar(s *Schema, value string) (interface{}, error) {
+	if s == nil {
+		return value, nil
+	}
+	switch s.Type.Primary() {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", value)
+		}
+		return float64(n), nil
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return n, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", value)
+		}
+		return b, nil
+	case "object":
+		var obj interface{}
+		if err := json.Unmarshal([]byte(value), &obj); err != nil {
+			return nil, fmt.Errorf("%q is not a JSON object", value)
+		}
+		return obj, nil
+	}
+	return value, nil
+}
+
+// resolveRequestBody follows a local request body component reference. A
+// description on the reference overrides the one of the component.
+func (d *Document) resolveRequestBody(body *RequestBody) *RequestBody {
+	description := ""
+	for i := 0; body != nil && body.Ref != nil && i < 16; i++ {
+		if description == "" {
+			description = body.Ref.Description
+		}
+		name, ok := strings.CutPrefix(body.Ref.Ref, "#/components/requestBodies/")
+		if !ok || d.Components == nil {
+			return body
+		}
+		resolved, ok := d.Components.RequestBodies[unescapePointer(name)]
+		if !ok {
+			return body
+		}
+		body = &resolved
+	}
+	if description != "" && body != nil && body.Ref == nil {
+		body.Description = description
+	}
+	return body
+}
+
+// explodes reports the effective explode setting of a parameter
+func explodes(p Parameter) bool {
+	if p.Explode != nil {
+		return *p.Explode
+	}
+	return p.Style == "" && (p.In == "query" || p.In == "cookie") || p.Style == "form"
+}
+
+func isObjectSchema(s *Schema) bool {
+	return s != nil && (s.Type.Is("object") || (len(s.Type) == 0 && len(s.Properties) > 0))
+}
+
+// collectBindFields indexes the struct fields carrying binding tags by
+// "location:name", descending into embedded structs
+func collectBindFields(v reflect.Value, fields map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectBindFields(v.Field(i), fields)
+			continue
+		}
+		for _, in := range []string{"path", "query", "header", "cookie"} {
+			if name, ok := field.Tag.Lookup(in); ok {
+				fields[in+":"+strings.ToLower(name)] = v.Field(i)
+			}
+		}
+		if _, ok := field.Tag.Lookup("body"); ok {
+			fields["body:"] = v.Field(i)
+		}
+	}
+}
+
+// assignValue stores a decoded JSON value into a Go value of any compatible type
+func assignValue(target reflect.Value, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, target.Addr().Interface()); err != nil {
+		return fmt.Errorf("cannot assign to %s: %v", target.Type(), err)
+	}
+	return nil
+}
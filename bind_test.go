@@ -0,0 +1,137 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type petQuery struct {
+	PetID   int64    `path:"petId"`
+	Limit   int      `query:"limit"`
+	Tags    []string `query:"tags"`
+	Tenant  string   `header:"X-Tenant-ID"`
+	Name    string   `json:"name"`
+	Ignored string
+}
+
+func bindDocument() *Document {
+	doc := NewDocument("Pet API", "1.0.0")
+	pet := NewObjectSchema().WithRequiredProperty("name", StringSchema(""))
+	op := NewOperation("updatePet", "Update pet", "").
+		WithPathParameter("petId", "", Int64Schema()).
+		WithQueryParameter("limit", "", false, Int32Schema()).
+		WithQueryParameter("tags", "", false, NewArraySchema(StringSchema(""))).
+		WithHeaderParameter("X-Tenant-ID", "", true, StringSchema("")).
+		WithJSONRequestBody("Pet", true, &pet).
+		WithNoContentResponse()
+	doc.AddOperation("/pets/{petId}", "PUT", op)
+	return doc
+}
+
+func TestBind(t *testing.T) {
+	binder, err := bindDocument().NewBinder("updatePet")
+	if err != nil {
+		t.Fatalf("Error creating binder: %v", err)
+	}
+
+	req := httptest.NewRequest("PUT", "/pets/42?limit=10&tags=a&tags=b", strings.NewReader(`{"name": "Rex"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", "acme")
+
+	var dst petQuery
+	if err := binder.Bind(req, &dst); err != nil {
+		t.Fatalf("Error binding request: %v", err)
+	}
+
+	if dst.PetID != 42 || dst.Limit != 10 || dst.Tenant != "acme" || dst.Name != "Rex" {
+		t.Errorf("Unexpected binding result: %+v", dst)
+	}
+
+	if len(dst.Tags) != 2 || dst.Tags[1] != "b" {
+		t.Errorf("Expected tags [a b], got %v", dst.Tags)
+	}
+}
+
+func TestBindReportsViolations(t *testing.T) {
+	binder, _ := bindDocument().NewBinder("updatePet")
+
+	req := httptest.NewRequest("PUT", "/pets/abc?limit=ten", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	var dst petQuery
+	err := binder.Bind(req, &dst)
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("Expected *RequestError, got %v", err)
+	}
+
+	for _, path := range []string{"/path/petId", "/query/limit", "/header/X-Tenant-ID", "/body"} {
+		if findValidationError(reqErr.Errors, path) == nil {
+			t.Errorf("Expected error at '%s', got %v", path, reqErr.Errors)
+		}
+	}
+}
+
+type createPetRequest struct {
+	Tenant string `header:"X-Tenant-ID"`
+	Name   string `json:"name"`
+	Age    int    `json:"age,omitempty"`
+}
+
+type createdPet struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestHandle(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	mux := http.NewServeMux()
+	op := Handle(doc, mux, "POST /pets", func(ctx context.Context, req createPetRequest) (createdPet, error) {
+		if req.Name == "taken" {
+			return createdPet{}, NewHTTPError(http.StatusConflict, "name taken")
+		}
+		return createdPet{ID: 1, Name: req.Name}, nil
+	})
+
+	if op.OperationID != "postPets" {
+		t.Errorf("Expected operationId 'postPets', got '%s'", op.OperationID)
+	}
+
+	if len(op.Parameters) != 1 || op.Parameters[0].Name != "X-Tenant-ID" {
+		t.Errorf("Expected X-Tenant-ID header parameter, got %+v", op.Parameters)
+	}
+
+	body := op.RequestBody.Content["application/json"].Schema
+	if _, ok := body.Properties["X-Tenant-ID"]; ok || len(body.Required) != 1 || body.Required[0] != "name" {
+		t.Errorf("Expected body with required 'name' only, got %+v", body)
+	}
+
+	if ref := op.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/CreatedPet" {
+		t.Errorf("Expected response reference to CreatedPet, got '%s'", ref)
+	}
+
+	for input, status := range map[string]int{`{"name": "Rex"}`: 200, `{"name": "taken"}`: 409, `{"age": 3}`: 400} {
+		req := httptest.NewRequest("POST", "/pets", strings.NewReader(input))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != status {
+			t.Errorf("Expected status %d for %s, got %d: %s", status, input, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	SchemaOf[[]createdPet](doc)
+
+	source, ok := doc.Provenance()["CreatedPet"]
+	if !ok || source.String() != "github.com/nyxstack/openapi.createdPet" {
+		t.Errorf("Expected CreatedPet to come from github.com/nyxstack/openapi.createdPet, got %v", source)
+	}
+}
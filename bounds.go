@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ExclusiveBound is an exclusiveMinimum or exclusiveMaximum in either of its
+// forms: in OpenAPI 3.0 a flag making minimum or maximum exclusive, in
+// OpenAPI 3.1 a bound of its own. Documents marshal it in the form of their
+// OpenAPI version.
+type ExclusiveBound struct {
+	// Flag makes minimum or maximum exclusive, the OpenAPI 3.0 form
+	Flag bool
+	// Value is the exclusive bound, the OpenAPI 3.1 form
+	Value *float64
+}
+
+// IsZero reports whether the bound is unset, so it is omitted from JSON
+func (b ExclusiveBound) IsZero() bool {
+	return !b.Flag && b.Value == nil
+}
+
+// MarshalJSON implements custom JSON marshaling for ExclusiveBound
+func (b ExclusiveBound) MarshalJSON() ([]byte, error) {
+	if b.Value != nil {
+		return json.Marshal(*b.Value)
+	}
+	return json.Marshal(b.Flag)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for ExclusiveBound,
+// accepting a flag or a number
+func (b *ExclusiveBound) UnmarshalJSON(data []byte) error {
+	*b = ExclusiveBound{}
+	if err := json.Unmarshal(data, &b.Flag); err == nil {
+		return nil
+	}
+	var value float64
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("exclusive bound: %w", err)
+	}
+	b.Value = &value
+	return nil
+}
+
+// WithExclusiveMinimum sets an exclusive lower bound
+func (s Schema) WithExclusiveMinimum(min float64) Schema {
+	s.ExclusiveMinimum = ExclusiveBound{Value: &min}
+	return s
+}
+
+// WithExclusiveMaximum sets an exclusive upper bound
+func (s Schema) WithExclusiveMaximum(max float64) Schema {
+	s.ExclusiveMaximum = ExclusiveBound{Value: &max}
+	return s
+}
+
+// LowerBound returns the effective lower bound of the schema and whether it
+// is exclusive, whichever form its exclusive minimum takes. When minimum and
+// a 3.1 exclusive minimum are both set, the stricter one is returned.
+func (s *Schema) LowerBound() (*float64, bool) {
+	return effectiveBound(s.Minimum, s.ExclusiveMinimum, func(a, b float64) bool { return a >= b })
+}
+
+// UpperBound returns the effective upper bound of the schema and whether it
+// is exclusive, like LowerBound
+func (s *Schema) UpperBound() (*float64, bool) {
+	return effectiveBound(s.Maximum, s.ExclusiveMaximum, func(a, b float64) bool { return a <= b })
+}
+
+// effectiveBound combines an inclusive bound with an exclusive one; stricter
+// reports whether the first bound is at least as strict as the second
+func effectiveBound(inclusive *float64, exclusive ExclusiveBound, stricter func(a, b float64) bool) (*float64, bool) {
+	if exclusive.Value == nil {
+		return inclusive, inclusive != nil && exclusive.Flag
+	}
+	if inclusive == nil || stricter(*exclusive.Value, *inclusive) {
+		return exclusive.Value, true
+	}
+	return inclusive, false
+}
+
+// hasForeignExclusiveBounds reports whether a schema of the document uses the
+// exclusive bound form of another OpenAPI version
+func (d *Document) hasForeignExclusiveBounds() bool {
+	numeric := isOpenAPI31(d.OpenAPI)
+	foreign := false
+	d.walkSchemas(func(_ string, s *Schema) {
+		for _, b := range []ExclusiveBound{s.ExclusiveMinimum, s.ExclusiveMaximum} {
+			if (numeric && b.Flag) || (!numeric && b.Value != nil) {
+				foreign = true
+			}
+		}
+	})
+	return foreign
+}
+
+// convertExclusiveBounds rewrites the exclusive bounds of every schema in the
+// form of the document's OpenAPI version
+func (d *Document) convertExclusiveBounds() {
+	numeric := isOpenAPI31(d.OpenAPI)
+	d.walkSchemas(func(_ string, s *Schema) {
+		for _, bound := range []struct {
+			inclusive **float64
+			exclusive *ExclusiveBound
+			effective func() (*float64, bool)
+		}{
+			{&s.Minimum, &s.ExclusiveMinimum, s.LowerBound},
+			{&s.Maximum, &s.ExclusiveMaximum, s.UpperBound},
+		} {
+			switch {
+			case numeric && bound.exclusive.Flag:
+				if *bound.inclusive != nil {
+					*bound.exclusive = ExclusiveBound{Value: *bound.inclusive}
+					*bound.inclusive = nil
+				} else {
+					*bound.exclusive = ExclusiveBound{}
+				}
+			case !numeric && bound.exclusive.Value != nil:
+				value, exclusive := bound.effective()
+				*bound.inclusive = value
+				*bound.exclusive = ExclusiveBound{Flag: exclusive}
+			}
+		}
+	})
+}
@@ -0,0 +1,314 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// RefLoader loads the documents that external references point to
+type RefLoader interface {
+	// Load returns the content of the JSON or YAML document at a location:
+	// a slash-separated path or an absolute URL
+	Load(ctx context.Context, location string) ([]byte, error)
+}
+
+// RefLoaderFunc adapts a function to the RefLoader interface
+type RefLoaderFunc func(ctx context.Context, location string) ([]byte, error)
+
+// Load calls f
+func (f RefLoaderFunc) Load(ctx context.Context, location string) ([]byte, error) {
+	return f(ctx, location)
+}
+
+// Loader is a RefLoader reading http and https URLs with Client, or
+// http.DefaultClient when nil, and other locations from FS
+type Loader struct {
+	FS     fs.FS
+	Client *http.Client
+}
+
+// Load reads the document at a location
+func (l Loader) Load(ctx context.Context, location string) ([]byte, error) {
+	if isHTTPLocation(location) {
+		client := l.Client
+		if client == nil {
+			client = http.DefaultClient
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", location, resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}
+	if l.FS == nil {
+		return nil, fmt.Errorf("no file system to read %s from", location)
+	}
+	return fs.ReadFile(l.FS, location)
+}
+
+func isHTTPLocation(location string) bool {
+	u, err := url.Parse(location)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// bundler folds external references into the components of a document
+type bundler struct {
+	ctx    context.Context
+	loader RefLoader
+	// files caches the loaded documents by location
+	files map[string]interface{}
+	// refs maps the external targets bundled so far, by kind and
+	// "location#fragment", to their component reference
+	refs map[string]string
+	// components holds the components of the bundled document by kind and name
+	components map[string]interface{}
+}
+
+// Bundle returns a self-contained copy of the document: every reference to
+// another file or URL is resolved through the loader and its target folded
+// into the components, along with the targets it references in turn, and
+// the reference rewritten. base is the location of the document itself,
+// against which relative references resolve.
+//
+// Components are named after the last token of the reference fragment, or
+// after the file for references without a fragment, and are renamed with a
+// numeric suffix when the name is already taken. The kind of component
+// follows from where the reference appears; referenced path items are
+// inlined as components can't hold them.
+func (d *Document) Bundle(ctx context.Context, base string, loader RefLoader) (*Document, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	components, _ := root["components"].(map[string]interface{})
+	if components == nil {
+		components = make(map[string]interface{})
+	}
+	b := &bundler{
+		ctx:        ctx,
+		loader:     loader,
+		files:      make(map[string]interface{}),
+		refs:       make(map[string]string),
+		components: components,
+	}
+	bundled, err := b.rewrite(base, root, nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	out := bundled.(map[string]interface{})
+	// The rewritten components replace the originals next to the bundled ones
+	rewritten, _ := out["components"].(map[string]interface{})
+	for kind, value := range rewritten {
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name, component := range entries {
+			b.kind(kind)[name] = component
+		}
+	}
+	if len(b.components) > 0 {
+		out["components"] = b.components
+	}
+	if data, err = json.Marshal(out); err != nil {
+		return nil, err
+	}
+	return FromJSON(data)
+}
+
+// kind returns the components of a kind, creating the map if needed
+func (b *bundler) kind(kind string) map[string]interface{} {
+	entries, ok := b.components[kind].(map[string]interface{})
+	if !ok {
+		entries = make(map[string]interface{})
+		b.components[kind] = entries
+	}
+	return entries
+}
+
+// rewrite replaces the external references in a value found at location,
+// whose own local references are external to the root document unless local
+// is set. tokens is the JSON pointer of the value in its document, used to
+// tell the kind of component a reference denotes; inSchema is set inside
+// schemas, where every reference denotes a schema.
+func (b *bundler) rewrite(location string, value interface{}, tokens []string, inSchema, local bool) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && (!local || !strings.HasPrefix(ref, "#")) {
+			replaced, inlined, err := b.resolve(location, ref, tokens, inSchema)
+			if err != nil {
+				return nil, err
+			}
+			if inlined != nil {
+				return inlined, nil
+			}
+			out := make(map[string]interface{}, len(v))
+			for key, child := range v {
+				out[key] = child
+			}
+			out["$ref"] = replaced
+			return out, nil
+		}
+		out := make(map[string]interface{}, len(v))
+		for _, key := range sortedKeys(v) {
+			child := v[key]
+			childInSchema := inSchema || key == "schema" || (key == "schemas" && len(tokens) == 1 && tokens[0] == "components")
+			rewritten, err := b.rewrite(location, child, append(tokens, key), childInSchema, local)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = rewritten
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			rewritten, err := b.rewrite(location, child, append(tokens, strconv.Itoa(i)), inSchema, local)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rewritten
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// resolve bundles the target of a reference made from location and returns
+// the reference to its component, or the target itself for path items
+func (b *bundler) resolve(location, ref string, tokens []string, inSchema bool) (string, interface{}, error) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	if file == "" {
+		file = location
+	} else {
+		file = resolveLocation(location, file)
+	}
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return "", nil, fmt.Errorf("openapi: bundling %s: %w", ref, err)
+	}
+	kind := componentKind(tokens, inSchema)
+
+	target, err := b.target(file, fragment)
+	if err != nil {
+		return "", nil, fmt.Errorf("openapi: bundling %s: %w", ref, err)
+	}
+	if kind == "" {
+		inlined, err := b.rewrite(file, target, tokens, inSchema, false)
+		return "", inlined, err
+	}
+
+	id := kind + " " + file + "#" + fragment
+	if bundled, ok := b.refs[id]; ok {
+		return bundled, nil, nil
+	}
+	name := path.Base(fragment)
+	if fragment == "" || fragment == "/" {
+		stem := path.Base(file)
+		name = exportedName(strings.TrimSuffix(stem, path.Ext(stem)))
+	} else {
+		name = unescapePointer(name)
+	}
+	entries := b.kind(kind)
+	unique := name
+	for i := 2; entries[unique] != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	bundled := "#/components/" + kind + "/" + escapePointer(unique)
+	b.refs[id] = bundled
+	// Reserve the name before rewriting the target, which may refer to itself
+	entries[unique] = map[string]interface{}{}
+
+	rewritten, err := b.rewrite(file, target, []string{"components", kind, unique}, kind == "schemas", false)
+	if err != nil {
+		return "", nil, err
+	}
+	entries[unique] = rewritten
+	return bundled, nil, nil
+}
+
+// target loads the value at a JSON pointer in the document at a location
+func (b *bundler) target(location, fragment string) (interface{}, error) {
+	root, ok := b.files[location]
+	if !ok {
+		data, err := b.loader.Load(b.ctx, location)
+		if err != nil {
+			return nil, err
+		}
+		converted, err := yamlToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", location, err)
+		}
+		if err := json.Unmarshal(converted, &root); err != nil {
+			return nil, err
+		}
+		b.files[location] = root
+	}
+	value, ok := pointerValue(root, strings.TrimSuffix(fragment, "/"))
+	if !ok {
+		return nil, fmt.Errorf("%s has nothing at %q", location, fragment)
+	}
+	return value, nil
+}
+
+// resolveLocation resolves a reference against the location it appears in
+func resolveLocation(base, ref string) string {
+	if isHTTPLocation(ref) {
+		return ref
+	}
+	if isHTTPLocation(base) {
+		u, _ := url.Parse(base)
+		r, err := url.Parse(ref)
+		if err != nil {
+			return ref
+		}
+		return u.ResolveReference(r).String()
+	}
+	if path.IsAbs(ref) {
+		return strings.TrimPrefix(path.Clean(ref), "/")
+	}
+	return path.Join(path.Dir(base), ref)
+}
+
+// componentKind returns the kind of component a reference at tokens denotes,
+// or "" for path items, which can't be components
+func componentKind(tokens []string, inSchema bool) string {
+	if inSchema {
+		return "schemas"
+	}
+	if len(tokens) < 2 {
+		return ""
+	}
+	last, parent := tokens[len(tokens)-1], tokens[len(tokens)-2]
+	switch {
+	case last == "requestBody" || parent == "requestBodies":
+		return "requestBodies"
+	case parent == "paths" || parent == "webhooks" || (len(tokens) >= 3 && tokens[len(tokens)-3] == "callbacks"):
+		return ""
+	}
+	switch parent {
+	case "responses", "parameters", "examples", "headers", "links", "callbacks", "securitySchemes":
+		return parent
+	}
+	return "schemas"
+}
@@ -1,15 +1,71 @@
 package openapi
 
-// Callback represents a callback in OpenAPI
-type Callback map[string]PathItem
+import (
+	"encoding/json"
+)
+
+// Callback represents a callback in OpenAPI: the requests the API sends,
+// keyed by the runtime expression of their URL, or a reference to a callback
+// component
+type Callback struct {
+	Ref        *Reference
+	Paths      map[string]PathItem
+	Extensions map[string]interface{}
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification
+// extensions. A reference is marshaled as the Reference Object alone.
+func (c Callback) MarshalJSON() ([]byte, error) {
+	if c.Ref != nil {
+		return json.Marshal(c.Ref)
+	}
+	paths := c.Paths
+	if paths == nil {
+		paths = map[string]PathItem{}
+	}
+	return marshalWithExtensions(paths, c.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification
+// extensions and decode Reference Objects
+func (c *Callback) UnmarshalJSON(data []byte) error {
+	ref, err := unmarshalReference(data)
+	if err != nil || ref != nil {
+		*c = Callback{Ref: ref}
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*c = Callback{}
+	for expression, raw := range fields {
+		if isExtensionKey(expression) {
+			continue
+		}
+		var item PathItem
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return err
+		}
+		if c.Paths == nil {
+			c.Paths = make(map[string]PathItem)
+		}
+		c.Paths[expression] = item
+	}
+	c.Extensions, err = unmarshalExtensions(data)
+	return err
+}
 
 // NewCallback creates a new callback
 func NewCallback() Callback {
-	return make(Callback)
+	return Callback{Paths: make(map[string]PathItem)}
 }
 
 // WithPath adds a path to the callback
 func (c Callback) WithPath(expression string, pathItem PathItem) Callback {
-	c[expression] = pathItem
+	if c.Paths == nil {
+		c.Paths = make(map[string]PathItem)
+	}
+	c.Paths[expression] = pathItem
 	return c
 }
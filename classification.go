@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Classification extensions on operations
+const (
+	// ExtensionDataClassification holds the sensitivity of the data an operation handles
+	ExtensionDataClassification = "x-data-classification"
+	// ExtensionAudience holds who an operation is exposed to
+	ExtensionAudience = "x-audience"
+)
+
+// DataClassification is the sensitivity of the data an operation handles
+type DataClassification string
+
+const (
+	ClassificationPublic       DataClassification = "public"
+	ClassificationInternal     DataClassification = "internal"
+	ClassificationConfidential DataClassification = "confidential"
+	// ClassificationPII marks personally identifiable information
+	ClassificationPII DataClassification = "pii"
+)
+
+// Audience is who an operation is exposed to
+type Audience string
+
+const (
+	AudiencePublic   Audience = "public"
+	AudiencePartner  Audience = "partner"
+	AudienceInternal Audience = "internal"
+)
+
+// dataClassifications and audiences list the known values, from least to most sensitive
+var (
+	dataClassifications = []DataClassification{ClassificationPublic, ClassificationInternal, ClassificationConfidential, ClassificationPII}
+	audiences           = []Audience{AudiencePublic, AudiencePartner, AudienceInternal}
+)
+
+// WithDataClassification sets the sensitivity of the data the operation handles
+func (o Operation) WithDataClassification(classification DataClassification) Operation {
+	return o.WithExtension(ExtensionDataClassification, classification)
+}
+
+// WithAudience sets who the operation is exposed to
+func (o Operation) WithAudience(audience Audience) Operation {
+	return o.WithExtension(ExtensionAudience, audience)
+}
+
+// DataClassification returns the sensitivity of the data the operation handles
+func (o Operation) DataClassification() (DataClassification, bool) {
+	var classification DataClassification
+	ok := decodeExtension(o.Extensions[ExtensionDataClassification], &classification)
+	return classification, ok
+}
+
+// Audience returns who the operation is exposed to
+func (o Operation) Audience() (Audience, bool) {
+	var audience Audience
+	ok := decodeExtension(o.Extensions[ExtensionAudience], &audience)
+	return audience, ok
+}
+
+// ValidateClassification checks that every operation declares a known data
+// classification and audience, for APIs whose compliance reviews require them
+func (d *Document) ValidateClassification() []ValidationError {
+	v := &validator{doc: d}
+	d.walkOperations(func(path, method string, op *Operation) {
+		pointer := operationPointer(path, method)
+		if _, ok := op.Extensions[ExtensionDataClassification]; !ok {
+			v.errorf(pointer, "%s %s has no %s", method, path, ExtensionDataClassification)
+		} else if classification, _ := op.DataClassification(); !slices.Contains(dataClassifications, classification) {
+			v.errorf(pointer+"/"+ExtensionDataClassification, "unknown data classification %q", classification)
+		}
+		if _, ok := op.Extensions[ExtensionAudience]; !ok {
+			v.errorf(pointer, "%s %s has no %s", method, path, ExtensionAudience)
+		} else if audience, _ := op.Audience(); !slices.Contains(audiences, audience) {
+			v.errorf(pointer+"/"+ExtensionAudience, "unknown audience %q", audience)
+		}
+	})
+	return v.errs
+}
+
+// ClassificationEntry is a single row of a classification report
+type ClassificationEntry struct {
+	Path           string             `json:"path"`
+	Method         string             `json:"method"`
+	OperationID    string             `json:"operationId,omitempty"`
+	Classification DataClassification `json:"classification,omitempty"`
+	Audience       Audience           `json:"audience,omitempty"`
+}
+
+// ClassificationReport rolls up the classification of every operation
+type ClassificationReport struct {
+	Entries []ClassificationEntry `json:"entries"`
+	// ByClassification counts operations per data classification
+	ByClassification map[DataClassification]int `json:"byClassification"`
+	// ByAudience counts operations per audience
+	ByAudience map[Audience]int `json:"byAudience"`
+	// Exposed lists confidential or PII operations with a public audience as "METHOD path"
+	Exposed []string `json:"exposed,omitempty"`
+	// Unclassified lists operations missing a classification or audience as "METHOD path"
+	Unclassified []string `json:"unclassified,omitempty"`
+}
+
+// ClassificationReport collects the classification of every operation in the document
+func (d *Document) ClassificationReport() ClassificationReport {
+	report := ClassificationReport{
+		ByClassification: make(map[DataClassification]int),
+		ByAudience:       make(map[Audience]int),
+	}
+	d.walkOperations(func(path, method string, op *Operation) {
+		classification, classified := op.DataClassification()
+		audience, audienced := op.Audience()
+		if !classified || !audienced {
+			report.Unclassified = append(report.Unclassified, method+" "+path)
+		}
+		if classified {
+			report.ByClassification[classification]++
+		}
+		if audienced {
+			report.ByAudience[audience]++
+		}
+		if audience == AudiencePublic && (classification == ClassificationConfidential || classification == ClassificationPII) {
+			report.Exposed = append(report.Exposed, method+" "+path)
+		}
+		report.Entries = append(report.Entries, ClassificationEntry{
+			Path:           path,
+			Method:         method,
+			OperationID:    op.OperationID,
+			Classification: classification,
+			Audience:       audience,
+		})
+	})
+	return report
+}
+
+// Markdown renders the report as a roll-up table per classification followed
+// by the operations needing attention
+func (r ClassificationReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString("| Classification | Operations |\n|---|---|\n")
+	for _, classification := range dataClassifications {
+		fmt.Fprintf(&b, "| %s | %d |\n", classification, r.ByClassification[classification])
+	}
+	b.WriteString("\n| Audience | Operations |\n|---|---|\n")
+	for _, audience := range audiences {
+		fmt.Fprintf(&b, "| %s | %d |\n", audience, r.ByAudience[audience])
+	}
+	if len(r.Exposed) > 0 {
+		b.WriteString("\nConfidential or PII operations with a public audience:\n\n")
+		for _, op := range r.Exposed {
+			fmt.Fprintf(&b, "- %s\n", op)
+		}
+	}
+	if len(r.Unclassified) > 0 {
+		b.WriteString("\nOperations without a classification or audience:\n\n")
+		for _, op := range r.Unclassified {
+			fmt.Fprintf(&b, "- %s\n", op)
+		}
+	}
+	return b.String()
+}
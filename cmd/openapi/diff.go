@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nyxstack/openapi"
+	"github.com/nyxstack/openapi/diff"
+)
+
+// runDiff prints the changes between two documents, breaking ones first, and
+// reports whether any is breaking
+func runDiff(oldDoc, newDoc *openapi.Document, out io.Writer) bool {
+	log := diff.Compare(oldDoc, newDoc)
+	if len(log.Changes) == 0 {
+		fmt.Fprintln(out, "no changes")
+		return false
+	}
+	for _, breaking := range []bool{true, false} {
+		for _, change := range log.Changes {
+			if change.Breaking == breaking {
+				fmt.Fprintln(out, change)
+			}
+		}
+	}
+	breaking := len(log.Breaking())
+	fmt.Fprintf(out, "%d changes, %d breaking\n", len(log.Changes), breaking)
+	return breaking > 0
+}
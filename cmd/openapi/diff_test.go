@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyxstack/openapi"
+)
+
+func TestDiff(t *testing.T) {
+	oldDoc := openapi.NewDocument("Pet API", "1.0.0")
+	oldDoc.AddOperation("/pets", "GET", openapi.NewOperation("listPets", "", "").
+		WithResponse("200", "Pets", openapi.Response{}))
+	oldDoc.AddOperation("/pets/{petId}", "DELETE", openapi.NewOperation("deletePet", "", ""))
+	newDoc := openapi.NewDocument("Pet API", "1.1.0")
+	newDoc.AddOperation("/pets", "GET", openapi.NewOperation("listPets", "", "").
+		WithResponse("200", "Pets", openapi.Response{}))
+	newDoc.AddOperation("/owners", "GET", openapi.NewOperation("listOwners", "", ""))
+
+	var out strings.Builder
+	if !runDiff(oldDoc, newDoc, &out) {
+		t.Errorf("Expected the removed path to be breaking, got:\n%s", out.String())
+	}
+	expected := "[breaking] path-removed: path /pets/{petId} was removed\n[non-breaking] path-added: path /owners was added\n2 changes, 1 breaking\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	if runDiff(oldDoc, oldDoc, &out) || out.String() != "no changes\n" {
+		t.Errorf("Expected no changes, got %q", out.String())
+	}
+}
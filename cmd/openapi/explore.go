@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nyxstack/openapi"
+)
+
+const explorerHelp = `Commands:
+  paths              list paths and their operations
+  schemas            list component schemas
+  search <text>      find operations and schemas by path, ID, summary, tag or name
+  <number>           open a numbered entry of the current screen
+  op <operationId>   open an operation by its ID
+  schema <name>      open a component schema
+  ref <reference>    open a schema or operation reference, e.g. #/components/schemas/Pet
+  back               return to the previous screen
+  help               show this help
+  quit               leave the explorer
+`
+
+// view is a screen of the explorer
+type view struct {
+	// kind is "paths", "schemas", "search", "operation" or "schema"
+	kind string
+	// key is the method and path of an operation, the name of a schema or
+	// the search text
+	key string
+}
+
+// explorer browses a document interactively, one command per line
+type explorer struct {
+	doc     *openapi.Document
+	out     io.Writer
+	history []view
+	// entries are the views opened by the numbers of the current screen
+	entries []view
+}
+
+func newExplorer(doc *openapi.Document, out io.Writer) *explorer {
+	return &explorer{doc: doc, out: out}
+}
+
+// run reads commands until "quit" or the end of the input
+func (e *explorer) run(in io.Reader) error {
+	fmt.Fprintf(e.out, "%s %s (OpenAPI %s), type \"help\" for commands\n", e.doc.Info.Title, e.doc.Info.Version, e.doc.OpenAPI)
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(e.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(e.out)
+			return scanner.Err()
+		}
+		if !e.exec(strings.TrimSpace(scanner.Text())) {
+			return nil
+		}
+	}
+}
+
+// exec runs a command, reporting whether the explorer goes on
+func (e *explorer) exec(line string) bool {
+	command, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch command {
+	case "":
+	case "quit", "exit":
+		return false
+	case "help":
+		fmt.Fprint(e.out, explorerHelp)
+	case "paths", "schemas":
+		e.open(view{kind: command})
+	case "search":
+		if arg == "" {
+			fmt.Fprintln(e.out, "usage: search <text>")
+			break
+		}
+		e.open(view{kind: "search", key: arg})
+	case "op":
+		path, method, op := e.doc.FindOperation(arg)
+		if op == nil {
+			fmt.Fprintf(e.out, "no operation with ID %q\n", arg)
+			break
+		}
+		e.open(view{kind: "operation", key: method + " " + path})
+	case "schema":
+		e.open(view{kind: "schema", key: arg})
+	case "ref":
+		e.openRef(arg)
+	case "back":
+		if len(e.history) < 2 {
+			fmt.Fprintln(e.out, "nothing to go back to")
+			break
+		}
+		e.history = e.history[:len(e.history)-1]
+		e.show(e.history[len(e.history)-1])
+	default:
+		n, err := strconv.Atoi(command)
+		if err != nil {
+			fmt.Fprintf(e.out, "unknown command %q, type \"help\" for commands\n", command)
+			break
+		}
+		if n < 1 || n > len(e.entries) {
+			fmt.Fprintf(e.out, "no entry %d on this screen\n", n)
+			break
+		}
+		e.open(e.entries[n-1])
+	}
+	return true
+}
+
+// open shows a view and records it in the history. Views that don't exist
+// leave the numbering of the current screen intact.
+func (e *explorer) open(v view) {
+	entries := e.entries
+	if e.show(v) {
+		e.history = append(e.history, v)
+	} else {
+		e.entries = entries
+	}
+}
+
+// openRef opens the schema or operation a reference points to
+func (e *explorer) openRef(ref string) {
+	if name, ok := strings.CutPrefix(ref, "#/components/schemas/"); ok {
+		e.open(view{kind: "schema", key: unescape(name)})
+		return
+	}
+	if pointer, ok := strings.CutPrefix(ref, "#/paths/"); ok {
+		if i := strings.LastIndex(pointer, "/"); i >= 0 {
+			e.open(view{kind: "operation", key: strings.ToUpper(pointer[i+1:]) + " " + unescape(pointer[:i])})
+			return
+		}
+	}
+	fmt.Fprintf(e.out, "cannot open reference %q\n", ref)
+}
+
+// show renders a view, reporting whether it exists
+func (e *explorer) show(v view) bool {
+	e.entries = nil
+	switch v.kind {
+	case "paths":
+		e.doc.WalkOperations(func(path, method string, op *openapi.Operation) {
+			e.listOperation(path, method, op)
+		})
+	case "schemas":
+		for _, name := range e.schemaNames() {
+			e.listSchema(name)
+		}
+	case "search":
+		e.search(v.key)
+	case "operation":
+		return e.showOperation(v.key)
+	case "schema":
+		return e.showSchema(v.key)
+	}
+	return true
+}
+
+// search lists the operations and schemas matching text, case-insensitively
+func (e *explorer) search(text string) {
+	text = strings.ToLower(text)
+	matches := func(fields ...string) bool {
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field), text) {
+				return true
+			}
+		}
+		return false
+	}
+	e.doc.WalkOperations(func(path, method string, op *openapi.Operation) {
+		if matches(append([]string{path, op.OperationID, op.Summary}, op.Tags...)...) {
+			e.listOperation(path, method, op)
+		}
+	})
+	for _, name := range e.schemaNames() {
+		if matches(name, e.doc.Components.Schemas[name].Title) {
+			e.listSchema(name)
+		}
+	}
+	if len(e.entries) == 0 {
+		fmt.Fprintln(e.out, "no matches")
+	}
+}
+
+// showOperation renders an operation, numbering the schemas it references
+func (e *explorer) showOperation(key string) bool {
+	method, path, _ := strings.Cut(key, " ")
+	item, ok := e.doc.Paths[path]
+	op := item.Operation(method)
+	if !ok || op == nil {
+		fmt.Fprintf(e.out, "no operation %s\n", key)
+		return false
+	}
+
+	fmt.Fprintf(e.out, "%s %s", method, path)
+	if op.OperationID != "" {
+		fmt.Fprintf(e.out, " (%s)", op.OperationID)
+	}
+	fmt.Fprintln(e.out)
+	for _, text := range []string{op.Summary, op.Description} {
+		if text != "" {
+			fmt.Fprintf(e.out, "  %s\n", text)
+		}
+	}
+
+	parameters := append(append([]openapi.Parameter{}, item.Parameters...), op.Parameters...)
+	if len(parameters) > 0 {
+		fmt.Fprintln(e.out, "Parameters:")
+		for _, p := range parameters {
+			if p.Ref != nil {
+				fmt.Fprintf(e.out, "  %s\n", p.Ref)
+				continue
+			}
+			required := ""
+			if p.Required {
+				required = ", required"
+			}
+			fmt.Fprintf(e.out, "  %s (%s%s): %s\n", p.Name, p.In, required, e.schemaLabel(p.Schema))
+		}
+	}
+	if op.RequestBody != nil {
+		fmt.Fprintln(e.out, "Request body:")
+		if op.RequestBody.Ref != nil {
+			fmt.Fprintf(e.out, "  %s\n", op.RequestBody.Ref)
+		}
+		for _, name := range sortedKeys(op.RequestBody.Content) {
+			fmt.Fprintf(e.out, "  %s: %s\n", name, e.schemaLabel(op.RequestBody.Content[name].Schema))
+		}
+	}
+	if len(op.Responses) > 0 {
+		fmt.Fprintln(e.out, "Responses:")
+		for _, code := range sortedKeys(op.Responses) {
+			response := op.Responses[code]
+			if response.Ref != nil {
+				fmt.Fprintf(e.out, "  %s: %s\n", code, response.Ref)
+				continue
+			}
+			fmt.Fprintf(e.out, "  %s: %s\n", code, response.Description)
+			for _, name := range sortedKeys(response.Content) {
+				fmt.Fprintf(e.out, "    %s: %s\n", name, e.schemaLabel(response.Content[name].Schema))
+			}
+		}
+	}
+	return true
+}
+
+// showSchema renders a component schema, numbering the schemas it references
+func (e *explorer) showSchema(name string) bool {
+	var schema *openapi.Schema
+	if e.doc.Components != nil {
+		schema = e.doc.Components.Schemas[name]
+	}
+	if schema == nil {
+		fmt.Fprintf(e.out, "no schema %q\n", name)
+		return false
+	}
+	fmt.Fprintf(e.out, "%s: ", name)
+	fmt.Fprint(e.out, schema.Render(openapi.RenderTree, openapi.RenderOptions{Document: e.doc, Depth: 2}))
+	if schema.Description != "" {
+		fmt.Fprintf(e.out, "  %s\n", schema.Description)
+	}
+	refs := schemaRefs(schema)
+	if len(refs) > 0 {
+		fmt.Fprintln(e.out, "References:")
+		for _, ref := range refs {
+			name := unescape(strings.TrimPrefix(ref, "#/components/schemas/"))
+			fmt.Fprintf(e.out, "  [%d] %s\n", e.entry(view{kind: "schema", key: name}), name)
+		}
+	}
+	return true
+}
+
+// listOperation prints a numbered line for an operation
+func (e *explorer) listOperation(path, method string, op *openapi.Operation) {
+	n := e.entry(view{kind: "operation", key: method + " " + path})
+	fmt.Fprintf(e.out, "%3d  %-7s %s", n, method, path)
+	if op.Summary != "" {
+		fmt.Fprintf(e.out, "  %s", op.Summary)
+	}
+	fmt.Fprintln(e.out)
+}
+
+// listSchema prints a numbered line for a component schema
+func (e *explorer) listSchema(name string) {
+	n := e.entry(view{kind: "schema", key: name})
+	fmt.Fprintf(e.out, "%3d  %s", n, name)
+	if title := e.doc.Components.Schemas[name].Title; title != "" {
+		fmt.Fprintf(e.out, "  %s", title)
+	}
+	fmt.Fprintln(e.out)
+}
+
+// entry numbers a view on the current screen, reusing the number of a view
+// listed before
+func (e *explorer) entry(v view) int {
+	for i, entry := range e.entries {
+		if entry == v {
+			return i + 1
+		}
+	}
+	e.entries = append(e.entries, v)
+	return len(e.entries)
+}
+
+// schemaLabel describes a schema in a line, numbering the component schemas
+// it references so they can be opened
+func (e *explorer) schemaLabel(s *openapi.Schema) string {
+	if s == nil {
+		return "any"
+	}
+	if name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/"); ok {
+		name = unescape(name)
+		return fmt.Sprintf("%s [%d]", name, e.entry(view{kind: "schema", key: name}))
+	}
+	if s.Ref != "" {
+		return s.Ref
+	}
+	if s.Type.Is("array") && s.Items != nil {
+		return "array of " + e.schemaLabel(s.Items)
+	}
+	if len(s.Type) == 0 {
+		return "object"
+	}
+	return s.Type.String()
+}
+
+// schemaNames returns the names of the component schemas in sorted order
+func (e *explorer) schemaNames() []string {
+	if e.doc.Components == nil {
+		return nil
+	}
+	return sortedKeys(e.doc.Components.Schemas)
+}
+
+// schemaRefs returns the references to component schemas inside a schema,
+// without following them, in sorted order
+func schemaRefs(s *openapi.Schema) []string {
+	seen := make(map[string]bool)
+	var collect func(s *openapi.Schema)
+	collect = func(s *openapi.Schema) {
+		if s == nil {
+			return
+		}
+		if strings.HasPrefix(s.Ref, "#/components/schemas/") {
+			seen[s.Ref] = true
+			return
+		}
+		for _, property := range s.Properties {
+			collect(property)
+		}
+		collect(s.Items)
+		for _, group := range [][]*openapi.Schema{s.AllOf, s.OneOf, s.AnyOf, s.PrefixItems} {
+			for _, child := range group {
+				collect(child)
+			}
+		}
+		if s.AdditionalProperties != nil {
+			collect(s.AdditionalProperties.Schema)
+		}
+	}
+	collect(s)
+	return sortedKeys(seen)
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unescape decodes a JSON pointer reference token
+func unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
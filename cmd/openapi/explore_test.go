@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyxstack/openapi"
+)
+
+func TestExplorer(t *testing.T) {
+	doc := openapi.NewDocument("Pet API", "1.0.0")
+	doc.AddSchema("Owner", openapi.NewObjectSchema().WithRequiredProperty("name", openapi.StringSchema("")))
+	doc.AddSchema("Pet", openapi.NewObjectSchema().
+		WithRequiredProperty("name", openapi.StringSchema("")).
+		WithProperty("owner", &openapi.Schema{Ref: "#/components/schemas/Owner"}))
+	doc.AddOperation("/pets", "GET", openapi.NewOperation("listPets", "List pets", "").
+		WithJSONResponse("200", "Pets", openapi.NewArraySchema(&openapi.Schema{Ref: "#/components/schemas/Pet"})))
+	doc.AddOperation("/owners", "GET", openapi.NewOperation("listOwners", "List owners", ""))
+
+	var out strings.Builder
+	session := "search pets\n1\n1\nschema Missing\n1\nback\nref #/components/schemas/Owner\n9\nquit\n"
+	if err := newExplorer(doc, &out).run(strings.NewReader(session)); err != nil {
+		t.Fatalf("Error running explorer: %v", err)
+	}
+	for _, expected := range []string{
+		"  1  GET     /pets  List pets\n",
+		"GET /pets (listPets)\n",
+		"application/json: array of Pet [1]\n",
+		"Pet: object\n  name: string, required\n  owner: Owner\n    name: string, required\n",
+		"References:\n  [1] Owner\n",
+		"Owner: object\n",
+		"no schema \"Missing\"\n",
+		"no entry 9 on this screen\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "/owners  List owners") {
+		t.Errorf("Expected the search not to list /owners, got:\n%s", out.String())
+	}
+	if strings.Count(out.String(), "Pet: object") != 2 {
+		t.Errorf("Expected back to show the Pet schema again, got:\n%s", out.String())
+	}
+}
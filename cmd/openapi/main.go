@@ -0,0 +1,66 @@
+// Command openapi inspects OpenAPI documents from the terminal.
+//
+// Usage:
+//
+//	openapi explore <document>
+//	openapi diff <old document> <new document>
+//
+// explore opens an interactive explorer for browsing the paths, operations
+// and schemas of a JSON or YAML document, searching them and following
+// references. Type "help" at its prompt for the commands.
+//
+// diff lists the changes between two versions of a document, marking those
+// that break existing clients. It exits with status 1 when any change is
+// breaking, so deployments can be gated on it.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nyxstack/openapi"
+)
+
+const usage = "usage: openapi explore <document>\n       openapi diff <old document> <new document>"
+
+func main() {
+	switch {
+	case len(os.Args) == 3 && os.Args[1] == "explore":
+		doc := mustLoad(os.Args[2])
+		if err := newExplorer(doc, os.Stdout).run(os.Stdin); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case len(os.Args) == 4 && os.Args[1] == "diff":
+		if runDiff(mustLoad(os.Args[2]), mustLoad(os.Args[3]), os.Stdout) {
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// mustLoad loads a document, exiting when it can't
+func mustLoad(name string) *openapi.Document {
+	doc, err := load(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return doc
+}
+
+// load reads a document, as JSON when the file name says so and as YAML otherwise
+func load(name string) (*openapi.Document, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		return openapi.FromJSON(data)
+	}
+	return openapi.FromYAML(data)
+}
@@ -0,0 +1,102 @@
+package openapi
+
+// Extensions controlling Go code generators such as oapi-codegen
+const (
+	// ExtensionGoName overrides the Go identifier of a schema, property, parameter or operation
+	ExtensionGoName = "x-go-name"
+	// ExtensionGoType replaces the generated Go type of a schema with an existing type
+	ExtensionGoType = "x-go-type"
+	// ExtensionGoTypeImport names the package providing the x-go-type type
+	ExtensionGoTypeImport = "x-go-type-import"
+	// ExtensionExtraTags adds struct tags to the field generated for a property
+	ExtensionExtraTags = "x-oapi-codegen-extra-tags"
+)
+
+// GoTypeImport is the package import of an x-go-type type
+type GoTypeImport struct {
+	Path string `json:"path"`
+	// Name is the import alias, empty to use the package name
+	Name string `json:"name,omitempty"`
+}
+
+// WithGoName sets the Go identifier generated for the schema or property
+func (s Schema) WithGoName(name string) Schema {
+	return s.WithExtension(ExtensionGoName, name)
+}
+
+// WithGoType makes generators use an existing Go type for the schema, e.g.
+// "decimal.Decimal" imported from "github.com/shopspring/decimal". The import
+// path may be empty for builtin and same-package types.
+func (s Schema) WithGoType(typeName, importPath string) Schema {
+	s = s.WithExtension(ExtensionGoType, typeName)
+	if importPath != "" {
+		s = s.WithExtension(ExtensionGoTypeImport, GoTypeImport{Path: importPath})
+	}
+	return s
+}
+
+// WithGoTypeImportAlias sets the alias the x-go-type package is imported under
+func (s Schema) WithGoTypeImportAlias(alias string) Schema {
+	imp, _ := s.GoTypeImport()
+	imp.Name = alias
+	return s.WithExtension(ExtensionGoTypeImport, imp)
+}
+
+// WithExtraTag adds a struct tag, such as validate:"required", to the field
+// generated for the property
+func (s Schema) WithExtraTag(key, value string) Schema {
+	tags := s.ExtraTags()
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	tags[key] = value
+	return s.WithExtension(ExtensionExtraTags, tags)
+}
+
+// GoName returns the x-go-name of the schema, or an empty string
+func (s *Schema) GoName() string {
+	name, _ := s.Extensions[ExtensionGoName].(string)
+	return name
+}
+
+// GoType returns the x-go-type of the schema, or an empty string
+func (s *Schema) GoType() string {
+	typeName, _ := s.Extensions[ExtensionGoType].(string)
+	return typeName
+}
+
+// GoTypeImport returns the x-go-type-import of the schema
+func (s *Schema) GoTypeImport() (GoTypeImport, bool) {
+	var imp GoTypeImport
+	ok := decodeExtension(s.Extensions[ExtensionGoTypeImport], &imp)
+	return imp, ok && imp.Path != ""
+}
+
+// ExtraTags returns the x-oapi-codegen-extra-tags of the schema
+func (s *Schema) ExtraTags() map[string]string {
+	var tags map[string]string
+	decodeExtension(s.Extensions[ExtensionExtraTags], &tags)
+	return tags
+}
+
+// WithGoName sets the Go identifier generated for the parameter
+func (p Parameter) WithGoName(name string) Parameter {
+	return p.WithExtension(ExtensionGoName, name)
+}
+
+// GoName returns the x-go-name of the parameter, or an empty string
+func (p *Parameter) GoName() string {
+	name, _ := p.Extensions[ExtensionGoName].(string)
+	return name
+}
+
+// WithGoName sets the Go identifier generated for the operation's client and server methods
+func (o Operation) WithGoName(name string) Operation {
+	return o.WithExtension(ExtensionGoName, name)
+}
+
+// GoName returns the x-go-name of the operation, or an empty string
+func (o *Operation) GoName() string {
+	name, _ := o.Extensions[ExtensionGoName].(string)
+	return name
+}
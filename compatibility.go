@@ -0,0 +1,182 @@
+package openapi
+
+import (
+	"slices"
+)
+
+// CompatibilityMode selects the direction of a schema compatibility check,
+// following the conventions of schema registries
+type CompatibilityMode int
+
+const (
+	// CompatibilityBackward requires the new schema to read data written with the old one
+	CompatibilityBackward CompatibilityMode = iota
+	// CompatibilityForward requires the old schema to read data written with the new one
+	CompatibilityForward
+	// CompatibilityFull requires both backward and forward compatibility
+	CompatibilityFull
+)
+
+// String returns the registry name of the mode
+func (m CompatibilityMode) String() string {
+	switch m {
+	case CompatibilityForward:
+		return "FORWARD"
+	case CompatibilityFull:
+		return "FULL"
+	}
+	return "BACKWARD"
+}
+
+// IsCompatible checks the evolution of a payload schema structurally. A reader
+// schema can read data written with a writer schema when every value valid for
+// the writer is valid for the reader: types may only widen (integer to number),
+// enums, bounds and lengths may only be relaxed, and fields the reader requires
+// must be required by the writer. The findings point into the schemas and
+// describe why the check failed. References are compared by name; resolve
+// them first, e.g. with Document.IsCompatible, to compare referenced schemas.
+func IsCompatible(newSchema, oldSchema *Schema, mode CompatibilityMode) (bool, []ValidationError) {
+	return compatible(&Document{}, newSchema, &Document{}, oldSchema, mode)
+}
+
+// IsCompatible checks the compatibility of schemas like the IsCompatible
+// function, resolving references to components of the old and new documents
+func (d *Document) IsCompatible(newSchema *Schema, oldDoc *Document, oldSchema *Schema, mode CompatibilityMode) (bool, []ValidationError) {
+	return compatible(d, newSchema, oldDoc, oldSchema, mode)
+}
+
+func compatible(newDoc *Document, newSchema *Schema, oldDoc *Document, oldSchema *Schema, mode CompatibilityMode) (bool, []ValidationError) {
+	var errs []ValidationError
+	if mode == CompatibilityBackward || mode == CompatibilityFull {
+		c := &compatibilityChecker{reader: newDoc, writer: oldDoc, direction: "new schema can't read old data"}
+		c.check("", newSchema, oldSchema, 0)
+		errs = append(errs, c.errs...)
+	}
+	if mode == CompatibilityForward || mode == CompatibilityFull {
+		c := &compatibilityChecker{reader: oldDoc, writer: newDoc, direction: "old schema can't read new data"}
+		c.check("", oldSchema, newSchema, 0)
+		errs = append(errs, c.errs...)
+	}
+	return len(errs) == 0, errs
+}
+
+type compatibilityChecker struct {
+	validator
+	reader, writer *Document
+	direction      string
+}
+
+func (c *compatibilityChecker) fail(pointer, format string, args ...interface{}) {
+	c.errorf(pointer, c.direction+": "+format, args...)
+}
+
+// check reports values the writer schema allows that the reader schema rejects
+func (c *compatibilityChecker) check(pointer string, readerSchema, writerSchema *Schema, depth int) {
+	if readerSchema != nil && writerSchema != nil && readerSchema.Ref != "" && readerSchema.Ref == writerSchema.Ref && c.reader == c.writer {
+		return
+	}
+	r, w := c.reader.resolveSchema(readerSchema), c.writer.resolveSchema(writerSchema)
+	if r == nil || depth > 16 {
+		return
+	}
+	if w == nil {
+		if len(r.Type) > 0 {
+			c.fail(pointer, "expects %s but any value may be written", r.Type)
+		}
+		return
+	}
+
+	if len(r.Type) > 0 && r.Type.Primary() != w.Type.Primary() && !(r.Type.Is("number") && w.Type.Is("integer")) {
+		written := w.Type.String()
+		if written == "" {
+			written = "any value"
+		}
+		c.fail(pointer, "expects %s but %s may be written", r.Type, written)
+		return
+	}
+	if w.AllowsNull() && !r.AllowsNull() {
+		c.fail(pointer, "null may be written but isn't allowed")
+	}
+	if len(r.Enum) > 0 {
+		if len(w.Enum) == 0 {
+			c.fail(pointer, "values are restricted to an enum")
+		}
+		for _, value := range w.Enum {
+			if !containsValue(r.Enum, value) {
+				c.fail(pointer, "enum value %v may be written but isn't allowed", value)
+			}
+		}
+	}
+	if r.Pattern != "" && r.Pattern != w.Pattern {
+		c.fail(pointer, "values must match pattern %q", r.Pattern)
+	}
+	if r.Format != "" && r.Format != w.Format {
+		c.warnf(pointer, "%s: values are expected in format %q", c.direction, r.Format)
+	}
+
+	readerMin, readerMinExclusive := r.LowerBound()
+	writerMin, writerMinExclusive := w.LowerBound()
+	c.checkLowerFloat(pointer, "minimum", readerMin, writerMin, readerMinExclusive && !writerMinExclusive)
+	readerMax, readerMaxExclusive := r.UpperBound()
+	writerMax, writerMaxExclusive := w.UpperBound()
+	c.checkUpperFloat(pointer, "maximum", readerMax, writerMax, readerMaxExclusive && !writerMaxExclusive)
+	c.checkLowerInt(pointer, "minLength", r.MinLength, w.MinLength)
+	c.checkUpperInt(pointer, "maxLength", r.MaxLength, w.MaxLength)
+	c.checkLowerInt(pointer, "minItems", r.MinItems, w.MinItems)
+	c.checkUpperInt(pointer, "maxItems", r.MaxItems, w.MaxItems)
+	if r.UniqueItems && !w.UniqueItems {
+		c.fail(pointer, "items must be unique")
+	}
+	if r.Items != nil {
+		c.check(pointer+"/items", r.Items, w.Items, depth+1)
+	}
+
+	readerProps, _ := c.reader.schemaProperties(r)
+	writerProps, _ := c.writer.schemaProperties(w)
+	writerRequired := c.writer.schemaRequired(w)
+	for _, name := range c.reader.schemaRequired(r) {
+		if !slices.Contains(writerRequired, name) {
+			c.fail(pointer+"/properties/"+escapePointer(name), "field %q is required but may be missing", name)
+		}
+	}
+	readerClosed := r.AdditionalProperties != nil && r.AdditionalProperties.Bool != nil && !*r.AdditionalProperties.Bool
+	writerClosed := w.AdditionalProperties != nil && w.AdditionalProperties.Bool != nil && !*w.AdditionalProperties.Bool
+	if readerClosed && !writerClosed {
+		c.fail(pointer, "additional properties may be written but aren't allowed")
+	}
+	for _, name := range sortedKeys(writerProps) {
+		propPointer := pointer + "/properties/" + escapePointer(name)
+		readerProp, ok := readerProps[name]
+		if !ok {
+			if readerClosed {
+				c.fail(propPointer, "field %q may be written but isn't allowed", name)
+			}
+			continue
+		}
+		c.check(propPointer, readerProp, writerProps[name], depth+1)
+	}
+}
+
+func (c *compatibilityChecker) checkLowerFloat(pointer, keyword string, reader, writer *float64, exclusive bool) {
+	if reader != nil && (writer == nil || *writer < *reader || (*writer == *reader && exclusive)) {
+		c.fail(pointer, "%s %v may be violated", keyword, *reader)
+	}
+}
+
+func (c *compatibilityChecker) checkUpperFloat(pointer, keyword string, reader, writer *float64, exclusive bool) {
+	if reader != nil && (writer == nil || *writer > *reader || (*writer == *reader && exclusive)) {
+		c.fail(pointer, "%s %v may be violated", keyword, *reader)
+	}
+}
+
+func (c *compatibilityChecker) checkLowerInt(pointer, keyword string, reader, writer *int) {
+	if reader != nil && (writer == nil || *writer < *reader) {
+		c.fail(pointer, "%s %d may be violated", keyword, *reader)
+	}
+}
+
+func (c *compatibilityChecker) checkUpperInt(pointer, keyword string, reader, writer *int) {
+	if reader != nil && (writer == nil || *writer > *reader) {
+		c.fail(pointer, "%s %d may be violated", keyword, *reader)
+	}
+}
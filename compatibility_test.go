@@ -0,0 +1,43 @@
+package openapi
+
+import "testing"
+
+func TestIsCompatible(t *testing.T) {
+	oldSchema := &Schema{
+		Type:     Types{"object"},
+		Required: []string{"id"},
+		Properties: map[string]*Schema{
+			"id":     {Type: Types{"integer"}},
+			"status": {Type: Types{"string"}, Enum: []interface{}{"active", "disabled"}},
+		},
+	}
+
+	added := &Schema{
+		Type:     Types{"object"},
+		Required: []string{"id"},
+		Properties: map[string]*Schema{
+			"id":     {Type: Types{"number"}},
+			"status": {Type: Types{"string"}, Enum: []interface{}{"active", "disabled", "pending"}},
+			"name":   {Type: Types{"string"}},
+		},
+	}
+	if ok, errs := IsCompatible(added, oldSchema, CompatibilityBackward); !ok {
+		t.Errorf("Expected widened schema to be backward compatible, got %v", errs)
+	}
+	if ok, _ := IsCompatible(added, oldSchema, CompatibilityForward); ok {
+		t.Error("Expected widened schema not to be forward compatible")
+	}
+
+	required := &Schema{
+		Type:     Types{"object"},
+		Required: []string{"id", "name"},
+		Properties: map[string]*Schema{
+			"id":   {Type: Types{"integer"}},
+			"name": {Type: Types{"string"}},
+		},
+	}
+	ok, errs := IsCompatible(required, oldSchema, CompatibilityBackward)
+	if ok || findValidationError(errs, "/properties/name") == nil {
+		t.Errorf("Expected newly required field to break backward compatibility, got %v", errs)
+	}
+}
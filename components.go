@@ -1,5 +1,9 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // Components represents the components object in OpenAPI
 type Components struct {
 	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
@@ -11,6 +15,24 @@ type Components struct {
 	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
 	Links           map[string]Link           `json:"links,omitempty"`
 	Callbacks       map[string]Callback       `json:"callbacks,omitempty"`
+	Extensions      map[string]interface{}    `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (c Components) MarshalJSON() ([]byte, error) {
+	type components Components
+	return marshalWithExtensions(components(c), c.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (c *Components) UnmarshalJSON(data []byte) error {
+	type components Components
+	if err := json.Unmarshal(data, (*components)(c)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	c.Extensions = ext
+	return err
 }
 
 // NewComponents creates a new components object
@@ -27,3 +49,35 @@ func NewComponents() *Components {
 		Callbacks:       make(map[string]Callback),
 	}
 }
+
+// initMaps creates the component maps that are nil, as left by decoding or
+// cloning components that only hold some kinds
+func (c *Components) initMaps() {
+	if c.Schemas == nil {
+		c.Schemas = make(map[string]*Schema)
+	}
+	if c.Responses == nil {
+		c.Responses = make(map[string]Response)
+	}
+	if c.Parameters == nil {
+		c.Parameters = make(map[string]Parameter)
+	}
+	if c.Examples == nil {
+		c.Examples = make(map[string]Example)
+	}
+	if c.RequestBodies == nil {
+		c.RequestBodies = make(map[string]RequestBody)
+	}
+	if c.Headers == nil {
+		c.Headers = make(map[string]Header)
+	}
+	if c.SecuritySchemes == nil {
+		c.SecuritySchemes = make(map[string]SecurityScheme)
+	}
+	if c.Links == nil {
+		c.Links = make(map[string]Link)
+	}
+	if c.Callbacks == nil {
+		c.Callbacks = make(map[string]Callback)
+	}
+}
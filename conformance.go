@@ -0,0 +1,114 @@
+package openapi
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// CheckResponse verifies that a response produced for an operation is documented:
+// the status code must be declared, the content type must match a declared media
+// type and JSON bodies must validate against the media type schema.
+func (d *Document) CheckResponse(operationID string, status int, header http.Header, body []byte) []ValidationError {
+	path, method, op := d.FindOperation(operationID)
+	if op == nil {
+		return []ValidationError{{Message: fmt.Sprintf("unknown operation %q", operationID), Severity: SeverityError}}
+	}
+	return d.checkResponse(path, method, op, status, header, body)
+}
+
+// checkResponse verifies a response produced for the operation at path and method
+func (d *Document) checkResponse(path, method string, op *Operation, status int, header http.Header, body []byte) []ValidationError {
+	pointer := operationPointer(path, method) + "/responses"
+	key, response, ok := op.ResponseFor(status)
+	if !ok {
+		return []ValidationError{{Path: pointer, Message: fmt.Sprintf("status %d is not documented", status), Severity: SeverityError}}
+	}
+	pointer += "/" + escapePointer(key)
+	response = d.resolveResponse(response)
+
+	if len(response.Content) == 0 {
+		if len(body) > 0 {
+			return []ValidationError{{Path: pointer, Message: "response has a body but none is documented", Severity: SeverityError}}
+		}
+		return nil
+	}
+	if len(body) == 0 {
+		return nil
+	}
+
+	contentType := header.Get("Content-Type")
+	name, mt, ok := matchMediaType(response.Content, contentType)
+	if !ok {
+		return []ValidationError{{Path: pointer + "/content", Message: fmt.Sprintf("content type %q is not documented", contentType), Severity: SeverityError}}
+	}
+	if mt.Schema == nil || !isJSONMediaType(name) {
+		return nil
+	}
+
+	var errs []ValidationError
+	for _, err := range d.ValidateJSON(mt.Schema, body) {
+		err.Message = fmt.Sprintf("body%s: %s", err.Path, err.Message)
+		err.Path = pointer + "/content/" + escapePointer(name) + "/schema"
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// resolveResponse follows a local response component reference. A
+// description on the reference overrides the one of the component.
+func (d *Document) resolveResponse(r Response) Response {
+	description := ""
+	for i := 0; r.Ref != nil && i < 16; i++ {
+		if description == "" {
+			description = r.Ref.Description
+		}
+		name, ok := strings.CutPrefix(r.Ref.Ref, "#/components/responses/")
+		if !ok || d.Components == nil {
+			return r
+		}
+		resolved, ok := d.Components.Responses[unescapePointer(name)]
+		if !ok {
+			return r
+		}
+		r = resolved
+	}
+	if description != "" && r.Ref == nil {
+		r.Description = description
+	}
+	return r
+}
+
+// matchMediaType finds the content entry matching a Content-Type header value,
+// honoring wildcard entries such as "image/*" and "*/*"
+func matchMediaType(content map[string]MediaType, contentType string) (string, MediaType, bool) {
+	actual, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		actual = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	if mt, ok := content[actual]; ok {
+		return actual, mt, true
+	}
+	for _, name := range sortedKeys(content) {
+		declared, _, err := mime.ParseMediaType(name)
+		if err != nil {
+			continue
+		}
+		if declared == actual || declared == "*/*" ||
+			(strings.HasSuffix(declared, "/*") && strings.HasPrefix(actual, strings.TrimSuffix(declared, "*"))) {
+			return name, content[name], true
+		}
+	}
+	return "", MediaType{}, false
+}
+
+// isJSONMediaType reports whether a media type carries JSON, including
+// structured syntax suffixes like application/problem+json
+func isJSONMediaType(name string) bool {
+	mediaType, _, err := mime.ParseMediaType(name)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,211 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ConformanceReport accumulates contract violations over a test cycle, per
+// operation, from the exchanges of a verifying proxy or middleware. Pass its
+// Record method as ProxyOptions.OnExchange. Reports marshal to JSON and can
+// be loaded again to carry on over several runs.
+type ConformanceReport struct {
+	mu sync.Mutex
+	// Operations holds the conformance of every documented operation, keyed
+	// by "METHOD path"
+	Operations map[string]*OperationConformance `json:"operations"`
+	// Unmatched counts requests matching no operation, keyed by method only
+	// so that scanned or random paths can't grow the report without bound.
+	// Methods no operation can have are counted under OtherMethod.
+	Unmatched map[string]int `json:"unmatched,omitempty"`
+}
+
+// OperationConformance is the conformance of one operation
+type OperationConformance struct {
+	Path        string `json:"path"`
+	Method      string `json:"method"`
+	OperationID string `json:"operationId,omitempty"`
+	// Requests counts the exchanges seen
+	Requests int `json:"requests"`
+	// Statuses counts the response status codes seen
+	Statuses           map[int]int            `json:"statuses,omitempty"`
+	RequestViolations  []ConformanceViolation `json:"requestViolations,omitempty"`
+	ResponseViolations []ConformanceViolation `json:"responseViolations,omitempty"`
+}
+
+// ConformanceViolation is a distinct violation and how often it occurred
+type ConformanceViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// NewConformanceReport creates an empty report covering the operations of the document
+func NewConformanceReport(doc *Document) *ConformanceReport {
+	r := &ConformanceReport{
+		Operations: make(map[string]*OperationConformance),
+		Unmatched:  make(map[string]int),
+	}
+	doc.walkOperations(func(path, method string, op *Operation) {
+		r.Operations[method+" "+path] = &OperationConformance{Path: path, Method: method, OperationID: op.OperationID}
+	})
+	return r
+}
+
+// LoadConformanceReport loads a report saved as JSON, adding the operations
+// of the document it doesn't cover yet. Unmatched requests saved by
+// "METHOD path" are counted under their method.
+func LoadConformanceReport(doc *Document, data []byte) (*ConformanceReport, error) {
+	r := NewConformanceReport(doc)
+	var saved ConformanceReport
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("openapi: loading conformance report: %w", err)
+	}
+	for key, op := range saved.Operations {
+		r.Operations[key] = op
+	}
+	for key, count := range saved.Unmatched {
+		method, _, _ := strings.Cut(key, " ")
+		r.Unmatched[observedMethod(method)] += count
+	}
+	return r, nil
+}
+
+// Record adds an exchange to the report
+func (r *ConformanceReport) Record(exchange ProxyExchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if exchange.Match == nil {
+		r.Unmatched[observedMethod(exchange.Method)]++
+		return
+	}
+	key := exchange.Match.Method + " " + exchange.Match.Path
+	op, ok := r.Operations[key]
+	if !ok {
+		op = &OperationConformance{Path: exchange.Match.Path, Method: exchange.Match.Method, OperationID: exchange.Match.Operation.OperationID}
+		r.Operations[key] = op
+	}
+	op.Requests++
+	if exchange.Status != 0 {
+		if op.Statuses == nil {
+			op.Statuses = make(map[int]int)
+		}
+		op.Statuses[exchange.Status]++
+	}
+	op.RequestViolations = addViolations(op.RequestViolations, exchange.RequestErrors)
+	op.ResponseViolations = addViolations(op.ResponseViolations, exchange.ResponseErrors)
+}
+
+// addViolations counts errors into a list of distinct violations
+func addViolations(violations []ConformanceViolation, errs []ValidationError) []ConformanceViolation {
+	for _, err := range errs {
+		found := false
+		for i := range violations {
+			if violations[i].Path == err.Path && violations[i].Message == err.Message {
+				violations[i].Count++
+				found = true
+				break
+			}
+		}
+		if !found {
+			violations = append(violations, ConformanceViolation{Path: err.Path, Message: err.Message, Count: 1})
+		}
+	}
+	return violations
+}
+
+// Unexercised lists the operations no exchange was recorded for, as "METHOD path"
+func (r *ConformanceReport) Unexercised() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []string
+	for key, op := range r.Operations {
+		if op.Requests == 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Conformant reports whether every exercised operation conformed and every
+// request matched an operation
+func (r *ConformanceReport) Conformant() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.Unmatched) > 0 {
+		return false
+	}
+	for _, op := range r.Operations {
+		if len(op.RequestViolations) > 0 || len(op.ResponseViolations) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalJSON implements json.Marshaler, holding the report's lock
+func (r *ConformanceReport) MarshalJSON() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	type report struct {
+		Operations map[string]*OperationConformance `json:"operations"`
+		Unmatched  map[string]int                   `json:"unmatched,omitempty"`
+	}
+	return json.Marshal(report{Operations: r.Operations, Unmatched: r.Unmatched})
+}
+
+// Markdown renders the report as a table of operations followed by their
+// violations and the requests that matched no operation
+func (r *ConformanceReport) Markdown() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := sortedKeys(r.Operations)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return r.Operations[keys[i]].Path < r.Operations[keys[j]].Path
+	})
+
+	var b strings.Builder
+	b.WriteString("| Operation | Method | Path | Requests | Request violations | Response violations |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, key := range keys {
+		op := r.Operations[key]
+		requests := fmt.Sprint(op.Requests)
+		if op.Requests == 0 {
+			requests = "not exercised"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %d | %d |\n",
+			op.OperationID, op.Method, op.Path, requests, countViolations(op.RequestViolations), countViolations(op.ResponseViolations))
+	}
+	for _, key := range keys {
+		op := r.Operations[key]
+		if len(op.RequestViolations) == 0 && len(op.ResponseViolations) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", key)
+		for _, v := range op.RequestViolations {
+			fmt.Fprintf(&b, "- request `%s`: %s (%d)\n", v.Path, v.Message, v.Count)
+		}
+		for _, v := range op.ResponseViolations {
+			fmt.Fprintf(&b, "- response `%s`: %s (%d)\n", v.Path, v.Message, v.Count)
+		}
+	}
+	if len(r.Unmatched) > 0 {
+		b.WriteString("\nRequests matching no operation, by method:\n\n")
+		for _, key := range sortedKeys(r.Unmatched) {
+			fmt.Fprintf(&b, "- %s (%d)\n", key, r.Unmatched[key])
+		}
+	}
+	return b.String()
+}
+
+func countViolations(violations []ConformanceViolation) int {
+	total := 0
+	for _, v := range violations {
+		total += v.Count
+	}
+	return total
+}
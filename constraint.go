@@ -0,0 +1,236 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExtensionConstraints is the schema extension listing cross-field constraints
+const ExtensionConstraints = "x-constraints"
+
+// ConstraintKind is the kind of a cross-field constraint
+type ConstraintKind string
+
+const (
+	// ConstraintCompare compares two fields with an operator
+	ConstraintCompare ConstraintKind = "compare"
+	// ConstraintExactlyOne requires exactly one of the fields
+	ConstraintExactlyOne ConstraintKind = "exactlyOne"
+	// ConstraintAtLeastOne requires at least one of the fields
+	ConstraintAtLeastOne ConstraintKind = "atLeastOne"
+	// ConstraintDependentRequired requires the other fields when the first is present
+	ConstraintDependentRequired ConstraintKind = "dependentRequired"
+	// ConstraintCustom is a rule only described in prose
+	ConstraintCustom ConstraintKind = "custom"
+)
+
+// Constraint is a rule between the fields of an object schema
+type Constraint struct {
+	Kind   ConstraintKind `json:"kind"`
+	Fields []string       `json:"fields,omitempty"`
+	// Operator compares the first field to the second for ConstraintCompare:
+	// "==", "!=", "<", "<=", ">" or ">="
+	Operator    string `json:"operator,omitempty"`
+	Description string `json:"description"`
+}
+
+// constraintOperators maps comparison operators to their prose
+var constraintOperators = map[string]string{
+	"==": "equal to",
+	"!=": "different from",
+	"<":  "before or less than",
+	"<=": "before, equal to or less than",
+	">":  "after or greater than",
+	">=": "after, equal to or greater than",
+}
+
+func init() {
+	RegisterKeyword(Keyword{Name: ExtensionConstraints, Check: checkConstraints, Validate: validateConstraints})
+}
+
+// WithConstraint adds a cross-field constraint to the schema and appends its
+// description to the schema description, so that documentation renderers
+// show it. Use the dedicated builders where the rule is expressible in
+// JSON Schema; other constraints are only enforced by ValidateValue.
+func (s Schema) WithConstraint(constraint Constraint) Schema {
+	constraints := append(slices.Clone(s.Constraints()), constraint)
+	if s.Description == "" {
+		s.Description = constraint.Description
+	} else if !strings.Contains(s.Description, constraint.Description) {
+		s.Description += " " + constraint.Description
+	}
+	return s.WithExtension(ExtensionConstraints, constraints)
+}
+
+// WithFieldComparison constrains a field relative to another, e.g.
+// WithFieldComparison("endDate", ">=", "startDate"). Numbers compare as
+// numbers, dates and date-times chronologically and other strings
+// lexically. The constraint only applies when both fields are present.
+func (s Schema) WithFieldComparison(field, operator, other string) Schema {
+	return s.WithConstraint(Constraint{
+		Kind:        ConstraintCompare,
+		Fields:      []string{field, other},
+		Operator:    operator,
+		Description: fmt.Sprintf("%s must be %s %s.", field, constraintOperators[operator], other),
+	})
+}
+
+// WithExactlyOneOf requires exactly one of the fields, through a oneOf of
+// required fields
+func (s Schema) WithExactlyOneOf(fields ...string) Schema {
+	s = s.withRequiredAlternatives(fields, true)
+	return s.WithConstraint(Constraint{
+		Kind:        ConstraintExactlyOne,
+		Fields:      fields,
+		Description: "Exactly one of " + strings.Join(fields, ", ") + " must be set.",
+	})
+}
+
+// WithAtLeastOneOf requires at least one of the fields, through an anyOf of
+// required fields
+func (s Schema) WithAtLeastOneOf(fields ...string) Schema {
+	s = s.withRequiredAlternatives(fields, false)
+	return s.WithConstraint(Constraint{
+		Kind:        ConstraintAtLeastOne,
+		Fields:      fields,
+		Description: "At least one of " + strings.Join(fields, ", ") + " must be set.",
+	})
+}
+
+// WithDependentRequired requires the dependent fields whenever field is present
+func (s Schema) WithDependentRequired(field string, dependents ...string) Schema {
+	dependentRequired := make(map[string][]string, len(s.DependentRequired)+1)
+	for name, fields := range s.DependentRequired {
+		dependentRequired[name] = fields
+	}
+	dependentRequired[field] = append(slices.Clone(dependentRequired[field]), dependents...)
+	s.DependentRequired = dependentRequired
+	return s.WithConstraint(Constraint{
+		Kind:        ConstraintDependentRequired,
+		Fields:      append([]string{field}, dependents...),
+		Description: "When " + field + " is set, " + strings.Join(dependents, ", ") + " must be set too.",
+	})
+}
+
+// withRequiredAlternatives adds a oneOf or anyOf with a required-only schema
+// per field. When the schema already has alternatives, for polymorphism, the
+// new ones are nested in allOf.
+func (s Schema) withRequiredAlternatives(fields []string, exactlyOne bool) Schema {
+	alternatives := make([]*Schema, len(fields))
+	for i, field := range fields {
+		alternatives[i] = &Schema{Required: []string{field}}
+	}
+	switch {
+	case exactlyOne && len(s.OneOf) == 0:
+		s.OneOf = alternatives
+	case !exactlyOne && len(s.AnyOf) == 0:
+		s.AnyOf = alternatives
+	case exactlyOne:
+		s.AllOf = append(slices.Clone(s.AllOf), &Schema{OneOf: alternatives})
+	default:
+		s.AllOf = append(slices.Clone(s.AllOf), &Schema{AnyOf: alternatives})
+	}
+	return s
+}
+
+// Constraints returns the cross-field constraints of the schema
+func (s *Schema) Constraints() []Constraint {
+	var constraints []Constraint
+	decodeExtension(s.Extensions[ExtensionConstraints], &constraints)
+	return constraints
+}
+
+// checkConstraints checks the value of an x-constraints extension
+func checkConstraints(value interface{}) error {
+	var constraints []Constraint
+	if !decodeExtension(value, &constraints) {
+		return errors.New("expected a list of constraints")
+	}
+	for i, c := range constraints {
+		if c.Kind == ConstraintCompare {
+			if _, ok := constraintOperators[c.Operator]; !ok || len(c.Fields) != 2 {
+				return fmt.Errorf("constraint %d must compare two fields with a known operator", i)
+			}
+		}
+	}
+	return nil
+}
+
+// validateConstraints checks the comparison constraints of a schema against
+// an object; the other kinds are enforced through their JSON Schema keywords
+func validateConstraints(value, instance interface{}, _ *Schema) error {
+	obj, ok := instance.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var constraints []Constraint
+	decodeExtension(value, &constraints)
+	for _, c := range constraints {
+		if c.Kind != ConstraintCompare || len(c.Fields) != 2 {
+			continue
+		}
+		left, lok := obj[c.Fields[0]]
+		right, rok := obj[c.Fields[1]]
+		if !lok || !rok {
+			continue
+		}
+		cmp, ok := compareFields(left, right)
+		if !ok {
+			continue
+		}
+		var holds bool
+		switch c.Operator {
+		case "==":
+			holds = cmp == 0
+		case "!=":
+			holds = cmp != 0
+		case "<":
+			holds = cmp < 0
+		case "<=":
+			holds = cmp <= 0
+		case ">":
+			holds = cmp > 0
+		case ">=":
+			holds = cmp >= 0
+		default:
+			holds = true
+		}
+		if !holds {
+			return errors.New(c.Description)
+		}
+	}
+	return nil
+}
+
+// compareFields compares two JSON values as numbers, times or strings
+func compareFields(a, b interface{}) (int, bool) {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	x, xok := a.(string)
+	y, yok := b.(string)
+	if !xok || !yok {
+		return 0, false
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		tx, xerr := time.Parse(layout, x)
+		ty, yerr := time.Parse(layout, y)
+		if xerr == nil && yerr == nil {
+			return tx.Compare(ty), true
+		}
+	}
+	return strings.Compare(x, y), true
+}
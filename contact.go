@@ -1,8 +1,30 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // Contact information for the API
 type Contact struct {
-	Name  string `json:"name,omitempty"`
-	URL   string `json:"url,omitempty"`
-	Email string `json:"email,omitempty"`
+	Name       string                 `json:"name,omitempty"`
+	URL        string                 `json:"url,omitempty"`
+	Email      string                 `json:"email,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (c Contact) MarshalJSON() ([]byte, error) {
+	type contact Contact
+	return marshalWithExtensions(contact(c), c.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (c *Contact) UnmarshalJSON(data []byte) error {
+	type contact Contact
+	if err := json.Unmarshal(data, (*contact)(c)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	c.Extensions = ext
+	return err
 }
@@ -0,0 +1,237 @@
+package openapi
+
+import (
+	"slices"
+	"strconv"
+)
+
+// VerifyConsumerContract checks that the provider document satisfies everything
+// the consumer document relies on, for consumer-driven contract testing with
+// OpenAPI documents on both sides. The consumer document describes only the
+// operations, parameters, responses and fields the consumer uses. Every one of
+// them must exist in the provider with compatible types: responses must provide
+// every field the consumer reads, and requests the consumer sends must be
+// accepted. Path templates match regardless of variable names. Findings point
+// into the consumer document.
+func VerifyConsumerContract(provider, consumer *Document) []ValidationError {
+	c := &contractChecker{validator: validator{doc: consumer}, provider: provider}
+
+	providerPaths := make(map[string]string, len(provider.Paths))
+	for path := range provider.Paths {
+		providerPaths[templateVariable.ReplaceAllString(path, "{}")] = path
+	}
+
+	consumer.walkOperations(func(path, method string, op *Operation) {
+		pointer := operationPointer(path, method)
+		providerPath, ok := providerPaths[templateVariable.ReplaceAllString(path, "{}")]
+		if !ok {
+			c.errorf(pointer, "provider has no path matching %s", path)
+			return
+		}
+		item := provider.Paths[providerPath]
+		providerOp := item.Operation(method)
+		if providerOp == nil {
+			c.errorf(pointer, "provider has no %s operation on %s", method, providerPath)
+			return
+		}
+		c.checkParameters(pointer, consumer.OperationParameters(path, op), provider.OperationParameters(providerPath, providerOp))
+		c.checkRequestBody(pointer+"/requestBody", op.RequestBody, providerOp.RequestBody)
+		for _, code := range sortedKeys(op.Responses) {
+			c.checkResponse(pointer+"/responses/"+escapePointer(code), code, op.Responses[code], providerOp)
+		}
+	})
+	return c.errs
+}
+
+type contractChecker struct {
+	validator
+	provider *Document
+}
+
+func (c *contractChecker) checkParameters(pointer string, consumerParams, providerParams []Parameter) {
+	sent := make(map[string]Parameter, len(consumerParams))
+	for _, p := range consumerParams {
+		sent[p.In+":"+p.Name] = p
+	}
+	accepted := make(map[string]Parameter, len(providerParams))
+	for _, p := range providerParams {
+		accepted[p.In+":"+p.Name] = p
+		if _, ok := sent[p.In+":"+p.Name]; p.Required && !ok {
+			c.errorf(pointer+"/parameters", "provider requires %s parameter %q, which the consumer doesn't send", p.In, p.Name)
+		}
+	}
+	for i, p := range consumerParams {
+		target, ok := accepted[p.In+":"+p.Name]
+		if !ok {
+			c.errorf(pointer+"/parameters/"+strconv.Itoa(i), "provider doesn't accept %s parameter %q", p.In, p.Name)
+			continue
+		}
+		c.checkSent(pointer+"/parameters/"+strconv.Itoa(i)+"/schema", p.Schema, target.Schema, 0)
+	}
+}
+
+func (c *contractChecker) checkRequestBody(pointer string, consumerBody, providerBody *RequestBody) {
+	if consumerBody == nil {
+		if providerBody != nil && providerBody.Required {
+			c.errorf(pointer, "provider requires a request body, which the consumer doesn't send")
+		}
+		return
+	}
+	if providerBody == nil {
+		c.errorf(pointer, "provider doesn't accept a request body")
+		return
+	}
+	for _, name := range sortedKeys(consumerBody.Content) {
+		mtPointer := pointer + "/content/" + escapePointer(name)
+		_, target, ok := matchMediaType(providerBody.Content, name)
+		if !ok {
+			c.errorf(mtPointer, "provider doesn't accept %s request bodies", name)
+			continue
+		}
+		c.checkSent(mtPointer+"/schema", consumerBody.Content[name].Schema, target.Schema, 0)
+	}
+}
+
+func (c *contractChecker) checkResponse(pointer, code string, response Response, providerOp *Operation) {
+	var target Response
+	var ok bool
+	if status, err := strconv.Atoi(code); err == nil {
+		_, target, ok = providerOp.ResponseFor(status)
+	} else {
+		target, ok = providerOp.Responses[code]
+	}
+	if !ok {
+		c.errorf(pointer, "provider doesn't document response %s", code)
+		return
+	}
+	response = c.doc.resolveResponse(response)
+	target = c.provider.resolveResponse(target)
+
+	for _, name := range sortedKeys(response.Headers) {
+		if _, ok := target.Headers[name]; !ok {
+			c.errorf(pointer+"/headers/"+escapePointer(name), "provider doesn't document response header %s", name)
+		}
+	}
+	for _, name := range sortedKeys(response.Content) {
+		mtPointer := pointer + "/content/" + escapePointer(name)
+		_, mt, ok := matchMediaType(target.Content, name)
+		if !ok {
+			c.errorf(mtPointer, "provider doesn't produce %s responses", name)
+			continue
+		}
+		c.checkReceived(mtPointer+"/schema", response.Content[name].Schema, mt.Schema, 0)
+	}
+}
+
+// checkReceived checks that every value the provider may return for its
+// schema is one the consumer can read with its schema
+func (c *contractChecker) checkReceived(pointer string, consumerSchema, providerSchema *Schema, depth int) {
+	cs, ps := c.doc.resolveSchema(consumerSchema), c.provider.resolveSchema(providerSchema)
+	if cs == nil || ps == nil || depth > 16 {
+		return
+	}
+	if len(cs.Type) > 0 && len(ps.Type) > 0 && cs.Type.Primary() != ps.Type.Primary() && !(cs.Type.Is("number") && ps.Type.Is("integer")) {
+		c.errorf(pointer, "provider returns %s where the consumer expects %s", ps.Type, cs.Type)
+		return
+	}
+	if ps.AllowsNull() && !cs.AllowsNull() {
+		c.errorf(pointer, "provider may return null where the consumer expects a value")
+	}
+	if len(cs.Enum) > 0 {
+		if len(ps.Enum) == 0 {
+			c.errorf(pointer, "provider may return values outside the consumer's enum")
+		}
+		for _, value := range ps.Enum {
+			if !containsValue(cs.Enum, value) {
+				c.errorf(pointer, "provider may return %v, which isn't in the consumer's enum", value)
+			}
+		}
+	}
+	if cs.Items != nil {
+		c.checkReceived(pointer+"/items", cs.Items, ps.Items, depth+1)
+	}
+
+	consumerProps, _ := c.doc.schemaProperties(cs)
+	providerProps, _ := c.provider.schemaProperties(ps)
+	providerRequired := c.provider.schemaRequired(ps)
+	for _, name := range sortedKeys(consumerProps) {
+		propPointer := pointer + "/properties/" + escapePointer(name)
+		if _, ok := providerProps[name]; !ok {
+			c.errorf(propPointer, "provider doesn't return field %q", name)
+			continue
+		}
+		if slices.Contains(c.doc.schemaRequired(cs), name) && !slices.Contains(providerRequired, name) {
+			c.errorf(propPointer, "consumer requires field %q, which the provider may omit", name)
+		}
+		c.checkReceived(propPointer, consumerProps[name], providerProps[name], depth+1)
+	}
+}
+
+// checkSent checks that every value the consumer may send with its schema is
+// accepted by the provider's schema
+func (c *contractChecker) checkSent(pointer string, consumerSchema, providerSchema *Schema, depth int) {
+	cs, ps := c.doc.resolveSchema(consumerSchema), c.provider.resolveSchema(providerSchema)
+	if cs == nil || ps == nil || depth > 16 {
+		return
+	}
+	if len(cs.Type) > 0 && len(ps.Type) > 0 && cs.Type.Primary() != ps.Type.Primary() && !(ps.Type.Is("number") && cs.Type.Is("integer")) {
+		c.errorf(pointer, "consumer sends %s where the provider expects %s", cs.Type, ps.Type)
+		return
+	}
+	if cs.AllowsNull() && !ps.AllowsNull() {
+		c.errorf(pointer, "consumer may send null, which the provider doesn't accept")
+	}
+	if len(ps.Enum) > 0 {
+		if len(cs.Enum) == 0 {
+			c.errorf(pointer, "consumer may send values outside the provider's enum")
+		}
+		for _, value := range cs.Enum {
+			if !containsValue(ps.Enum, value) {
+				c.errorf(pointer, "consumer may send %v, which isn't in the provider's enum", value)
+			}
+		}
+	}
+	if cs.Items != nil {
+		c.checkSent(pointer+"/items", cs.Items, ps.Items, depth+1)
+	}
+
+	consumerProps, _ := c.doc.schemaProperties(cs)
+	providerProps, _ := c.provider.schemaProperties(ps)
+	consumerRequired := c.doc.schemaRequired(cs)
+	for _, name := range c.provider.schemaRequired(ps) {
+		if !slices.Contains(consumerRequired, name) {
+			c.errorf(pointer, "provider requires field %q, which the consumer may omit", name)
+		}
+	}
+	closed := ps.AdditionalProperties != nil && ps.AdditionalProperties.Bool != nil && !*ps.AdditionalProperties.Bool
+	for _, name := range sortedKeys(consumerProps) {
+		propPointer := pointer + "/properties/" + escapePointer(name)
+		target, ok := providerProps[name]
+		if !ok {
+			if closed {
+				c.errorf(propPointer, "provider doesn't accept field %q", name)
+			}
+			continue
+		}
+		c.checkSent(propPointer, consumerProps[name], target, depth+1)
+	}
+}
+
+// schemaRequired collects the required properties of an object schema,
+// including those of allOf subschemas
+func (d *Document) schemaRequired(s *Schema) []string {
+	var required []string
+	var collect func(s *Schema, depth int)
+	collect = func(s *Schema, depth int) {
+		s = d.resolveSchema(s)
+		if s == nil || depth > 16 {
+			return
+		}
+		required = append(required, s.Required...)
+		for _, sub := range s.AllOf {
+			collect(sub, depth+1)
+		}
+	}
+	collect(s, 0)
+	return required
+}
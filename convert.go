@@ -0,0 +1,262 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ExtensionWebhooks carries webhooks in OpenAPI 3.0 documents, which have no
+// webhooks of their own; tools such as Redoc read it
+const ExtensionWebhooks = "x-webhooks"
+
+// ConversionWarning is a construct that converting a document to another
+// OpenAPI version dropped or could only approximate
+type ConversionWarning struct {
+	// Pointer is the JSON pointer of the construct
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// String describes the warning
+func (w ConversionWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Pointer, w.Message)
+}
+
+// ConvertTo31 converts a document to OpenAPI 3.1.0: nullable schemas become
+// "null" types, boolean exclusive bounds numeric ones, schema examples lists
+// and webhooks in the x-webhooks extension proper webhooks. Schemas with a
+// $ref and other keywords are reported, since 3.0 ignores the other keywords
+// and 3.1 applies them. The document itself is left untouched.
+func ConvertTo31(doc *Document) (*Document, []ConversionWarning, error) {
+	converted, err := doc.Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+	c := &converter{}
+	converted.OpenAPI = string(V31)
+	converted.convertExclusiveBounds()
+	converted.walkSchemas(c.schemaTo31)
+
+	if value, ok := converted.Extensions[ExtensionWebhooks]; ok {
+		var webhooks map[string]PathItem
+		if !decodeExtension(value, &webhooks) {
+			c.warn("/"+ExtensionWebhooks, "not a map of path items, left as is")
+		} else {
+			for _, name := range sortedKeys(webhooks) {
+				if _, taken := converted.Webhooks[name]; taken {
+					c.warn("/"+ExtensionWebhooks+"/"+escapePointer(name), "webhook already declared, dropped")
+					continue
+				}
+				if converted.Webhooks == nil {
+					converted.Webhooks = make(map[string]PathItem)
+				}
+				converted.Webhooks[name] = webhooks[name]
+			}
+			delete(converted.Extensions, ExtensionWebhooks)
+		}
+	}
+	return converted, c.warnings, nil
+}
+
+// ConvertTo30 converts a document to OpenAPI 3.0.3: "null" types become
+// nullable, lists of types anyOf, const an enum, numeric exclusive bounds
+// boolean ones, schema examples lists a single example, schemas with a $ref
+// and other keywords an allOf, and webhooks the x-webhooks extension. Schema
+// keywords, reference summaries and descriptions and other constructs 3.0
+// doesn't have are dropped with a warning. The document itself is left
+// untouched.
+func ConvertTo30(doc *Document) (*Document, []ConversionWarning, error) {
+	converted, err := doc.Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+	c := &converter{}
+	converted.OpenAPI = string(V30)
+	converted.convertExclusiveBounds()
+	converted.walkSchemas(c.schemaTo30)
+
+	if converted.JSONSchemaDialect != "" {
+		c.warn("/jsonSchemaDialect", "not supported, dropped")
+		converted.JSONSchemaDialect = ""
+	}
+	if converted.Info.Summary != "" {
+		c.warn("/info/summary", "not supported, dropped")
+		converted.Info.Summary = ""
+	}
+	if license := converted.Info.License; license != nil && license.Identifier != "" {
+		c.warn("/info/license/identifier", "not supported, dropped")
+		license.Identifier = ""
+	}
+	if converted.Components != nil {
+		for _, name := range sortedKeys(converted.Components.SecuritySchemes) {
+			if converted.Components.SecuritySchemes[name].Type == "mutualTLS" {
+				c.warn("/components/securitySchemes/"+escapePointer(name), "mutualTLS security schemes aren't supported, dropped")
+				delete(converted.Components.SecuritySchemes, name)
+			}
+		}
+	}
+	if len(converted.Webhooks) > 0 {
+		c.warn("/webhooks", "moved to the "+ExtensionWebhooks+" extension")
+		if converted.Extensions == nil {
+			converted.Extensions = make(map[string]interface{})
+		}
+		converted.Extensions[ExtensionWebhooks] = converted.Webhooks
+		converted.Webhooks = make(map[string]PathItem)
+	}
+
+	// Reference objects are found in the JSON tree, where they are objects
+	// holding nothing but $ref, summary and description. Schemas no longer
+	// have keywords next to $ref, and path items aren't reference objects.
+	data, err := json.Marshal(converted)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, nil, err
+	}
+	walkObjects("", tree, func(pointer string, object map[string]interface{}) {
+		if _, ok := object["$ref"].(string); !ok || len(object) == 1 || isPathItemPointer(pointer) {
+			return
+		}
+		for key := range object {
+			if key != "$ref" && key != "summary" && key != "description" {
+				return
+			}
+		}
+		c.warn(pointer, "reference summary and description aren't supported, dropped")
+		delete(object, "summary")
+		delete(object, "description")
+	})
+	if data, err = json.Marshal(tree); err != nil {
+		return nil, nil, err
+	}
+	converted, err = FromJSON(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return converted, c.warnings, nil
+}
+
+// converter collects the warnings of a conversion
+type converter struct {
+	warnings []ConversionWarning
+}
+
+func (c *converter) warn(pointer, format string, args ...interface{}) {
+	c.warnings = append(c.warnings, ConversionWarning{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// schemaTo31 converts the keywords of a schema to their OpenAPI 3.1 form
+func (c *converter) schemaTo31(pointer string, s *Schema) {
+	if s.Ref != "" && s.hasSiblings() && !s.Nullable {
+		c.warn(pointer, "keywords next to $ref were ignored and now apply")
+	}
+	if s.Nullable {
+		s.Nullable = false
+		switch {
+		case len(s.Type) > 0:
+			s.Type = append(s.Type, "null")
+			if len(s.Enum) > 0 && !slices.Contains(s.Enum, nil) {
+				s.Enum = append(s.Enum, nil)
+			}
+		case s.Ref != "" || len(s.AllOf) > 0 || len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+			// Only an untyped schema without subschemas accepts null as is
+			inner := *s
+			*s = Schema{AnyOf: []*Schema{&inner, {Type: Types{"null"}}}}
+		}
+	}
+	if foreignExamples(s, true) {
+		s.Examples = []interface{}{s.Example}
+		s.Example = nil
+	}
+}
+
+// schemaTo30 converts the keywords of a schema to their OpenAPI 3.0 form,
+// dropping those 3.0 doesn't have
+func (c *converter) schemaTo30(pointer string, s *Schema) {
+	if s.Ref != "" && s.hasSiblings() {
+		s.AllOf = append([]*Schema{{Ref: s.Ref}}, s.AllOf...)
+		s.Ref = ""
+	}
+
+	if s.Type.Is("null") {
+		types := slices.DeleteFunc(slices.Clone(s.Type), func(t string) bool { return t == "null" })
+		s.Type, s.Nullable = types, true
+		if len(types) == 0 {
+			c.warn(pointer, "type null approximated by a nullable enum of null")
+			s.Enum = []interface{}{nil}
+		}
+	}
+	if len(s.Type) > 1 {
+		var branches []*Schema
+		for _, t := range s.Type {
+			branches = append(branches, &Schema{Type: Types{t}, Nullable: s.Nullable})
+		}
+		s.Type, s.Nullable = nil, false
+		if len(s.AnyOf) == 0 {
+			s.AnyOf = branches
+		} else {
+			s.AllOf = append(s.AllOf, &Schema{AnyOf: branches})
+		}
+	}
+	if s.Const != nil {
+		s.Enum = []interface{}{s.Const}
+		s.Const = nil
+	}
+	if foreignExamples(s, false) {
+		if len(s.Examples) > 1 {
+			c.warn(pointer+"/examples", "only the first example is kept")
+		}
+		if s.Example == nil {
+			s.Example = s.Examples[0]
+		}
+		s.Examples = nil
+	}
+
+	var dropped []string
+	drop := func(keyword string, set bool, unset func()) {
+		if set {
+			dropped = append(dropped, keyword)
+			unset()
+		}
+	}
+	drop("$schema", s.Dialect != "", func() { s.Dialect = "" })
+	drop("$comment", s.Comment != "", func() { s.Comment = "" })
+	drop("$defs", len(s.Defs) > 0, func() { s.Defs = nil })
+	drop("dependentRequired", len(s.DependentRequired) > 0, func() { s.DependentRequired = nil })
+	drop("dependentSchemas", len(s.DependentSchemas) > 0, func() { s.DependentSchemas = nil })
+	drop("if", s.If != nil, func() { s.If = nil })
+	drop("then", s.Then != nil, func() { s.Then = nil })
+	drop("else", s.Else != nil, func() { s.Else = nil })
+	drop("prefixItems", len(s.PrefixItems) > 0, func() { s.PrefixItems = nil })
+	drop("contains", s.Contains != nil, func() { s.Contains = nil })
+	drop("minContains", s.MinContains != nil, func() { s.MinContains = nil })
+	drop("maxContains", s.MaxContains != nil, func() { s.MaxContains = nil })
+	drop("patternProperties", len(s.PatternProperties) > 0, func() { s.PatternProperties = nil })
+	drop("propertyNames", s.PropertyNames != nil, func() { s.PropertyNames = nil })
+	drop("unevaluatedProperties", s.UnevaluatedProperties != nil, func() { s.UnevaluatedProperties = nil })
+	if len(dropped) > 0 {
+		c.warn(pointer, "%s not supported, dropped", strings.Join(dropped, ", "))
+	}
+}
+
+// hasSiblings reports whether a schema has keywords besides $ref
+func (s *Schema) hasSiblings() bool {
+	siblings := *s
+	siblings.Ref = ""
+	data, err := json.Marshal(siblings)
+	return err == nil && string(data) != "{}"
+}
+
+// isPathItemPointer reports whether the object at a pointer is a path item:
+// a member of paths or webhooks, or the expression of a callback
+func isPathItemPointer(pointer string) bool {
+	tokens := strings.Split(pointer, "/")
+	if len(tokens) == 3 && (tokens[1] == "paths" || tokens[1] == "webhooks" || tokens[1] == ExtensionWebhooks) {
+		return true
+	}
+	return len(tokens) >= 3 && tokens[len(tokens)-3] == "callbacks"
+}
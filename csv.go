@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+)
+
+// ToOperationsCSV lists every operation as a CSV row for governance
+// spreadsheets and audits, ordered by path and method. The auth column holds
+// the effective security requirements: alternatives are separated by " | ",
+// schemes required together by " + ", with their scopes in parentheses.
+// Operations without requirements are listed as "none".
+func (d *Document) ToOperationsCSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"path", "method", "operationId", "tags", "auth", "deprecated", "summary"}); err != nil {
+		return nil, err
+	}
+	var err error
+	d.walkOperations(func(path, method string, op *Operation) {
+		if err != nil {
+			return
+		}
+		err = w.Write([]string{
+			path,
+			method,
+			op.OperationID,
+			strings.Join(op.Tags, ", "),
+			securitySummary(d.EffectiveSecurity(op)),
+			strconv.FormatBool(op.Deprecated),
+			op.Summary,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// securitySummary renders security requirements on one line, e.g.
+// "oauth2(read:pets) | apiKey"
+func securitySummary(requirements []SecurityRequirement) string {
+	var alternatives []string
+	for _, requirement := range requirements {
+		var schemes []string
+		for _, name := range sortedKeys(requirement) {
+			if scopes := requirement[name]; len(scopes) > 0 {
+				name += "(" + strings.Join(scopes, " ") + ")"
+			}
+			schemes = append(schemes, name)
+		}
+		if len(schemes) == 0 {
+			schemes = append(schemes, "none")
+		}
+		alternatives = append(alternatives, strings.Join(schemes, " + "))
+	}
+	if len(alternatives) == 0 {
+		return "none"
+	}
+	return strings.Join(alternatives, " | ")
+}
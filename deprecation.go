@@ -0,0 +1,23 @@
+package openapi
+
+import "time"
+
+// ExtensionSunset is the operation extension holding the date after which a
+// deprecated operation may be removed
+const ExtensionSunset = "x-sunset"
+
+// WithSunset deprecates the operation and records the date it may be removed
+func (o Operation) WithSunset(date time.Time) Operation {
+	o.Deprecated = true
+	return o.WithExtension(ExtensionSunset, date.Format(time.DateOnly))
+}
+
+// Sunset returns the sunset date of the operation, if one is set
+func (o *Operation) Sunset() (time.Time, bool) {
+	value, ok := o.Extensions[ExtensionSunset].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	return date, err == nil
+}
@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+)
+
+// cowState tracks which parts of a derived document are still shared with
+// the document it was derived from
+type cowState struct {
+	paths      bool
+	operations bool
+	webhooks   bool
+	components bool
+	tags       bool
+	extensions bool
+}
+
+// Derive returns a copy-on-write view of the document for building variants
+// such as public, partner and internal editions of one base document. The view
+// shares paths, operations, components and tags with the document until they are
+// changed through the document methods, which copy only the modified container.
+// Changes made by writing through fields or returned pointers are not isolated:
+// freeze the base document to protect it from such writes. Both documents
+// copy before writing from then on, so neither sees the other's changes.
+func (d *Document) Derive() *Document {
+	derived := *d
+	derived.frozen = nil
+	derived.shared = &cowState{paths: true, operations: true, webhooks: true, components: true, tags: true, extensions: true}
+	derived.Servers = slices.Clip(d.Servers)
+	derived.Security = slices.Clip(d.Security)
+	derived.Tags = slices.Clip(d.Tags)
+	if d.frozen == nil {
+		d.shared = &cowState{paths: true, operations: true, webhooks: true, components: true, tags: true, extensions: true}
+	}
+	return &derived
+}
+
+// ownPaths gives the document its own paths map
+func (d *Document) ownPaths() {
+	if d.shared != nil && d.shared.paths {
+		d.Paths = maps.Clone(d.Paths)
+		d.shared.paths = false
+	}
+	if d.Paths == nil {
+		d.Paths = make(map[string]PathItem)
+	}
+}
+
+// ownOperations gives the document shallow copies of all of its operations,
+// for methods that update operations in place
+func (d *Document) ownOperations() {
+	d.ownPaths()
+	if d.shared == nil || !d.shared.operations {
+		return
+	}
+	for path, item := range d.Paths {
+		for _, method := range httpMethods {
+			if op := item.Operation(method); op != nil {
+				clone := *op
+				clone.Parameters = slices.Clip(clone.Parameters)
+				item.SetOperation(method, &clone)
+			}
+		}
+		d.Paths[path] = item
+	}
+	d.shared.operations = false
+}
+
+// ownWebhooks gives the document its own webhooks map and shallow copies of
+// the webhook operations
+func (d *Document) ownWebhooks() {
+	if d.shared == nil || !d.shared.webhooks {
+		return
+	}
+	webhooks := make(map[string]PathItem, len(d.Webhooks))
+	for name, item := range d.Webhooks {
+		for _, method := range httpMethods {
+			if op := item.Operation(method); op != nil {
+				clone := *op
+				clone.Parameters = slices.Clip(clone.Parameters)
+				item.SetOperation(method, &clone)
+			}
+		}
+		webhooks[name] = item
+	}
+	d.Webhooks = webhooks
+	d.shared.webhooks = false
+}
+
+// ownComponents gives the document its own components and component maps
+func (d *Document) ownComponents() {
+	if d.shared == nil || !d.shared.components || d.Components == nil {
+		return
+	}
+	c := *d.Components
+	c.Schemas = maps.Clone(c.Schemas)
+	c.Responses = maps.Clone(c.Responses)
+	c.Parameters = maps.Clone(c.Parameters)
+	c.Examples = maps.Clone(c.Examples)
+	c.RequestBodies = maps.Clone(c.RequestBodies)
+	c.Headers = maps.Clone(c.Headers)
+	c.SecuritySchemes = maps.Clone(c.SecuritySchemes)
+	c.Links = maps.Clone(c.Links)
+	c.Callbacks = maps.Clone(c.Callbacks)
+	d.Components = &c
+	d.shared.components = false
+}
+
+// ownTags gives the document its own tags and tag extension maps
+func (d *Document) ownTags() {
+	if d.shared == nil || !d.shared.tags {
+		return
+	}
+	d.Tags = slices.Clone(d.Tags)
+	for i := range d.Tags {
+		d.Tags[i].Extensions = maps.Clone(d.Tags[i].Extensions)
+	}
+	d.shared.tags = false
+}
+
+// ownExtensions gives the document its own extensions map
+func (d *Document) ownExtensions() {
+	if d.shared != nil && d.shared.extensions {
+		d.Extensions = maps.Clone(d.Extensions)
+		d.shared.extensions = false
+	}
+}
@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"net/url"
+)
+
+const (
+	// DialectOAS31 is the default dialect of OpenAPI 3.1 schemas: JSON Schema
+	// 2020-12 extended with the OpenAPI vocabulary
+	DialectOAS31 = "https://spec.openapis.org/oas/3.1/dialect/base"
+	// DialectJSONSchema202012 is plain JSON Schema 2020-12
+	DialectJSONSchema202012 = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// WithDialect declares the JSON Schema dialect of the schema in "$schema"
+func (s Schema) WithDialect(dialect string) Schema {
+	s.Dialect = dialect
+	return s
+}
+
+// WithJSONSchemaDialect sets the default dialect of the schemas of the
+// document, such as DialectJSONSchema202012
+func (d *Document) WithJSONSchemaDialect(dialect string) *Document {
+	if !d.mutable("WithJSONSchemaDialect") {
+		return d
+	}
+	d.JSONSchemaDialect = dialect
+	return d
+}
+
+// SchemaDialect returns the dialect the schemas of the document use when they
+// don't declare one: the jsonSchemaDialect of the document, or DialectOAS31
+func (d *Document) SchemaDialect() string {
+	if d.JSONSchemaDialect != "" {
+		return d.JSONSchemaDialect
+	}
+	return DialectOAS31
+}
+
+// StampSchemaDialect declares the dialect of the document in the "$schema" of
+// every component schema that doesn't declare one, so the schemas keep their
+// dialect when extracted from the document and used on their own
+func (d *Document) StampSchemaDialect() *Document {
+	if !d.mutable("StampSchemaDialect") || d.Components == nil {
+		return d
+	}
+	d.ownComponents()
+	dialect := d.SchemaDialect()
+	for name, schema := range d.Components.Schemas {
+		if schema == nil || schema.Dialect != "" || schema.Ref != "" {
+			continue
+		}
+		stamped := *schema
+		stamped.Dialect = dialect
+		d.Components.Schemas[name] = &stamped
+	}
+	return d
+}
+
+// validateDialect checks that the jsonSchemaDialect of the document is an
+// absolute URI and only used by OpenAPI 3.1 documents
+func (v *validator) validateDialect() {
+	dialect := v.doc.JSONSchemaDialect
+	if dialect == "" {
+		return
+	}
+	if !isOpenAPI31(v.doc.OpenAPI) {
+		v.errorf("/jsonSchemaDialect", "jsonSchemaDialect requires OpenAPI 3.1, document uses %s", v.doc.OpenAPI)
+	}
+	if u, err := url.Parse(dialect); err != nil || !u.IsAbs() {
+		v.errorf("/jsonSchemaDialect", "jsonSchemaDialect %q must be an absolute URI", dialect)
+	}
+}
@@ -0,0 +1,313 @@
+// Package diff compares OpenAPI documents and classifies the differences as
+// breaking or non-breaking for API consumers
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nyxstack/openapi"
+)
+
+// Kind identifies a type of change between two documents
+type Kind string
+
+// Change kinds reported by Compare
+const (
+	PathAdded                 Kind = "path-added"
+	PathRemoved               Kind = "path-removed"
+	OperationAdded            Kind = "operation-added"
+	OperationRemoved          Kind = "operation-removed"
+	OperationDeprecated       Kind = "operation-deprecated"
+	ParameterAdded            Kind = "parameter-added"
+	ParameterRemoved          Kind = "parameter-removed"
+	ParameterBecameRequired   Kind = "parameter-became-required"
+	ParameterBecameOptional   Kind = "parameter-became-optional"
+	ResponseAdded             Kind = "response-added"
+	ResponseRemoved           Kind = "response-removed"
+	RequestBodyAdded          Kind = "request-body-added"
+	RequestBodyRemoved        Kind = "request-body-removed"
+	RequestBodyBecameRequired Kind = "request-body-became-required"
+	RequestMediaTypeRemoved   Kind = "request-media-type-removed"
+	ResponseMediaTypeRemoved  Kind = "response-media-type-removed"
+)
+
+// Change describes one difference between two documents
+type Change struct {
+	Kind Kind
+	// Path and Method locate the affected operation; Method is empty for path changes
+	Path   string
+	Method string
+	// Pointer is the JSON pointer of the changed element, in the new document
+	// or, for removals, in the old one. Pointers into referenced responses and
+	// request bodies run through the operation's reference.
+	Pointer  string
+	Message  string
+	Breaking bool
+	// Old and New hold the element before and after the change: a PathItem,
+	// *Operation, Parameter, Response, *RequestBody, MediaType, *Schema or
+	// enum value. Old is nil for additions and New is nil for removals.
+	Old interface{}
+	New interface{}
+}
+
+// String formats the change for logs and CI output
+func (c Change) String() string {
+	marker := "non-breaking"
+	if c.Breaking {
+		marker = "breaking"
+	}
+	return fmt.Sprintf("[%s] %s: %s", marker, c.Kind, c.Message)
+}
+
+// Changelog lists the changes between two documents
+type Changelog struct {
+	Changes []Change
+}
+
+// Breaking returns the breaking changes
+func (c *Changelog) Breaking() []Change {
+	var breaking []Change
+	for _, change := range c.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// HasBreaking reports whether any change is breaking
+func (c *Changelog) HasBreaking() bool {
+	return len(c.Breaking()) > 0
+}
+
+// methods lists the operation methods of a path item in canonical order
+var methods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// Compare reports the changes made between the old and the new document
+func Compare(oldDoc, newDoc *openapi.Document) *Changelog {
+	c := &comparer{oldDoc: oldDoc, newDoc: newDoc, log: &Changelog{}}
+	c.comparePaths()
+	c.compareComponentSchemas()
+	return c.log
+}
+
+// comparer accumulates the changes found while comparing
+type comparer struct {
+	oldDoc, newDoc *openapi.Document
+	log            *Changelog
+}
+
+func (c *comparer) add(change Change) {
+	c.log.Changes = append(c.log.Changes, change)
+}
+
+func (c *comparer) comparePaths() {
+	for _, path := range unionKeys(c.oldDoc.Paths, c.newDoc.Paths) {
+		oldItem, inOld := c.oldDoc.Paths[path]
+		newItem, inNew := c.newDoc.Paths[path]
+		pointer := "/paths/" + escape(path)
+		switch {
+		case !inNew:
+			c.add(Change{Kind: PathRemoved, Path: path, Pointer: pointer, Breaking: true,
+				Message: fmt.Sprintf("path %s was removed", path), Old: oldItem})
+			continue
+		case !inOld:
+			c.add(Change{Kind: PathAdded, Path: path, Pointer: pointer,
+				Message: fmt.Sprintf("path %s was added", path), New: newItem})
+			continue
+		}
+
+		for _, method := range methods {
+			oldOp, newOp := oldItem.Operation(method), newItem.Operation(method)
+			opPointer := pointer + "/" + strings.ToLower(method)
+			switch {
+			case oldOp == nil && newOp == nil:
+			case newOp == nil:
+				c.add(Change{Kind: OperationRemoved, Path: path, Method: method, Pointer: opPointer, Breaking: true,
+					Message: fmt.Sprintf("%s %s was removed", method, path), Old: oldOp})
+			case oldOp == nil:
+				c.add(Change{Kind: OperationAdded, Path: path, Method: method, Pointer: opPointer,
+					Message: fmt.Sprintf("%s %s was added", method, path), New: newOp})
+			default:
+				c.compareOperation(path, method, opPointer, oldOp, newOp)
+			}
+		}
+	}
+}
+
+func (c *comparer) compareOperation(path, method, pointer string, oldOp, newOp *openapi.Operation) {
+	operation := method + " " + path
+	if newOp.Deprecated && !oldOp.Deprecated {
+		c.add(Change{Kind: OperationDeprecated, Path: path, Method: method, Pointer: pointer,
+			Message: fmt.Sprintf("%s was deprecated", operation), Old: oldOp, New: newOp})
+	}
+
+	oldParams := indexParameters(c.oldDoc.OperationParameters(path, oldOp))
+	newParams := indexParameters(c.newDoc.OperationParameters(path, newOp))
+	for _, key := range unionKeys(oldParams, newParams) {
+		oldParam, inOld := oldParams[key]
+		newParam, inNew := newParams[key]
+		paramPointer := parameterPointer(c.newDoc, path, method, newOp, key)
+		if !inNew {
+			paramPointer = parameterPointer(c.oldDoc, path, method, oldOp, key)
+		}
+		name := fmt.Sprintf("%s parameter %q", newParam.In, newParam.Name)
+		switch {
+		case !inNew:
+			c.add(Change{Kind: ParameterRemoved, Path: path, Method: method, Pointer: paramPointer, Breaking: true,
+				Message: fmt.Sprintf("%s parameter %q was removed from %s", oldParam.In, oldParam.Name, operation), Old: oldParam})
+		case !inOld:
+			c.add(Change{Kind: ParameterAdded, Path: path, Method: method, Pointer: paramPointer, Breaking: newParam.Required,
+				Message: fmt.Sprintf("%s was added to %s", requiredName(newParam, name), operation), New: newParam})
+		case newParam.Required && !oldParam.Required:
+			c.add(Change{Kind: ParameterBecameRequired, Path: path, Method: method, Pointer: paramPointer, Breaking: true,
+				Message: fmt.Sprintf("%s of %s became required", name, operation), Old: oldParam, New: newParam})
+		case oldParam.Required && !newParam.Required:
+			c.add(Change{Kind: ParameterBecameOptional, Path: path, Method: method, Pointer: paramPointer,
+				Message: fmt.Sprintf("%s of %s became optional", name, operation), Old: oldParam, New: newParam})
+		}
+		if inOld && inNew {
+			c.compareSchemas(paramPointer+"/schema", path, method, oldParam.Schema, newParam.Schema, requestContext, 0)
+		}
+	}
+
+	oldBody, newBody := resolveRequestBody(c.oldDoc, oldOp.RequestBody), resolveRequestBody(c.newDoc, newOp.RequestBody)
+	bodyPointer := pointer + "/requestBody"
+	switch {
+	case oldBody == nil && newBody == nil:
+	case newBody == nil:
+		c.add(Change{Kind: RequestBodyRemoved, Path: path, Method: method, Pointer: bodyPointer, Breaking: true,
+			Message: fmt.Sprintf("request body was removed from %s", operation), Old: oldBody})
+	case oldBody == nil:
+		c.add(Change{Kind: RequestBodyAdded, Path: path, Method: method, Pointer: bodyPointer, Breaking: newBody.Required,
+			Message: fmt.Sprintf("%s request body was added to %s", requiredBody(newBody), operation), New: newBody})
+	default:
+		if newBody.Required && !oldBody.Required {
+			c.add(Change{Kind: RequestBodyBecameRequired, Path: path, Method: method, Pointer: bodyPointer, Breaking: true,
+				Message: fmt.Sprintf("request body of %s became required", operation), Old: oldBody, New: newBody})
+		}
+		c.compareContent(bodyPointer, path, method, "the request body of "+operation, oldBody.Content, newBody.Content, requestContext)
+	}
+
+	for _, code := range unionKeys(oldOp.Responses, newOp.Responses) {
+		oldResponse, inOld := oldOp.Responses[code]
+		newResponse, inNew := newOp.Responses[code]
+		oldResponse, newResponse = resolveResponse(c.oldDoc, oldResponse), resolveResponse(c.newDoc, newResponse)
+		responsePointer := pointer + "/responses/" + escape(code)
+		switch {
+		case !inNew:
+			c.add(Change{Kind: ResponseRemoved, Path: path, Method: method, Pointer: responsePointer,
+				Breaking: strings.HasPrefix(code, "2"),
+				Message:  fmt.Sprintf("response %s was removed from %s", code, operation), Old: oldResponse})
+		case !inOld:
+			c.add(Change{Kind: ResponseAdded, Path: path, Method: method, Pointer: responsePointer,
+				Message: fmt.Sprintf("response %s was added to %s", code, operation), New: newResponse})
+		default:
+			c.compareContent(responsePointer, path, method, fmt.Sprintf("response %s of %s", code, operation),
+				oldResponse.Content, newResponse.Content, responseContext)
+		}
+	}
+}
+
+// parameterPointer locates a parameter by location and name, on the operation
+// or else on its path item. Parameters pulled in by reference are located by
+// the position of the reference.
+func parameterPointer(doc *openapi.Document, path, method string, op *openapi.Operation, key string) string {
+	pointer := "/paths/" + escape(path)
+	find := func(params []openapi.Parameter) int {
+		for i, p := range params {
+			if name, ok := strings.CutPrefix(p.Ref.String(), "#/components/parameters/"); ok && doc.Components != nil {
+				p = doc.Components.Parameters[unescape(name)]
+			}
+			if p.In+":"+p.Name == key {
+				return i
+			}
+		}
+		return -1
+	}
+	if i := find(op.Parameters); i >= 0 {
+		return fmt.Sprintf("%s/%s/parameters/%d", pointer, strings.ToLower(method), i)
+	}
+	if i := find(doc.Paths[path].Parameters); i >= 0 {
+		return fmt.Sprintf("%s/parameters/%d", pointer, i)
+	}
+	return pointer + "/" + strings.ToLower(method)
+}
+
+// resolveRequestBody follows a reference to a request body component. A
+// reference that doesn't resolve is returned as is.
+func resolveRequestBody(doc *openapi.Document, body *openapi.RequestBody) *openapi.RequestBody {
+	if body == nil || body.Ref == nil {
+		return body
+	}
+	if resolved, err := doc.ResolveRef(body.Ref.Ref); err == nil {
+		if component, ok := resolved.(openapi.RequestBody); ok {
+			return &component
+		}
+	}
+	return body
+}
+
+// resolveResponse follows a reference to a response component. A reference
+// that doesn't resolve is returned as is.
+func resolveResponse(doc *openapi.Document, response openapi.Response) openapi.Response {
+	if response.Ref == nil {
+		return response
+	}
+	if resolved, err := doc.ResolveRef(response.Ref.Ref); err == nil {
+		if component, ok := resolved.(openapi.Response); ok {
+			return component
+		}
+	}
+	return response
+}
+
+// indexParameters keys parameters by location and name
+func indexParameters(params []openapi.Parameter) map[string]openapi.Parameter {
+	index := make(map[string]openapi.Parameter, len(params))
+	for _, p := range params {
+		index[p.In+":"+p.Name] = p
+	}
+	return index
+}
+
+func requiredBody(body *openapi.RequestBody) string {
+	if body.Required {
+		return "required"
+	}
+	return "optional"
+}
+
+func requiredName(p openapi.Parameter, name string) string {
+	if p.Required {
+		return "required " + name
+	}
+	return "optional " + name
+}
+
+// unionKeys returns the keys present in either map, sorted
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escape escapes a JSON pointer reference token
+func escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// unescape unescapes a JSON pointer reference token
+func unescape(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
@@ -0,0 +1,363 @@
+package diff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nyxstack/openapi"
+)
+
+func baseDocument() *openapi.Document {
+	doc := openapi.NewDocument("Test API", "1.0.0")
+	doc.AddOperation("/users", "GET", openapi.NewOperation("listUsers", "List users", "").
+		WithParameter(openapi.Parameter{Name: "limit", In: "query", Schema: openapi.Int32Schema()}).
+		WithResponse("200", "OK", openapi.Response{}))
+	doc.AddOperation("/legacy", "GET", openapi.NewOperation("legacy", "Legacy", "").
+		WithSunset(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).
+		WithResponse("200", "OK", openapi.Response{}))
+	return doc
+}
+
+func TestCompare(t *testing.T) {
+	oldDoc := baseDocument()
+	newDoc := baseDocument()
+	newDoc.AddOperation("/users", "GET", openapi.NewOperation("listUsers", "List users", "").
+		WithParameter(openapi.Parameter{Name: "limit", In: "query", Required: true, Schema: openapi.Int32Schema()}).
+		WithResponse("201", "Created", openapi.Response{}))
+
+	log := Compare(oldDoc, newDoc)
+
+	kinds := map[Kind]Change{}
+	for _, c := range log.Changes {
+		kinds[c.Kind] = c
+	}
+	if c, ok := kinds[ParameterBecameRequired]; !ok || !c.Breaking {
+		t.Errorf("Expected breaking parameter-became-required change, got %v", log.Changes)
+	}
+	if c := kinds[ParameterBecameRequired]; c.Pointer != "/paths/~1users/get/parameters/0" {
+		t.Errorf("Expected parameter pointer, got %s", c.Pointer)
+	}
+	if c, ok := kinds[ResponseRemoved]; !ok || !c.Breaking {
+		t.Errorf("Expected breaking response-removed change, got %v", log.Changes)
+	}
+	if c, ok := kinds[ResponseAdded]; !ok || c.Breaking {
+		t.Errorf("Expected non-breaking response-added change, got %v", log.Changes)
+	}
+	if len(log.Changes) != 3 {
+		t.Errorf("Expected 3 changes, got %d: %v", len(log.Changes), log.Changes)
+	}
+}
+
+func TestCompareReferencedParameters(t *testing.T) {
+	document := func(required bool) *openapi.Document {
+		doc := baseDocument()
+		doc.AddComponents().Parameters = map[string]openapi.Parameter{
+			"page/size": {Name: "size", In: "query", Required: required, Schema: openapi.Int32Schema()},
+		}
+		doc.AddOperation("/pets", "GET", openapi.NewOperation("listPets", "List pets", "").
+			WithParameter(openapi.Parameter{Name: "q", In: "query", Schema: openapi.StringSchema("")}).
+			WithParameter(openapi.Parameter{Ref: openapi.NewReference("#/components/parameters/page~1size")}).
+			WithResponse("200", "OK", openapi.Response{}))
+		return doc
+	}
+
+	log := Compare(document(false), document(true))
+	if len(log.Changes) != 1 || log.Changes[0].Kind != ParameterBecameRequired {
+		t.Fatalf("Expected parameter-became-required change, got %v", log.Changes)
+	}
+	if c := log.Changes[0]; c.Pointer != "/paths/~1pets/get/parameters/1" {
+		t.Errorf("Expected the pointer of the reference, got %s", c.Pointer)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	legacy := openapi.NewOperation("legacy", "Legacy", "").
+		WithSunset(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).
+		WithResponse("200", "OK", openapi.Response{})
+	createUser := openapi.NewOperation("createUser", "Create user", "").
+		WithResponse("201", "Created", openapi.Response{})
+	log := &Changelog{Changes: []Change{
+		{Kind: PathRemoved, Path: "/legacy", Pointer: "/paths/~1legacy", Breaking: true,
+			Message: "path /legacy was removed", Old: openapi.PathItem{Get: &legacy}},
+		{Kind: OperationAdded, Path: "/users", Method: "POST", Pointer: "/paths/~1users/post",
+			Message: "POST /users was added", New: &createUser},
+	}}
+
+	if !log.HasBreaking() || len(log.Breaking()) != 1 {
+		t.Errorf("Expected one breaking change, got %v", log.Breaking())
+	}
+	if result := NewPolicy().Evaluate(log); result.Passed {
+		t.Error("Expected default policy to reject removed path")
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := NewPolicy(AllowRemovalAfterSunset(now)).Evaluate(log)
+	if !result.Passed {
+		t.Errorf("Expected removal after sunset to pass, got %v", result.Violations())
+	}
+	before := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	if result := NewPolicy(AllowRemovalAfterSunset(before)).Evaluate(log); result.Passed {
+		t.Error("Expected removal before sunset to be rejected")
+	}
+
+	result = NewPolicy(AllowRemovalAfterSunset(now), Deny(OperationAdded, "new operations need review")).Evaluate(log)
+	if result.Passed || len(result.Violations()) != 1 {
+		t.Fatalf("Expected added operation to be rejected, got %v", result.Violations())
+	}
+	if v := result.Violations()[0]; v.Rule != "deny-operation-added" || v.Reason != "new operations need review" {
+		t.Errorf("Expected verdict of the deny rule, got %+v", v)
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	order := func(values ...interface{}) *openapi.Schema {
+		status := openapi.StringSchema("").WithEnum(values...)
+		return &openapi.Schema{Properties: map[string]*openapi.Schema{"status": &status}}
+	}
+
+	log := CompareSchemas(order("active", "disabled"), order("active", "pending"))
+	if len(log.Changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %v", log.Changes)
+	}
+	if c := log.Changes[0]; c.Kind != EnumValueRemoved || !c.Breaking || c.Old != "disabled" {
+		t.Errorf("Expected breaking removal of disabled, got %v", c)
+	}
+	if c := log.Changes[1]; c.Kind != EnumValueAdded || c.Breaking || c.Pointer != "/properties/status/enum" {
+		t.Errorf("Expected non-breaking addition of pending, got %v", c)
+	}
+
+	result := NewPolicy(Deny(EnumValueAdded, "clients switch exhaustively over enums"), Allow(EnumValueRemoved, "")).Evaluate(log)
+	if result.Passed || len(result.Violations()) != 1 {
+		t.Errorf("Expected enum addition to be rejected, got %v", result.Violations())
+	}
+
+	if log := CompareSchemas(order(1, 2), order(1.0, 2.0)); len(log.Changes) != 0 {
+		t.Errorf("Expected numbers to compare by value, got %v", log.Changes)
+	}
+}
+
+func TestCompareEnums(t *testing.T) {
+	status := func(values ...interface{}) openapi.Schema {
+		return openapi.Schema{Type: openapi.Types{"string"}}.WithEnum(values...)
+	}
+	oldDoc := baseDocument()
+	oldDoc.AddSchema("Status", status("active", "disabled"))
+	newDoc := baseDocument()
+	newDoc.AddSchema("Status", status("active", "pending"))
+
+	log := Compare(oldDoc, newDoc)
+	if len(log.Changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %v", log.Changes)
+	}
+	if c := log.Changes[0]; c.Kind != EnumValueRemoved || !c.Breaking || c.Old != "disabled" {
+		t.Errorf("Expected breaking removal of disabled, got %v", c)
+	}
+	if c := log.Changes[1]; c.Kind != EnumValueAdded || c.Breaking || c.Pointer != "/components/schemas/Status/enum" {
+		t.Errorf("Expected non-breaking addition of pending, got %v", c)
+	}
+
+	result := NewPolicy(Deny(EnumValueAdded, "clients switch exhaustively over enums"), Allow(EnumValueRemoved, "")).Evaluate(log)
+	if result.Passed || len(result.Violations()) != 1 {
+		t.Errorf("Expected enum addition to be rejected, got %v", result.Violations())
+	}
+}
+
+func TestCompareRequestBodies(t *testing.T) {
+	pet := &openapi.Schema{Type: openapi.Types{"object"}, Properties: map[string]*openapi.Schema{"name": openapi.StringSchema("")}}
+	document := func(body *openapi.RequestBody) *openapi.Document {
+		doc := baseDocument()
+		op := openapi.NewOperation("createPet", "Create pet", "").WithResponse("201", "Created", openapi.Response{})
+		op.RequestBody = body
+		doc.AddOperation("/pets", "POST", op)
+		return doc
+	}
+	body := func(required bool, mediaTypes ...string) *openapi.RequestBody {
+		b := openapi.NewRequestBody("Pet", required)
+		for _, name := range mediaTypes {
+			b = b.WithContent(name, openapi.MediaType{Schema: pet})
+		}
+		return &b
+	}
+
+	tests := []struct {
+		name     string
+		old, new *openapi.RequestBody
+		kind     Kind
+		breaking bool
+		pointer  string
+	}{
+		{"optional body added", nil, body(false, "application/json"), RequestBodyAdded, false, "/paths/~1pets/post/requestBody"},
+		{"required body added", nil, body(true, "application/json"), RequestBodyAdded, true, "/paths/~1pets/post/requestBody"},
+		{"body removed", body(false, "application/json"), nil, RequestBodyRemoved, true, "/paths/~1pets/post/requestBody"},
+		{"body became required", body(false, "application/json"), body(true, "application/json"), RequestBodyBecameRequired, true, "/paths/~1pets/post/requestBody"},
+		{"media type removed", body(false, "application/json", "application/xml"), body(false, "application/json"), RequestMediaTypeRemoved, true,
+			"/paths/~1pets/post/requestBody/content/application~1xml"},
+	}
+	for _, tt := range tests {
+		log := Compare(document(tt.old), document(tt.new))
+		if len(log.Changes) != 1 {
+			t.Errorf("%s: Expected 1 change, got %v", tt.name, log.Changes)
+			continue
+		}
+		if c := log.Changes[0]; c.Kind != tt.kind || c.Breaking != tt.breaking || c.Pointer != tt.pointer {
+			t.Errorf("%s: Expected %s (breaking %v) at %s, got %v at %s", tt.name, tt.kind, tt.breaking, tt.pointer, c, c.Pointer)
+		}
+	}
+}
+
+func TestCompareProperties(t *testing.T) {
+	object := func(required []string, properties map[string]*openapi.Schema) *openapi.Schema {
+		return &openapi.Schema{Type: openapi.Types{"object"}, Required: required, Properties: properties}
+	}
+	document := func(request, response *openapi.Schema) *openapi.Document {
+		doc := baseDocument()
+		doc.AddOperation("/pets", "POST", openapi.NewOperation("createPet", "Create pet", "").
+			WithJSONRequestBody("Pet", true, request).
+			WithJSONResponse("201", "Created", response))
+		return doc
+	}
+	oldDoc := document(
+		object([]string{"name"}, map[string]*openapi.Schema{
+			"name": openapi.StringSchema(""),
+			"age":  openapi.Int32Schema(),
+			"tag":  openapi.StringSchema(""),
+		}),
+		object(nil, map[string]*openapi.Schema{
+			"id":    openapi.Int64Schema(),
+			"name":  openapi.StringSchema(""),
+			"score": {Type: openapi.Types{"integer"}},
+		}))
+	newDoc := document(
+		object([]string{"name", "tag", "owner"}, map[string]*openapi.Schema{
+			"name":  openapi.StringSchema(""),
+			"age":   {Type: openapi.Types{"integer", "string"}},
+			"tag":   openapi.StringSchema(""),
+			"owner": openapi.StringSchema(""),
+		}),
+		object(nil, map[string]*openapi.Schema{
+			"id":    {Type: openapi.Types{"string"}},
+			"score": {Type: openapi.Types{"integer", "null"}},
+		}))
+
+	log := Compare(oldDoc, newDoc)
+	expected := []struct {
+		kind     Kind
+		breaking bool
+		pointer  string
+	}{
+		{RequiredRequestPropertyAdded, true, "/paths/~1pets/post/requestBody/content/application~1json/schema/properties/owner"},
+		{RequiredRequestPropertyAdded, true, "/paths/~1pets/post/requestBody/content/application~1json/schema/properties/tag"},
+		{TypeChanged, false, "/paths/~1pets/post/requestBody/content/application~1json/schema/properties/age/type"},
+		{ResponsePropertyRemoved, true, "/paths/~1pets/post/responses/201/content/application~1json/schema/properties/name"},
+		{TypeChanged, true, "/paths/~1pets/post/responses/201/content/application~1json/schema/properties/id/type"},
+		{TypeChanged, true, "/paths/~1pets/post/responses/201/content/application~1json/schema/properties/score/type"},
+	}
+	if len(log.Changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d: %v", len(expected), len(log.Changes), log.Changes)
+	}
+	for i, e := range expected {
+		if c := log.Changes[i]; c.Kind != e.kind || c.Breaking != e.breaking || c.Pointer != e.pointer {
+			t.Errorf("Expected %s (breaking %v) at %s, got %v at %s", e.kind, e.breaking, e.pointer, c, c.Pointer)
+		}
+	}
+
+	shared := CompareSchemas(
+		object(nil, map[string]*openapi.Schema{"id": openapi.Int64Schema(), "name": openapi.StringSchema("")}),
+		object([]string{"id"}, map[string]*openapi.Schema{"id": openapi.Int64Schema()}))
+	if len(shared.Changes) != 2 || shared.Changes[0].Kind != RequiredRequestPropertyAdded || shared.Changes[1].Kind != ResponsePropertyRemoved {
+		t.Errorf("Expected shared schemas to report both ways, got %v", shared.Changes)
+	}
+}
+
+func TestCompareTypes(t *testing.T) {
+	pet := func(name *openapi.Schema) *openapi.Schema {
+		return &openapi.Schema{Type: openapi.Types{"object"}, Properties: map[string]*openapi.Schema{"name": name}}
+	}
+	document := func(limit, body *openapi.Schema) *openapi.Document {
+		doc := baseDocument()
+		doc.AddSchema("Pet", *pet(openapi.StringSchema("")))
+		doc.AddSchema("NewPet", *pet(openapi.StringSchema("")))
+		doc.AddOperation("/pets", "POST", openapi.NewOperation("createPet", "Create pet", "").
+			WithParameter(openapi.Parameter{Name: "limit", In: "query", Schema: limit}).
+			WithJSONRequestBody("Pet", true, body).
+			WithResponse("204", "Created", openapi.Response{}))
+		return doc
+	}
+	ref := func(name string) *openapi.Schema { return &openapi.Schema{Ref: "#/components/schemas/" + name} }
+	inline := pet(openapi.Int32Schema())
+	bodyPointer := "/paths/~1pets/post/requestBody/content/application~1json/schema"
+
+	log := Compare(document(openapi.Int32Schema(), ref("Pet")), document(openapi.StringSchema(""), ref("Pet")))
+	if len(log.Changes) != 1 {
+		t.Fatalf("Expected 1 change, got %v", log.Changes)
+	}
+	if c := log.Changes[0]; c.Kind != TypeChanged || !c.Breaking || c.Pointer != "/paths/~1pets/post/parameters/0/schema/type" {
+		t.Errorf("Expected a breaking parameter type change, got %v at %s", c, c.Pointer)
+	}
+
+	tests := []struct {
+		name     string
+		old, new *openapi.Schema
+		kinds    []Kind
+		breaking bool
+	}{
+		{"reference inlined unchanged", ref("Pet"), pet(openapi.StringSchema("")), []Kind{SchemaRefChanged}, false},
+		{"reference inlined with changes", ref("Pet"), inline, []Kind{SchemaRefChanged, TypeChanged}, true},
+		{"inline replaced by a reference", inline, ref("Pet"), []Kind{SchemaRefChanged, TypeChanged}, true},
+		{"reference to another component", ref("Pet"), ref("NewPet"), []Kind{SchemaRefChanged}, false},
+		{"reference to a missing component", ref("Pet"), ref("Missing"), []Kind{SchemaRefChanged}, true},
+	}
+	for _, tt := range tests {
+		log := Compare(document(openapi.Int32Schema(), tt.old), document(openapi.Int32Schema(), tt.new))
+		if len(log.Changes) != len(tt.kinds) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.kinds, log.Changes)
+			continue
+		}
+		for i, kind := range tt.kinds {
+			if log.Changes[i].Kind != kind {
+				t.Errorf("%s: Expected %s, got %v", tt.name, kind, log.Changes[i])
+			}
+		}
+		if c := log.Changes[0]; c.Pointer != bodyPointer || log.HasBreaking() != tt.breaking {
+			t.Errorf("%s: Expected the change at %s (breaking %v), got %v at %s", tt.name, bodyPointer, tt.breaking, log.Changes, c.Pointer)
+		}
+	}
+
+	if log := CompareSchemas(ref("Pet"), inline); len(log.Changes) != 1 || log.Changes[0].Kind != SchemaRefChanged || !log.Changes[0].Breaking {
+		t.Errorf("Expected a breaking reference change without documents, got %v", log.Changes)
+	}
+}
+
+func TestCompareReferencedResponses(t *testing.T) {
+	document := func(mediaTypes ...string) *openapi.Document {
+		doc := baseDocument()
+		components := doc.AddComponents()
+		response := openapi.NewResponse("Pet")
+		body := openapi.NewRequestBody("Pet", false)
+		for _, name := range mediaTypes {
+			response = response.WithContent(name, openapi.MediaType{Schema: openapi.StringSchema("")})
+			body = body.WithContent(name, openapi.MediaType{Schema: openapi.StringSchema("")})
+		}
+		components.Responses["Pet"] = response
+		components.RequestBodies["Pet"] = body
+		op := openapi.NewOperation("updatePet", "Update pet", "").
+			WithResponse("200", "", openapi.Response{Ref: openapi.NewReference("#/components/responses/Pet")})
+		op.RequestBody = &openapi.RequestBody{Ref: openapi.NewReference("#/components/requestBodies/Pet")}
+		doc.AddOperation("/pets", "PUT", op)
+		return doc
+	}
+
+	if log := Compare(document("application/json"), document("application/json")); len(log.Changes) != 0 {
+		t.Errorf("Expected no changes, got %v", log.Changes)
+	}
+	log := Compare(document("application/json", "text/plain"), document("application/json"))
+	if len(log.Changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %v", log.Changes)
+	}
+	if c := log.Changes[0]; c.Kind != RequestMediaTypeRemoved || !c.Breaking || c.Pointer != "/paths/~1pets/put/requestBody/content/text~1plain" {
+		t.Errorf("Expected the request media type removed, got %v at %s", c, c.Pointer)
+	}
+	if c := log.Changes[1]; c.Kind != ResponseMediaTypeRemoved || !c.Breaking || c.Pointer != "/paths/~1pets/put/responses/200/content/text~1plain" {
+		t.Errorf("Expected the response media type removed, got %v at %s", c, c.Pointer)
+	}
+}
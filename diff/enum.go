@@ -0,0 +1,162 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/nyxstack/openapi"
+)
+
+// Enum change kinds
+const (
+	EnumValueAdded   Kind = "enum-value-added"
+	EnumValueRemoved Kind = "enum-value-removed"
+)
+
+// schemaContext tells which way the data described by a schema flows, which
+// decides whether an enum change is breaking
+type schemaContext int
+
+const (
+	// requestContext schemas describe data clients send
+	requestContext schemaContext = iota
+	// responseContext schemas describe data clients receive
+	responseContext
+	// sharedContext schemas may be used either way, like components
+	sharedContext
+)
+
+// CompareSchemas reports the type changes and enum values added to and removed
+// from a schema and its inline subschemas, and the properties removed or newly
+// required. Removing a value or a property, requiring a property and changing
+// a type are breaking, as the schema may describe data clients send or receive.
+// References can't be resolved without their documents, so differing ones are
+// reported as breaking.
+func CompareSchemas(oldSchema, newSchema *openapi.Schema) *Changelog {
+	c := &comparer{log: &Changelog{}}
+	c.compareSchemas("", "", "", oldSchema, newSchema, sharedContext, 0)
+	return c.log
+}
+
+// compareComponentSchemas compares schemas registered under the same name
+func (c *comparer) compareComponentSchemas() {
+	if c.oldDoc.Components == nil || c.newDoc.Components == nil {
+		return
+	}
+	for _, name := range unionKeys(c.oldDoc.Components.Schemas, c.newDoc.Components.Schemas) {
+		oldSchema, newSchema := c.oldDoc.Components.Schemas[name], c.newDoc.Components.Schemas[name]
+		if oldSchema != nil && newSchema != nil {
+			c.compareSchemas("/components/schemas/"+escape(name), "", "", oldSchema, newSchema, sharedContext, 0)
+		}
+	}
+}
+
+// compareSchemas compares the types, enums and properties of two schemas and
+// of their inline subschemas. Identical references are skipped, as component
+// schemas are compared on their own; differing ones are reported and the
+// schemas they resolve to compared.
+func (c *comparer) compareSchemas(pointer, path, method string, oldSchema, newSchema *openapi.Schema, context schemaContext, depth int) {
+	if oldSchema == nil || newSchema == nil || (oldSchema.Ref != "" && oldSchema.Ref == newSchema.Ref) || depth > 32 {
+		return
+	}
+	if oldSchema.Ref != newSchema.Ref {
+		var resolved bool
+		if oldSchema, newSchema, resolved = c.compareRefs(pointer, path, method, oldSchema, newSchema); !resolved {
+			return
+		}
+	}
+
+	c.compareTypes(pointer, path, method, oldSchema, newSchema, context)
+
+	if len(oldSchema.Enum) > 0 && len(newSchema.Enum) > 0 {
+		for _, value := range oldSchema.Enum {
+			if !containsValue(newSchema.Enum, value) {
+				c.add(Change{Kind: EnumValueRemoved, Path: path, Method: method, Pointer: pointer + "/enum",
+					Breaking: context != responseContext,
+					Message:  fmt.Sprintf("enum value %v was removed from %s", value, location(pointer, path, method)), Old: value})
+			}
+		}
+		for _, value := range newSchema.Enum {
+			if !containsValue(oldSchema.Enum, value) {
+				c.add(Change{Kind: EnumValueAdded, Path: path, Method: method, Pointer: pointer + "/enum",
+					Message: fmt.Sprintf("enum value %v was added to %s", value, location(pointer, path, method)), New: value})
+			}
+		}
+	}
+
+	c.compareProperties(pointer, path, method, oldSchema, newSchema, context)
+
+	c.compareSchemas(pointer+"/items", path, method, oldSchema.Items, newSchema.Items, context, depth+1)
+	c.compareSchemas(pointer+"/not", path, method, oldSchema.Not, newSchema.Not, context, depth+1)
+	for _, name := range unionKeys(oldSchema.Properties, newSchema.Properties) {
+		c.compareSchemas(pointer+"/properties/"+escape(name), path, method,
+			oldSchema.Properties[name], newSchema.Properties[name], context, depth+1)
+	}
+	for _, keyword := range []struct {
+		name     string
+		old, new []*openapi.Schema
+	}{
+		{"allOf", oldSchema.AllOf, newSchema.AllOf},
+		{"anyOf", oldSchema.AnyOf, newSchema.AnyOf},
+		{"oneOf", oldSchema.OneOf, newSchema.OneOf},
+	} {
+		for i := 0; i < len(keyword.old) && i < len(keyword.new); i++ {
+			c.compareSchemas(fmt.Sprintf("%s/%s/%d", pointer, keyword.name, i), path, method,
+				keyword.old[i], keyword.new[i], context, depth+1)
+		}
+	}
+}
+
+// compareContent reports the media types removed from a request body or
+// response, described by what, and compares the schemas of media types
+// present in both versions
+func (c *comparer) compareContent(pointer, path, method, what string, oldContent, newContent map[string]openapi.MediaType, context schemaContext) {
+	for _, name := range unionKeys(oldContent, newContent) {
+		oldMT, inOld := oldContent[name]
+		newMT, inNew := newContent[name]
+		mtPointer := pointer + "/content/" + escape(name)
+		switch {
+		case inOld && inNew:
+			c.compareSchemas(mtPointer+"/schema", path, method, oldMT.Schema, newMT.Schema, context, 0)
+		case inOld:
+			kind := ResponseMediaTypeRemoved
+			if context == requestContext {
+				kind = RequestMediaTypeRemoved
+			}
+			c.add(Change{Kind: kind, Path: path, Method: method, Pointer: mtPointer, Breaking: true,
+				Message: fmt.Sprintf("media type %s was removed from %s", name, what), Old: oldMT})
+		}
+	}
+}
+
+// location describes where a schema sits for change messages
+func location(pointer, path, method string) string {
+	switch {
+	case method != "":
+		return fmt.Sprintf("%s %s (%s)", method, path, pointer)
+	case pointer == "":
+		return "the schema"
+	}
+	return pointer
+}
+
+// containsValue reports whether an enum contains a value, comparing numbers by value
+func containsValue(enum []interface{}, value interface{}) bool {
+	for _, candidate := range enum {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+		if isNumber(candidate) && isNumber(value) && fmt.Sprint(candidate) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package diff
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nyxstack/openapi"
+)
+
+// Rule decides whether changes it matches are allowed. Rules let a team
+// override the default classification, e.g. to forbid a change classified as
+// non-breaking or to permit a breaking one under conditions.
+type Rule struct {
+	// Name identifies the rule in verdicts
+	Name string
+	// Kind restricts the rule to one kind of change; empty matches every kind
+	Kind Kind
+	// Match further restricts the rule; nil matches every change of Kind
+	Match func(Change) bool
+	// Allow tells whether matched changes are allowed or rejected
+	Allow  bool
+	Reason string
+}
+
+// Allow returns a rule allowing every change of a kind
+func Allow(kind Kind, reason string) Rule {
+	return Rule{Name: "allow-" + string(kind), Kind: kind, Allow: true, Reason: reason}
+}
+
+// Deny returns a rule rejecting every change of a kind
+func Deny(kind Kind, reason string) Rule {
+	return Rule{Name: "deny-" + string(kind), Kind: kind, Reason: reason}
+}
+
+// AllowRemovalAfterSunset returns a rule allowing the removal of deprecated
+// operations whose x-sunset date is before now. A removed path qualifies when
+// all of its operations do.
+func AllowRemovalAfterSunset(now time.Time) Rule {
+	sunset := func(op *openapi.Operation) bool {
+		date, ok := op.Sunset()
+		return op.Deprecated && ok && date.Before(now)
+	}
+	return Rule{
+		Name:   "allow-removal-after-sunset",
+		Allow:  true,
+		Reason: "deprecated operation removed after its sunset date",
+		Match: func(c Change) bool {
+			switch old := c.Old.(type) {
+			case *openapi.Operation:
+				return c.Kind == OperationRemoved && sunset(old)
+			case openapi.PathItem:
+				found := false
+				for _, method := range methods {
+					if op := old.Operation(method); op != nil {
+						if !sunset(op) {
+							return false
+						}
+						found = true
+					}
+				}
+				return c.Kind == PathRemoved && found
+			}
+			return false
+		},
+	}
+}
+
+// Policy evaluates a changelog against rules declared by a team. The first
+// rule matching a change decides it; changes no rule matches are allowed
+// unless they are breaking.
+type Policy struct {
+	Rules []Rule
+}
+
+// NewPolicy creates a policy from rules, in order of precedence
+func NewPolicy(rules ...Rule) *Policy {
+	return &Policy{Rules: rules}
+}
+
+// Verdict is the decision of a policy on one change
+type Verdict struct {
+	Change  Change
+	Allowed bool
+	// Rule is the name of the deciding rule, empty when the default applied
+	Rule   string
+	Reason string
+}
+
+// String formats the verdict for logs and CI output
+func (v Verdict) String() string {
+	outcome := "allowed"
+	if !v.Allowed {
+		outcome = "rejected"
+	}
+	return fmt.Sprintf("%s: %s (%s)", outcome, v.Change.Message, v.Reason)
+}
+
+// Result is the outcome of evaluating a changelog against a policy
+type Result struct {
+	Passed   bool
+	Verdicts []Verdict
+}
+
+// Violations returns the verdicts rejecting a change
+func (r Result) Violations() []Verdict {
+	var violations []Verdict
+	for _, v := range r.Verdicts {
+		if !v.Allowed {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// Evaluate decides every change of the changelog. The result passes when no
+// change is rejected.
+func (p *Policy) Evaluate(log *Changelog) Result {
+	result := Result{Passed: true}
+	for _, change := range log.Changes {
+		verdict := p.decide(change)
+		if !verdict.Allowed {
+			result.Passed = false
+		}
+		result.Verdicts = append(result.Verdicts, verdict)
+	}
+	return result
+}
+
+func (p *Policy) decide(change Change) Verdict {
+	for _, rule := range p.Rules {
+		if rule.Kind != "" && rule.Kind != change.Kind {
+			continue
+		}
+		if rule.Match != nil && !rule.Match(change) {
+			continue
+		}
+		return Verdict{Change: change, Allowed: rule.Allow, Rule: rule.Name, Reason: rule.Reason}
+	}
+	if change.Breaking {
+		return Verdict{Change: change, Reason: "breaking change"}
+	}
+	return Verdict{Change: change, Allowed: true, Reason: "non-breaking change"}
+}
@@ -0,0 +1,112 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/nyxstack/openapi"
+)
+
+// Schema change kinds
+const (
+	TypeChanged                  Kind = "type-changed"
+	SchemaRefChanged             Kind = "schema-ref-changed"
+	ResponsePropertyRemoved      Kind = "response-property-removed"
+	RequiredRequestPropertyAdded Kind = "required-request-property-added"
+)
+
+// compareTypes reports a change of the types a schema allows
+func (c *comparer) compareTypes(pointer, path, method string, oldSchema, newSchema *openapi.Schema, context schemaContext) {
+	if len(oldSchema.Type) == 0 || len(newSchema.Type) == 0 || sameTypes(oldSchema.Type, newSchema.Type) {
+		return
+	}
+	c.add(Change{Kind: TypeChanged, Path: path, Method: method, Pointer: pointer + "/type",
+		Breaking: typeChangeBreaking(oldSchema.Type, newSchema.Type, context),
+		Message:  fmt.Sprintf("type of %s changed from %s to %s", location(pointer, path, method), oldSchema.Type, newSchema.Type),
+		Old:      oldSchema, New: newSchema})
+}
+
+// compareRefs reports a schema pointing at another component, or switching
+// between a reference and an inline schema, and returns the schemas the
+// references resolve to so their contents can be compared. The change is
+// breaking when a side doesn't resolve, as the schemas can't be compared.
+func (c *comparer) compareRefs(pointer, path, method string, oldSchema, newSchema *openapi.Schema) (*openapi.Schema, *openapi.Schema, bool) {
+	describe := func(s *openapi.Schema) string {
+		if s.Ref == "" {
+			return "an inline schema"
+		}
+		return s.Ref
+	}
+	oldResolved, newResolved := resolveSchema(c.oldDoc, oldSchema), resolveSchema(c.newDoc, newSchema)
+	resolved := oldResolved.Ref == "" && newResolved.Ref == ""
+	c.add(Change{Kind: SchemaRefChanged, Path: path, Method: method, Pointer: pointer, Breaking: !resolved,
+		Message: fmt.Sprintf("schema of %s changed from %s to %s", location(pointer, path, method), describe(oldSchema), describe(newSchema)),
+		Old:     oldSchema, New: newSchema})
+	return oldResolved, newResolved, resolved
+}
+
+// resolveSchema follows a reference to a schema component. A reference that
+// doesn't resolve, or a schema compared without its document, is returned as is.
+func resolveSchema(doc *openapi.Document, schema *openapi.Schema) *openapi.Schema {
+	if doc == nil || schema.Ref == "" {
+		return schema
+	}
+	if resolved, err := doc.ResolveRef(schema.Ref); err == nil {
+		if component, ok := resolved.(*openapi.Schema); ok && component != nil {
+			return component
+		}
+	}
+	return schema
+}
+
+// compareProperties reports the properties removed from a schema clients
+// receive and the properties a schema clients send newly requires. Shared
+// schemas count both ways.
+func (c *comparer) compareProperties(pointer, path, method string, oldSchema, newSchema *openapi.Schema, context schemaContext) {
+	where := location(pointer, path, method)
+	for _, name := range unionKeys(oldSchema.Properties, newSchema.Properties) {
+		oldProp, newProp := oldSchema.Properties[name], newSchema.Properties[name]
+		propPointer := pointer + "/properties/" + escape(name)
+		if newProp == nil && context != requestContext {
+			c.add(Change{Kind: ResponsePropertyRemoved, Path: path, Method: method, Pointer: propPointer, Breaking: true,
+				Message: fmt.Sprintf("property %q was removed from %s", name, where), Old: oldProp})
+		}
+
+		if context == responseContext || !slices.Contains(newSchema.Required, name) || slices.Contains(oldSchema.Required, name) {
+			continue
+		}
+		message := fmt.Sprintf("property %q of %s became required", name, where)
+		if oldProp == nil {
+			message = fmt.Sprintf("required property %q was added to %s", name, where)
+		}
+		c.add(Change{Kind: RequiredRequestPropertyAdded, Path: path, Method: method, Pointer: propPointer, Breaking: true,
+			Message: message, Old: oldProp, New: newProp})
+	}
+}
+
+// sameTypes reports whether two type lists hold the same types in any order
+func sameTypes(a, b openapi.Types) bool {
+	return containsTypes(a, b) && containsTypes(b, a)
+}
+
+// containsTypes reports whether every type of b is in a
+func containsTypes(a, b openapi.Types) bool {
+	for _, t := range b {
+		if !a.Is(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// typeChangeBreaking tells whether a type change breaks clients: widening
+// is safe for data they send and narrowing for data they receive
+func typeChangeBreaking(oldTypes, newTypes openapi.Types, context schemaContext) bool {
+	switch context {
+	case requestContext:
+		return !containsTypes(newTypes, oldTypes)
+	case responseContext:
+		return !containsTypes(oldTypes, newTypes)
+	}
+	return true
+}
@@ -2,12 +2,33 @@ package openapi
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 )
 
 // ExternalDocs represents external documentation
 type ExternalDocs struct {
-	Description string `json:"description,omitempty"`
-	URL         string `json:"url"`
+	Description string                 `json:"description,omitempty"`
+	URL         string                 `json:"url"`
+	Extensions  map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (e ExternalDocs) MarshalJSON() ([]byte, error) {
+	type externalDocs ExternalDocs
+	return marshalWithExtensions(externalDocs(e), e.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (e *ExternalDocs) UnmarshalJSON(data []byte) error {
+	type externalDocs ExternalDocs
+	if err := json.Unmarshal(data, (*externalDocs)(e)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	e.Extensions = ext
+	return err
 }
 
 // SecurityRequirement represents a security requirement
@@ -15,22 +36,105 @@ type SecurityRequirement map[string][]string
 
 // Document represents the root OpenAPI v3 document
 type Document struct {
-	OpenAPI           string                `json:"openapi"`
-	Info              Info                  `json:"info"`
-	JSONSchemaDialect string                `json:"jsonSchemaDialect,omitempty"`
-	Servers           []Server              `json:"servers,omitempty"`
-	Paths             map[string]PathItem   `json:"paths"`
-	Webhooks          map[string]PathItem   `json:"webhooks,omitempty"`
-	Components        *Components           `json:"components,omitempty"`
-	Security          []SecurityRequirement `json:"security,omitempty"`
-	Tags              []Tag                 `json:"tags,omitempty"`
-	ExternalDocs      *ExternalDocs         `json:"externalDocs,omitempty"`
+	OpenAPI           string                 `json:"openapi"`
+	Info              Info                   `json:"info"`
+	JSONSchemaDialect string                 `json:"jsonSchemaDialect,omitempty"`
+	Servers           []Server               `json:"servers,omitempty"`
+	Paths             map[string]PathItem    `json:"paths"`
+	Webhooks          map[string]PathItem    `json:"webhooks,omitempty"`
+	Components        *Components            `json:"components,omitempty"`
+	Security          []SecurityRequirement  `json:"security,omitempty"`
+	Tags              []Tag                  `json:"tags,omitempty"`
+	ExternalDocs      *ExternalDocs          `json:"externalDocs,omitempty"`
+	Extensions        map[string]interface{} `json:"-"`
+
+	frozen *freezeState
+	shared *cowState
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification
+// extensions. Schemas are written in the form of the document's OpenAPI
+// version, see SetVersion; schemas using the other form are converted on a copy.
+// Empty paths are left out of OpenAPI 3.1 documents, which don't require them.
+func (d Document) MarshalJSON() ([]byte, error) {
+	type document Document
+	if d.hasForeignSchemaForms() {
+		data, err := json.Marshal(document(d))
+		if err != nil {
+			return nil, err
+		}
+		var converted Document
+		if err := json.Unmarshal(data, &converted); err != nil {
+			return nil, err
+		}
+		converted.convertSchemaForms()
+		converted.Extensions = d.Extensions
+		d = converted
+	}
+	if len(d.Paths) == 0 && isOpenAPI31(d.OpenAPI) {
+		return marshalWithExtensions(struct {
+			document
+			Paths map[string]PathItem `json:"paths,omitempty"`
+		}{document: document(d)}, d.Extensions)
+	}
+	return marshalWithExtensions(document(d), d.Extensions)
+}
+
+// isOpenAPI31 reports whether an OpenAPI version is 3.1 or later
+func isOpenAPI31(version string) bool {
+	return !strings.HasPrefix(version, "3.0") && !strings.HasPrefix(version, "2.")
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (d *Document) UnmarshalJSON(data []byte) error {
+	type document Document
+	if err := json.Unmarshal(data, (*document)(d)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	d.Extensions = ext
+	return err
+}
+
+// FromJSON loads a document from its JSON form, so that existing specifications
+// can be modified and marshaled again
+func FromJSON(data []byte) (*Document, error) {
+	var d Document
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("openapi: parsing document: %w", err)
+	}
+	if d.OpenAPI == "" {
+		return nil, errors.New("openapi: parsing document: missing openapi version")
+	}
+	if d.Paths == nil {
+		d.Paths = make(map[string]PathItem)
+	}
+	if d.Webhooks == nil {
+		d.Webhooks = make(map[string]PathItem)
+	}
+	if d.Tags == nil {
+		d.Tags = []Tag{}
+	}
+	if d.Components != nil {
+		d.Components.initMaps()
+	}
+	return &d, nil
+}
+
+// FromYAML loads a document from its YAML form. The YAML is converted to JSON
+// first, so that documents load exactly as through FromJSON.
+func FromYAML(data []byte) (*Document, error) {
+	converted, err := yamlToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: parsing YAML document: %w", err)
+	}
+	return FromJSON(converted)
 }
 
 // NewDocument creates a new OpenAPI document with basic info
 func NewDocument(title, version string) *Document {
 	return &Document{
-		OpenAPI: "3.1.0",
+		OpenAPI: string(V31),
 		Info: Info{
 			Title:   title,
 			Version: version,
@@ -43,13 +147,28 @@ func NewDocument(title, version string) *Document {
 
 // WithInfo sets additional info for the OpenAPI document
 func (d *Document) WithInfo(description, termsOfService string) *Document {
+	if !d.mutable("WithInfo") {
+		return d
+	}
 	d.Info.Description = description
 	d.Info.TermsOfService = termsOfService
 	return d
 }
 
+// WithSummary sets a short summary of the API, shown next to its title
+func (d *Document) WithSummary(summary string) *Document {
+	if !d.mutable("WithSummary") {
+		return d
+	}
+	d.Info.Summary = summary
+	return d
+}
+
 // WithContact adds contact information to the OpenAPI document
 func (d *Document) WithContact(name, url, email string) *Document {
+	if !d.mutable("WithContact") {
+		return d
+	}
 	d.Info.Contact = &Contact{
 		Name:  name,
 		URL:   url,
@@ -60,6 +179,9 @@ func (d *Document) WithContact(name, url, email string) *Document {
 
 // WithLicense adds license information to the OpenAPI document
 func (d *Document) WithLicense(name, url string) *Document {
+	if !d.mutable("WithLicense") {
+		return d
+	}
 	d.Info.License = &License{
 		Name: name,
 		URL:  url,
@@ -67,8 +189,24 @@ func (d *Document) WithLicense(name, url string) *Document {
 	return d
 }
 
+// WithLicenseSPDX adds license information by SPDX identifier, such as
+// "MIT", which also serves as the license name
+func (d *Document) WithLicenseSPDX(identifier string) *Document {
+	if !d.mutable("WithLicenseSPDX") {
+		return d
+	}
+	d.Info.License = &License{
+		Name:       identifier,
+		Identifier: identifier,
+	}
+	return d
+}
+
 // AddServer adds a server to the document
 func (d *Document) AddServer(url, description string) *Document {
+	if !d.mutable("AddServer") {
+		return d
+	}
 	d.Servers = append(d.Servers, Server{
 		URL:         url,
 		Description: description,
@@ -79,6 +217,9 @@ func (d *Document) AddServer(url, description string) *Document {
 
 // AddTag adds a tag to the document
 func (d *Document) AddTag(name, description string) *Document {
+	if !d.mutable("AddTag") {
+		return d
+	}
 	d.Tags = append(d.Tags, Tag{
 		Name:        name,
 		Description: description,
@@ -88,6 +229,9 @@ func (d *Document) AddTag(name, description string) *Document {
 
 // AddTagWithDocs adds a tag with external documentation
 func (d *Document) AddTagWithDocs(name, description, docsURL, docsDescription string) *Document {
+	if !d.mutable("AddTagWithDocs") {
+		return d
+	}
 	d.Tags = append(d.Tags, Tag{
 		Name:        name,
 		Description: description,
@@ -101,6 +245,9 @@ func (d *Document) AddTagWithDocs(name, description, docsURL, docsDescription st
 
 // SetExternalDocs sets external documentation for the entire API
 func (d *Document) SetExternalDocs(url, description string) *Document {
+	if !d.mutable("SetExternalDocs") {
+		return d
+	}
 	d.ExternalDocs = &ExternalDocs{
 		URL:         url,
 		Description: description,
@@ -110,6 +257,10 @@ func (d *Document) SetExternalDocs(url, description string) *Document {
 
 // AddPath adds a path to the document with an empty PathItem
 func (d *Document) AddPath(path string) *PathItem {
+	if !d.mutable("AddPath") {
+		return &PathItem{}
+	}
+	d.ownPaths()
 	if d.Paths == nil {
 		d.Paths = make(map[string]PathItem)
 	}
@@ -120,9 +271,6 @@ func (d *Document) AddPath(path string) *PathItem {
 
 // GetPath gets a path item or creates it if it doesn't exist
 func (d *Document) GetPath(path string) *PathItem {
-	if d.Paths == nil {
-		d.Paths = make(map[string]PathItem)
-	}
 	if pathItem, exists := d.Paths[path]; exists {
 		return &pathItem
 	}
@@ -131,6 +279,10 @@ func (d *Document) GetPath(path string) *PathItem {
 
 // SetPath sets a complete path item
 func (d *Document) SetPath(path string, pathItem PathItem) *Document {
+	if !d.mutable("SetPath") {
+		return d
+	}
+	d.ownPaths()
 	if d.Paths == nil {
 		d.Paths = make(map[string]PathItem)
 	}
@@ -140,51 +292,51 @@ func (d *Document) SetPath(path string, pathItem PathItem) *Document {
 
 // AddOperation adds an operation to a specific path and method
 func (d *Document) AddOperation(path, method string, operation Operation) *Document {
+	if !d.mutable("AddOperation") {
+		return d
+	}
+	d.ownPaths()
 	pathItem := d.GetPath(path)
+	pathItem.SetOperation(method, &operation)
+	d.Paths[path] = *pathItem
+	return d
+}
 
-	switch method {
-	case "GET":
-		pathItem.Get = &operation
-	case "POST":
-		pathItem.Post = &operation
-	case "PUT":
-		pathItem.Put = &operation
-	case "DELETE":
-		pathItem.Delete = &operation
-	case "PATCH":
-		pathItem.Patch = &operation
-	case "HEAD":
-		pathItem.Head = &operation
-	case "OPTIONS":
-		pathItem.Options = &operation
-	case "TRACE":
-		pathItem.Trace = &operation
+// AddWebhook adds an operation to a webhook, a request the API sends to
+// consumers rather than one it receives. Webhooks require OpenAPI 3.1.
+func (d *Document) AddWebhook(name, method string, operation Operation) *Document {
+	if !d.mutable("AddWebhook") {
+		return d
 	}
-
-	d.Paths[path] = *pathItem
+	d.ownWebhooks()
+	if d.Webhooks == nil {
+		d.Webhooks = make(map[string]PathItem)
+	}
+	item := d.Webhooks[name]
+	item.SetOperation(method, &operation)
+	d.Webhooks[name] = item
 	return d
 }
 
 // AddComponents adds or updates components section
 func (d *Document) AddComponents() *Components {
+	if !d.mutable("AddComponents") {
+		// Callers write into the maps, so hand out a detached set
+		return NewComponents()
+	}
+	d.ownComponents()
 	if d.Components == nil {
-		d.Components = &Components{
-			Schemas:         make(map[string]*Schema),
-			Responses:       make(map[string]Response),
-			Parameters:      make(map[string]Parameter),
-			Examples:        make(map[string]Example),
-			RequestBodies:   make(map[string]RequestBody),
-			Headers:         make(map[string]Header),
-			SecuritySchemes: make(map[string]SecurityScheme),
-			Links:           make(map[string]Link),
-			Callbacks:       make(map[string]Callback),
-		}
+		d.Components = &Components{}
 	}
+	d.Components.initMaps()
 	return d.Components
 }
 
 // AddSchema adds a schema to components
 func (d *Document) AddSchema(name string, schema Schema) *Document {
+	if !d.mutable("AddSchema") {
+		return d
+	}
 	components := d.AddComponents()
 	components.Schemas[name] = &schema
 	return d
@@ -192,13 +344,32 @@ func (d *Document) AddSchema(name string, schema Schema) *Document {
 
 // AddSecurityScheme adds a security scheme to components
 func (d *Document) AddSecurityScheme(name string, scheme SecurityScheme) *Document {
+	if !d.mutable("AddSecurityScheme") {
+		return d
+	}
 	components := d.AddComponents()
 	components.SecuritySchemes[name] = scheme
 	return d
 }
 
+// WithExtension sets a document-level specification extension; the name must start with "x-"
+func (d *Document) WithExtension(name string, value interface{}) *Document {
+	if !d.mutable("WithExtension") {
+		return d
+	}
+	d.ownExtensions()
+	if d.Extensions == nil {
+		d.Extensions = make(map[string]interface{})
+	}
+	d.Extensions[name] = value
+	return d
+}
+
 // AddSecurityRequirement adds a security requirement at document level
 func (d *Document) AddSecurityRequirement(requirement SecurityRequirement) *Document {
+	if !d.mutable("AddSecurityRequirement") {
+		return d
+	}
 	d.Security = append(d.Security, requirement)
 	return d
 }
@@ -208,6 +379,11 @@ func (d *Document) ToJSON() ([]byte, error) {
 	return json.MarshalIndent(d, "", "  ")
 }
 
+// ToYAML converts the OpenAPI document to block-style YAML
+func (d *Document) ToYAML() ([]byte, error) {
+	return marshalYAML(d)
+}
+
 // ToJSONString converts the OpenAPI document to JSON string
 func (d *Document) ToJSONString() (string, error) {
 	data, err := d.ToJSON()
@@ -216,3 +392,15 @@ func (d *Document) ToJSONString() (string, error) {
 	}
 	return string(data), nil
 }
+
+// FindOperation looks up an operation by its operationId and returns it along
+// with the path and method it is registered under. The operation is nil when
+// no operation has the given ID.
+func (d *Document) FindOperation(operationID string) (path, method string, operation *Operation) {
+	d.walkOperations(func(p, m string, op *Operation) {
+		if operation == nil && op.OperationID == operationID {
+			path, method, operation = p, m, op
+		}
+	})
+	return path, method, operation
+}
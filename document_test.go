@@ -1,8 +1,19 @@
 package openapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestNewDocument(t *testing.T) {
@@ -50,6 +61,583 @@ func TestDocumentMarshalJSON(t *testing.T) {
 	}
 }
 
+func TestFromJSON(t *testing.T) {
+	input := `{
+  "openapi": "3.1.0",
+  "info": {"title": "Pet API", "version": "1.0.0"},
+  "paths": {
+    "/pets/{petId}": {
+      "get": {
+        "operationId": "getPet",
+        "parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "integer"}}],
+        "responses": {
+          "200": {
+            "description": "A pet",
+            "content": {"application/json": {
+              "schema": {"$ref": "#/components/schemas/Pet"},
+              "examples": {"rex": {"value": {"name": "Rex"}, "x-scenario": "found"}}
+            }}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "properties": {
+          "name": {"type": ["string", "null"]},
+          "age": {"type": "integer", "exclusiveMinimum": 0},
+          "tags": {"type": "object", "additionalProperties": {"type": "string"}}
+        }
+      }
+    }
+  }
+}`
+	doc, err := FromJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("Error parsing document: %v", err)
+	}
+	_, _, op := doc.FindOperation("getPet")
+	if op == nil || !op.Parameters[0].Schema.Type.Is("integer") {
+		t.Fatalf("Expected the getPet operation to be loaded, got %+v", op)
+	}
+	if ext := op.Responses["200"].Content["application/json"].Examples["rex"].Extensions["x-scenario"]; ext != "found" {
+		t.Errorf("Expected the example extension to be loaded, got %v", ext)
+	}
+	pet := doc.Components.Schemas["Pet"]
+	if name := pet.Properties["name"]; !name.Type.Is("string") || !name.AllowsNull() {
+		t.Errorf("Expected a nullable string name, got %+v", name)
+	}
+	if min, exclusive := pet.Properties["age"].LowerBound(); !exclusive || min == nil || *min != 0 {
+		t.Errorf("Expected an exclusive minimum of 0, got %+v", pet.Properties["age"])
+	}
+	if tags := pet.Properties["tags"]; tags.AdditionalProperties == nil || tags.AdditionalProperties.Schema == nil {
+		t.Errorf("Expected a schema for additional properties, got %+v", tags.AdditionalProperties)
+	}
+
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", ""))
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Error marshaling document: %v", err)
+	}
+	again, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("Error parsing the marshaled document: %v", err)
+	}
+	if len(again.Paths) != 2 {
+		t.Errorf("Expected both paths after a roundtrip, got %v", sortedKeys(again.Paths))
+	}
+
+	partial := `{"openapi": "3.1.0", "info": {"title": "Pet API", "version": "1.0.0"}, "paths": {},
+		"components": {"securitySchemes": {"bearer": {"type": "http", "scheme": "bearer"}}}}`
+	doc, err = FromJSON([]byte(partial))
+	if err != nil {
+		t.Fatalf("Error parsing document: %v", err)
+	}
+	type loadedPet struct {
+		Name string `json:"name"`
+	}
+	SchemaOf[loadedPet](doc.AddSchema("Owner", Schema{Type: Types{"object"}}))
+	doc.Components.Responses["NotFound"] = NewResponse("Not found")
+	data, err = json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Error marshaling document: %v", err)
+	}
+	for _, want := range []string{`"bearer"`, `"Owner"`, `"LoadedPet"`, `"NotFound"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected the marshaled document to contain %s, got %s", want, data)
+		}
+	}
+	if strings.Contains(string(data), `"links"`) {
+		t.Errorf("Expected empty component maps to be omitted, got %s", data)
+	}
+
+	doc, err = FromJSON([]byte(`{"openapi": "3.1.0", "info": {"title": "Pet API", "version": "1.0.0"}, "paths": {},
+		"components": {"schemas": {"Pet": {"type": "object"}}}}`))
+	if err != nil {
+		t.Fatalf("Error parsing document: %v", err)
+	}
+	doc.AddSecurityScheme("bearer", SecurityScheme{Type: "http", Scheme: "bearer"})
+	if len(doc.Components.SecuritySchemes) != 1 || len(doc.Components.Schemas) != 1 {
+		t.Errorf("Expected the security scheme added next to the schema, got %+v", doc.Components)
+	}
+
+	if _, err := FromJSON([]byte(`{"info": {"title": "Pet API"}}`)); err == nil {
+		t.Error("Expected an error for a document without an openapi version")
+	}
+}
+
+func TestExtensionsRoundTrip(t *testing.T) {
+	input := `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "Pet API", "version": "1.0.0", "x-logo": {"url": "logo.png"},
+    "contact": {"name": "Team", "x-slack": "#pets"},
+    "license": {"name": "MIT", "x-spdx": "MIT"}
+  },
+  "servers": [{"url": "https://{region}.example.com", "x-env": "prod",
+    "variables": {"region": {"default": "eu", "x-internal": true}}}],
+  "tags": [{"name": "pets", "x-display-name": "Pets"}],
+  "externalDocs": {"url": "https://docs.example.com", "x-version": 2},
+  "paths": {
+    "/pets": {
+      "x-path-owner": "pets",
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {
+          "x-body": 1,
+          "content": {"application/json": {"schema": {"type": "object", "xml": {"name": "pet", "x-xml": true}}, "x-media": "a",
+            "encoding": {"photo": {"contentType": "image/png", "x-encoding": "b"}}}}
+        },
+        "responses": {
+          "201": {
+            "description": "Created",
+            "x-response": "c",
+            "headers": {"Location": {"schema": {"type": "string"}, "x-header": "d"}},
+            "links": {"self": {"operationId": "createPet", "x-link": "e"}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "x-components": "f",
+    "schemas": {"Pet": {"oneOf": [{"type": "object"}], "discriminator": {"propertyName": "kind", "x-discriminator": "g"}}},
+    "securitySchemes": {
+      "oauth": {"type": "oauth2", "x-scheme": "h", "flows": {"x-flows": "i",
+        "clientCredentials": {"tokenUrl": "https://auth.example.com/token", "scopes": {}, "x-flow": "j"}}}
+    }
+  }
+}`
+	doc, err := FromJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if doc.Info.Extensions["x-logo"] == nil || doc.Components.SecuritySchemes["oauth"].Flows.ClientCredentials.Extensions["x-flow"] != "j" {
+		t.Errorf("Expected extensions to be decoded, got %v", doc.Info.Extensions)
+	}
+
+	output, err := doc.ToJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var want, got interface{}
+	json.Unmarshal([]byte(input), &want)
+	json.Unmarshal(output, &got)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected extensions to survive a round-trip, got %s", output)
+	}
+
+	response := NewResponse("OK").WithExtension("x-cache", "public")
+	data, _ := json.Marshal(response)
+	if !strings.Contains(string(data), `"x-cache":"public"`) {
+		t.Errorf("Expected x-cache in %s", data)
+	}
+}
+
+func TestOptionalPaths(t *testing.T) {
+	doc := NewDocument("Events", "1.0.0")
+	if errs := doc.Validate(); findValidationError(errs, "") == nil {
+		t.Errorf("Expected an error for a document without paths, webhooks or components, got %v", errs)
+	}
+
+	op := NewOperation("petCreated", "", "").WithResponse("200", "Received", Response{})
+	doc.Webhooks["petCreated"] = PathItem{Post: &op}
+	if errs := doc.Validate(); findValidationError(errs, "") != nil {
+		t.Errorf("Expected a webhook-only document to be valid, got %v", errs)
+	}
+	data, _ := json.Marshal(doc)
+	if strings.Contains(string(data), `"paths"`) {
+		t.Errorf("Expected empty paths to be left out, got %s", data)
+	}
+
+	decoded, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("Error decoding document: %v", err)
+	}
+	if _, ok := decoded.Webhooks["petCreated"]; !ok || decoded.Paths == nil {
+		t.Errorf("Expected the webhook and initialized paths, got %+v", decoded)
+	}
+
+	doc.OpenAPI = "3.0.3"
+	data, _ = json.Marshal(doc)
+	if !strings.Contains(string(data), `"paths":{}`) {
+		t.Errorf("Expected OpenAPI 3.0 documents to keep empty paths, got %s", data)
+	}
+}
+
+func TestSetVersion(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	legacy := NewStringSchema().WithNullable(true).WithExample("Rex")
+	modern := NullableStringSchema()
+	modern.Examples = []interface{}{"Rex", "Fido"}
+	doc.AddSchema("Legacy", legacy)
+	doc.AddSchema("Modern", *modern)
+
+	data, _ := json.Marshal(doc)
+	if !strings.Contains(string(data), `"Legacy":{"type":["string","null"],"examples":["Rex"]}`) {
+		t.Errorf("Expected the 3.1 form, got %s", data)
+	}
+	if !strings.Contains(string(data), `"Modern":{"type":["string","null"],"examples":["Rex","Fido"]}`) {
+		t.Errorf("Expected the 3.1 form to be kept, got %s", data)
+	}
+
+	doc.SetVersion(V30)
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("Expected OpenAPI version '3.0.3', got '%s'", doc.OpenAPI)
+	}
+	data, _ = json.Marshal(doc)
+	if !strings.Contains(string(data), `"Legacy":{"type":"string","nullable":true,"example":"Rex"}`) {
+		t.Errorf("Expected the 3.0 form to be kept, got %s", data)
+	}
+	if !strings.Contains(string(data), `"Modern":{"type":"string","nullable":true,"example":"Rex"}`) {
+		t.Errorf("Expected the 3.0 form, got %s", data)
+	}
+	if !doc.Components.Schemas["Modern"].Type.Is("null") || len(doc.Components.Schemas["Modern"].Examples) != 2 {
+		t.Error("Expected marshaling to leave the document untouched")
+	}
+}
+
+func TestConvertVersions(t *testing.T) {
+	legacy, err := FromJSON([]byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "Pet API", "version": "1.0.0"},
+  "paths": {},
+  "components": {"schemas": {
+    "Pet": {"type": "object", "properties": {
+      "name": {"type": "string", "nullable": true, "example": "Rex"},
+      "age": {"type": "integer", "minimum": 0, "exclusiveMinimum": true},
+      "owner": {"$ref": "#/components/schemas/Owner", "nullable": true}
+    }},
+    "Owner": {"type": "object"}
+  }},
+  "x-webhooks": {"petCreated": {"post": {"responses": {"200": {"description": "OK"}}}}}
+}`))
+	if err != nil {
+		t.Fatalf("Error loading document: %v", err)
+	}
+
+	modern, warnings, err := ConvertTo31(legacy)
+	if err != nil {
+		t.Fatalf("Error converting to 3.1: %v", err)
+	}
+	if modern.OpenAPI != "3.1.0" || len(warnings) != 0 {
+		t.Errorf("Expected a 3.1 document without warnings, got %s and %v", modern.OpenAPI, warnings)
+	}
+	pet := modern.Components.Schemas["Pet"]
+	if name := pet.Properties["name"]; !name.Type.Is("null") || name.Nullable || len(name.Examples) != 1 {
+		t.Errorf("Expected name in the 3.1 form, got %+v", name)
+	}
+	if age := pet.Properties["age"]; age.Minimum != nil || age.ExclusiveMinimum.Value == nil {
+		t.Errorf("Expected a numeric exclusive minimum, got %+v", age)
+	}
+	if owner := pet.Properties["owner"]; len(owner.AnyOf) != 2 || owner.AnyOf[0].Ref != "#/components/schemas/Owner" || !owner.AnyOf[1].Type.Is("null") {
+		t.Errorf("Expected the nullable reference as an anyOf, got %+v", owner)
+	}
+	if _, ok := modern.Webhooks["petCreated"]; !ok || modern.Extensions[ExtensionWebhooks] != nil {
+		t.Errorf("Expected the x-webhooks extension to become webhooks, got %+v", modern.Webhooks)
+	}
+	if !legacy.Components.Schemas["Pet"].Properties["name"].Nullable {
+		t.Error("Expected converting to leave the document untouched")
+	}
+
+	modern.Info.Summary = "Pets"
+	tags := NewArraySchema(StringSchema("")).WithComment("deprecated soon")
+	tags.Examples = []interface{}{[]string{"a"}, []string{"b"}}
+	modern.Components.Schemas["Tags"] = &tags
+	modern.Components.Schemas["Id"] = &Schema{Type: Types{"string", "integer"}}
+	modern.Components.Responses = map[string]Response{"NotFound": {Description: "Not found"}}
+	modern.AddOperation("/pets/{id}", "GET", NewOperation("getPet", "", "").
+		WithResponse("404", "", Response{Ref: NewReference("#/components/responses/NotFound").WithDescription("No such pet")}))
+
+	back, warnings, err := ConvertTo30(modern)
+	if err != nil {
+		t.Fatalf("Error converting to 3.0: %v", err)
+	}
+	if back.OpenAPI != "3.0.3" || back.Info.Summary != "" || len(back.Webhooks) != 0 || back.Extensions[ExtensionWebhooks] == nil {
+		t.Errorf("Expected a 3.0 document with x-webhooks, got %+v", back)
+	}
+	pet = back.Components.Schemas["Pet"]
+	if name := pet.Properties["name"]; !name.Type.Is("string") || len(name.Type) != 1 || !name.Nullable || name.Example != "Rex" {
+		t.Errorf("Expected name in the 3.0 form, got %+v", name)
+	}
+	if age := pet.Properties["age"]; age.Minimum == nil || !age.ExclusiveMinimum.Flag {
+		t.Errorf("Expected a boolean exclusive minimum, got %+v", age)
+	}
+	if id := back.Components.Schemas["Id"]; len(id.Type) != 0 || len(id.AnyOf) != 2 {
+		t.Errorf("Expected the list of types as an anyOf, got %+v", id)
+	}
+	if ref := back.Paths["/pets/{id}"].Get.Responses["404"].Ref; ref == nil || ref.Description != "" {
+		t.Errorf("Expected the reference description to be dropped, got %+v", ref)
+	}
+
+	messages := make(map[string]string)
+	for _, w := range warnings {
+		messages[w.Pointer] = w.Message
+	}
+	for _, pointer := range []string{"/info/summary", "/webhooks", "/components/schemas/Tags", "/components/schemas/Tags/examples", "/paths/~1pets~1{id}/get/responses/404"} {
+		if _, ok := messages[pointer]; !ok {
+			t.Errorf("Expected a warning at %s, got %v", pointer, warnings)
+		}
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	pet := NewObjectSchema().
+		WithProperty("name", StringSchema("")).
+		WithExtension("x-entity", "pet")
+	pet.AdditionalProperties = &AdditionalProperties{Schema: StringSchema("")}
+	doc.AddSchema("Pet", pet)
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithJSONResponse("200", "Pets", NewArraySchema(&Schema{Ref: "#/components/schemas/Pet"})))
+
+	data, err := doc.ToYAML()
+	if err != nil {
+		t.Fatalf("Error marshaling YAML: %v", err)
+	}
+	loaded, err := FromYAML(data)
+	if err != nil {
+		t.Fatalf("Error parsing YAML: %v", err)
+	}
+	want, _ := json.Marshal(doc)
+	got, _ := json.Marshal(loaded)
+	if string(want) != string(got) {
+		t.Errorf("Expected the document to round-trip through YAML:\n%s\n%s", want, got)
+	}
+
+	input := `openapi: 3.0.3
+info: {title: Pet API, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        200: &ok
+          description: Pets
+        default:
+          <<: *ok
+          description: Error
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        born: {type: string, format: date, example: 2024-01-01}
+`
+	loaded, err = FromYAML([]byte(input))
+	if err != nil {
+		t.Fatalf("Error parsing YAML: %v", err)
+	}
+	_, _, op := loaded.FindOperation("listPets")
+	if op == nil || op.Responses["200"].Description != "Pets" || op.Responses["default"].Description != "Error" {
+		t.Fatalf("Expected numeric response codes and merge keys to be read, got %+v", op)
+	}
+	if example := loaded.Components.Schemas["Pet"].Properties["born"].Example; example != "2024-01-01" {
+		t.Errorf("Expected the date example as a string, got %#v", example)
+	}
+	if _, err := FromYAML([]byte("openapi: [")); err == nil {
+		t.Error("Expected an error for malformed YAML")
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddSchema("Pet", *NewObjectSchema())
+	pet := &Schema{Ref: "#/components/schemas/Pet"}
+	op := NewOperation("getPet", "", "").
+		WithJSONResponse("200", "A pet", pet).
+		WithJSONResponse("404", "Not found", NewObjectSchema())
+	ok := op.Responses["200"]
+	ok.Content["application/json"] = MediaType{Schema: pet, Example: map[string]interface{}{"name": "Rex"}}
+	doc.AddOperation("/pets/{petId}", "GET", op)
+
+	wrapped, err := doc.WrapResponses(DefaultEnvelope())
+	if err != nil {
+		t.Fatalf("Error wrapping responses: %v", err)
+	}
+	_, _, got := wrapped.FindOperation("getPet")
+	body := got.Responses["200"].Content["application/json"]
+	if body.Schema.Properties["data"].Ref != pet.Ref || body.Schema.Properties["errors"] == nil || body.Schema.Required[0] != "data" {
+		t.Errorf("Expected the body to be wrapped, got %+v", body.Schema)
+	}
+	if example, _ := body.Example.(map[string]interface{}); example == nil || example["data"] == nil {
+		t.Errorf("Expected the example to be wrapped, got %v", body.Example)
+	}
+	if errorBody := got.Responses["404"].Content["application/json"]; errorBody.Schema.Properties["data"] != nil {
+		t.Error("Expected error responses to be left untouched")
+	}
+	if _, _, original := doc.FindOperation("getPet"); original.Responses["200"].Content["application/json"].Schema.Ref == "" {
+		t.Error("Expected the original document to be left untouched")
+	}
+
+	again, _ := wrapped.WrapResponses(DefaultEnvelope())
+	if _, _, twice := again.FindOperation("getPet"); twice.Responses["200"].Content["application/json"].Schema.Properties["data"].Ref != pet.Ref {
+		t.Error("Expected enveloped bodies not to be wrapped twice")
+	}
+
+	unwrapped, err := wrapped.UnwrapResponses(DefaultEnvelope())
+	if err != nil {
+		t.Fatalf("Error unwrapping responses: %v", err)
+	}
+	want, _ := json.Marshal(doc)
+	back, _ := json.Marshal(unwrapped)
+	if string(want) != string(back) {
+		t.Errorf("Expected unwrapping to restore the document:\n%s\n%s", want, back)
+	}
+}
+
+func TestResolveRef(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	node := NewObjectSchema().
+		WithProperty("name", StringSchema("")).
+		WithProperty("children", NewArraySchema(&Schema{Ref: "#/components/schemas/Node"}))
+	doc.AddSchema("Node", node)
+	doc.AddSchema("Tree", Schema{Ref: "#/components/schemas/Node"})
+	doc.Components.Responses["Tree"] = Response{Description: "A tree", Content: map[string]MediaType{
+		"application/json": {Schema: &Schema{Ref: "#/components/schemas/Tree"}},
+	}}
+	doc.AddOperation("/tree", "GET", NewOperation("getTree", "", "").
+		WithResponse("200", "", Response{Ref: NewReference("#/components/responses/Tree")}))
+
+	resolved, err := doc.ResolveRef("#/components/schemas/Tree")
+	if err != nil {
+		t.Fatalf("Error resolving reference: %v", err)
+	}
+	if s, ok := resolved.(*Schema); !ok || s.Properties["name"] == nil {
+		t.Errorf("Expected the Node schema through Tree, got %#v", resolved)
+	}
+	if response, err := doc.ResolveRef("#/components/responses/Tree"); err != nil || response.(Response).Description != "A tree" {
+		t.Errorf("Expected the Tree response, got %v (%v)", response, err)
+	}
+	for _, ref := range []string{"#/components/schemas/Missing", "#/paths/~1tree", "Node.json"} {
+		if _, err := doc.ResolveRef(ref); err == nil {
+			t.Errorf("Expected an error resolving %s", ref)
+		}
+	}
+
+	inlined, err := doc.Dereference()
+	if err != nil {
+		t.Fatalf("Error dereferencing: %v", err)
+	}
+	_, _, op := inlined.FindOperation("getTree")
+	response := op.Responses["200"]
+	if response.Ref != nil || response.Description != "A tree" {
+		t.Fatalf("Expected the response to be inlined, got %+v", response)
+	}
+	schema := response.Content["application/json"].Schema
+	if schema.Ref != "" || schema.Properties["name"] == nil {
+		t.Fatalf("Expected the schema to be inlined, got %+v", schema)
+	}
+	if children := schema.Properties["children"].Items; children.Ref != "#/components/schemas/Node" {
+		t.Errorf("Expected the recursive reference to be kept, got %+v", children)
+	}
+}
+
+func TestBundle(t *testing.T) {
+	tags := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "type: array\nitems: {type: string}\n")
+	}))
+	defer tags.Close()
+
+	files := fstest.MapFS{
+		"specs/schemas/pet.yaml": {Data: []byte(`type: object
+properties:
+  owner: {$ref: owner.json}
+  children: {type: array, items: {$ref: pet.yaml}}
+  tags: {$ref: "` + tags.URL + `/tags.yaml"}
+`)},
+		"specs/schemas/owner.json": {Data: []byte(`{"type": "object", "properties": {"name": {"type": "string"}}}`)},
+		"specs/common.yaml": {Data: []byte(`components:
+  parameters:
+    Limit: {name: limit, in: query, schema: {type: integer}}
+  responses:
+    NotFound:
+      description: Not found
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Error"}
+  schemas:
+    Error: {type: object, properties: {message: {type: string}}}
+`)},
+	}
+
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddSchema("Error", *StringSchema(""))
+	op := NewOperation("getPet", "", "").
+		WithParameter(Parameter{Ref: NewReference("common.yaml#/components/parameters/Limit")}).
+		WithJSONResponse("200", "A pet", &Schema{Ref: "schemas/pet.yaml"}).
+		WithResponse("404", "", Response{Ref: NewReference("common.yaml#/components/responses/NotFound")})
+	doc.AddOperation("/pets/{petId}", "GET", op)
+
+	bundled, err := doc.Bundle(context.Background(), "specs/api.yaml", Loader{FS: files, Client: tags.Client()})
+	if err != nil {
+		t.Fatalf("Error bundling: %v", err)
+	}
+	_, _, got := bundled.FindOperation("getPet")
+	if ref := got.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/Pet" {
+		t.Errorf("Expected the pet schema to be bundled, got %q", ref)
+	}
+	if ref := got.Parameters[0].Ref.String(); ref != "#/components/parameters/Limit" {
+		t.Errorf("Expected the parameter to be bundled, got %q", ref)
+	}
+	pet := bundled.Components.Schemas["Pet"]
+	if pet == nil || pet.Properties["children"].Items.Ref != "#/components/schemas/Pet" || pet.Properties["owner"].Ref != "#/components/schemas/Owner" {
+		t.Fatalf("Expected the pet schema to reference itself and the owner, got %+v", pet)
+	}
+	if tags := bundled.Components.Schemas["Tags"]; tags == nil || !tags.Type.Is("array") {
+		t.Errorf("Expected the tags schema to be loaded over HTTP, got %+v", tags)
+	}
+	notFound := bundled.Components.Responses["NotFound"]
+	if ref := notFound.Content["application/json"].Schema.Ref; ref != "#/components/schemas/Error2" {
+		t.Errorf("Expected the colliding error schema to be renamed, got %q", ref)
+	}
+	if !bundled.Components.Schemas["Error"].Type.Is("string") || !bundled.Components.Schemas["Error2"].Type.Is("object") {
+		t.Error("Expected both error schemas to be kept")
+	}
+
+	if _, err := doc.Bundle(context.Background(), "api.yaml", Loader{FS: files}); err == nil {
+		t.Error("Expected an error for missing files")
+	}
+}
+
+func TestPromoteSharedParameters(t *testing.T) {
+	tenant := func() Parameter {
+		return NewHeaderParameter("X-Tenant-ID", "Tenant", true, StringSchema(""))
+	}
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddComponents().Parameters["XTenantID"] = NewHeaderParameter("X-Tenant-ID", "Other tenant", false, StringSchema(""))
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithParameter(tenant()).
+		WithParameter(NewQueryParameter("limit", "", false, Int32Schema())))
+	doc.AddOperation("/pets", "POST", NewOperation("createPet", "", "").WithParameter(tenant()))
+	doc.AddOperation("/owners", "GET", NewOperation("listOwners", "", "").
+		WithParameter(tenant()).
+		WithParameter(NewQueryParameter("limit", "", false, Int32Schema())))
+
+	shared := doc.SharedParameters(3)
+	if len(shared) != 1 || shared[0].Name != "XTenantIDHeader" || len(shared[0].Pointers) != 3 {
+		t.Fatalf("Expected the tenant header to be shared, got %+v", shared)
+	}
+	if shared := doc.SharedParameters(2); len(shared) != 2 || shared[0].Name != "Limit" {
+		t.Errorf("Expected the limit parameter to be shared twice, got %+v", shared)
+	}
+
+	doc.PromoteSharedParameters(3)
+	_, _, op := doc.FindOperation("listOwners")
+	if op.Parameters[0].Ref.String() != "#/components/parameters/XTenantIDHeader" || op.Parameters[1].Ref != nil {
+		t.Errorf("Expected only the tenant header to be replaced, got %+v", op.Parameters)
+	}
+	if p := doc.Components.Parameters["XTenantIDHeader"]; p.Description != "Tenant" {
+		t.Errorf("Expected the tenant header component, got %+v", p)
+	}
+	if errs := doc.Validate(); len(errs) != 0 {
+		t.Errorf("Expected the promoted document to be valid, got %v", errs)
+	}
+}
+
 func TestDocumentWithContact(t *testing.T) {
 	doc := NewDocument("Test API", "1.0.0")
 	doc.WithContact("Test Team", "https://example.com", "test@example.com")
@@ -105,3 +693,1053 @@ func TestDocumentWithServer(t *testing.T) {
 		t.Errorf("Expected server description 'Production server', got '%s'", server.Description)
 	}
 }
+
+func TestFreeze(t *testing.T) {
+	schema := &Schema{Type: Types{"string"}}
+	doc := NewDocument("Test API", "1.0.0")
+	doc.AddOperation("/users", "GET", Operation{
+		OperationID: "listUsers",
+		Responses: map[string]Response{
+			"200": {Description: "OK", Content: map[string]MediaType{"application/json": {Schema: schema}}},
+		},
+	})
+
+	if err := doc.Freeze(); err != nil {
+		t.Fatalf("Error freezing document: %v", err)
+	}
+	if !doc.Frozen() {
+		t.Error("Expected document to be frozen")
+	}
+
+	schema.Type = Types{"integer"}
+	doc.AddTag("users", "User operations")
+	type frozenUser struct {
+		Name string `json:"name"`
+	}
+	SchemaOf[frozenUser](doc)
+
+	_, _, op := doc.FindOperation("listUsers")
+	if got := op.Responses["200"].Content["application/json"].Schema.Type; !got.Is("string") {
+		t.Errorf("Expected shared schema to be detached, got type %s", got)
+	}
+	if len(doc.Tags) != 0 {
+		t.Errorf("Expected no tags after frozen mutation, got %d", len(doc.Tags))
+	}
+	if doc.Components != nil && doc.Components.Schemas["FrozenUser"] != nil {
+		t.Error("Expected no schema registered after frozen mutation")
+	}
+	if !errors.Is(doc.Err(), ErrFrozen) {
+		t.Errorf("Expected ErrFrozen, got %v", doc.Err())
+	}
+}
+
+func TestDerive(t *testing.T) {
+	base := NewDocument("Test API", "1.0.0")
+	base.AddOperation("/users", "GET", Operation{OperationID: "listUsers"})
+	base.AddSchema("User", Schema{Type: Types{"object"}})
+
+	internal := base.Derive()
+	internal.AddOperation("/admin", "GET", Operation{OperationID: "admin"})
+	internal.AddSchema("Admin", Schema{Type: Types{"object"}})
+	internal.WithExtension("x-audience", "internal")
+
+	if _, exists := base.Paths["/admin"]; exists {
+		t.Error("Expected base document to be unchanged by derived document")
+	}
+	if _, exists := base.Components.Schemas["Admin"]; exists {
+		t.Error("Expected base components to be unchanged by derived document")
+	}
+	if len(internal.Paths) != 2 || len(internal.Components.Schemas) != 2 {
+		t.Errorf("Expected derived document to have 2 paths and 2 schemas, got %d and %d",
+			len(internal.Paths), len(internal.Components.Schemas))
+	}
+	if base.Paths["/users"].Get != internal.Paths["/users"].Get {
+		t.Error("Expected unmodified operations to be shared")
+	}
+
+	base.AddOperation("/health", "GET", Operation{OperationID: "health"})
+	if _, exists := internal.Paths["/health"]; exists {
+		t.Error("Expected derived document to be unchanged by base document")
+	}
+
+	secured := NewDocument("Test API", "1.0.0").
+		AddSecurityScheme("bearer", SecurityScheme{Type: "http", Scheme: "bearer"})
+	if err := secured.Freeze(); err != nil {
+		t.Fatalf("Error freezing document: %v", err)
+	}
+	derived := secured.Derive().AddSchema("User", Schema{Type: Types{"object"}})
+	if _, exists := derived.Components.Schemas["User"]; !exists || len(derived.Components.SecuritySchemes) != 1 {
+		t.Errorf("Expected the schema added next to the security scheme, got %+v", derived.Components)
+	}
+	if secured.Components.Schemas != nil {
+		t.Errorf("Expected base components to be unchanged by derived document, got %v", secured.Components.Schemas)
+	}
+}
+
+func TestScaffoldOperation(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", ""))
+
+	if op := doc.ScaffoldOperation("GET", "/pets"); op.OperationID != "listPets" {
+		t.Errorf("Expected existing operation to be kept, got '%s'", op.OperationID)
+	}
+
+	op := doc.ScaffoldOperation("DELETE", "/pets/{petId}")
+	if op.OperationID != "deletePetsByPetId" {
+		t.Errorf("Expected operationId 'deletePetsByPetId', got '%s'", op.OperationID)
+	}
+	if len(op.Parameters) != 1 || op.Parameters[0].Name != "petId" || !op.Parameters[0].Required {
+		t.Errorf("Expected required 'petId' parameter, got %+v", op.Parameters)
+	}
+	if doc.Paths["/pets/{petId}"].Delete != op {
+		t.Error("Expected scaffolded operation to be registered")
+	}
+}
+
+func TestDocumentHandler(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	handler := doc.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/openapi.json", nil))
+	if rec.Code != 200 || !strings.Contains(rec.Body.String(), `"title": "Pet API"`) {
+		t.Errorf("Expected JSON document, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/openapi.json", nil))
+	if rec.Code != 405 {
+		t.Errorf("Expected status 405 for POST, got %d", rec.Code)
+	}
+}
+
+func TestDocumentHandlerFilter(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0").
+		AddTag("pet", "Pets").
+		AddTag("admin", "Administration")
+	doc.AddSchema("Pet", NewObjectSchema().WithProperty("name", StringSchema("")))
+	doc.AddSchema("User", NewObjectSchema().WithProperty("email", EmailSchema()))
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").WithTags("pet").
+		WithOkResponse("Pets", &Schema{Ref: "#/components/schemas/Pet"}))
+	doc.AddOperation("/admin/users", "GET", NewOperation("listUsers", "", "").WithTags("admin").
+		WithOkResponse("Users", &Schema{Ref: "#/components/schemas/User"}))
+	handler := doc.Handler()
+
+	get := func(target string) string {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+		if rec.Code != 200 {
+			t.Fatalf("Expected status 200 for %s, got %d", target, rec.Code)
+		}
+		return rec.Body.String()
+	}
+	body := get("/openapi.json?tag=pet")
+	if !strings.Contains(body, `"/pets"`) || strings.Contains(body, "/admin/users") || strings.Contains(body, `"User"`) || strings.Contains(body, `"admin"`) {
+		t.Errorf("Expected only the pet operations, schemas and tags, got %s", body)
+	}
+	body = get("/openapi.json?path=/admin/*")
+	if !strings.Contains(body, `"/admin/users"`) || strings.Contains(body, `"/pets"`) {
+		t.Errorf("Expected only the admin paths, got %s", body)
+	}
+
+	doc.AddOperation("/pets/{petId}", "GET", NewOperation("getPet", "", "").WithTags("pet").
+		WithOkResponse("Pet", &Schema{Ref: "#/components/schemas/Pet"}))
+	if body := get("/openapi.json?tag=pet"); !strings.Contains(body, "getPet") {
+		t.Errorf("Expected the cached view to follow document changes, got %s", body)
+	}
+	if _, ok := doc.Paths["/admin/users"]; !ok {
+		t.Error("Expected filtering to leave the document untouched")
+	}
+}
+
+func TestProject(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0").AddTag("pet", "Pets")
+	doc.AddSchema("Pet", NewObjectSchema().WithProperty("name", StringSchema("")).WithExample(map[string]interface{}{"name": "Rex"}))
+	doc.AddSchema("NewPet", NewObjectSchema().WithProperty("name", StringSchema("")))
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithOkResponse("Pets", NewArraySchema(&Schema{Ref: "#/components/schemas/Pet"})))
+	doc.AddOperation("/pets", "POST", NewOperation("createPet", "", "").
+		WithJSONRequestBody("Pet", true, &Schema{Ref: "#/components/schemas/NewPet"}))
+
+	reads, err := doc.Project(Selector{Methods: []string{"get"}, DropExamples: true})
+	if err != nil {
+		t.Fatalf("Error projecting document: %v", err)
+	}
+	if item := reads.Paths["/pets"]; item.Get == nil || item.Post != nil {
+		t.Errorf("Expected only the GET operation, got %+v", item)
+	}
+	if _, ok := reads.Components.Schemas["NewPet"]; ok {
+		t.Error("Expected NewPet to be left out with createPet")
+	}
+	if pet := reads.Components.Schemas["Pet"]; pet == nil || pet.Example != nil {
+		t.Errorf("Expected Pet without its example, got %+v", pet)
+	}
+
+	schemas, err := doc.Project(Selector{Members: []string{"components"}, Components: []string{"schemas"}})
+	if err != nil {
+		t.Fatalf("Error projecting document: %v", err)
+	}
+	if len(schemas.Paths) != 0 || len(schemas.Tags) != 0 || len(schemas.Components.Schemas) != 2 || schemas.Info.Title != "Pet API" {
+		t.Errorf("Expected only the info and schemas, got %+v", schemas)
+	}
+	if doc.Paths["/pets"].Post == nil || doc.Components.Schemas["Pet"].Example == nil {
+		t.Error("Expected projecting to leave the document untouched")
+	}
+
+	if _, err := doc.Project(Selector{Members: []string{"operations"}}); err == nil {
+		t.Error("Expected an error for an unknown member")
+	}
+}
+
+func TestForVersion(t *testing.T) {
+	doc := NewDocument("Pet API", "2024-01-01")
+	pet := NewObjectSchema().
+		WithRequiredProperty("name", StringSchema("")).
+		WithProperty("nickname", StringSchema(""))
+	nickname := pet.Properties["nickname"].WithVersions("2023-10-01", "")
+	pet.Properties["nickname"] = &nickname
+	pet.Required = append(pet.Required, "nickname")
+	doc.AddSchema("Pet", pet)
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithParameter(NewQueryParameter("legacy", "", false, NewBooleanSchema()).WithVersions("", "2023-10-01")).
+		WithOkResponse("Pets", &Schema{Ref: "#/components/schemas/Pet"}))
+	doc.AddOperation("/pets/search", "POST", NewOperation("searchPets", "", "").WithVersions("2023-10-01", ""))
+
+	old, err := doc.ForVersion("2023-06-01")
+	if err != nil {
+		t.Fatalf("Error materializing version: %v", err)
+	}
+	if _, ok := old.Paths["/pets/search"]; ok {
+		t.Error("Expected searchPets to be left out before its introduction")
+	}
+	if params := old.Paths["/pets"].Get.Parameters; len(params) != 1 || len(params[0].Extensions) != 0 {
+		t.Errorf("Expected legacy parameter without version extensions, got %+v", params)
+	}
+	if schema := old.Components.Schemas["Pet"]; schema.Properties["nickname"] != nil || len(schema.Required) != 1 {
+		t.Errorf("Expected nickname to be left out, got %+v", schema)
+	}
+	if old.Info.Version != "2023-06-01" {
+		t.Errorf("Expected info version '2023-06-01', got '%s'", old.Info.Version)
+	}
+
+	current, _ := doc.ForVersion("2023-10-01")
+	if _, ok := current.Paths["/pets/search"]; !ok || len(current.Paths["/pets"].Get.Parameters) != 0 {
+		t.Error("Expected searchPets and no legacy parameter from 2023-10-01 on")
+	}
+	if doc.Components.Schemas["Pet"].Properties["nickname"] == nil {
+		t.Error("Expected the source document to be left untouched")
+	}
+
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{{"1.9", "1.10", -1}, {"v2", "1.10", 1}, {"1", "1.0", 0}, {"2023-10-01", "2023-09-30", 1}} {
+		if got := CompareVersions(c.a, c.b); got != c.want {
+			t.Errorf("Expected CompareVersions(%s, %s) = %d, got %d", c.a, c.b, c.want, got)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddSchema("Pet", *NewObjectSchema())
+	doc.AddSchema("Invoice", *NewObjectSchema())
+	doc.AddSchema("Unused", *NewObjectSchema())
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithOkResponse("Pets", &Schema{Ref: "#/components/schemas/Pet"}))
+	doc.AddOperation("/invoices", "GET", NewOperation("listInvoices", "", "").
+		WithFeature("billing").
+		WithOkResponse("Invoices", &Schema{Ref: "#/components/schemas/Invoice"}))
+
+	built, err := doc.Build(map[string]bool{"billing": false})
+	if err != nil {
+		t.Fatalf("Error building document: %v", err)
+	}
+	if _, ok := built.Paths["/invoices"]; ok {
+		t.Error("Expected disabled billing operations to be left out")
+	}
+	if _, ok := built.Components.Schemas["Invoice"]; ok {
+		t.Error("Expected Invoice to be pruned once unreferenced")
+	}
+	for _, name := range []string{"Pet", "Unused"} {
+		if _, ok := built.Components.Schemas[name]; !ok {
+			t.Errorf("Expected %s to be kept", name)
+		}
+	}
+
+	built, _ = doc.Build(map[string]bool{"billing": true})
+	if op := built.Paths["/invoices"].Get; op == nil || op.Extensions[ExtensionFeature] != nil {
+		t.Errorf("Expected enabled billing operation without feature extension, got %+v", op)
+	}
+}
+
+func TestImportFromLibrary(t *testing.T) {
+	common := NewDocument("Common", "1.0.0")
+	common.AddSchema("Problem", NewObjectSchema().WithProperty("detail", StringSchema("")))
+	common.AddSchema("Error", NewObjectSchema().WithProperty("problem", &Schema{Ref: "#/components/schemas/Problem"}))
+	common.AddSchema("Unrelated", *NewObjectSchema())
+	lib := NewLibrary("common", common)
+
+	doc := NewDocument("Pet API", "1.0.0")
+	ref, err := doc.ImportSchema(lib, "Error")
+	if err != nil {
+		t.Fatalf("Error importing schema: %v", err)
+	}
+	if ref.Ref != "#/components/schemas/CommonError" {
+		t.Errorf("Expected reference to CommonError, got '%s'", ref.Ref)
+	}
+	imported := doc.Components.Schemas["CommonError"]
+	if imported == nil || imported.Properties["problem"].Ref != "#/components/schemas/CommonProblem" {
+		t.Fatalf("Expected CommonError referencing CommonProblem, got %+v", imported)
+	}
+	if _, ok := doc.Components.Schemas["CommonProblem"]; !ok {
+		t.Error("Expected referenced CommonProblem to be imported")
+	}
+	if _, ok := doc.Components.Schemas["CommonUnrelated"]; ok {
+		t.Error("Expected unreferenced library schemas not to be imported")
+	}
+
+	if _, err := doc.ImportSchema(lib, "Error"); err != nil {
+		t.Errorf("Expected importing again to succeed, got %v", err)
+	}
+	doc.AddSchema("CommonProblem", *NewStringSchema())
+	if _, err := doc.ImportSchema(lib, "Error"); err == nil {
+		t.Error("Expected an error when an imported name is taken by a different component")
+	}
+	if _, err := doc.ImportSchema(lib, "Missing"); err == nil {
+		t.Error("Expected an error for unknown library components")
+	}
+}
+
+func TestLibraryDocument(t *testing.T) {
+	common := NewLibraryDocument("Common", "1.0.0")
+	common.AddSchema("Problem", NewObjectSchema().WithProperty("detail", StringSchema("")))
+	common.AddSchema("Error", NewObjectSchema().WithProperty("problem", &Schema{Ref: "#/components/schemas/Problem"}))
+	if !common.IsLibrary() {
+		t.Error("Expected a library document")
+	}
+	if errs := common.Validate(); len(errs) != 0 {
+		t.Errorf("Expected library document to be valid, got %v", errs)
+	}
+	if errs := common.ValidateProfiles(LibraryProfile{}); len(errs) != 0 {
+		t.Errorf("Expected no library findings, got %v", errs)
+	}
+
+	common.AddSchema("Broken", Schema{Ref: "#/components/schemas/Missing"})
+	common.AddOperation("/pets", "GET", NewOperation("listPets", "List pets", ""))
+	rules := make(map[string]string)
+	for _, err := range common.ValidateProfiles(LibraryProfile{}) {
+		rules[err.Rule] = err.Path
+	}
+	if rules["library/operations"] != "/paths/~1pets" {
+		t.Errorf("Expected library/operations finding at /paths/~1pets, got %v", rules)
+	}
+	if rules["library/unresolved-ref"] != "/components/schemas/Broken/$ref" {
+		t.Errorf("Expected library/unresolved-ref finding for Broken, got %v", rules)
+	}
+	if common.IsLibrary() {
+		t.Error("Expected a document with paths not to be a library")
+	}
+
+	lib := NewLibrary("common", common)
+	if _, err := lib.ReferenceSchema("Error"); err == nil {
+		t.Error("Expected an error referencing a library without location")
+	}
+	lib.Location = "https://schemas.example.com/common.json"
+	ref, err := lib.ReferenceSchema("Error")
+	if err != nil {
+		t.Fatalf("Error referencing schema: %v", err)
+	}
+	if ref.Ref != "https://schemas.example.com/common.json#/components/schemas/Error" {
+		t.Errorf("Expected external reference to Error, got '%s'", ref.Ref)
+	}
+	if _, err := lib.ReferenceResponse("Error"); err == nil {
+		t.Error("Expected an error for unknown library components")
+	}
+}
+
+func TestMergeOverrides(t *testing.T) {
+	generate := func(version string, fields ...string) *Document {
+		doc := NewDocument("Pet API", version)
+		pet := NewObjectSchema()
+		for _, field := range fields {
+			*pet = pet.WithProperty(field, StringSchema(""))
+		}
+		doc.AddSchema("Pet", *pet)
+		doc.AddOperation("/pets", "GET", NewOperation("listPets", "List pets", "").
+			WithOkResponse("Pets", &Schema{Ref: "#/components/schemas/Pet"}))
+		return doc
+	}
+	base := generate("1.0.0", "name")
+	generated := generate("1.1.0", "name", "species")
+
+	overrides, _ := base.Clone()
+	overrides.Paths["/pets"].Get.Description = "Lists every pet in the store"
+	overrides.Info.Version = "1.0.1"
+
+	merged, conflicts, err := MergeOverrides(base, generated, overrides)
+	if err != nil {
+		t.Fatalf("Error merging overrides: %v", err)
+	}
+	if merged.Paths["/pets"].Get.Description != "Lists every pet in the store" {
+		t.Errorf("Expected the manual description to be kept, got '%s'", merged.Paths["/pets"].Get.Description)
+	}
+	if merged.Components.Schemas["Pet"].Properties["species"] == nil {
+		t.Error("Expected the generated species property to be adopted")
+	}
+	if len(conflicts) != 1 || conflicts[0].Pointer != "/info/version" {
+		t.Errorf("Expected a conflict on /info/version, got %+v", conflicts)
+	}
+	if merged.Info.Version != "1.0.1" {
+		t.Errorf("Expected the override to win the conflict, got '%s'", merged.Info.Version)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	service := func(name string, fields ...string) *Document {
+		doc := NewDocument(name, "1.0.0")
+		pet := NewObjectSchema()
+		for _, field := range fields {
+			*pet = pet.WithProperty(field, StringSchema(""))
+		}
+		doc.AddSchema("Pet", *pet)
+		doc.AddSchema("Error", *NewObjectSchema())
+		doc.AddTag("pets", "Pets")
+		return doc
+	}
+	gateway := service("Gateway", "name")
+	gateway.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithOkResponse("Pets", &Schema{Ref: "#/components/schemas/Pet"}))
+	orders := service("Orders", "id")
+	orders.AddOperation("/orders", "GET", NewOperation("listOrders", "", "").
+		WithOkResponse("Orders", &Schema{Ref: "#/components/schemas/Pet"}))
+
+	if _, report, err := Merge(gateway, orders, MergeOptions{}); err == nil || len(report.Unresolved()) != 1 {
+		t.Errorf("Expected the Pet collision to fail the merge, got %v", err)
+	}
+
+	merged, _, err := Merge(gateway, orders, MergeOptions{Collision: CollisionRename, Prefix: "orders"})
+	if err != nil {
+		t.Fatalf("Error merging with renames: %v", err)
+	}
+	if _, ok := merged.Components.Schemas["OrdersPet"]; !ok {
+		t.Errorf("Expected the colliding Pet to be renamed, got %v", sortedKeys(merged.Components.Schemas))
+	}
+	if _, ok := merged.Components.Schemas["OrdersError"]; ok {
+		t.Error("Expected the identical Error to be merged, not renamed")
+	}
+	if ref := merged.Paths["/orders"].Get.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/OrdersPet" {
+		t.Errorf("Expected the reference to be rewritten, got '%s'", ref)
+	}
+	if merged.Info.Title != "Gateway" || len(merged.Tags) != 1 {
+		t.Errorf("Expected the destination info and a single tag, got %+v", merged)
+	}
+
+	merged, _, err = Merge(gateway, orders, MergeOptions{Collision: CollisionPreferDst})
+	if err != nil {
+		t.Fatalf("Error merging preferring dst: %v", err)
+	}
+	if _, ok := merged.Components.Schemas["Pet"].Properties["name"]; !ok {
+		t.Error("Expected the destination Pet to be kept")
+	}
+
+	orders.AddOperation("/pets", "GET", NewOperation("listOrderPets", "", ""))
+	if _, _, err := Merge(gateway, orders, MergeOptions{Collision: CollisionRename, Prefix: "orders"}); err == nil {
+		t.Error("Expected a colliding operation to fail the merge")
+	}
+}
+
+func TestMergeDocuments(t *testing.T) {
+	service := func(name string, fields ...string) *Document {
+		doc := NewDocument(name, "1.0.0")
+		pet := NewObjectSchema()
+		for _, field := range fields {
+			*pet = pet.WithProperty(field, StringSchema(""))
+		}
+		doc.AddSchema("Pet", *pet)
+		doc.AddSchema("Error", *NewObjectSchema())
+		doc.AddTag("pets", "Pets")
+		return doc
+	}
+	ours := service("Pets", "name")
+	ours.AddSecurityScheme("auth", *NewHTTPSecurityScheme("bearer"))
+	ours.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithOkResponse("Pets", &Schema{Ref: "#/components/schemas/Pet"}))
+
+	theirs := service("Orders", "id", "owner")
+	theirs.AddSecurityScheme("auth", *NewAPIKeySecurityScheme("X-API-Key", "header"))
+	theirs.AddTag("orders", "Orders")
+	theirs.AddOperation("/pets", "GET", NewOperation("listAllPets", "", ""))
+	createOrder := NewOperation("createOrder", "", "").
+		WithOkResponse("Order", &Schema{Ref: "#/components/schemas/Pet"})
+	createOrder.Security = []SecurityRequirement{{"auth": {}}}
+	theirs.AddOperation("/orders", "POST", createOrder)
+
+	merged, report, err := MergeDocuments(nil, ours, theirs, func(c MergeConflict) MergeStrategy {
+		if c.Kind == MergeConflictOperation {
+			return ""
+		}
+		return MergeRename
+	})
+	if err != nil {
+		t.Fatalf("Error merging documents: %v", err)
+	}
+	if len(report.Conflicts) != 3 {
+		t.Fatalf("Expected 3 conflicts, got %+v", report.Conflicts)
+	}
+	if c := report.Conflicts[1]; c.Kind != MergeConflictSecurityScheme || c.RenamedTo != "auth2" || !strings.Contains(c.Message, "incompatible") {
+		t.Errorf("Expected an incompatible security scheme renamed to auth2, got %+v", c)
+	}
+	if unresolved := report.Unresolved(); len(unresolved) != 1 || unresolved[0].Name != "GET /pets" {
+		t.Errorf("Expected GET /pets to be unresolved, got %+v", unresolved)
+	}
+	if merged.Paths["/pets"].Get.OperationID != "listPets" {
+		t.Errorf("Expected our operation to be kept, got '%s'", merged.Paths["/pets"].Get.OperationID)
+	}
+	order := merged.Paths["/orders"].Post
+	if ref := order.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/Pet2" {
+		t.Errorf("Expected their reference to be rewritten, got '%s'", ref)
+	}
+	if _, ok := order.Security[0]["auth2"]; !ok {
+		t.Errorf("Expected their security requirement to be renamed, got %v", order.Security)
+	}
+	if merged.Components.Schemas["Pet"].Properties["name"] == nil || merged.Components.Schemas["Pet2"].Properties["owner"] == nil {
+		t.Error("Expected both Pet shapes to be kept")
+	}
+	if len(merged.Tags) != 2 || merged.Tags[1].Name != "orders" {
+		t.Errorf("Expected tags to be merged, got %v", merged.Tags)
+	}
+
+	base := service("Pets", "name")
+	ours = service("Pets", "name")
+	ours.Components.Schemas["Error"].Description = "An error"
+	theirs = service("Pets", "name", "species")
+	merged, report, err = MergeDocuments(base, ours, theirs, nil)
+	if err != nil {
+		t.Fatalf("Error merging documents: %v", err)
+	}
+	if len(report.Conflicts) != 0 {
+		t.Errorf("Expected changes on different components not to conflict, got %+v", report.Conflicts)
+	}
+	if merged.Components.Schemas["Pet"].Properties["species"] == nil || merged.Components.Schemas["Error"].Description != "An error" {
+		t.Error("Expected both sides' changes to be adopted")
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	doc := NewDocument("Orders", "1.0.0")
+	doc.AddSchema("Address", *NewObjectSchema())
+	doc.AddSchema("Order", NewObjectSchema().WithProperty("shipTo", &Schema{Ref: "#/components/schemas/Address"}))
+	doc.AddSchema("Invoice", NewObjectSchema().WithProperty("billTo", &Schema{Ref: "#/components/schemas/Address"}))
+	doc.AddSecurityScheme("auth", *NewHTTPSecurityScheme("bearer"))
+	createOrder := NewOperation("createOrder", "", "").WithTags("orders").
+		WithOkResponse("Order", &Schema{Ref: "#/components/schemas/Order"})
+	createOrder.Security = []SecurityRequirement{{"auth": {}}}
+	doc.AddOperation("/orders", "POST", createOrder)
+	doc.AddOperation("/invoices", "GET", NewOperation("listInvoices", "", "").WithTags("billing").
+		WithOkResponse("Invoices", &Schema{Ref: "#/components/schemas/Invoice"}))
+
+	prefixed, err := doc.WithNamespace(PrefixNamespace("orders"))
+	if err != nil {
+		t.Fatalf("Error namespacing document: %v", err)
+	}
+	if ref := prefixed.Components.Schemas["OrdersOrder"].Properties["shipTo"].Ref; ref != "#/components/schemas/OrdersAddress" {
+		t.Errorf("Expected references between components to be rewritten, got '%s'", ref)
+	}
+	if _, ok := prefixed.Paths["/orders"].Post.Security[0]["OrdersAuth"]; !ok {
+		t.Errorf("Expected the security requirement to be renamed, got %v", prefixed.Paths["/orders"].Post.Security)
+	}
+	if _, ok := doc.Components.Schemas["Order"]; !ok {
+		t.Error("Expected the original document to be left untouched")
+	}
+
+	tagged, err := doc.WithNamespace(TagNamespace())
+	if err != nil {
+		t.Fatalf("Error namespacing document: %v", err)
+	}
+	for _, name := range []string{"OrdersOrder", "BillingInvoice", "Address"} {
+		if tagged.Components.Schemas[name] == nil {
+			t.Errorf("Expected schema %s, got %v", name, sortedKeys(tagged.Components.Schemas))
+		}
+	}
+	if ref := tagged.Paths["/invoices"].Get.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/BillingInvoice" {
+		t.Errorf("Expected the operation reference to be rewritten, got '%s'", ref)
+	}
+
+	theirs, _ := doc.Clone()
+	theirs.Components.Schemas["Address"].Description = "A postal address"
+	_, report, err := MergeDocuments(nil, doc, theirs, func(MergeConflict) MergeStrategy { return MergeHashSuffix })
+	if err != nil {
+		t.Fatalf("Error merging documents: %v", err)
+	}
+	if len(report.Conflicts) != 1 || !strings.HasPrefix(report.Conflicts[0].RenamedTo, "Address_") || len(report.Conflicts[0].RenamedTo) != len("Address_")+8 {
+		t.Errorf("Expected Address to get a hash suffix, got %+v", report.Conflicts)
+	}
+}
+
+func TestMarshalPreserving(t *testing.T) {
+	original := `# Pet store
+openapi: 3.0.3
+paths:
+    /pets:
+        get:
+            tags: [pets]
+            summary: 'List pets'
+            operationId: listPets
+            responses:
+                "200":
+                    description: Pets
+info:
+    version: '1.0'
+    title: Pet API # shown in the portal
+`
+	var tree interface{}
+	if err := yaml.Unmarshal([]byte(original), &tree); err != nil {
+		t.Fatalf("Error parsing YAML: %v", err)
+	}
+	data, _ := json.Marshal(tree)
+	doc := &Document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		t.Fatalf("Error loading document: %v", err)
+	}
+	doc.Paths["/pets"].Get.Summary = "List all pets"
+
+	out, err := doc.MarshalPreserving([]byte(original))
+	if err != nil {
+		t.Fatalf("Error marshaling: %v", err)
+	}
+	expected := strings.Replace(original, "'List pets'", "'List all pets'", 1)
+	if string(out) != expected {
+		t.Errorf("Expected only the summary to change, got:\n%s", out)
+	}
+
+	original = "{\n\t\"openapi\": \"3.0.3\",\n\t\"info\": {\"version\": \"1.0\", \"title\": \"Pet API\"},\n\t\"paths\": {}\n}"
+	doc = &Document{}
+	if err := json.Unmarshal([]byte(original), doc); err != nil {
+		t.Fatalf("Error loading document: %v", err)
+	}
+	out, err = doc.MarshalPreserving([]byte(original))
+	if err != nil {
+		t.Fatalf("Error marshaling: %v", err)
+	}
+	if !strings.HasPrefix(string(out), "{\n\t\"openapi\": \"3.0.3\",\n\t\"info\": {\n\t\t\"version\": \"1.0\"") {
+		t.Errorf("Expected the original order and indentation, got:\n%s", out)
+	}
+}
+
+func TestClassification(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddOperation("/owners", "GET", NewOperation("listOwners", "", "").
+		WithDataClassification(ClassificationPII).
+		WithAudience(AudiencePublic))
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithDataClassification(ClassificationPublic))
+
+	errs := doc.ValidateClassification()
+	if len(errs) != 1 || errs[0].Path != "/paths/~1pets/get" {
+		t.Errorf("Expected listPets to miss an audience, got %v", errs)
+	}
+
+	report := doc.ClassificationReport()
+	if report.ByClassification[ClassificationPII] != 1 || report.ByAudience[AudiencePublic] != 1 {
+		t.Errorf("Expected one public PII operation, got %+v", report)
+	}
+	if len(report.Exposed) != 1 || report.Exposed[0] != "GET /owners" {
+		t.Errorf("Expected GET /owners to be exposed, got %v", report.Exposed)
+	}
+	if len(report.Unclassified) != 1 || report.Unclassified[0] != "GET /pets" {
+		t.Errorf("Expected GET /pets to be unclassified, got %v", report.Unclassified)
+	}
+}
+
+func TestDataMap(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	email := StringSchema("email").WithPII("contact")
+	doc.AddSchema("Owner", NewObjectSchema().
+		WithProperty("name", StringSchema("")).
+		WithProperty("email", &email))
+	owner := &Schema{Ref: "#/components/schemas/Owner"}
+	doc.AddOperation("/owners", "GET", NewOperation("listOwners", "", "").
+		WithParameter(NewQueryParameter("email", "", false, &email)).
+		WithOkResponse("Owners", NewArraySchema(owner)))
+	doc.AddOperation("/owners", "POST", NewOperation("createOwner", "", "").
+		WithRequestBody("", true, map[string]MediaType{"application/json": {Schema: owner}}))
+
+	fields := doc.DataMap().Fields
+	if len(fields) != 2 {
+		t.Fatalf("Expected 2 fields, got %+v", fields)
+	}
+	if f := fields[0]; f.Field != "Owner.email" || f.Category != "contact" ||
+		len(f.Reads) != 1 || f.Reads[0] != "GET /owners" || len(f.Writes) != 1 || f.Writes[0] != "POST /owners" {
+		t.Errorf("Expected Owner.email read by GET and written by POST, got %+v", f)
+	}
+	if f := fields[1]; f.Field != "query.email" || len(f.Writes) != 1 || f.Writes[0] != "GET /owners" {
+		t.Errorf("Expected the email query parameter written by GET, got %+v", f)
+	}
+}
+
+func TestSequenceDiagrams(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	create := NewOperation("createPet", "Create a pet", "").
+		WithResponse("201", "Created", NewResponse("Created").WithLink("GetPet", NewLink().
+			WithOperationID("getPet").
+			WithParameter("id", "$response.body#/id")))
+	create.Callbacks = map[string]Callback{
+		"petAdopted": NewCallback().WithPath("{$request.body#/callbackUrl}", PathItem{
+			Post: &Operation{Responses: map[string]Response{"204": {Description: "Received"}}},
+		}),
+	}
+	doc.AddOperation("/pets", "POST", create)
+	doc.AddOperation("/pets/{id}", "GET", NewOperation("getPet", "Get a pet", "").
+		WithResponse("200", "Pet", NewResponse("Pet").WithLink("Update", NewLink().
+			WithOperationRef("#/paths/~1pets~1{id}/put"))))
+	doc.AddOperation("/pets/{id}", "PUT", NewOperation("updatePet", "Update a pet", "").
+		WithResponse("200", "Updated", NewResponse("Updated").WithLink("Get", NewLink().WithOperationID("getPet"))))
+	doc.AddOperation("/health", "GET", NewOperation("health", "Health", "").WithResponse("200", "OK", Response{}))
+
+	diagrams := doc.SequenceDiagrams()
+	if len(diagrams) != 1 || diagrams[0].Title != "createPet" {
+		t.Fatalf("Expected a single diagram starting at createPet, got %+v", diagrams)
+	}
+	for _, line := range []string{
+		"participant API as Pet API",
+		"Client->>API: POST /pets\n    API-->>Client: 201 Created",
+		"API-)Consumer: POST {$request.body#35;/callbackUrl} (petAdopted)\n    Consumer--)API: 204",
+		"Note over Client,API: GetPet: id = $response.body#35;/id\n    Client->>API: GET /pets/{id}",
+		"Client->>API: PUT /pets/{id}",
+		"Note over Client,API: Get (back to GET /pets/{id})",
+	} {
+		if !strings.Contains(diagrams[0].Mermaid, line) {
+			t.Errorf("Expected diagram to contain %q, got:\n%s", line, diagrams[0].Mermaid)
+		}
+	}
+	if !strings.Contains(doc.SequenceDiagramMarkdown(), "## createPet\n\n```mermaid\nsequenceDiagram\n") {
+		t.Errorf("Expected a mermaid section for createPet, got %s", doc.SequenceDiagramMarkdown())
+	}
+}
+
+func TestReferenceObjects(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddComponents().Responses["NotFound"] = NewResponse("Not found")
+	doc.Components.Callbacks["petAdopted"] = NewCallback().WithPath("{$request.body#/callbackUrl}", PathItem{
+		Post: &Operation{Responses: map[string]Response{"204": {Description: "Received"}}},
+	})
+	op := NewOperation("getPet", "Get a pet", "").
+		WithResponse("404", "", Response{Ref: NewReference("#/components/responses/NotFound").WithDescription("No pet with this ID")})
+	op.Callbacks = map[string]Callback{"adopted": {Ref: NewReference("#/components/callbacks/petAdopted")}}
+	doc.AddOperation("/pets/{id}", "GET", op)
+
+	data, err := json.Marshal(op.Responses["404"])
+	if err != nil {
+		t.Fatalf("Error marshaling reference: %v", err)
+	}
+	if string(data) != `{"$ref":"#/components/responses/NotFound","description":"No pet with this ID"}` {
+		t.Errorf("Expected the Reference Object alone, got %s", data)
+	}
+	if resolved := doc.resolveResponse(op.Responses["404"]); resolved.Description != "No pet with this ID" {
+		t.Errorf("Expected the reference description to override the component, got '%s'", resolved.Description)
+	}
+
+	data, _ = json.Marshal(doc)
+	decoded, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("Error decoding document: %v", err)
+	}
+	_, _, got := decoded.FindOperation("getPet")
+	if ref := got.Responses["404"].Ref; ref == nil || ref.Ref != "#/components/responses/NotFound" || ref.Description != "No pet with this ID" {
+		t.Errorf("Expected the response reference to round-trip, got %+v", ref)
+	}
+	if ref := got.Callbacks["adopted"].Ref.String(); ref != "#/components/callbacks/petAdopted" {
+		t.Errorf("Expected the callback reference to round-trip, got '%s'", ref)
+	}
+	if item, ok := decoded.Components.Callbacks["petAdopted"].Paths["{$request.body#/callbackUrl}"]; !ok || item.Post == nil {
+		t.Errorf("Expected the callback component to round-trip, got %+v", decoded.Components.Callbacks)
+	}
+	resolved, err := decoded.ResolveRef("#/components/callbacks/petAdopted")
+	if _, ok := resolved.(Callback); err != nil || !ok {
+		t.Errorf("Expected the callback component to resolve, got %v, %v", resolved, err)
+	}
+
+	var example Example
+	if err := json.Unmarshal([]byte(`{"$ref":"#/components/examples/Cat","summary":"A cat"}`), &example); err != nil || example.Ref.String() != "#/components/examples/Cat" || example.Ref.Summary != "A cat" {
+		t.Errorf("Expected an example reference with summary, got %+v, %v", example.Ref, err)
+	}
+}
+
+func TestRenderSchema(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	id := NewIntegerSchema().WithFormat("int64").WithMinimum(1).WithDescription("Unique | identifier")
+	tags := NewArraySchema(StringSchema("")).WithMaxItems(10)
+	owner := NewObjectSchema().
+		WithRequiredProperty("name", StringSchema("")).
+		WithProperty("pets", NewArraySchema(&Schema{Ref: "#/components/schemas/Pet"}))
+	pet := NewObjectSchema().
+		WithRequiredProperty("id", &id).
+		WithProperty("tags", &tags).
+		WithProperty("owner", &Schema{Ref: "#/components/schemas/Owner"})
+	doc.AddSchema("Pet", pet)
+	doc.AddSchema("Owner", owner)
+	ref := &Schema{Ref: "#/components/schemas/Pet"}
+
+	tree := ref.Render(RenderTree, RenderOptions{Document: doc})
+	expected := "Pet\n" +
+		"  id: integer (int64), required, >= 1 - Unique | identifier\n" +
+		"  owner: Owner\n" +
+		"    name: string, required\n" +
+		"    pets: array of Pet\n" +
+		"  tags: array of string, items <= 10\n"
+	if tree != expected {
+		t.Errorf("Expected tree:\n%s\ngot:\n%s", expected, tree)
+	}
+
+	table := ref.Render(RenderTable, RenderOptions{Document: doc, Depth: 1})
+	if !strings.Contains(table, "| `id` | integer (int64) | yes | >= 1 | Unique \\| identifier |\n") {
+		t.Errorf("Expected a row for id, got:\n%s", table)
+	}
+	if strings.Contains(table, "owner.name") {
+		t.Errorf("Expected depth 1 not to expand owner, got:\n%s", table)
+	}
+	if table = ref.Render(RenderTable, RenderOptions{Document: doc}); !strings.Contains(table, "| `owner.name` | string | yes |") {
+		t.Errorf("Expected a row for owner.name, got:\n%s", table)
+	}
+	if tree := ref.Render(RenderTree, RenderOptions{}); tree != "Pet\n" {
+		t.Errorf("Expected an unresolved reference to render by name, got %q", tree)
+	}
+}
+
+func TestSchemaDialect(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddSchema("Pet", *NewObjectSchema())
+	doc.AddSchema("Legacy", NewObjectSchema().WithDialect("https://json-schema.org/draft-07/schema"))
+	if doc.SchemaDialect() != DialectOAS31 {
+		t.Errorf("Expected the OpenAPI 3.1 dialect by default, got '%s'", doc.SchemaDialect())
+	}
+
+	pet := doc.Components.Schemas["Pet"]
+	doc.WithJSONSchemaDialect(DialectJSONSchema202012).StampSchemaDialect()
+	if doc.Components.Schemas["Pet"].Dialect != DialectJSONSchema202012 {
+		t.Errorf("Expected Pet to be stamped, got '%s'", doc.Components.Schemas["Pet"].Dialect)
+	}
+	if pet.Dialect != "" {
+		t.Error("Expected stamping not to modify the original schema")
+	}
+	if doc.Components.Schemas["Legacy"].Dialect != "https://json-schema.org/draft-07/schema" {
+		t.Errorf("Expected Legacy to keep its dialect, got '%s'", doc.Components.Schemas["Legacy"].Dialect)
+	}
+	data, _ := json.Marshal(doc)
+	if !strings.Contains(string(data), `"jsonSchemaDialect":"https://json-schema.org/draft/2020-12/schema"`) ||
+		!strings.Contains(string(data), `"Pet":{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object"`) {
+		t.Errorf("Expected the dialect in the document and the Pet schema, got %s", data)
+	}
+	if errs := doc.Validate(); findValidationError(errs, "/jsonSchemaDialect") != nil {
+		t.Errorf("Expected a valid dialect, got %v", errs)
+	}
+
+	doc.JSONSchemaDialect = "draft-2020-12"
+	doc.OpenAPI = "3.0.3"
+	count := 0
+	for _, err := range doc.Validate() {
+		if err.Path == "/jsonSchemaDialect" {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected errors for a relative dialect in an OpenAPI 3.0 document, got %d", count)
+	}
+}
+
+func TestOptimizeSize(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0").WithInfo(strings.Repeat("A long introduction. ", 20), "")
+	owner := func() *Schema {
+		address := NewObjectSchema().
+			WithProperty("street", StringSchema("")).
+			WithProperty("city", StringSchema("")).
+			WithExample(map[string]interface{}{"street": "Main St", "city": "Springfield"})
+		owner := NewObjectSchema().WithTitle("owner").WithProperty("address", &address)
+		return &owner
+	}
+	pet := NewObjectSchema().WithProperty("name", StringSchema("")).WithDescription("A pet")
+	inlinePet := pet
+	doc.AddSchema("Pet", pet)
+	doc.AddOperation("/owners", "POST", NewOperation("createOwner", "Create an owner", "").
+		WithJSONRequestBody("Owner", true, owner()).
+		WithJSONResponse("201", "Created", owner()))
+	doc.AddOperation("/pets", "POST", NewOperation("createPet", "Create a pet", "").
+		WithJSONRequestBody("Pet", true, &inlinePet).
+		WithResponse("201", "Created", Response{}))
+
+	original, _ := json.Marshal(doc)
+	optimized, report, err := doc.OptimizeSize(SizeOptions{DropExamples: true, StripDescriptions: true, DedupSchemas: true})
+	if err != nil {
+		t.Fatalf("Error optimizing: %v", err)
+	}
+	if after, _ := json.Marshal(doc); !bytes.Equal(original, after) {
+		t.Error("Expected the document to be left untouched")
+	}
+
+	if report.LongestDescriptions[0].Pointer != "/info/description" {
+		t.Errorf("Expected the info description to be the longest, got %+v", report.LongestDescriptions)
+	}
+	if len(report.DuplicatedExamples) != 1 || report.DuplicatedExamples[0].Count != 2 {
+		t.Errorf("Expected the address example to be reported twice, got %+v", report.DuplicatedExamples)
+	}
+	if len(report.LargestSchemas) == 0 || strings.Contains(report.LargestSchemas[0].Pointer, "/properties/") {
+		t.Errorf("Expected only top-level schemas, got %+v", report.LargestSchemas)
+	}
+	if report.OptimizedBytes >= report.Bytes || report.GzipBytes != GzipSize(original) {
+		t.Errorf("Expected the optimized document to be smaller, got %+v", report)
+	}
+	if !strings.Contains(report.Markdown(), "### Duplicated examples") {
+		t.Errorf("Expected a duplicated examples section, got %s", report.Markdown())
+	}
+
+	extracted := optimized.Components.Schemas["Owner"]
+	if extracted == nil || extracted.Properties["address"].Example != nil {
+		t.Fatalf("Expected an Owner component without example, got %+v", optimized.Components.Schemas)
+	}
+	_, _, createOwner := optimized.FindOperation("createOwner")
+	if ref := createOwner.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/Owner" {
+		t.Errorf("Expected the inline owner to reference Owner, got '%s'", ref)
+	}
+	if ref := createOwner.Responses["201"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/Owner" {
+		t.Errorf("Expected the inline owner to reference Owner, got '%s'", ref)
+	}
+	_, _, createPet := optimized.FindOperation("createPet")
+	if ref := createPet.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/Pet" {
+		t.Errorf("Expected the inline pet to reference Pet, got '%s'", ref)
+	}
+	if optimized.Info.Description != "" || createPet.Responses["201"].Description != "Created" {
+		t.Errorf("Expected descriptions stripped except for responses, got '%s' and '%s'", optimized.Info.Description, createPet.Responses["201"].Description)
+	}
+}
+
+func TestApplyRateLimits(t *testing.T) {
+	doc := NewDocument("Test API", "1.0.0")
+	doc.WithRateLimit(NewRateLimitPolicy(1000, 3600))
+	doc.SetTagRateLimit("search", NewRateLimitPolicy(10, 60).WithScope("ip"))
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithOkResponse("Pets", NewArraySchema(StringSchema(""))))
+	doc.AddOperation("/search", "GET", NewOperation("search", "", "Search pets.").
+		WithTag("search").
+		WithOkResponse("Results", NewArraySchema(StringSchema(""))))
+	doc.AddOperation("/search", "POST", NewOperation("searchAll", "", "").
+		WithTag("search").
+		WithResponse("200", "Results", NewResponse("Results").
+			WithHeader("ratelimit-limit", NewHeader().WithSchema(Int32Schema()))))
+	doc.AddOperation("/upload", "POST", NewOperation("upload", "", "").
+		WithTag("search").
+		WithRateLimit(NewRateLimitPolicy(1, 60)).
+		WithResponse("201", "", Response{Ref: NewReference("#/components/responses/Created")}).
+		WithResponse("429", "", Response{Ref: NewReference("#/components/responses/TooManyRequests")}))
+
+	tests := []struct {
+		operationID string
+		limit       int
+	}{
+		{"listPets", 1000},
+		{"search", 10},
+		{"upload", 1},
+	}
+	for _, tt := range tests {
+		_, _, op := doc.FindOperation(tt.operationID)
+		policy, ok := doc.EffectiveRateLimit(op)
+		if !ok || policy.Limit != tt.limit {
+			t.Errorf("Expected %s limited to %d, got %+v", tt.operationID, tt.limit, policy)
+		}
+	}
+
+	doc.ApplyRateLimits()
+
+	_, _, search := doc.FindOperation("search")
+	if search.Description != "Search pets.\n\nRate limit: 10 requests per 60 seconds per ip." {
+		t.Errorf("Expected the policy appended to the description, got '%s'", search.Description)
+	}
+	for _, name := range []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"} {
+		if _, ok := search.Responses["200"].Headers[name]; !ok {
+			t.Errorf("Expected %s on the 200 response, got %v", name, search.Responses["200"].Headers)
+		}
+		if _, ok := search.Responses["429"].Headers[name]; !ok {
+			t.Errorf("Expected %s on the 429 response, got %v", name, search.Responses["429"].Headers)
+		}
+	}
+	if _, ok := search.Responses["429"].Headers["Retry-After"]; !ok {
+		t.Errorf("Expected Retry-After on the 429 response, got %v", search.Responses["429"].Headers)
+	}
+	if !strings.HasPrefix(search.Responses["429"].Description, "Too Many Requests") {
+		t.Errorf("Expected a Too Many Requests response, got '%s'", search.Responses["429"].Description)
+	}
+
+	_, _, searchAll := doc.FindOperation("searchAll")
+	if headers := searchAll.Responses["200"].Headers; len(headers) != 3 {
+		t.Errorf("Expected the declared ratelimit-limit header to stand for RateLimit-Limit, got %v", headers)
+	}
+
+	_, _, upload := doc.FindOperation("upload")
+	if created := upload.Responses["201"]; created.Ref == nil || len(created.Headers) != 0 {
+		t.Errorf("Expected the referenced 201 response left alone, got %+v", created)
+	}
+	if tooMany := upload.Responses["429"]; tooMany.Ref == nil || tooMany.Ref.Ref != "#/components/responses/TooManyRequests" || len(tooMany.Headers) != 0 {
+		t.Errorf("Expected the referenced 429 response left alone, got %+v", tooMany)
+	}
+
+	before, _ := json.Marshal(doc)
+	doc.ApplyRateLimits()
+	if after, _ := json.Marshal(doc); !bytes.Equal(before, after) {
+		t.Errorf("Expected a second ApplyRateLimits to change nothing, got\n%s\nthen\n%s", before, after)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	cursor := CursorPagination("cursor", "$response.body#/meta/next", "$response.body#/meta/prev")
+	op := NewOperation("listPets", "", "").
+		WithPathParameter("owner", "", StringSchema("")).
+		WithQueryParameter("cursor", "", false, StringSchema("")).
+		WithQueryParameter("status", "", false, StringSchema("")).
+		WithHeaderParameter("X-Request-ID", "", false, UUIDSchema()).
+		WithResponse("200", "Pets", NewResponse("Pets").WithHeader("X-Total-Count", NewHeader().WithSchema(Int64Schema()))).
+		WithPagination("200", cursor)
+
+	response := op.Responses["200"]
+	if _, ok := response.Headers["X-Total-Count"]; !ok {
+		t.Errorf("Expected the response headers kept, got %v", response.Headers)
+	}
+	if len(response.Links) != 2 {
+		t.Fatalf("Expected next and prev links, got %v", response.Links)
+	}
+	next := response.Links["next"]
+	if next.OperationID != "listPets" {
+		t.Errorf("Expected the link to target listPets, got '%s'", next.OperationID)
+	}
+	expected := map[string]interface{}{
+		"cursor": "$response.body#/meta/next",
+		"owner":  "$request.path.owner",
+		"status": "$request.query.status",
+	}
+	if !reflect.DeepEqual(next.Parameters, expected) {
+		t.Errorf("Expected parameters %v, got %v", expected, next.Parameters)
+	}
+	if prev := response.Links["prev"]; prev.Parameters["cursor"] != "$response.body#/meta/prev" {
+		t.Errorf("Expected the prev cursor expression, got %v", prev.Parameters)
+	}
+	if _, ok := CursorPagination("cursor", "$response.body#/next", "").Links(op)["prev"]; ok {
+		t.Error("Expected no prev link without a prev expression")
+	}
+
+	offset := OffsetPagination("offset", "$response.body#/next", "", "$response.body#/last")
+	op = NewOperation("listOwners", "", "").
+		WithQueryParameter("offset", "", false, Int64Schema()).
+		WithPagination("200", offset)
+	links := op.Responses["200"].Links
+	if len(links) != 3 {
+		t.Fatalf("Expected next, first and last links, got %v", links)
+	}
+	if links["first"].Parameters["offset"] != 0 {
+		t.Errorf("Expected the first page at offset 0, got %v", links["first"].Parameters)
+	}
+	if links["last"].Parameters["offset"] != "$response.body#/last" {
+		t.Errorf("Expected the last offset expression, got %v", links["last"].Parameters)
+	}
+	if op.Responses["200"].Description != "Paginated result" {
+		t.Errorf("Expected a response added for an undocumented code, got '%s'", op.Responses["200"].Description)
+	}
+}
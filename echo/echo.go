@@ -0,0 +1,91 @@
+// Package echoopenapi integrates documents with the Echo web framework
+// (github.com/labstack/echo/v4): it scaffolds operations from the routes of
+// an application, attaches operations to routes, makes the operation of the
+// matched route available to handlers and serves the document
+package echoopenapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nyxstack/openapi"
+)
+
+// SpecRouteName is the name of the routes registered by Serve, which Scaffold skips
+const SpecRouteName = "openapi.spec"
+
+// contextKey is the Echo context key under which Middleware stores the operation
+const contextKey = "openapi.operation"
+
+// methods are the HTTP methods an OpenAPI path item can describe
+var methods = map[string]bool{
+	http.MethodGet: true, http.MethodPut: true, http.MethodPost: true, http.MethodDelete: true,
+	http.MethodOptions: true, http.MethodHead: true, http.MethodPatch: true, http.MethodTrace: true,
+}
+
+// Path converts an Echo route path into an OpenAPI path template: ":id"
+// segments become "{id}" and a trailing "*" becomes "{wildcard}"
+func Path(echoPath string) string {
+	segments := strings.Split(echoPath, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = "{" + segment[1:] + "}"
+		case segment == "*":
+			segments[i] = "{wildcard}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Scaffold documents the routes of an Echo instance that the document doesn't
+// describe yet with placeholder operations (see Document.ScaffoldOperation).
+// Routes serving the document and routes for non-standard methods are skipped.
+func Scaffold(doc *openapi.Document, e *echo.Echo) {
+	for _, route := range e.Routes() {
+		if route.Name == SpecRouteName || !methods[route.Method] {
+			continue
+		}
+		doc.ScaffoldOperation(route.Method, Path(route.Path))
+	}
+}
+
+// Describe documents a registered route with an operation, declaring the
+// path parameters of the route the operation leaves out, and returns the route
+//
+//	echoopenapi.Describe(doc, e.GET("/pets/:id", getPet), openapi.NewOperation("getPet", "Get a pet", ""))
+func Describe(doc *openapi.Document, route *echo.Route, op openapi.Operation) *echo.Route {
+	path := Path(route.Path)
+	doc.AddOperation(path, route.Method, op.WithPathTemplateParameters(path))
+	return route
+}
+
+// Middleware stores the operation documenting the matched route in the Echo
+// context, where handlers read it with Operation. Register it with Echo.Use,
+// which runs after routing.
+func Middleware(doc *openapi.Document) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if item, ok := doc.Paths[Path(c.Path())]; ok {
+				if op := item.Operation(c.Request().Method); op != nil {
+					c.Set(contextKey, op)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// Operation returns the operation stored by Middleware, or nil when the
+// matched route isn't documented
+func Operation(c echo.Context) *openapi.Operation {
+	op, _ := c.Get(contextKey).(*openapi.Operation)
+	return op
+}
+
+// Serve registers a route serving the document as JSON at path, e.g.
+// "/openapi.json"
+func Serve(e *echo.Echo, path string, doc *openapi.Document) {
+	e.GET(path, echo.WrapHandler(doc.Handler())).Name = SpecRouteName
+}
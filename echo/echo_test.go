@@ -0,0 +1,62 @@
+package echoopenapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nyxstack/openapi"
+)
+
+func TestPath(t *testing.T) {
+	for echoPath, want := range map[string]string{
+		"/pets":             "/pets",
+		"/pets/:id":         "/pets/{id}",
+		"/owners/:id/pets/": "/owners/{id}/pets/",
+		"/static/*":         "/static/{wildcard}",
+	} {
+		if got := Path(echoPath); got != want {
+			t.Errorf("Expected '%s' for '%s', got '%s'", want, echoPath, got)
+		}
+	}
+}
+
+func TestEcho(t *testing.T) {
+	doc := openapi.NewDocument("Pet API", "1.0.0")
+	e := echo.New()
+	e.Use(Middleware(doc))
+
+	var seen *openapi.Operation
+	handler := func(c echo.Context) error {
+		seen = Operation(c)
+		return c.NoContent(http.StatusNoContent)
+	}
+	Describe(doc, e.GET("/pets/:id", handler), openapi.NewOperation("getPet", "Get a pet", ""))
+	e.DELETE("/pets/:id", handler)
+	Serve(e, "/openapi.json", doc)
+	Scaffold(doc, e)
+
+	item := doc.Paths["/pets/{id}"]
+	if item.Get == nil || len(item.Get.Parameters) != 1 || item.Get.Parameters[0].Name != "id" {
+		t.Fatalf("Expected described GET with 'id' parameter, got %+v", item.Get)
+	}
+	if item.Delete == nil || item.Delete.OperationID != "deletePetsById" {
+		t.Errorf("Expected scaffolded DELETE operation, got %+v", item.Delete)
+	}
+	if _, ok := doc.Paths["/openapi.json"]; ok {
+		t.Error("Expected spec routes not to be scaffolded")
+	}
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/pets/1", nil))
+	if seen == nil || seen.OperationID != "getPet" {
+		t.Errorf("Expected handler to see getPet, got %+v", seen)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/openapi.json", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "getPet") {
+		t.Errorf("Expected JSON spec, got %d: %s", rec.Code, rec.Body)
+	}
+}
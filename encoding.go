@@ -1,12 +1,36 @@
 package openapi
 
+import (
+	"encoding/json"
+	"mime"
+	"strings"
+)
+
 // Encoding represents encoding in OpenAPI
 type Encoding struct {
-	ContentType   string            `json:"contentType,omitempty"`
-	Headers       map[string]Header `json:"headers,omitempty"`
-	Style         string            `json:"style,omitempty"`
-	Explode       bool              `json:"explode,omitempty"`
-	AllowReserved bool              `json:"allowReserved,omitempty"`
+	ContentType   string                 `json:"contentType,omitempty"`
+	Headers       map[string]Header      `json:"headers,omitempty"`
+	Style         string                 `json:"style,omitempty"`
+	Explode       bool                   `json:"explode,omitempty"`
+	AllowReserved bool                   `json:"allowReserved,omitempty"`
+	Extensions    map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	type encoding Encoding
+	return marshalWithExtensions(encoding(e), e.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	type encoding Encoding
+	if err := json.Unmarshal(data, (*encoding)(e)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	e.Extensions = ext
+	return err
 }
 
 // NewEncoding creates a new encoding
@@ -48,3 +72,120 @@ func (e Encoding) WithAllowReserved(allow bool) Encoding {
 	e.AllowReserved = allow
 	return e
 }
+
+// encodingStyles lists the serialization styles allowed on an Encoding object
+var encodingStyles = map[string]bool{
+	"form":           true,
+	"spaceDelimited": true,
+	"pipeDelimited":  true,
+	"deepObject":     true,
+}
+
+// DefaultEncodingContentType returns the content type the specification assigns
+// to a form or multipart property when the Encoding object does not set one
+func DefaultEncodingContentType(property *Schema) string {
+	if property == nil {
+		return "application/octet-stream"
+	}
+	switch property.Type.Primary() {
+	case "object":
+		return "application/json"
+	case "array":
+		return DefaultEncodingContentType(property.Items)
+	case "string":
+		if property.Format == "binary" || property.Format == "byte" {
+			return "application/octet-stream"
+		}
+	}
+	return "text/plain"
+}
+
+// validateMediaType checks the encoding map of a media type against its schema
+func (v *validator) validateMediaType(pointer, name string, mt MediaType, requestBody bool) {
+	if len(mt.Encoding) == 0 {
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(name)
+	if err != nil {
+		mediaType = strings.ToLower(name)
+	}
+	form := mediaType == "application/x-www-form-urlencoded"
+	multipart := strings.HasPrefix(mediaType, "multipart/")
+	if !form && !multipart {
+		v.warnf(pointer+"/encoding", "encoding is ignored for media type %q; it only applies to multipart and application/x-www-form-urlencoded bodies", name)
+		return
+	}
+	if !requestBody {
+		v.warnf(pointer+"/encoding", "encoding only applies to request bodies and is ignored here")
+	}
+
+	properties, open := v.doc.schemaProperties(mt.Schema)
+	for _, prop := range sortedKeys(mt.Encoding) {
+		enc := mt.Encoding[prop]
+		encPointer := pointer + "/encoding/" + escapePointer(prop)
+
+		if _, ok := properties[prop]; !ok && !open {
+			v.errorf(encPointer, "encoding key %q does not match any property of the media type schema", prop)
+		}
+
+		if enc.ContentType != "" {
+			for _, ct := range strings.Split(enc.ContentType, ",") {
+				if _, _, err := mime.ParseMediaType(strings.TrimSpace(ct)); err != nil {
+					v.errorf(encPointer+"/contentType", "invalid content type %q: %v", strings.TrimSpace(ct), err)
+				}
+			}
+			if def := DefaultEncodingContentType(properties[prop]); def == "application/json" && strings.HasPrefix(enc.ContentType, "text/plain") {
+				v.warnf(encPointer+"/contentType", "structured property is encoded as %q; the default would be %q", enc.ContentType, def)
+			}
+		}
+
+		if enc.Style != "" && !encodingStyles[enc.Style] {
+			v.errorf(encPointer+"/style", "style %q is not allowed; use form, spaceDelimited, pipeDelimited or deepObject", enc.Style)
+		}
+		if multipart && mediaType != "multipart/form-data" && (enc.Style != "" || enc.Explode || enc.AllowReserved) {
+			v.warnf(encPointer, "style, explode and allowReserved are ignored for media type %q", name)
+		}
+		if form && len(enc.Headers) > 0 {
+			v.warnf(encPointer+"/headers", "headers are ignored for application/x-www-form-urlencoded bodies")
+		}
+	}
+}
+
+// schemaProperties collects the properties of an object schema, following references
+// and allOf members. The open flag reports whether additional properties are permitted
+// or the shape could not be determined, in which case any key is acceptable.
+func (d *Document) schemaProperties(s *Schema) (properties map[string]*Schema, open bool) {
+	properties = make(map[string]*Schema)
+	var collect func(s *Schema, depth int)
+	collect = func(s *Schema, depth int) {
+		s = d.resolveSchema(s)
+		if s == nil || depth > 16 {
+			open = true
+			return
+		}
+		for name, prop := range s.Properties {
+			properties[name] = d.resolveSchema(prop)
+		}
+		for _, sub := range s.AllOf {
+			collect(sub, depth+1)
+		}
+		if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+			open = true
+		}
+		if ap := s.AdditionalProperties; ap != nil && (ap.Schema != nil || (ap.Bool != nil && *ap.Bool)) {
+			open = true
+		}
+	}
+	collect(s, 0)
+	return properties, open
+}
+
+// WithExtension sets a specification extension; the name must start with "x-"
+func (e Encoding) WithExtension(name string, value interface{}) Encoding {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]interface{})
+	}
+	e.Extensions[name] = value
+	return e
+}
@@ -0,0 +1,97 @@
+package openapi
+
+// Extensions documenting enum values, in the form understood by common code generators
+const (
+	// ExtensionEnumVarNames lists the identifier of each enum value, in enum order
+	ExtensionEnumVarNames = "x-enum-varnames"
+	// ExtensionEnumDescriptions lists the description of each enum value, in enum order
+	ExtensionEnumDescriptions = "x-enum-descriptions"
+	// ExtensionEnumDeprecated lists the enum values that are deprecated
+	ExtensionEnumDeprecated = "x-enum-deprecated"
+)
+
+// EnumValue documents one value of an enum
+type EnumValue struct {
+	Value interface{}
+	// Name is the identifier code generators use for the value
+	Name        string
+	Description string
+	Deprecated  bool
+}
+
+// NewEnumValue creates an enum value with its identifier and description
+func NewEnumValue(value interface{}, name, description string) EnumValue {
+	return EnumValue{Value: value, Name: name, Description: description}
+}
+
+// WithDeprecated marks the enum value as deprecated
+func (e EnumValue) WithDeprecated() EnumValue {
+	e.Deprecated = true
+	return e
+}
+
+// WithEnumValues sets the enum of the schema along with the names,
+// descriptions and deprecation of its values
+func (s Schema) WithEnumValues(values ...EnumValue) Schema {
+	enum := make([]interface{}, len(values))
+	names := make([]string, len(values))
+	descriptions := make([]string, len(values))
+	var deprecated []interface{}
+	hasNames, hasDescriptions := false, false
+	for i, v := range values {
+		enum[i] = v.Value
+		names[i] = v.Name
+		descriptions[i] = v.Description
+		hasNames = hasNames || v.Name != ""
+		hasDescriptions = hasDescriptions || v.Description != ""
+		if v.Deprecated {
+			deprecated = append(deprecated, v.Value)
+		}
+	}
+
+	s.Enum = enum
+	if hasNames {
+		s = s.WithExtension(ExtensionEnumVarNames, names)
+	}
+	if hasDescriptions {
+		s = s.WithExtension(ExtensionEnumDescriptions, descriptions)
+	}
+	if len(deprecated) > 0 {
+		s = s.WithExtension(ExtensionEnumDeprecated, deprecated)
+	}
+	return s
+}
+
+// WithDeprecatedEnumValues marks values of the schema's enum as deprecated
+func (s Schema) WithDeprecatedEnumValues(values ...interface{}) Schema {
+	var deprecated []interface{}
+	decodeExtension(s.Extensions[ExtensionEnumDeprecated], &deprecated)
+	for _, v := range values {
+		if !containsValue(deprecated, v) {
+			deprecated = append(deprecated, v)
+		}
+	}
+	return s.WithExtension(ExtensionEnumDeprecated, deprecated)
+}
+
+// EnumValues returns the enum of the schema with the metadata documented
+// through the enum extensions
+func (s *Schema) EnumValues() []EnumValue {
+	var names, descriptions []string
+	var deprecated []interface{}
+	decodeExtension(s.Extensions[ExtensionEnumVarNames], &names)
+	decodeExtension(s.Extensions[ExtensionEnumDescriptions], &descriptions)
+	decodeExtension(s.Extensions[ExtensionEnumDeprecated], &deprecated)
+
+	values := make([]EnumValue, len(s.Enum))
+	for i, v := range s.Enum {
+		values[i] = EnumValue{Value: v, Deprecated: containsValue(deprecated, v)}
+		if i < len(names) {
+			values[i].Name = names[i]
+		}
+		if i < len(descriptions) {
+			values[i].Description = descriptions[i]
+		}
+	}
+	return values
+}
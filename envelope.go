@@ -0,0 +1,165 @@
+package openapi
+
+import (
+	"strings"
+)
+
+// Envelope describes a response body envelope such as {data, meta, errors}
+type Envelope struct {
+	// DataProperty holds the original body; defaults to "data"
+	DataProperty string
+	// Properties are the other properties of the envelope
+	Properties map[string]*Schema
+	// Required lists the envelope properties required besides the data property
+	Required []string
+}
+
+// DefaultEnvelope returns the {data, meta, errors} envelope, with free-form
+// metadata and a list of error objects
+func DefaultEnvelope() Envelope {
+	errorObject := NewObjectSchema().
+		WithRequiredProperty("message", StringSchema("")).
+		WithProperty("code", StringSchema(""))
+	return Envelope{
+		DataProperty: "data",
+		Properties: map[string]*Schema{
+			"meta":   NewObjectSchema(),
+			"errors": NewArraySchema(&errorObject),
+		},
+	}
+}
+
+func (e Envelope) dataProperty() string {
+	if e.DataProperty == "" {
+		return "data"
+	}
+	return e.DataProperty
+}
+
+// WrapResponses returns a copy of the document in which the JSON bodies of
+// every success response are wrapped in the envelope, along with their
+// examples. Responses defined in components and referenced by success
+// responses are wrapped in place, so the references stay valid. Bodies that
+// are already enveloped are left untouched.
+func (d *Document) WrapResponses(envelope Envelope) (*Document, error) {
+	wrapped, err := d.Clone()
+	if err != nil {
+		return nil, err
+	}
+	data := envelope.dataProperty()
+	wrapped.transformSuccessBodies(func(mt MediaType) MediaType {
+		if mt.Schema == nil || wrapped.isEnvelope(envelope, mt.Schema) {
+			return mt
+		}
+		schema := NewObjectSchema().WithRequiredProperty(data, mt.Schema)
+		for _, name := range sortedKeys(envelope.Properties) {
+			prop := *envelope.Properties[name]
+			schema = schema.WithProperty(name, &prop)
+		}
+		schema = schema.WithRequired(envelope.Required...)
+		mt.Schema = &schema
+		mt.Example, mt.Examples = transformExamples(mt, func(value interface{}) interface{} {
+			return map[string]interface{}{data: value}
+		})
+		return mt
+	})
+	return wrapped, nil
+}
+
+// UnwrapResponses reverses WrapResponses: it returns a copy of the document
+// in which enveloped success response bodies are replaced by the schema of
+// their data property, along with their examples. Component schemas only the
+// envelopes referenced are removed.
+func (d *Document) UnwrapResponses(envelope Envelope) (*Document, error) {
+	unwrapped, err := d.Clone()
+	if err != nil {
+		return nil, err
+	}
+	data := envelope.dataProperty()
+	before := unwrapped.referencedComponents()
+	unwrapped.transformSuccessBodies(func(mt MediaType) MediaType {
+		if mt.Schema == nil || !unwrapped.isEnvelope(envelope, mt.Schema) {
+			return mt
+		}
+		mt.Schema = unwrapped.resolveSchema(mt.Schema).Properties[data]
+		mt.Example, mt.Examples = transformExamples(mt, func(value interface{}) interface{} {
+			if obj, ok := value.(map[string]interface{}); ok {
+				return obj[data]
+			}
+			return value
+		})
+		return mt
+	})
+	after := unwrapped.referencedComponents()
+	for key := range before {
+		if !after[key] {
+			unwrapped.removeComponent(key)
+		}
+	}
+	return unwrapped, nil
+}
+
+// isEnvelope reports whether a schema has the data property and the other
+// properties of the envelope
+func (d *Document) isEnvelope(envelope Envelope, s *Schema) bool {
+	s = d.resolveSchema(s)
+	if s == nil || s.Properties[envelope.dataProperty()] == nil {
+		return false
+	}
+	for name := range envelope.Properties {
+		if s.Properties[name] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// transformSuccessBodies replaces the JSON media types of the 2XX responses
+// of every operation, and of the response components they reference
+func (d *Document) transformSuccessBodies(fn func(MediaType) MediaType) {
+	transform := func(r Response) {
+		for name, mt := range r.Content {
+			if isJSONMediaType(name) {
+				r.Content[name] = fn(mt)
+			}
+		}
+	}
+	components := make(map[string]bool)
+	d.walkOperations(func(_, _ string, op *Operation) {
+		for code, r := range op.Responses {
+			if !strings.HasPrefix(code, "2") {
+				continue
+			}
+			if name, ok := strings.CutPrefix(r.Ref.String(), "#/components/responses/"); ok {
+				components[unescapePointer(name)] = true
+				continue
+			}
+			transform(r)
+		}
+	})
+	if d.Components == nil {
+		return
+	}
+	for _, name := range sortedKeys(components) {
+		if r, ok := d.Components.Responses[name]; ok {
+			transform(r)
+		}
+	}
+}
+
+// transformExamples applies fn to the example and to the example values of a
+// media type
+func transformExamples(mt MediaType, fn func(interface{}) interface{}) (interface{}, map[string]Example) {
+	example := mt.Example
+	if example != nil {
+		example = fn(example)
+	}
+	examples := mt.Examples
+	for name, ex := range examples {
+		if ex.Value != nil {
+			ex.Value = fn(ex.Value)
+			examples[name] = ex
+		}
+	}
+	return example, examples
+}
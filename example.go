@@ -1,7 +1,17 @@
 package openapi
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
 // Example represents an example in OpenAPI
 type Example struct {
+	Ref           *Reference             `json:"-"`
 	Summary       string                 `json:"summary,omitempty"`
 	Description   string                 `json:"description,omitempty"`
 	Value         interface{}            `json:"value,omitempty"`
@@ -9,6 +19,33 @@ type Example struct {
 	Extensions    map[string]interface{} `json:"-"`
 }
 
+// MarshalJSON implements custom JSON marshaling to include specification
+// extensions. A reference is marshaled as the Reference Object alone.
+func (e Example) MarshalJSON() ([]byte, error) {
+	if e.Ref != nil {
+		return json.Marshal(e.Ref)
+	}
+	type example Example
+	return marshalWithExtensions(example(e), e.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification
+// extensions and decode Reference Objects
+func (e *Example) UnmarshalJSON(data []byte) error {
+	ref, err := unmarshalReference(data)
+	if err != nil || ref != nil {
+		*e = Example{Ref: ref}
+		return err
+	}
+	type example Example
+	if err := json.Unmarshal(data, (*example)(e)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	e.Extensions = ext
+	return err
+}
+
 // NewExample creates a new example
 func NewExample() Example {
 	return Example{}
@@ -37,3 +74,90 @@ func (e Example) WithExternalValue(url string) Example {
 	e.ExternalValue = url
 	return e
 }
+
+// validateExamples checks that Example objects and their owners don't set
+// mutually exclusive fields
+func (v *validator) validateExamples() {
+	v.doc.walkExamples(func(pointer string, ex Example) {
+		if ex.Value != nil && ex.ExternalValue != "" {
+			v.errorf(pointer, "value and externalValue are mutually exclusive")
+		}
+	})
+
+	exclusive := func(pointer string, example interface{}, examples map[string]Example) {
+		if example != nil && len(examples) > 0 {
+			v.errorf(pointer, "example and examples are mutually exclusive")
+		}
+	}
+	v.doc.walkParameters(func(pointer string, p Parameter) {
+		exclusive(pointer, p.Example, p.Examples)
+	})
+	v.doc.walkHeaders(func(pointer, _ string, h Header) {
+		exclusive(pointer, h.Example, h.Examples)
+	})
+	v.doc.walkMediaTypes(func(pointer, _ string, mt MediaType, _ bool) {
+		exclusive(pointer, mt.Example, mt.Examples)
+	})
+}
+
+// CheckExternalExamples verifies that every absolute externalValue URL in the
+// document resolves, using an HTTP HEAD request (falling back to GET when HEAD
+// is not allowed). It is intended for CI pipelines; a nil client uses one with
+// a 10 second timeout. Relative URLs are skipped because they depend on where
+// the document is hosted.
+func CheckExternalExamples(ctx context.Context, d *Document, client *http.Client) []ValidationError {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	var errs []ValidationError
+	checked := make(map[string]error)
+	d.walkExamples(func(pointer string, ex Example) {
+		u, err := url.Parse(ex.ExternalValue)
+		if ex.ExternalValue == "" || (err == nil && !u.IsAbs()) {
+			return
+		}
+		if err != nil {
+			errs = append(errs, ValidationError{Path: pointer + "/externalValue", Message: fmt.Sprintf("invalid externalValue URL: %v", err), Severity: SeverityError})
+			return
+		}
+
+		result, ok := checked[ex.ExternalValue]
+		if !ok {
+			result = fetchExternalExample(ctx, client, ex.ExternalValue)
+			checked[ex.ExternalValue] = result
+		}
+		if result != nil {
+			errs = append(errs, ValidationError{Path: pointer + "/externalValue", Message: result.Error(), Severity: SeverityError})
+		}
+	})
+	return errs
+}
+
+// fetchExternalExample checks that a single external example URL resolves
+func fetchExternalExample(ctx context.Context, client *http.Client, target string) error {
+	status, err := requestStatus(ctx, client, http.MethodHead, target)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = requestStatus(ctx, client, http.MethodGet, target)
+	}
+	if err != nil {
+		return fmt.Errorf("external example %s could not be fetched: %v", target, err)
+	}
+	if status >= 400 {
+		return fmt.Errorf("external example %s returned status %d", target, status)
+	}
+	return nil
+}
+
+func requestStatus(ctx context.Context, client *http.Client, method, target string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
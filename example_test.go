@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateExampleValueAndExternalValue(t *testing.T) {
+	doc := NewDocument("Test API", "1.0.0")
+	doc.AddComponents().Examples["pet"] = NewExample().
+		WithValue(map[string]interface{}{"name": "Rex"}).
+		WithExternalValue("https://example.com/pet.json")
+
+	errs := doc.Validate()
+	if err := findValidationError(errs, "/components/examples/pet"); err == nil || err.Severity != SeverityError {
+		t.Errorf("Expected error for value and externalValue, got %v", errs)
+	}
+}
+
+func TestCheckExternalExamples(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok.json" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	doc := NewDocument("Test API", "1.0.0")
+	examples := doc.AddComponents().Examples
+	examples["ok"] = NewExample().WithExternalValue(server.URL + "/ok.json")
+	examples["missing"] = NewExample().WithExternalValue(server.URL + "/missing.json")
+	examples["relative"] = NewExample().WithExternalValue("examples/pet.json")
+
+	errs := CheckExternalExamples(context.Background(), doc, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+
+	if errs[0].Path != "/components/examples/missing/externalValue" {
+		t.Errorf("Expected error for missing example, got '%s'", errs[0].Path)
+	}
+}
+
+func TestExampleSet(t *testing.T) {
+	op := NewOperation("createPet", "", "").
+		WithRequestBody("", true, map[string]MediaType{"application/json": {Schema: NewObjectSchema()}}).
+		WithJSONResponse("201", "Created", NewObjectSchema()).
+		WithExampleSet(NewExampleSet("happy-path", "A valid pet").
+			WithRequest(map[string]interface{}{"name": "Rex"}).
+			WithResponse("201", map[string]interface{}{"id": 1, "name": "Rex"})).
+		WithExampleSet(NewExampleSet("validation-error", "A pet without a name").
+			WithRequest(map[string]interface{}{}).
+			WithResponse("400", map[string]interface{}{"error": "name is required"}))
+
+	if ex := op.RequestBody.Content["application/json"].Examples["validation-error"]; ex.Summary != "A pet without a name" {
+		t.Errorf("Expected the request example of validation-error, got %+v", ex)
+	}
+	if response := op.Responses["400"]; response.Description != "Bad Request" || response.Content["application/json"].Examples["validation-error"].Value == nil {
+		t.Errorf("Expected a 400 response with the validation-error example, got %+v", response)
+	}
+
+	sets := op.ExampleSets()
+	if len(sets) != 2 || sets[0].Name != "happy-path" || sets[0].Responses["201"] == nil || sets[1].Responses["400"] == nil {
+		t.Errorf("Expected the happy-path and validation-error sets, got %+v", sets)
+	}
+}
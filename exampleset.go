@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ExampleSet is a named scenario pairing a request example with the response
+// examples it produces, such as "happy-path" or "validation-error". Its
+// examples are stored under the set's name in the examples maps of the
+// request body and responses, so documentation renderers can show coherent
+// request/response pairs.
+type ExampleSet struct {
+	Name    string
+	Summary string
+	// Request is the request body example; nil for scenarios without a body
+	Request interface{}
+	// Responses maps status codes to response body examples
+	Responses map[string]interface{}
+}
+
+// NewExampleSet creates an example set
+func NewExampleSet(name, summary string) ExampleSet {
+	return ExampleSet{Name: name, Summary: summary}
+}
+
+// WithRequest sets the request body example
+func (s ExampleSet) WithRequest(value interface{}) ExampleSet {
+	s.Request = value
+	return s
+}
+
+// WithResponse sets the response body example for a status code
+func (s ExampleSet) WithResponse(code string, value interface{}) ExampleSet {
+	responses := make(map[string]interface{}, len(s.Responses)+1)
+	for c, v := range s.Responses {
+		responses[c] = v
+	}
+	responses[code] = value
+	s.Responses = responses
+	return s
+}
+
+// WithExampleSet stores the examples of a set in the examples maps of every
+// media type of the request body and of the responses with the set's status
+// codes. Responses without content gain an application/json media type for
+// their example; missing responses are added with the status text as
+// description.
+func (o Operation) WithExampleSet(set ExampleSet) Operation {
+	example := func(value interface{}) Example {
+		return Example{Summary: set.Summary, Value: value}
+	}
+	withExample := func(content map[string]MediaType, value interface{}) map[string]MediaType {
+		if len(content) == 0 {
+			content = map[string]MediaType{"application/json": {}}
+		}
+		updated := make(map[string]MediaType, len(content))
+		for name, mt := range content {
+			examples := make(map[string]Example, len(mt.Examples)+1)
+			for n, ex := range mt.Examples {
+				examples[n] = ex
+			}
+			examples[set.Name] = example(value)
+			mt.Examples = examples
+			updated[name] = mt
+		}
+		return updated
+	}
+
+	if set.Request != nil {
+		body := RequestBody{}
+		if o.RequestBody != nil {
+			body = *o.RequestBody
+		}
+		body.Content = withExample(body.Content, set.Request)
+		o.RequestBody = &body
+	}
+
+	responses := make(map[string]Response, len(o.Responses)+len(set.Responses))
+	for code, response := range o.Responses {
+		responses[code] = response
+	}
+	for code, value := range set.Responses {
+		response, ok := responses[code]
+		if !ok {
+			status, _ := strconv.Atoi(code)
+			response.Description = http.StatusText(status)
+		}
+		response.Content = withExample(response.Content, value)
+		responses[code] = response
+	}
+	o.Responses = responses
+	return o
+}
+
+// ExampleSets reassembles the example sets of the operation from the examples
+// its request body and responses share by name, ordered by name
+func (o Operation) ExampleSets() []ExampleSet {
+	sets := make(map[string]*ExampleSet)
+	set := func(name string, ex Example) *ExampleSet {
+		s, ok := sets[name]
+		if !ok {
+			s = &ExampleSet{Name: name, Summary: ex.Summary}
+			sets[name] = s
+		}
+		return s
+	}
+	if o.RequestBody != nil {
+		for _, mediaType := range sortedKeys(o.RequestBody.Content) {
+			for name, ex := range o.RequestBody.Content[mediaType].Examples {
+				if s := set(name, ex); s.Request == nil {
+					s.Request = ex.Value
+				}
+			}
+		}
+	}
+	for _, code := range sortedKeys(o.Responses) {
+		content := o.Responses[code].Content
+		for _, mediaType := range sortedKeys(content) {
+			for name, ex := range content[mediaType].Examples {
+				s := set(name, ex)
+				if _, ok := s.Responses[code]; !ok {
+					*s = s.WithResponse(code, ex.Value)
+				}
+			}
+		}
+	}
+
+	var result []ExampleSet
+	for _, name := range sortedKeys(sets) {
+		result = append(result, *sets[name])
+	}
+	return result
+}
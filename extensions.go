@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// marshalWithExtensions marshals v, which must encode to a JSON object, and
+// appends the specification extensions in ext in sorted key order.
+// Keys that don't start with "x-" are not valid extensions and are skipped.
+func marshalWithExtensions(v interface{}, ext map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(ext) == 0 {
+		return data, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	empty := bytes.Equal(bytes.TrimSpace(data), []byte("{}"))
+	for _, key := range sortedKeys(ext) {
+		if !isExtensionKey(key) {
+			continue
+		}
+		value, err := json.Marshal(ext[key])
+		if err != nil {
+			return nil, err
+		}
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalExtensions extracts the specification extensions of a JSON object.
+// It returns nil when the object has none.
+func unmarshalExtensions(data []byte) (map[string]interface{}, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var ext map[string]interface{}
+	for key, raw := range fields {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		if ext == nil {
+			ext = make(map[string]interface{})
+		}
+		ext[key] = value
+	}
+	return ext, nil
+}
+
+// decodeExtension converts an extension value into a typed struct. Values set
+// through typed builders are stored as-is, while values loaded from JSON are
+// generic maps, so both are normalized through a JSON round-trip.
+func decodeExtension(value interface{}, dst interface{}) bool {
+	if value == nil {
+		return false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, dst) == nil
+}
@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOperationExtensionsRoundTrip(t *testing.T) {
+	op := NewOperation("listPets", "List pets", "").
+		WithSLA(SLA{P99Ms: 250, RateLimit: "100/min"}).
+		WithExtension("x-internal", true)
+
+	data, err := json.Marshal(op)
+	if err != nil {
+		t.Fatalf("Error marshaling operation: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"x-sla":{"p99_ms":250,"rate_limit":"100/min"}`) {
+		t.Errorf("Expected x-sla extension in output, got %s", data)
+	}
+
+	var unmarshaled Operation
+	if err := json.Unmarshal(data, &unmarshaled); err != nil {
+		t.Fatalf("Error unmarshaling operation: %v", err)
+	}
+
+	if unmarshaled.Extensions["x-internal"] != true {
+		t.Errorf("Expected x-internal extension to survive round-trip, got %v", unmarshaled.Extensions)
+	}
+
+	sla, ok := unmarshaled.SLA()
+	if !ok || sla.P99Ms != 250 || sla.RateLimit != "100/min" {
+		t.Errorf("Expected SLA to survive round-trip, got %+v", sla)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	doc := NewDocument("Test API", "1.0.0")
+	doc.Webhooks["orderCreated"] = PathItem{Post: &Operation{
+		Summary: "Order created",
+		RequestBody: &RequestBody{Content: map[string]MediaType{
+			"application/json": {Schema: &Schema{Type: Types{"object"}, Properties: map[string]*Schema{"id": {Type: Types{"string"}, Example: "ord_1"}}}},
+		}},
+		Responses: map[string]Response{"200": {Description: "Received"}},
+	}}
+
+	sig := NewWebhookSignature("X-Signature").WithTimestamp("X-Timestamp")
+	doc.WithWebhookSignature(sig).WithWebhookSignature(sig)
+
+	op := doc.Webhooks["orderCreated"].Post
+	if len(op.Parameters) != 2 {
+		t.Fatalf("Expected signature and timestamp headers, got %d parameters", len(op.Parameters))
+	}
+	if len(op.CodeSamples()) != 3 {
+		t.Errorf("Expected 3 code samples, got %d", len(op.CodeSamples()))
+	}
+
+	example, _ := op.Parameters[0].Example.(string)
+	if !sig.Verify(sig.SampleSecret, sig.SampleTimestamp, []byte(`{"id":"ord_1"}`), example) {
+		t.Errorf("Expected example signature to verify, got %s", example)
+	}
+
+	markdown := doc.WebhookMarkdown()
+	if !strings.Contains(markdown, "## orderCreated") || !strings.Contains(markdown, "X-Signature") {
+		t.Errorf("Expected webhook section in markdown, got %s", markdown)
+	}
+}
+
+func TestGoCodegenExtensions(t *testing.T) {
+	schema := Schema{Type: Types{"string"}}.
+		WithGoName("Amount").
+		WithGoType("decimal.Decimal", "github.com/shopspring/decimal").
+		WithExtraTag("validate", "required")
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Error marshaling schema: %v", err)
+	}
+	var decoded Schema
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Error unmarshaling schema: %v", err)
+	}
+
+	if decoded.GoName() != "Amount" || decoded.GoType() != "decimal.Decimal" {
+		t.Errorf("Expected go name and type, got %q and %q", decoded.GoName(), decoded.GoType())
+	}
+	if imp, ok := decoded.GoTypeImport(); !ok || imp.Path != "github.com/shopspring/decimal" {
+		t.Errorf("Expected go type import, got %+v", imp)
+	}
+	if decoded.ExtraTags()["validate"] != "required" {
+		t.Errorf("Expected extra tag, got %v", decoded.ExtraTags())
+	}
+
+	param := NewParameter("id", "path", "").WithGoName("ID")
+	if param.GoName() != "ID" {
+		t.Errorf("Expected parameter go name ID, got %q", param.GoName())
+	}
+}
+
+func TestClientGuidance(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0").WithClientGuidance(NewClientGuidance(10 * time.Second))
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithClientGuidance(NewClientGuidance(2*time.Second).
+			WithBackoff(NewBackoff(BackoffExponential, 100*time.Millisecond, 5).WithJitter(true))))
+	doc.AddOperation("/pets", "POST", NewOperation("createPet", "", ""))
+	doc.AddOperation("/orders", "POST", NewOperation("createOrder", "", "").WithIdempotencyKey(true))
+	doc.AddOperation("/orders", "PATCH", NewOperation("updateOrder", "", "").
+		WithClientGuidance(NewClientGuidance(time.Second).WithRetrySafe(false)))
+
+	list := doc.EffectiveClientGuidance("/pets", "GET", doc.Paths["/pets"].Get)
+	if list.Timeout != 2000 || !*list.RetrySafe {
+		t.Errorf("Expected the operation guidance and a retry-safe GET, got %+v", list)
+	}
+	if text := list.Description(); text != "Recommended timeout: 2s. Safe to retry with exponential backoff starting at 100ms, up to 5 attempts, with jitter." {
+		t.Errorf("Unexpected description '%s'", text)
+	}
+	if create := doc.EffectiveClientGuidance("/pets", "POST", doc.Paths["/pets"].Post); create.Timeout != 10000 || *create.RetrySafe {
+		t.Errorf("Expected the document guidance and an unsafe POST, got %+v", create)
+	}
+	if order := doc.EffectiveClientGuidance("/orders", "POST", doc.Paths["/orders"].Post); !*order.RetrySafe {
+		t.Error("Expected the idempotency key to make createOrder safe to retry")
+	}
+
+	errs := RetryProfile{}.Validate(doc)
+	if len(errs) != 1 || errs[0].Path != "/paths/~1pets/post" || errs[0].Rule != "retry/idempotency-key" {
+		t.Errorf("Expected only createPet to be reported, got %v", errs)
+	}
+}
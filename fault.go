@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Latency draws an artificial response delay
+type Latency func(rng *rand.Rand) time.Duration
+
+// FixedLatency delays every response by d
+func FixedLatency(d time.Duration) Latency {
+	return func(*rand.Rand) time.Duration {
+		return d
+	}
+}
+
+// UniformLatency delays responses by a duration drawn uniformly between min and max
+func UniformLatency(min, max time.Duration) Latency {
+	return func(rng *rand.Rand) time.Duration {
+		if max <= min {
+			return min
+		}
+		return min + time.Duration(rng.Int64N(int64(max-min)))
+	}
+}
+
+// NormalLatency delays responses by a normally distributed duration, never
+// less than zero
+func NormalLatency(mean, stddev time.Duration) Latency {
+	return func(rng *rand.Rand) time.Duration {
+		return time.Duration(math.Max(0, float64(mean)+rng.NormFloat64()*float64(stddev)))
+	}
+}
+
+// MockFaults degrades mock responses for resilience testing
+type MockFaults struct {
+	// Latency delays responses when set
+	Latency Latency
+	// ErrorRate is the share of requests, from 0 to 1, answered with one of the
+	// operation's documented 5XX responses, or 503 Service Unavailable when it
+	// documents none
+	ErrorRate float64
+	// BytesPerSecond throttles response bodies when positive
+	BytesPerSecond int
+}
+
+// faultInjector applies MockFaults, drawing from a shared random source
+type faultInjector struct {
+	faults map[string]MockFaults
+	mu     sync.Mutex
+	rng    *rand.Rand
+}
+
+func newFaultInjector(faults map[string]MockFaults, rng *rand.Rand) *faultInjector {
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return &faultInjector{faults: faults, rng: rng}
+}
+
+// lookup returns the faults of an operation, falling back to the "*" entry
+func (f *faultInjector) lookup(op *Operation) (MockFaults, bool) {
+	if faults, ok := f.faults[op.OperationID]; ok && op.OperationID != "" {
+		return faults, true
+	}
+	faults, ok := f.faults["*"]
+	return faults, ok
+}
+
+// delay draws the latency of a response
+func (f *faultInjector) delay(latency Latency) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return latency(f.rng)
+}
+
+// fail reports whether to inject an error and which documented response to
+// answer with; the code is empty to answer 503 without a documented response
+func (f *faultInjector) fail(op *Operation, rate float64) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if rate <= 0 || f.rng.Float64() >= rate {
+		return "", false
+	}
+	var codes []string
+	for _, code := range sortedKeys(op.Responses) {
+		if strings.HasPrefix(code, "5") {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return "", true
+	}
+	return codes[f.rng.IntN(len(codes))], true
+}
+
+// throttledWriter paces response bodies to a number of bytes per second
+type throttledWriter struct {
+	http.ResponseWriter
+	rate int
+}
+
+func (w *throttledWriter) Write(b []byte) (int, error) {
+	chunk := max(w.rate/10, 1)
+	written := 0
+	for written < len(b) {
+		end := min(written+chunk, len(b))
+		n, err := w.ResponseWriter.Write(b[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		http.NewResponseController(w.ResponseWriter).Flush()
+		time.Sleep(time.Duration(n) * time.Second / time.Duration(w.rate))
+	}
+	return written, nil
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *throttledWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,47 @@
+package openapi
+
+// ExtensionFeature names the feature flag an element belongs to
+const ExtensionFeature = "x-feature"
+
+// WithFeature makes the operation part of a feature, included by Build only
+// when the feature is enabled
+func (o Operation) WithFeature(feature string) Operation {
+	return o.WithExtension(ExtensionFeature, feature)
+}
+
+// WithFeature makes the parameter part of a feature
+func (p Parameter) WithFeature(feature string) Parameter {
+	return p.WithExtension(ExtensionFeature, feature)
+}
+
+// WithFeature makes the schema part of a feature, as a component schema or
+// as a property
+func (s Schema) WithFeature(feature string) Schema {
+	return s.WithExtension(ExtensionFeature, feature)
+}
+
+// Build assembles the document published for a set of feature flags:
+// operations, parameters, component schemas and properties belonging to a
+// feature that isn't enabled are left out, and so are the components only
+// they referenced. Components that were unreferenced to begin with are kept.
+// Elements without a feature are always included. The feature extensions are
+// dropped and the document itself is left untouched.
+func (d *Document) Build(flags map[string]bool) (*Document, error) {
+	built, err := d.Clone()
+	if err != nil {
+		return nil, err
+	}
+	before := built.referencedComponents()
+	built.filterElements(func(ext map[string]interface{}) bool {
+		var feature string
+		return !decodeExtension(ext[ExtensionFeature], &feature) || flags[feature]
+	})
+	built.stripExtensions(ExtensionFeature)
+	after := built.referencedComponents()
+	for key := range before {
+		if !after[key] {
+			built.removeComponent(key)
+		}
+	}
+	return built, nil
+}
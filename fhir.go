@@ -0,0 +1,75 @@
+package openapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FHIRMediaType is the media type of FHIR JSON resources
+const FHIRMediaType = "application/fhir+json"
+
+// FHIRProfile checks the conventions of the FHIR RESTful API:
+//
+//   - fhir/resource-paths: paths start with a resource type such as
+//     /Patient, with "metadata" or with an operation such as $everything
+//   - fhir/media-type: request and response bodies are offered as
+//     application/fhir+json
+//   - fhir/operation-outcome: error responses return an OperationOutcome
+//     resource
+//   - fhir/capability-statement: GET /metadata returns the server's
+//     CapabilityStatement; reported as a warning
+type FHIRProfile struct{}
+
+var fhirResourceType = regexp.MustCompile(`^[A-Z][A-Za-z]+$`)
+
+// Name returns "fhir"
+func (FHIRProfile) Name() string {
+	return "fhir"
+}
+
+// Validate checks the document against the FHIR conventions
+func (FHIRProfile) Validate(d *Document) []ValidationError {
+	var errs []ValidationError
+	report := func(rule, pointer, message string, severity Severity) {
+		errs = append(errs, ValidationError{Path: pointer, Message: message, Severity: severity, Rule: rule})
+	}
+
+	for _, path := range sortedKeys(d.Paths) {
+		segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+		if !fhirResourceType.MatchString(segment) && segment != "metadata" && !strings.HasPrefix(segment, "$") {
+			report("fhir/resource-paths", "/paths/"+escapePointer(path), "path "+path+" doesn't start with a resource type", SeverityError)
+		}
+	}
+
+	bodies := func(pointer string, content map[string]MediaType) {
+		if _, ok := content[FHIRMediaType]; len(content) > 0 && !ok {
+			report("fhir/media-type", pointer+"/content", "bodies must be offered as "+FHIRMediaType, SeverityError)
+		}
+	}
+	d.walkOperations(func(path, method string, op *Operation) {
+		if op.RequestBody != nil {
+			bodies(operationPointer(path, method)+"/requestBody", op.RequestBody.Content)
+		}
+	})
+	d.walkResponses(func(pointer string, r Response) {
+		bodies(pointer, r.Content)
+	})
+
+	d.walkOperations(func(path, method string, op *Operation) {
+		for _, code := range sortedKeys(op.Responses) {
+			if !strings.HasPrefix(code, "4") && !strings.HasPrefix(code, "5") && code != "default" {
+				continue
+			}
+			pointer := operationPointer(path, method) + "/responses/" + code
+			mt, ok := d.resolveResponse(op.Responses[code]).Content[FHIRMediaType]
+			if !ok || mt.Schema == nil || mt.Schema.Ref != "#/components/schemas/OperationOutcome" {
+				report("fhir/operation-outcome", pointer, "error responses must return an OperationOutcome as "+FHIRMediaType, SeverityError)
+			}
+		}
+	})
+
+	if item, ok := d.Paths["/metadata"]; !ok || item.Get == nil {
+		report("fhir/capability-statement", "/paths", "GET /metadata should return the CapabilityStatement", SeverityWarning)
+	}
+	return errs
+}
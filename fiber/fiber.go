@@ -0,0 +1,142 @@
+// Package fiberopenapi integrates documents with the Fiber web framework
+// (github.com/gofiber/fiber/v2): it converts between Fiber route paths and
+// OpenAPI path templates, scaffolds operations from the routes of an app,
+// documents routes as they are registered through a Router and serves the
+// document
+package fiberopenapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/nyxstack/openapi"
+)
+
+// SpecRouteName is the name of the routes registered by Serve, which Scaffold skips
+const SpecRouteName = "openapi.spec"
+
+// localsKey is the Fiber locals key under which Router handlers find their operation
+const localsKey = "openapi.operation"
+
+// methods are the HTTP methods an OpenAPI path item can describe
+var methods = map[string]bool{
+	http.MethodGet: true, http.MethodPut: true, http.MethodPost: true, http.MethodDelete: true,
+	http.MethodOptions: true, http.MethodHead: true, http.MethodPatch: true, http.MethodTrace: true,
+}
+
+// Scaffold documents the routes of an app that the document doesn't describe
+// yet with placeholder operations (see Document.ScaffoldOperation), typing
+// path parameters after their route constraints. Middleware, the HEAD routes
+// Fiber adds for GET routes and the routes registered by Serve are skipped.
+func Scaffold(doc *openapi.Document, app *fiber.App) {
+	routes := app.GetRoutes(true)
+	gets := make(map[string]bool)
+	for _, route := range routes {
+		if route.Method == http.MethodGet {
+			gets[route.Path] = true
+		}
+	}
+	for _, route := range routes {
+		if !methods[route.Method] || strings.HasSuffix(route.Name, SpecRouteName) ||
+			(route.Method == http.MethodHead && gets[route.Path]) {
+			continue
+		}
+		path, params := parsePath(route.Path)
+		doc.ScaffoldOperation(route.Method, path, params...)
+	}
+}
+
+// Router registers routes on a Fiber app or group and documents each of them
+// with its operation at the same time
+type Router struct {
+	doc    *openapi.Document
+	router fiber.Router
+	prefix string
+}
+
+// NewRouter creates a router documenting the routes it registers on an app or group
+func NewRouter(doc *openapi.Document, router fiber.Router) *Router {
+	r := &Router{doc: doc, router: router}
+	if group, ok := router.(*fiber.Group); ok {
+		r.prefix = group.Prefix
+	}
+	return r
+}
+
+// Group creates a router for a sub-group sharing the path prefix and handlers
+func (r *Router) Group(prefix string, handlers ...fiber.Handler) *Router {
+	return NewRouter(r.doc, r.router.Group(prefix, handlers...))
+}
+
+// Add registers handlers for a method and path and documents the route with
+// the operation. Path parameters of the route that the operation leaves out
+// are declared after their route constraints. Handlers read the operation
+// with Operation.
+func (r *Router) Add(method, path string, op openapi.Operation, handlers ...fiber.Handler) fiber.Router {
+	templ, params := parsePath(r.prefix + path)
+	for _, p := range params {
+		if !declares(op, p.Name) {
+			op = op.WithParameter(p)
+		}
+	}
+	r.doc.AddOperation(templ, method, op)
+	var registered *openapi.Operation
+	if item, ok := r.doc.Paths[templ]; ok {
+		registered = item.Operation(method)
+	}
+	describe := func(c *fiber.Ctx) error {
+		c.Locals(localsKey, registered)
+		return c.Next()
+	}
+	return r.router.Add(method, path, append([]fiber.Handler{describe}, handlers...)...)
+}
+
+// Get registers and documents a GET route
+func (r *Router) Get(path string, op openapi.Operation, handlers ...fiber.Handler) fiber.Router {
+	return r.Add(http.MethodGet, path, op, handlers...)
+}
+
+// Post registers and documents a POST route
+func (r *Router) Post(path string, op openapi.Operation, handlers ...fiber.Handler) fiber.Router {
+	return r.Add(http.MethodPost, path, op, handlers...)
+}
+
+// Put registers and documents a PUT route
+func (r *Router) Put(path string, op openapi.Operation, handlers ...fiber.Handler) fiber.Router {
+	return r.Add(http.MethodPut, path, op, handlers...)
+}
+
+// Patch registers and documents a PATCH route
+func (r *Router) Patch(path string, op openapi.Operation, handlers ...fiber.Handler) fiber.Router {
+	return r.Add(http.MethodPatch, path, op, handlers...)
+}
+
+// Delete registers and documents a DELETE route
+func (r *Router) Delete(path string, op openapi.Operation, handlers ...fiber.Handler) fiber.Router {
+	return r.Add(http.MethodDelete, path, op, handlers...)
+}
+
+// Operation returns the operation of the route handling the request when it
+// was registered through a Router, or nil
+func Operation(c *fiber.Ctx) *openapi.Operation {
+	op, _ := c.Locals(localsKey).(*openapi.Operation)
+	return op
+}
+
+// Serve registers a route serving the document as JSON at path, e.g.
+// "/openapi.json"
+func Serve(router fiber.Router, path string, doc *openapi.Document) {
+	router.Get(path, adaptor.HTTPHandler(doc.Handler())).Name(SpecRouteName)
+}
+
+// declares reports whether an operation declares a path parameter
+func declares(op openapi.Operation, name string) bool {
+	for _, p := range op.Parameters {
+		if p.In == "path" && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package fiberopenapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nyxstack/openapi"
+)
+
+func TestPath(t *testing.T) {
+	for fiberPath, want := range map[string]string{
+		"/pets":              "/pets",
+		"/pets/:id<int>":     "/pets/{id}",
+		"/users/:name?":      "/users/{name}",
+		"/flights/:from-:to": "/flights/{from}-{to}",
+		"/static/*":          "/static/{wildcard}",
+		"/copy/*/to/*":       "/copy/{wildcard}/to/{wildcard2}",
+		"/files/+":           "/files/{plus}",
+		"/api/v1\\:run":      "/api/v1:run",
+	} {
+		if got := Path(fiberPath); got != want {
+			t.Errorf("Expected '%s' for '%s', got '%s'", want, fiberPath, got)
+		}
+	}
+
+	if got := FiberPath("/flights/{from}-{to}"); got != "/flights/:from-:to" {
+		t.Errorf("Expected '/flights/:from-:to', got '%s'", got)
+	}
+}
+
+func TestParameters(t *testing.T) {
+	params := Parameters("/pets/:id<int;min(1)>/:name<minLen(2);alpha>")
+	if len(params) != 2 {
+		t.Fatalf("Expected 2 parameters, got %d", len(params))
+	}
+
+	id := params[0].Schema
+	if !id.Type.Is("integer") || id.Minimum == nil || *id.Minimum != 1 {
+		t.Errorf("Expected integer id with minimum 1, got %+v", id)
+	}
+
+	name := params[1].Schema
+	if !name.Type.Is("string") || name.MinLength == nil || *name.MinLength != 2 || name.Pattern == "" {
+		t.Errorf("Expected letters-only name of at least 2 characters, got %+v", name)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	doc := openapi.NewDocument("Pet API", "1.0.0")
+	app := fiber.New()
+
+	var seen *openapi.Operation
+	handler := func(c *fiber.Ctx) error {
+		seen = Operation(c)
+		return c.SendStatus(http.StatusNoContent)
+	}
+	api := NewRouter(doc, app.Group("/v1"))
+	api.Get("/pets/:id<int>", openapi.NewOperation("getPet", "Get a pet", ""), handler)
+	app.Delete("/v1/pets/:id<int>", handler)
+	Serve(app, "/openapi.json", doc)
+	Scaffold(doc, app)
+
+	item := doc.Paths["/v1/pets/{id}"]
+	if item.Get == nil || len(item.Get.Parameters) != 1 || !item.Get.Parameters[0].Schema.Type.Is("integer") {
+		t.Fatalf("Expected registered GET with integer 'id' parameter, got %+v", item.Get)
+	}
+	if item.Delete == nil || item.Delete.OperationID != "deleteV1PetsById" {
+		t.Errorf("Expected scaffolded DELETE operation, got %+v", item.Delete)
+	}
+	if item.Head != nil || len(doc.Paths) != 1 {
+		t.Errorf("Expected HEAD and spec routes not to be scaffolded, got %d paths", len(doc.Paths))
+	}
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/v1/pets/1", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if seen == nil || seen.OperationID != "getPet" {
+		t.Errorf("Expected handler to see getPet, got %+v", seen)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi.json", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"operationId": "getPet"`) {
+		t.Errorf("Expected JSON spec, got %d: %s", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,172 @@
+package fiberopenapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nyxstack/openapi"
+)
+
+// Path converts a Fiber route path into an OpenAPI path template. Parameters
+// such as ":id", ":id<int>" or the optional ":id?" become "{id}", and the
+// greedy "*" and "+" parameters become "{wildcard}" and "{plus}", numbered
+// from the second one on ("{wildcard2}"). OpenAPI has no optional path
+// parameters, so routes with optional ones are documented with them set.
+func Path(fiberPath string) string {
+	path, _ := parsePath(fiberPath)
+	return path
+}
+
+// FiberPath converts an OpenAPI path template into a Fiber route path:
+// "{id}" becomes ":id"
+func FiberPath(template string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(template, "{")
+		end := strings.Index(template, "}")
+		if start < 0 || end < start {
+			b.WriteString(template)
+			return b.String()
+		}
+		b.WriteString(template[:start])
+		b.WriteString(":" + template[start+1:end])
+		template = template[end+1:]
+	}
+}
+
+// Parameters returns the path parameters of a Fiber route path, required and
+// typed after their constraints: ":id<int>" is an int64 integer,
+// ":name<minLen(2);alpha>" a letters-only string of at least two characters
+func Parameters(fiberPath string) []openapi.Parameter {
+	_, params := parsePath(fiberPath)
+	return params
+}
+
+// parsePath converts a Fiber route path into an OpenAPI path template and the
+// path parameters it declares
+func parsePath(fiberPath string) (string, []openapi.Parameter) {
+	var b strings.Builder
+	var params []openapi.Parameter
+	greedy := map[byte]int{}
+	for i := 0; i < len(fiberPath); i++ {
+		c := fiberPath[i]
+		switch {
+		case c == ':' && i+1 < len(fiberPath) && isNameChar(fiberPath[i+1]):
+			start := i + 1
+			i = start
+			for i < len(fiberPath) && isNameChar(fiberPath[i]) {
+				i++
+			}
+			name := fiberPath[start:i]
+			schema := openapi.NewStringSchema()
+			if i < len(fiberPath) && fiberPath[i] == '<' {
+				end := strings.IndexByte(fiberPath[i:], '>')
+				if end < 0 {
+					end = len(fiberPath) - i
+				}
+				schema = constraintSchema(fiberPath[i+1 : i+end])
+				i += end + 1
+			}
+			if i < len(fiberPath) && fiberPath[i] == '?' {
+				i++
+			}
+			i--
+			b.WriteString("{" + name + "}")
+			params = append(params, openapi.NewPathParameter(name, "", schema))
+		case c == '*' || c == '+':
+			greedy[c]++
+			name := "wildcard"
+			if c == '+' {
+				name = "plus"
+			}
+			if greedy[c] > 1 {
+				name += strconv.Itoa(greedy[c])
+			}
+			// Fiber allows numbering greedy parameters explicitly, e.g. "*1"
+			for i+1 < len(fiberPath) && fiberPath[i+1] >= '0' && fiberPath[i+1] <= '9' {
+				i++
+			}
+			b.WriteString("{" + name + "}")
+			params = append(params, openapi.NewPathParameter(name, "", openapi.NewStringSchema()))
+		case c == '\\' && i+1 < len(fiberPath):
+			// Escaped special characters are literal
+			i++
+			b.WriteByte(fiberPath[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), params
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// constraintSchema derives a schema from Fiber route constraints separated by ";"
+func constraintSchema(constraints string) *openapi.Schema {
+	schema := openapi.NewStringSchema()
+	for _, constraint := range strings.Split(constraints, ";") {
+		name, arg, _ := strings.Cut(strings.TrimSuffix(constraint, ")"), "(")
+		args := strings.Split(arg, ",")
+		number := func(s string) (float64, bool) {
+			f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			return f, err == nil
+		}
+		length := func(s string) (int, bool) {
+			n, err := strconv.Atoi(strings.TrimSpace(s))
+			return n, err == nil
+		}
+		switch name {
+		case "int":
+			schema.Type, schema.Format = openapi.Types{"integer"}, "int64"
+		case "float":
+			schema.Type, schema.Format = openapi.Types{"number"}, "double"
+		case "bool":
+			schema.Type = openapi.Types{"boolean"}
+		case "guid":
+			schema.Format = "uuid"
+		case "alpha":
+			schema.Pattern = "^[a-zA-Z]+$"
+		case "regex":
+			schema.Pattern = arg
+		case "datetime":
+			schema.Description = fmt.Sprintf("Date and time in the Go layout %q", arg)
+		case "minLen":
+			if n, ok := length(arg); ok {
+				schema.MinLength = &n
+			}
+		case "maxLen":
+			if n, ok := length(arg); ok {
+				schema.MaxLength = &n
+			}
+		case "len":
+			if n, ok := length(arg); ok {
+				schema.MinLength, schema.MaxLength = &n, &n
+			}
+		case "min":
+			if f, ok := number(arg); ok {
+				schema.Minimum = &f
+			}
+		case "max":
+			if f, ok := number(arg); ok {
+				schema.Maximum = &f
+			}
+		case "range":
+			if len(args) == 2 {
+				if lo, ok := number(args[0]); ok {
+					schema.Minimum = &lo
+				}
+				if hi, ok := number(args[1]); ok {
+					schema.Maximum = &hi
+				}
+			}
+		}
+	}
+	// Numeric bounds constrain integers unless another type was given
+	if schema.Type.Is("string") && (schema.Minimum != nil || schema.Maximum != nil) {
+		schema.Type, schema.Format = openapi.Types{"integer"}, "int64"
+	}
+	return schema
+}
@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"slices"
+	"strings"
+)
+
+// FilterOptions selects the operations a filtered view of a document keeps.
+// An operation is kept when it matches one of the tags, if any are given,
+// and one of the paths, if any are given.
+type FilterOptions struct {
+	// Tags keeps the operations tagged with any of them
+	Tags []string
+	// Paths keeps the operations of paths equal to any of them; a pattern
+	// ending in "*" matches the paths it prefixes, as in "/admin/*"
+	Paths []string
+}
+
+// Filter returns a view of the document with only the operations opts
+// selects, and without the components and tags only the other operations
+// used. Webhooks aren't under a path, so a path filter leaves them out. The
+// document itself is left untouched.
+func (d *Document) Filter(opts FilterOptions) (*Document, error) {
+	filtered, err := d.Clone()
+	if err != nil {
+		return nil, err
+	}
+	before := filtered.referencedComponents()
+	usedBefore := filtered.operationTags()
+	keep := func(path, _ string, op *Operation) bool {
+		if len(opts.Tags) > 0 && !slices.ContainsFunc(op.Tags, func(tag string) bool { return slices.Contains(opts.Tags, tag) }) {
+			return false
+		}
+		return len(opts.Paths) == 0 || slices.ContainsFunc(opts.Paths, func(pattern string) bool { return matchPath(pattern, path) })
+	}
+	filterOperations(filtered.Paths, keep)
+	filterOperations(filtered.Webhooks, func(_, method string, op *Operation) bool {
+		return len(opts.Paths) == 0 && keep("", method, op)
+	})
+
+	after := filtered.referencedComponents()
+	for key := range before {
+		if !after[key] {
+			filtered.removeComponent(key)
+		}
+	}
+	usedAfter := filtered.operationTags()
+	filtered.Tags = slices.DeleteFunc(filtered.Tags, func(tag Tag) bool {
+		return usedBefore[tag.Name] && !usedAfter[tag.Name]
+	})
+	return filtered, nil
+}
+
+// filterOperations removes the operations keep rejects from path items, along
+// with the path items left without operations
+func filterOperations(items map[string]PathItem, keep func(path, method string, op *Operation) bool) {
+	for key, item := range items {
+		remaining := 0
+		for _, method := range httpMethods {
+			if op := item.Operation(method); op != nil {
+				if keep(key, method, op) {
+					remaining++
+				} else {
+					item.SetOperation(method, nil)
+				}
+			}
+		}
+		if remaining == 0 {
+			delete(items, key)
+			continue
+		}
+		items[key] = item
+	}
+}
+
+// operationTags returns the tags used by the operations and webhooks
+func (d *Document) operationTags() map[string]bool {
+	tags := make(map[string]bool)
+	record := func(_, _ string, op *Operation) {
+		for _, tag := range op.Tags {
+			tags[tag] = true
+		}
+	}
+	d.walkOperations(record)
+	d.walkWebhooks(record)
+	return tags
+}
+
+// matchPath reports whether a path equals a pattern or, for a pattern ending
+// in "*", starts with the rest of the pattern
+func matchPath(pattern, path string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == pattern
+}
+
+// filterElements removes the operations, parameters, component schemas and
+// schema properties whose specification extensions keep rejects, along with
+// path items left without operations. It mutates the document in place, so
+// callers filter a clone.
+func (d *Document) filterElements(keep func(ext map[string]interface{}) bool) {
+	filterParameters := func(params []Parameter) []Parameter {
+		return slices.DeleteFunc(params, func(p Parameter) bool { return !keep(p.Extensions) })
+	}
+	filterItems := func(items map[string]PathItem) {
+		for key, item := range items {
+			item.Parameters = filterParameters(item.Parameters)
+			remaining := 0
+			for _, method := range httpMethods {
+				op := item.Operation(method)
+				if op == nil {
+					continue
+				}
+				if !keep(op.Extensions) {
+					item.SetOperation(method, nil)
+					continue
+				}
+				op.Parameters = filterParameters(op.Parameters)
+				remaining++
+			}
+			if remaining == 0 {
+				delete(items, key)
+				continue
+			}
+			items[key] = item
+		}
+	}
+	filterItems(d.Paths)
+	filterItems(d.Webhooks)
+
+	if d.Components != nil {
+		for name, schema := range d.Components.Schemas {
+			if schema != nil && !keep(schema.Extensions) {
+				delete(d.Components.Schemas, name)
+			}
+		}
+		for name, p := range d.Components.Parameters {
+			if !keep(p.Extensions) {
+				delete(d.Components.Parameters, name)
+			}
+		}
+	}
+	d.walkSchemas(func(_ string, s *Schema) {
+		for name, prop := range s.Properties {
+			if prop != nil && !keep(prop.Extensions) {
+				delete(s.Properties, name)
+				s.Required = slices.DeleteFunc(s.Required, func(r string) bool { return r == name })
+			}
+		}
+	})
+}
+
+// stripExtensions removes specification extensions from operations,
+// parameters and schemas
+func (d *Document) stripExtensions(names ...string) {
+	strip := func(ext map[string]interface{}) {
+		for _, name := range names {
+			delete(ext, name)
+		}
+	}
+	for _, items := range []map[string]PathItem{d.Paths, d.Webhooks} {
+		for _, item := range items {
+			for _, method := range httpMethods {
+				if op := item.Operation(method); op != nil {
+					strip(op.Extensions)
+				}
+			}
+		}
+	}
+	d.walkParameters(func(_ string, p Parameter) {
+		strip(p.Extensions)
+	})
+	d.walkSchemas(func(_ string, s *Schema) {
+		strip(s.Extensions)
+	})
+}
@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FirewallPolicy is a positive security model for schema-enforcing proxies
+// and web application firewalls: requests matching no rule, or breaking the
+// constraints of the rule they match, are to be rejected
+type FirewallPolicy struct {
+	Title   string         `json:"title"`
+	Version string         `json:"version"`
+	Rules   []FirewallRule `json:"rules"`
+	// Components holds the schemas the body schemas of the rules reference,
+	// so that their $ref pointers resolve within the policy
+	Components *Components `json:"components,omitempty"`
+}
+
+// FirewallRule allows the requests of one operation
+type FirewallRule struct {
+	OperationID string `json:"operationId,omitempty"`
+	Path        string `json:"path"`
+	// PathPattern is a regular expression matching the request path
+	PathPattern string `json:"pathPattern"`
+	Method      string `json:"method"`
+	// ContentTypes lists the allowed request content types; empty when the
+	// operation takes no body
+	ContentTypes []string `json:"contentTypes,omitempty"`
+	// BodyRequired rejects requests without a body
+	BodyRequired bool `json:"bodyRequired,omitempty"`
+	// BodySchemas maps content types to the JSON Schema of the body
+	BodySchemas map[string]*Schema `json:"bodySchemas,omitempty"`
+	// Parameters lists the allowed parameters; others are unknown to the contract
+	Parameters []FirewallParameter `json:"parameters,omitempty"`
+}
+
+// FirewallParameter constrains one parameter
+type FirewallParameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required,omitempty"`
+	// Pattern is a regular expression the raw value must match, when the
+	// schema constrains its shape
+	Pattern   string `json:"pattern,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+}
+
+// firewallPatterns maps string formats to the patterns their values match
+var firewallPatterns = map[string]string{
+	"uuid":      `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+	"date":      `^[0-9]{4}-[0-9]{2}-[0-9]{2}$`,
+	"time":      `^[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?([Zz]|[+-][0-9]{2}:[0-9]{2})$`,
+	"duration":  durationPattern.String(),
+	"date-time": `^[0-9]{4}-[0-9]{2}-[0-9]{2}[Tt ][0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?([Zz]|[+-][0-9]{2}:[0-9]{2})$`,
+}
+
+// FirewallPolicy derives a firewall policy from the document, with a rule per
+// operation ordered by path and method. Parameter patterns come from schema
+// patterns, enums, numeric and boolean types and well-known string formats.
+func (d *Document) FirewallPolicy() FirewallPolicy {
+	policy := FirewallPolicy{Title: d.Info.Title, Version: d.Info.Version}
+	d.walkOperations(func(path, method string, op *Operation) {
+		rule := FirewallRule{
+			OperationID: op.OperationID,
+			Path:        path,
+			PathPattern: pathRegexp(path),
+			Method:      method,
+		}
+		for _, p := range d.OperationParameters(path, op) {
+			parameter := FirewallParameter{Name: p.Name, In: p.In, Required: p.Required}
+			if s := d.resolveSchema(p.Schema); s != nil {
+				parameter.Pattern = d.firewallPattern(s)
+				parameter.MaxLength = s.MaxLength
+			}
+			rule.Parameters = append(rule.Parameters, parameter)
+		}
+		if body := d.resolveRequestBody(op.RequestBody); body != nil {
+			rule.ContentTypes = sortedKeys(body.Content)
+			rule.BodyRequired = body.Required
+			for _, name := range rule.ContentTypes {
+				if s := body.Content[name].Schema; s != nil {
+					if rule.BodySchemas == nil {
+						rule.BodySchemas = make(map[string]*Schema)
+					}
+					rule.BodySchemas[name] = s
+				}
+			}
+		}
+		policy.Rules = append(policy.Rules, rule)
+	})
+
+	// Copy the schemas the body schemas reference, transitively
+	seen := make(map[string]bool)
+	var queue []string
+	ref := func(ref string) {
+		if name, ok := strings.CutPrefix(ref, "#/components/schemas/"); ok && !seen[name] {
+			seen[name] = true
+			queue = append(queue, name)
+		}
+	}
+	for _, rule := range policy.Rules {
+		for _, s := range rule.BodySchemas {
+			collectSchemaRefs(s, ref)
+		}
+	}
+	for len(queue) > 0 && d.Components != nil {
+		name := queue[0]
+		queue = queue[1:]
+		s, ok := d.Components.Schemas[unescapePointer(name)]
+		if !ok {
+			continue
+		}
+		if policy.Components == nil {
+			policy.Components = &Components{Schemas: make(map[string]*Schema)}
+		}
+		policy.Components.Schemas[unescapePointer(name)] = s
+		collectSchemaRefs(s, ref)
+	}
+	return policy
+}
+
+// ToFirewallPolicy renders FirewallPolicy as JSON
+func (d *Document) ToFirewallPolicy() ([]byte, error) {
+	return json.MarshalIndent(d.FirewallPolicy(), "", "  ")
+}
+
+// firewallPattern returns the pattern of a parameter schema, or "" when its
+// values aren't constrained in shape
+func (d *Document) firewallPattern(s *Schema) string {
+	if s.Pattern != "" {
+		return s.Pattern
+	}
+	if len(s.Enum) > 0 {
+		var values []string
+		for _, value := range s.Enum {
+			if data, err := json.Marshal(value); err == nil {
+				values = append(values, regexp.QuoteMeta(strings.Trim(string(data), `"`)))
+			}
+		}
+		sort.Strings(values)
+		return "^(" + strings.Join(values, "|") + ")$"
+	}
+	switch s.Type.Primary() {
+	case "integer":
+		return `^-?[0-9]+$`
+	case "number":
+		return `^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`
+	case "boolean":
+		return `^(true|false)$`
+	case "string":
+		return firewallPatterns[s.Format]
+	}
+	return ""
+}
+
+// collectSchemaRefs calls fn with every $ref within a schema
+func collectSchemaRefs(s *Schema, fn func(ref string)) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	var value interface{}
+	if json.Unmarshal(data, &value) == nil {
+		collectRefs(value, fn)
+	}
+}
@@ -0,0 +1,329 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FixtureOptions configures the package produced by GenerateFixtures
+type FixtureOptions struct {
+	// Package is the package clause of the generated file
+	Package string
+}
+
+// GenerateFixtures emits a Go source file declaring a type for every component
+// schema and a fixture constructor returning a valid value of it, such as
+// NewPetFixture() Pet. Values come from Document.SampleValue, so examples,
+// defaults and enums are used where present and constraints are honored
+// otherwise. Object schemas become structs with JSON tags; optional fields
+// referencing other object schemas are pointers. The x-go-name, x-go-type and
+// x-oapi-codegen-extra-tags extensions are honored; x-go-type fields are left
+// at their zero value.
+func (d *Document) GenerateFixtures(opts FixtureOptions) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "fixtures"
+	}
+	g := &fixtureGenerator{doc: d}
+
+	var decls bytes.Buffer
+	if d.Components != nil {
+		for _, name := range sortedKeys(d.Components.Schemas) {
+			g.writeComponent(&decls, name, d.Components.Schemas[name])
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by openapi.GenerateFixtures. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n", opts.Package)
+	if len(g.imports) > 0 {
+		buf.WriteString("\nimport (\n")
+		for _, path := range sortedKeys(g.imports) {
+			fmt.Fprintf(&buf, "\t%s %q\n", g.imports[path], path)
+		}
+		buf.WriteString(")\n")
+	}
+	buf.Write(decls.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+type fixtureGenerator struct {
+	doc *Document
+	// imports maps import paths to their alias, empty for none
+	imports map[string]string
+}
+
+func (g *fixtureGenerator) addImport(path, alias string) {
+	if g.imports == nil {
+		g.imports = make(map[string]string)
+	}
+	g.imports[path] = alias
+}
+
+// typeName returns the Go type name of a component schema, honoring x-go-name
+func (g *fixtureGenerator) typeName(name string) string {
+	if s := g.doc.Components.Schemas[name]; s != nil && s.GoName() != "" {
+		return s.GoName()
+	}
+	return exportedName(name)
+}
+
+// fieldName returns the Go field name of a property, honoring x-go-name
+func fieldName(prop string, s *Schema) string {
+	if s != nil && s.GoName() != "" {
+		return s.GoName()
+	}
+	return exportedName(prop)
+}
+
+func (g *fixtureGenerator) writeComponent(buf *bytes.Buffer, name string, schema *Schema) {
+	typeName := g.typeName(name)
+	if schema.Description != "" {
+		fmt.Fprintf(buf, "\n// %s %s\n", typeName, strings.ReplaceAll(strings.TrimSpace(schema.Description), "\n", "\n// "))
+	} else {
+		buf.WriteString("\n")
+	}
+
+	if g.isStruct(schema) {
+		fmt.Fprintf(buf, "type %s struct {\n", typeName)
+		properties, _ := g.doc.schemaProperties(schema)
+		required := g.doc.schemaRequired(schema)
+		for _, prop := range sortedKeys(properties) {
+			propSchema := g.propertySchema(schema, prop)
+			omit := ",omitempty"
+			if slices.Contains(required, prop) {
+				omit = ""
+			}
+			tags := fmt.Sprintf("json:%q", prop+omit)
+			if propSchema != nil {
+				extra := propSchema.ExtraTags()
+				for _, key := range sortedKeys(extra) {
+					tags += fmt.Sprintf(" %s:%q", key, extra[key])
+				}
+			}
+			fmt.Fprintf(buf, "\t%s %s `%s`\n", fieldName(prop, propSchema), g.fieldType(propSchema, omit == ""), tags)
+		}
+		buf.WriteString("}\n")
+	} else {
+		fmt.Fprintf(buf, "type %s %s\n", typeName, g.goType(schema))
+	}
+
+	fmt.Fprintf(buf, "\n// New%sFixture returns a valid %s for tests and seed data\n", typeName, typeName)
+	fmt.Fprintf(buf, "func New%sFixture() %s {\n", typeName, typeName)
+	fmt.Fprintf(buf, "\treturn %s\n}\n", g.componentLiteral(name, g.doc.SampleValue(schema)))
+}
+
+// propertySchema returns the unresolved schema of a property, so references
+// to components keep their type name
+func (g *fixtureGenerator) propertySchema(s *Schema, name string) *Schema {
+	var found *Schema
+	var search func(s *Schema, depth int)
+	search = func(s *Schema, depth int) {
+		s = g.doc.resolveSchema(s)
+		if s == nil || found != nil || depth > 16 {
+			return
+		}
+		if prop, ok := s.Properties[name]; ok {
+			found = prop
+			return
+		}
+		for _, sub := range s.AllOf {
+			search(sub, depth+1)
+		}
+	}
+	search(s, 0)
+	return found
+}
+
+// isStruct reports whether a schema is generated as a struct
+func (g *fixtureGenerator) isStruct(s *Schema) bool {
+	s = g.doc.resolveSchema(s)
+	return s != nil && (s.Type.Is("object") || (len(s.Type) == 0 && (len(s.Properties) > 0 || len(s.AllOf) > 0)))
+}
+
+func (g *fixtureGenerator) fieldType(s *Schema, required bool) string {
+	typ := g.goType(s)
+	if !required && s != nil && s.Ref != "" && g.isStruct(s) {
+		return "*" + typ
+	}
+	return typ
+}
+
+func (g *fixtureGenerator) goType(s *Schema) string {
+	if s == nil {
+		return "interface{}"
+	}
+	if typeName := s.GoType(); typeName != "" {
+		if imp, ok := s.GoTypeImport(); ok {
+			g.addImport(imp.Path, imp.Name)
+		}
+		return typeName
+	}
+	if name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/"); ok {
+		return g.typeName(unescapePointer(name))
+	}
+	switch s.Type.Primary() {
+	case "string":
+		if s.Format == "date-time" {
+			g.addImport("time", "")
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(s.Items)
+	case "object":
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// componentLiteral renders a value of a component type
+func (g *fixtureGenerator) componentLiteral(name string, value interface{}) string {
+	schema := g.doc.Components.Schemas[name]
+	typeName := g.typeName(name)
+	if !g.isStruct(schema) {
+		if value == nil {
+			return zeroLiteral(typeName, g.goType(schema))
+		}
+		lit := g.literal(schema, value)
+		if lit == "" {
+			return zeroLiteral(typeName, g.goType(schema))
+		}
+		return typeName + "(" + lit + ")"
+	}
+
+	obj, _ := value.(map[string]interface{})
+	properties, _ := g.doc.schemaProperties(schema)
+	var b strings.Builder
+	b.WriteString(typeName + "{")
+	for _, prop := range sortedKeys(properties) {
+		v, ok := obj[prop]
+		if !ok || v == nil {
+			continue
+		}
+		propSchema := g.propertySchema(schema, prop)
+		lit := g.literal(propSchema, v)
+		if lit == "" {
+			continue
+		}
+		if strings.HasPrefix(g.fieldType(propSchema, slices.Contains(g.doc.schemaRequired(schema), prop)), "*") {
+			lit = "&" + lit
+		}
+		fmt.Fprintf(&b, "\n%s: %s,", fieldName(prop, propSchema), lit)
+	}
+	if len(obj) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// zeroLiteral renders the zero value of a named type with the underlying Go type typ
+func zeroLiteral(typeName, typ string) string {
+	switch typ {
+	case "interface{}":
+		return "nil"
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	case "int32", "int64", "float32", "float64":
+		return "0"
+	}
+	if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") {
+		return "nil"
+	}
+	return "*new(" + typeName + ")"
+}
+
+// literal renders a sample value as a Go expression of the schema's Go type.
+// It returns an empty string for x-go-type types, which are left at their zero value.
+func (g *fixtureGenerator) literal(s *Schema, value interface{}) string {
+	if s != nil && s.GoType() != "" {
+		return ""
+	}
+	if s != nil && s.Ref != "" {
+		if name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/"); ok {
+			name = unescapePointer(name)
+			if _, exists := g.doc.Components.Schemas[name]; exists {
+				return g.componentLiteral(name, value)
+			}
+		}
+	}
+
+	switch typ := g.goType(s); {
+	case typ == "time.Time":
+		t, err := time.Parse(time.RFC3339, fmt.Sprint(value))
+		if err != nil {
+			return "time.Time{}"
+		}
+		t = t.UTC()
+		return fmt.Sprintf("time.Date(%d, %d, %d, %d, %d, %d, %d, time.UTC)",
+			t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+	case typ == "string":
+		return strconv.Quote(fmt.Sprint(value))
+	case typ == "bool":
+		b, _ := value.(bool)
+		return strconv.FormatBool(b)
+	case typ == "int32" || typ == "int64":
+		n, _ := toFloat(value)
+		return strconv.FormatInt(int64(n), 10)
+	case typ == "float32" || typ == "float64":
+		n, _ := toFloat(value)
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	case strings.HasPrefix(typ, "[]"):
+		items, _ := value.([]interface{})
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			if item != nil {
+				parts = append(parts, g.literal(s.Items, item))
+			}
+		}
+		return typ + "{" + strings.Join(parts, ", ") + "}"
+	}
+	return genericLiteral(value)
+}
+
+// genericLiteral renders a JSON-like value as an interface{} expression
+func genericLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}:
+		parts := make([]string, 0, len(v))
+		for _, k := range sortedKeys(v) {
+			parts = append(parts, strconv.Quote(k)+": "+genericLiteral(v[k]))
+		}
+		return "map[string]interface{}{" + strings.Join(parts, ", ") + "}"
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, genericLiteral(item))
+		}
+		return "[]interface{}{" + strings.Join(parts, ", ") + "}"
+	}
+	if n, ok := toFloat(value); ok {
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	return "nil"
+}
@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateFixtures(t *testing.T) {
+	doc, err := FromJSON([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Pet API", "version": "1.0.0"},
+		"paths": {},
+		"components": {"schemas": {
+			"Anything": {},
+			"Status": {"type": "string", "enum": ["available", "sold"]},
+			"Tags": {"type": "array", "items": {"type": "string"}},
+			"Money": {"type": "string", "x-go-type": "big.Float", "x-go-type-import": {"path": "math/big"}},
+			"Owner": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}},
+			"Toy": {"type": "object", "properties": {"name": {"type": "string"}, "price": {"$ref": "#/components/schemas/Money"}}},
+			"Shape": {"oneOf": [{"$ref": "#/components/schemas/Owner"}, {"$ref": "#/components/schemas/Toy"}]},
+			"Pet": {
+				"type": "object",
+				"required": ["id", "name", "status"],
+				"properties": {
+					"id": {"type": "integer", "format": "int64"},
+					"name": {"type": "string", "example": "Rex"},
+					"status": {"$ref": "#/components/schemas/Status"},
+					"born": {"type": "string", "format": "date-time"},
+					"tags": {"$ref": "#/components/schemas/Tags"},
+					"owner": {"$ref": "#/components/schemas/Owner"},
+					"toys": {"type": "array", "items": {"$ref": "#/components/schemas/Toy"}},
+					"extra": {},
+					"choice": {"oneOf": [{"type": "string"}, {"type": "integer"}]},
+					"anything": {"$ref": "#/components/schemas/Anything"}
+				}
+			},
+			"Dog": {"allOf": [{"$ref": "#/components/schemas/Pet"}, {"type": "object", "properties": {"breed": {"type": "string"}}}]}
+		}}
+	}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	src, err := doc.GenerateFixtures(FixtureOptions{Package: "generated"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	code := string(src)
+	for _, fixture := range []string{"NewAnythingFixture() Anything", "NewPetFixture() Pet", "NewDogFixture() Dog", "NewMoneyFixture() Money"} {
+		if !strings.Contains(code, "func "+fixture) {
+			t.Errorf("Expected %s, got:\n%s", fixture, code)
+		}
+	}
+	if strings.Contains(code, "Anything{}") {
+		t.Errorf("Expected no composite literal of an interface type, got:\n%s", code)
+	}
+
+	testGeneratedPackage(t, map[string]string{
+		"generated.go": code,
+		"generated_test.go": "package generated\n\n" +
+			"import (\n\t\"encoding/json\"\n\t\"testing\"\n)\n\n" +
+			"func TestFixtures(t *testing.T) {\n" +
+			"\tfor _, fixture := range []interface{}{NewAnythingFixture(), NewStatusFixture(), NewTagsFixture(), NewMoneyFixture(),\n" +
+			"\t\tNewOwnerFixture(), NewToyFixture(), NewShapeFixture(), NewPetFixture(), NewDogFixture()} {\n" +
+			"\t\tif _, err := json.Marshal(fixture); err != nil {\n\t\t\tt.Error(err)\n\t\t}\n\t}\n}\n",
+	})
+}
@@ -0,0 +1,85 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrFrozen is reported when a frozen document is mutated
+var ErrFrozen = errors.New("openapi: document is frozen")
+
+// PanicOnFrozenMutation makes mutating a frozen document panic instead of
+// recording an error. Enable it in tests and debug builds to find the caller.
+var PanicOnFrozenMutation = false
+
+// freezeState is shared by copies of a frozen document so that recording a
+// rejected mutation is safe from concurrent serving goroutines
+type freezeState struct {
+	mu  sync.Mutex
+	err error
+}
+
+// Clone returns a deep copy of the document that shares no references with it.
+// The copy is made through a JSON round-trip, so example and extension values
+// come back as their generic JSON representation.
+func (d *Document) Clone() (*Document, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	clone := &Document{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// Freeze makes the document immutable. Its contents are deep copied first, so
+// schemas, operations and maps the caller still holds references to no longer
+// affect it. Afterwards the document's mutating methods leave it untouched and
+// record ErrFrozen, reported by Err, or panic when PanicOnFrozenMutation is set.
+func (d *Document) Freeze() error {
+	if d.frozen != nil {
+		return nil
+	}
+	clone, err := d.Clone()
+	if err != nil {
+		return err
+	}
+	*d = *clone
+	d.frozen = &freezeState{}
+	return nil
+}
+
+// Frozen reports whether Freeze has been called on the document
+func (d *Document) Frozen() bool {
+	return d.frozen != nil
+}
+
+// Err returns the mutations rejected since the document was frozen
+func (d *Document) Err() error {
+	if d.frozen == nil {
+		return nil
+	}
+	d.frozen.mu.Lock()
+	defer d.frozen.mu.Unlock()
+	return d.frozen.err
+}
+
+// mutable reports whether the named method may modify the document, recording
+// or panicking with ErrFrozen when it may not
+func (d *Document) mutable(method string) bool {
+	if d.frozen == nil {
+		return true
+	}
+	err := fmt.Errorf("%w: %s called", ErrFrozen, method)
+	if PanicOnFrozenMutation {
+		panic(err)
+	}
+	d.frozen.mu.Lock()
+	d.frozen.err = errors.Join(d.frozen.err, err)
+	d.frozen.mu.Unlock()
+	return false
+}
@@ -0,0 +1,244 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FuzzOptions configures the fuzz harness produced by GenerateFuzzTests
+type FuzzOptions struct {
+	// Package is the package clause of the generated file
+	Package string
+	// HandlerFunc names a function in the target package that returns the
+	// http.Handler under test
+	HandlerFunc string
+	// DocumentFunc names a function in the target package that returns the
+	// *openapi.Document the handler implements
+	DocumentFunc string
+}
+
+// GenerateFuzzTests emits a Go test file with one fuzz target per operation,
+// compatible with "go test -fuzz". Each target builds requests from string
+// parameters and a raw body seeded with schema-derived samples, asserts that the
+// handler doesn't panic, and checks every response with Document.CheckResponse.
+// Operations without an operationId are skipped.
+func (d *Document) GenerateFuzzTests(opts FuzzOptions) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "main"
+	}
+	if opts.HandlerFunc == "" {
+		opts.HandlerFunc = "newFuzzHandler"
+	}
+	if opts.DocumentFunc == "" {
+		opts.DocumentFunc = "newFuzzDocument"
+	}
+
+	imports := map[string]bool{"net/http/httptest": true, "testing": true}
+	var targets bytes.Buffer
+	d.walkOperations(func(path, method string, op *Operation) {
+		if op.OperationID == "" {
+			return
+		}
+		d.writeFuzzTarget(&targets, imports, path, method, op, opts)
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by openapi.GenerateFuzzTests. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n", opts.Package)
+	std := make([]string, 0, len(imports))
+	for imp := range imports {
+		std = append(std, imp)
+	}
+	sort.Strings(std)
+	for _, imp := range std {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	buf.WriteString("\n\t\"github.com/nyxstack/openapi\"\n)\n")
+	buf.Write(targets.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+func (d *Document) writeFuzzTarget(buf *bytes.Buffer, imports map[string]bool, path, method string, op *Operation, opts FuzzOptions) {
+	params := d.OperationParameters(path, op)
+	var args, names, seeds, empty []string
+	varNames := make(map[string]string)
+	for i, p := range params {
+		name := p.In + strconv.Itoa(i)
+		varNames[p.In+":"+p.Name] = name
+		args = append(args, name+" string")
+		names = append(names, name)
+		seeds = append(seeds, strconv.Quote(d.sampleParameter(p)))
+		empty = append(empty, `""`)
+	}
+
+	// Operations without parameters still fuzz a body, since f.Fuzz needs at
+	// least one argument
+	contentType, bodySeed := d.sampleRequestBody(op.RequestBody)
+	fuzzBody := op.RequestBody != nil || len(params) == 0
+	if fuzzBody {
+		args = append(args, "body []byte")
+		seeds = append(seeds, "[]byte("+strconv.Quote(bodySeed)+")")
+		empty = append(empty, "[]byte(nil)")
+		imports["bytes"] = true
+	}
+
+	fmt.Fprintf(buf, "\n// Fuzz%s fuzzes %s %s\n", exportedName(op.OperationID), method, path)
+	fmt.Fprintf(buf, "func Fuzz%s(f *testing.F) {\n", exportedName(op.OperationID))
+	fmt.Fprintf(buf, "\tf.Add(%s)\n", strings.Join(seeds, ", "))
+	fmt.Fprintf(buf, "\tf.Add(%s)\n\n", strings.Join(empty, ", "))
+	fmt.Fprintf(buf, "\thandler := %s()\n\tdoc := %s()\n\n", opts.HandlerFunc, opts.DocumentFunc)
+	fmt.Fprintf(buf, "\tf.Fuzz(func(%s) {\n", strings.Join(append([]string{"t *testing.T"}, args...), ", "))
+
+	// Build the target from the path template, escaping substituted values
+	var target []string
+	rest := path
+	for rest != "" {
+		start := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if start < 0 || end < start {
+			target = append(target, strconv.Quote(rest))
+			break
+		}
+		if start > 0 {
+			target = append(target, strconv.Quote(rest[:start]))
+		}
+		if name, ok := varNames["path:"+rest[start+1:end]]; ok {
+			target = append(target, "url.PathEscape("+name+")")
+			imports["net/url"] = true
+		} else {
+			target = append(target, strconv.Quote(rest[start:end+1]))
+		}
+		rest = rest[end+1:]
+	}
+	fmt.Fprintf(buf, "\t\ttarget := %s\n", strings.Join(target, " + "))
+
+	var queries, headers, cookies []string
+	for i, p := range params {
+		name := names[i]
+		switch p.In {
+		case "query":
+			queries = append(queries, fmt.Sprintf("\t\tquery.Set(%q, %s)\n", p.Name, name))
+		case "header":
+			headers = append(headers, fmt.Sprintf("\t\treq.Header.Set(%q, %s)\n", p.Name, name))
+		case "cookie":
+			cookies = append(cookies, fmt.Sprintf("\t\treq.AddCookie(&http.Cookie{Name: %q, Value: %s})\n", p.Name, name))
+			imports["net/http"] = true
+		}
+	}
+	if len(queries) > 0 {
+		imports["net/url"] = true
+		buf.WriteString("\t\tquery := url.Values{}\n")
+		buf.WriteString(strings.Join(queries, ""))
+		buf.WriteString("\t\ttarget += \"?\" + query.Encode()\n")
+	}
+
+	body := "nil"
+	if fuzzBody {
+		body = "bytes.NewReader(body)"
+	}
+	fmt.Fprintf(buf, "\t\treq := httptest.NewRequest(%q, target, %s)\n", method, body)
+	if contentType != "" {
+		fmt.Fprintf(buf, "\t\treq.Header.Set(\"Content-Type\", %q)\n", contentType)
+	}
+	buf.WriteString(strings.Join(headers, ""))
+	buf.WriteString(strings.Join(cookies, ""))
+	buf.WriteString("\t\trec := httptest.NewRecorder()\n")
+	buf.WriteString("\t\thandler.ServeHTTP(rec, req)\n\n")
+	fmt.Fprintf(buf, "\t\tfor _, err := range doc.CheckResponse(%q, rec.Code, rec.Header(), rec.Body.Bytes()) {\n", op.OperationID)
+	buf.WriteString("\t\t\tif err.Severity == openapi.SeverityError {\n\t\t\t\tt.Error(err)\n\t\t\t}\n\t\t}\n")
+	buf.WriteString("\t})\n}\n")
+}
+
+// sampleParameter produces a representative serialized value for a parameter
+func (d *Document) sampleParameter(p Parameter) string {
+	value := p.Example
+	if value == nil {
+		for _, name := range sortedKeys(p.Examples) {
+			value = p.Examples[name].Value
+			break
+		}
+	}
+	if value == nil {
+		value = d.SampleValue(p.Schema)
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+// sampleRequestBody picks the preferred media type of a request body and
+// produces a representative payload for it
+func (d *Document) sampleRequestBody(body *RequestBody) (contentType, payload string) {
+	if body == nil || len(body.Content) == 0 {
+		return "", ""
+	}
+	names := sortedKeys(body.Content)
+	contentType = names[0]
+	for _, name := range names {
+		if isJSONMediaType(name) {
+			contentType = name
+			break
+		}
+	}
+
+	mt := body.Content[contentType]
+	value := mt.Example
+	if value == nil {
+		for _, name := range sortedKeys(mt.Examples) {
+			value = mt.Examples[name].Value
+			break
+		}
+	}
+	if value == nil {
+		value = d.SampleValue(mt.Schema)
+	}
+	if s, ok := value.(string); ok && !isJSONMediaType(contentType) {
+		return contentType, s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return contentType, ""
+	}
+	return contentType, string(data)
+}
+
+// exportedName converts an identifier such as "get_pet-by.id" into an exported
+// Go identifier ("GetPetById")
+func exportedName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
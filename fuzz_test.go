@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testGeneratedPackage writes files into a module requiring this one and
+// runs "go test" on it, so generated code is checked to build and pass
+func testGeneratedPackage(t *testing.T, files map[string]string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping generated code test in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files["go.mod"] = "module generated\n\ngo 1.24.2\n\n" +
+		"require github.com/nyxstack/openapi v0.0.0\n\n" +
+		"replace github.com/nyxstack/openapi => " + filepath.ToSlash(root) + "\n"
+	files["go.sum"] = string(sum)
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Expected generated package to pass, got %v\n%s\n%s", err, out, files["generated_test.go"]+files["generated.go"])
+	}
+}
+
+func TestGenerateFuzzTests(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddOperation("/health", "GET", NewOperation("health", "Health check", "").
+		WithNoContentResponse())
+	doc.AddOperation("/pets/{id}", "PUT", NewOperation("updatePet", "Update pet", "").
+		WithPathParameter("id", "", Int64Schema()).
+		WithQueryParameter("dryRun", "", false, NewBooleanSchema()).
+		WithHeaderParameter("X-Request-ID", "", false, UUIDSchema()).
+		WithParameter(NewCookieParameter("session", "", false, StringSchema(""))).
+		WithJSONRequestBody("Pet", true, &Schema{Type: Types{"object"}, Properties: map[string]*Schema{"name": StringSchema("")}}).
+		WithNoContentResponse())
+	doc.AddOperation("/unnamed", "GET", NewOperation("", "No operationId", "").
+		WithNoContentResponse())
+
+	src, err := doc.GenerateFuzzTests(FuzzOptions{Package: "generated"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	code := string(src)
+	if !strings.Contains(code, "func FuzzHealth(f *testing.F)") || !strings.Contains(code, "func FuzzUpdatePet(f *testing.F)") {
+		t.Errorf("Expected fuzz targets for both named operations, got:\n%s", code)
+	}
+	if strings.Contains(code, "f.Fuzz(func(t *testing.T) {") {
+		t.Errorf("Expected every fuzz target to take an argument, got:\n%s", code)
+	}
+
+	spec, err := doc.ToJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	testGeneratedPackage(t, map[string]string{
+		"generated_test.go": code,
+		"handler_test.go": "package generated\n\n" +
+			"import (\n\t\"net/http\"\n\n\t\"github.com/nyxstack/openapi\"\n)\n\n" +
+			"func newFuzzHandler() http.Handler {\n" +
+			"\treturn http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })\n}\n\n" +
+			"func newFuzzDocument() *openapi.Document {\n" +
+			"\tdoc, err := openapi.FromJSON([]byte(" + "`" + string(spec) + "`" + "))\n" +
+			"\tif err != nil {\n\t\tpanic(err)\n\t}\n\treturn doc\n}\n",
+	})
+}
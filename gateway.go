@@ -0,0 +1,141 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ExtensionUpstream is the operation or document extension naming the upstream
+// URL a gateway should forward requests to
+const ExtensionUpstream = "x-upstream"
+
+// GatewayRoute is an entry of a reverse proxy route table
+type GatewayRoute struct {
+	// Path is the OpenAPI path template
+	Path    string
+	Methods []string
+	// Upstream is the base URL requests are forwarded to; the request path is
+	// appended to its path
+	Upstream *url.URL
+}
+
+// Patterns returns the net/http.ServeMux patterns matching the route, one per
+// method. Path segments mixing literals and variables ("{id}.json") can't be
+// expressed as ServeMux wildcards and match any value.
+func (r GatewayRoute) Patterns() []string {
+	segments := strings.Split(strings.Trim(r.Path, "/"), "/")
+	for i, segment := range segments {
+		if !strings.Contains(segment, "{") {
+			continue
+		}
+		name := "p" + strconv.Itoa(i)
+		if segmentRank(segment) == 2 {
+			name = wildcardName(segment[1:len(segment)-1], name)
+		}
+		segments[i] = "{" + name + "}"
+	}
+	path := "/" + strings.Join(segments, "/")
+
+	patterns := make([]string, len(r.Methods))
+	for i, method := range r.Methods {
+		patterns[i] = method + " " + path
+	}
+	return patterns
+}
+
+// GatewayRoutes derives a reverse proxy route table from the document. The
+// upstream of an operation is, in order of precedence: its x-upstream
+// extension, its first server, the first server of its path item, the
+// document's x-upstream extension, and the document's first server. Server
+// variables are replaced with their defaults. Operations on the same path
+// sharing an upstream are grouped into one route.
+func (d *Document) GatewayRoutes() ([]GatewayRoute, error) {
+	var routes []GatewayRoute
+	var err error
+	d.walkOperations(func(path, method string, op *Operation) {
+		if err != nil {
+			return
+		}
+		item := d.Paths[path]
+		candidates := []string{upstreamExtension(op.Extensions)}
+		if len(op.Servers) > 0 {
+			candidates = append(candidates, op.Servers[0].Expand(nil))
+		}
+		if len(item.Servers) > 0 {
+			candidates = append(candidates, item.Servers[0].Expand(nil))
+		}
+		candidates = append(candidates, upstreamExtension(d.Extensions))
+		if len(d.Servers) > 0 {
+			candidates = append(candidates, d.Servers[0].Expand(nil))
+		}
+
+		var upstream *url.URL
+		for _, candidate := range candidates {
+			if candidate == "" {
+				continue
+			}
+			upstream, err = url.Parse(candidate)
+			if err == nil && !upstream.IsAbs() {
+				err = fmt.Errorf("upstream %q for %s %s is not an absolute URL", candidate, method, path)
+			}
+			break
+		}
+		if err != nil {
+			return
+		}
+		if upstream == nil {
+			err = fmt.Errorf("no upstream for %s %s", method, path)
+			return
+		}
+
+		for i := range routes {
+			if routes[i].Path == path && routes[i].Upstream.String() == upstream.String() {
+				routes[i].Methods = append(routes[i].Methods, method)
+				return
+			}
+		}
+		routes = append(routes, GatewayRoute{Path: path, Methods: []string{method}, Upstream: upstream})
+	})
+	return routes, err
+}
+
+// NewGatewayHandler builds a ServeMux forwarding each route to its upstream
+// through an httputil.ReverseProxy
+func NewGatewayHandler(routes []GatewayRoute) http.Handler {
+	mux := http.NewServeMux()
+	proxies := make(map[string]*httputil.ReverseProxy)
+	for _, route := range routes {
+		proxy, ok := proxies[route.Upstream.String()]
+		if !ok {
+			proxy = httputil.NewSingleHostReverseProxy(route.Upstream)
+			proxies[route.Upstream.String()] = proxy
+		}
+		for _, pattern := range route.Patterns() {
+			mux.Handle(pattern, proxy)
+		}
+	}
+	return mux
+}
+
+func upstreamExtension(ext map[string]interface{}) string {
+	upstream, _ := ext[ExtensionUpstream].(string)
+	return upstream
+}
+
+// wildcardName turns a template variable into a valid ServeMux wildcard name
+func wildcardName(name, fallback string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || (i > 0 && '0' <= r && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return fallback
+	}
+	return b.String()
+}
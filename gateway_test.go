@@ -0,0 +1,601 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGatewayHandler(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Method+" "+r.URL.Path)
+	}))
+	defer upstream.Close()
+
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.Servers = []Server{{
+		URL:       upstream.URL + "/{version}",
+		Variables: map[string]ServerVariable{"version": {Default: "v1"}},
+	}}
+	doc.AddOperation("/pets/{petId}", "GET", NewOperation("getPet", "", ""))
+	doc.AddOperation("/pets/{petId}", "DELETE", NewOperation("deletePet", "", ""))
+
+	routes, err := doc.GatewayRoutes()
+	if err != nil {
+		t.Fatalf("Error building routes: %v", err)
+	}
+
+	if len(routes) != 1 || len(routes[0].Methods) != 2 {
+		t.Fatalf("Expected 1 route with 2 methods, got %+v", routes)
+	}
+
+	rec := httptest.NewRecorder()
+	NewGatewayHandler(routes).ServeHTTP(rec, httptest.NewRequest("GET", "/pets/42", nil))
+	if body := rec.Body.String(); body != "GET /v1/pets/42" {
+		t.Errorf("Expected request to be proxied to '/v1/pets/42', got '%s'", body)
+	}
+
+	rec = httptest.NewRecorder()
+	NewGatewayHandler(routes).ServeHTTP(rec, httptest.NewRequest("POST", "/pets/42", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for undeclared method, got %d", rec.Code)
+	}
+}
+
+func TestToProto(t *testing.T) {
+	doc := NewDocument("Pet Store", "1.0.0")
+	pet := NewObjectSchema()
+	doc.AddOperation("/pets/{petId}", "PUT", NewOperation("updatePet", "Update a pet", "").
+		WithPathParameter("petId", "", Int64Schema()).
+		WithQueryParameter("dryRun", "", false, NewBooleanSchema()).
+		WithHeaderParameter("X-Tenant-ID", "", true, StringSchema("")).
+		WithJSONRequestBody("Pet", true, pet).
+		WithNoContentResponse())
+
+	rules := doc.HTTPRules()
+	if len(rules) != 1 {
+		t.Fatalf("Expected 1 rule, got %d", len(rules))
+	}
+	rule := rules[0]
+	if rule.RPC != "UpdatePet" || rule.Pattern != "put" || rule.Template != "/pets/{pet_id}" || rule.Body != "body" {
+		t.Errorf("Unexpected rule: %+v", rule)
+	}
+	if len(rule.Notes) != 1 || !strings.Contains(rule.Notes[0], "X-Tenant-ID") {
+		t.Errorf("Expected a note about the header parameter, got %v", rule.Notes)
+	}
+
+	proto, err := doc.ToProto(ProtoOptions{})
+	if err != nil {
+		t.Fatalf("Error rendering proto: %v", err)
+	}
+	for _, want := range []string{
+		"package pet_store;",
+		"service PetStore {",
+		"rpc UpdatePet(UpdatePetRequest) returns (google.protobuf.Empty) {",
+		`put: "/pets/{pet_id}"`,
+		"int64 pet_id = 1;",
+		"bool dry_run = 2;",
+		"google.protobuf.Struct body = 3;",
+	} {
+		if !strings.Contains(string(proto), want) {
+			t.Errorf("Expected proto to contain '%s', got:\n%s", want, proto)
+		}
+	}
+}
+
+func TestToBackstageAPIEntity(t *testing.T) {
+	doc := NewDocument("Pet Store", "1.0.0")
+
+	if _, err := doc.ToBackstageAPIEntity(BackstageMetadata{}); err == nil {
+		t.Error("Expected an error without owner")
+	}
+
+	entity, err := doc.ToBackstageAPIEntity(BackstageMetadata{Owner: "group:pets", System: "store"})
+	if err != nil {
+		t.Fatalf("Error rendering entity: %v", err)
+	}
+	for _, want := range []string{"kind: API", "name: pet-store", "owner: group:pets", "system: store", "definition: |", `    "openapi": "3.1.0",`} {
+		if !strings.Contains(string(entity), want) {
+			t.Errorf("Expected entity to contain '%s', got:\n%s", want, entity)
+		}
+	}
+
+	entity, _ = doc.ToBackstageAPIEntity(BackstageMetadata{Owner: "group:pets", DefinitionURL: "https://example.com/openapi.yaml"})
+	if !strings.Contains(string(entity), "$text: https://example.com/openapi.yaml") {
+		t.Errorf("Expected referenced definition, got:\n%s", entity)
+	}
+}
+
+func TestToSQL(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddSchema("Owner", NewObjectSchema().
+		WithRequiredProperty("id", Int64Schema()).
+		WithProperty("email", StringSchema("email")))
+	status := StringSchema("").WithEnum("available", "sold")
+	doc.AddSchema("Pet", NewObjectSchema().
+		WithRequiredProperty("name", StringSchema("")).
+		WithProperty("status", &status).
+		WithProperty("owner", &Schema{Ref: "#/components/schemas/Owner"}).
+		WithProperty("tags", NewArraySchema(StringSchema(""))))
+
+	data, err := doc.ToSQL(SQLOptions{})
+	if err != nil {
+		t.Fatalf("Error exporting SQL: %v", err)
+	}
+	sql := string(data)
+	if strings.Index(sql, `CREATE TABLE "owner"`) > strings.Index(sql, `CREATE TABLE "pet"`) {
+		t.Error("Expected the owner table before the pet table referencing it")
+	}
+	for _, expected := range []string{
+		`"id" BIGINT NOT NULL PRIMARY KEY`,
+		`"name" TEXT NOT NULL`,
+		`"status" TEXT CHECK (status IN ('available', 'sold'))`,
+		`"owner_id" BIGINT REFERENCES "owner" ("id")`,
+		`"tags" JSONB`,
+		"-- Note: tags is an array and is stored as JSON",
+		"-- Note: no id property, so the table has no primary key",
+	} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expected SQL to contain %q, got:\n%s", expected, sql)
+		}
+	}
+}
+
+func TestToOperationsCSV(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddSecurityRequirement(SecurityRequirement{"oauth2": {"read:pets"}})
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "List pets, newest first", "").WithTags("pets", "public"))
+	doc.AddOperation("/health", "GET", NewOperation("health", "Health check", "").WithDeprecated())
+	doc.Paths["/health"].Get.Security = []SecurityRequirement{}
+
+	data, err := doc.ToOperationsCSV()
+	if err != nil {
+		t.Fatalf("Error exporting CSV: %v", err)
+	}
+	expected := "path,method,operationId,tags,auth,deprecated,summary\n" +
+		"/health,GET,health,,none,true,Health check\n" +
+		"/pets,GET,listPets,\"pets, public\",oauth2(read:pets),false,\"List pets, newest first\"\n"
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, data)
+	}
+}
+
+func TestSampleRequestFiles(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddServer("https://api.example.com/v1/", "")
+	doc.AddSecurityScheme("bearerAuth", *NewBearerSecurityScheme())
+	doc.AddSecurityRequirement(SecurityRequirement{"bearerAuth": {}})
+	doc.AddOperation("/pets/{petId}", "PUT", NewOperation("updatePet", "Update a pet", "").
+		WithParameter(NewPathParameter("petId", "", Int64Schema())).
+		WithRequestBody("", true, map[string]MediaType{
+			"application/json": {Example: map[string]interface{}{"name": "Rex's ball"}},
+		}))
+
+	httpFile := string(doc.ToHTTPFile())
+	for _, expected := range []string{
+		"@base_url = https://api.example.com/v1\n@bearer_auth = <token>\n",
+		"### Update a pet\n# @name updatePet\nPUT {{base_url}}/pets/0\nAuthorization: Bearer {{bearer_auth}}\nContent-Type: application/json\n\n{\n  \"name\": \"Rex's ball\"\n}\n",
+	} {
+		if !strings.Contains(httpFile, expected) {
+			t.Errorf("Expected .http file to contain %q, got:\n%s", expected, httpFile)
+		}
+	}
+
+	script := string(doc.ToCurlScript())
+	for _, expected := range []string{
+		`BEARER_AUTH="${BEARER_AUTH:-<token>}"`,
+		`curl -X PUT "${BASE_URL}/pets/0" \`,
+		`-H "Authorization: Bearer ${BEARER_AUTH}" \`,
+		`"name": "Rex'\''s ball"`,
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected script to contain %q, got:\n%s", expected, script)
+		}
+	}
+}
+
+func TestToInsomniaCollection(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddServer("https://api.example.com", "Production")
+	doc.AddSecurityScheme("apiKey", *NewAPIKeySecurityScheme("X-API-Key", "header"))
+	doc.AddSecurityRequirement(SecurityRequirement{"apiKey": {}})
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "List pets", "").WithTags("pets"))
+
+	data, err := doc.ToInsomniaCollection()
+	if err != nil {
+		t.Fatalf("Error exporting collection: %v", err)
+	}
+	var export insomniaExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("Error parsing collection: %v", err)
+	}
+	byID := make(map[string]insomniaResource)
+	for _, r := range export.Resources {
+		byID[r.ID] = r
+	}
+	if env := byID["env_server_1"]; env.Data["base_url"] != "https://api.example.com" || env.Name != "Production" {
+		t.Errorf("Expected a Production environment, got %+v", env)
+	}
+	request := byID["req_list_pets"]
+	if request.ParentID != "fld_pets" || request.URL != "{{ _.base_url }}/pets" {
+		t.Errorf("Expected listPets in the pets folder, got %+v", request)
+	}
+	if auth := request.Authentication; auth["type"] != "apikey" || auth["key"] != "X-API-Key" || auth["value"] != "{{ _.api_key }}" {
+		t.Errorf("Expected API key authentication, got %v", auth)
+	}
+}
+
+func TestToK6Script(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "List pets", ""))
+	doc.AddOperation("/pets", "POST", NewOperation("createPet", "Create a pet", "").
+		WithRequestBody("", true, map[string]MediaType{
+			"application/json": {Example: map[string]interface{}{"name": "Rex"}},
+		}))
+
+	data, err := doc.ToK6Script(K6Options{})
+	if err != nil {
+		t.Fatalf("Error generating script: %v", err)
+	}
+	if script := string(data); !strings.Contains(script, `name: "listPets"`) || strings.Contains(script, "createPet") {
+		t.Errorf("Expected only safe operations by default, got:\n%s", script)
+	}
+
+	data, err = doc.ToK6Script(K6Options{Operations: []string{"listPets", "createPet"}, Weights: map[string]int{"listPets": 9}})
+	if err != nil {
+		t.Fatalf("Error generating script: %v", err)
+	}
+	script := string(data)
+	for _, expected := range []string{
+		"const BASE_URL = __ENV.BASE_URL || \"http://localhost\";",
+		"weight: 9,\n    method: \"GET\",\n    url: `${BASE_URL}/pets`,",
+		`body: "{\n  \"name\": \"Rex\"\n}",`,
+		"headers: { \"Content-Type\": `application/json` },",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected script to contain %q, got:\n%s", expected, script)
+		}
+	}
+
+	if _, err := doc.ToK6Script(K6Options{Operations: []string{"deletePet"}}); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+}
+
+func TestFirewallPolicy(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddSchema("Tag", *StringSchema(""))
+	doc.AddSchema("Pet", NewObjectSchema().WithProperty("tag", &Schema{Ref: "#/components/schemas/Tag"}))
+	status := StringSchema("").WithEnum("sold", "available")
+	doc.AddOperation("/pets/{petId}", "PUT", NewOperation("updatePet", "", "").
+		WithParameter(NewPathParameter("petId", "", Int64Schema())).
+		WithParameter(NewQueryParameter("status", "", false, &status)).
+		WithRequestBody("", true, map[string]MediaType{
+			"application/json": {Schema: &Schema{Ref: "#/components/schemas/Pet"}},
+		}))
+
+	policy := doc.FirewallPolicy()
+	if len(policy.Rules) != 1 {
+		t.Fatalf("Expected 1 rule, got %d", len(policy.Rules))
+	}
+	rule := policy.Rules[0]
+	if rule.PathPattern != `^/pets/[^/]+$` || rule.Method != "PUT" {
+		t.Errorf("Expected a PUT rule for /pets/{petId}, got %s %s", rule.Method, rule.PathPattern)
+	}
+	if len(rule.ContentTypes) != 1 || rule.ContentTypes[0] != "application/json" || !rule.BodyRequired {
+		t.Errorf("Expected a required JSON body, got %v", rule.ContentTypes)
+	}
+	if len(rule.Parameters) != 2 || rule.Parameters[0].Pattern != `^-?[0-9]+$` || rule.Parameters[1].Pattern != `^(available|sold)$` {
+		t.Errorf("Expected integer and enum patterns, got %+v", rule.Parameters)
+	}
+	if policy.Components == nil || policy.Components.Schemas["Pet"] == nil || policy.Components.Schemas["Tag"] == nil {
+		t.Errorf("Expected the referenced Pet and Tag schemas, got %+v", policy.Components)
+	}
+}
+
+func TestVerifyingProxy(t *testing.T) {
+	doc := mockDocument()
+	limit := NewQueryParameter("limit", "", false, Int32Schema())
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithParameter(limit).
+		WithJSONResponse("200", "Pets", NewArraySchema(StringSchema(""))))
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[1]`))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	var exchanges []ProxyExchange
+	var logs strings.Builder
+	proxy := NewVerifyingProxy(doc, target, ProxyOptions{
+		Logger:     slog.New(slog.NewTextHandler(&logs, nil)),
+		OnExchange: func(e ProxyExchange) { exchanges = append(exchanges, e) },
+	})
+
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest("GET", "/pets?limit=ten", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `[1]` {
+		t.Errorf("Expected the upstream response to pass through, got %d %s", rec.Code, rec.Body)
+	}
+	if len(exchanges) != 1 {
+		t.Fatalf("Expected 1 exchange, got %d", len(exchanges))
+	}
+	if errs := exchanges[0].RequestErrors; len(errs) != 1 || errs[0].Path != "/query/limit" {
+		t.Errorf("Expected a violation on the limit parameter, got %v", errs)
+	}
+	if errs := exchanges[0].ResponseErrors; len(errs) != 1 || !strings.HasPrefix(errs[0].Path, "/paths/~1pets/get/responses/200") {
+		t.Errorf("Expected a violation in the response body, got %v", errs)
+	}
+	if !strings.Contains(logs.String(), "pointer=/query/limit") {
+		t.Errorf("Expected violations to be logged, got %s", logs.String())
+	}
+}
+
+func TestConformanceReport(t *testing.T) {
+	doc := mockDocument()
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithJSONResponse("200", "Pets", NewArraySchema(StringSchema(""))))
+
+	report := NewConformanceReport(doc)
+	match, _ := doc.Match("GET", "/pets")
+	violation := ValidationError{Path: "/query/limit", Message: "invalid integer"}
+	report.Record(ProxyExchange{Method: "GET", URLPath: "/pets", Match: match, Status: 200, RequestErrors: []ValidationError{violation}})
+	report.Record(ProxyExchange{Method: "GET", URLPath: "/pets", Match: match, Status: 200, RequestErrors: []ValidationError{violation}})
+	report.Record(ProxyExchange{Method: "GET", URLPath: "/unknown", Status: 404})
+	report.Record(ProxyExchange{Method: "GET", URLPath: "/unknown/1", Status: 404})
+	report.Record(ProxyExchange{Method: "PROPFIND", URLPath: "/unknown", Status: 404})
+
+	listed := report.Operations["GET /pets"]
+	if listed.Requests != 2 || len(listed.RequestViolations) != 1 || listed.RequestViolations[0].Count != 2 {
+		t.Errorf("Expected 2 requests with one violation seen twice, got %+v", listed)
+	}
+	if report.Conformant() {
+		t.Error("Expected the report not to be conformant")
+	}
+	if len(report.Unmatched) != 2 || report.Unmatched["GET"] != 2 || report.Unmatched[OtherMethod] != 1 {
+		t.Errorf("Expected unmatched requests counted by method, got %v", report.Unmatched)
+	}
+	unexercised := report.Unexercised()
+	if len(unexercised) == 0 || slices.Contains(unexercised, "GET /pets") {
+		t.Errorf("Expected the other operations to be unexercised, got %v", unexercised)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Failed to marshal report: %v", err)
+	}
+	loaded, err := LoadConformanceReport(doc, data)
+	if err != nil {
+		t.Fatalf("Failed to load report: %v", err)
+	}
+	loaded.Record(ProxyExchange{Method: "GET", URLPath: "/pets", Match: match, Status: 200})
+	if loaded.Operations["GET /pets"].Requests != 3 || loaded.Unmatched["GET"] != 2 {
+		t.Errorf("Expected the loaded report to carry on counting, got %+v and %v", loaded.Operations["GET /pets"], loaded.Unmatched)
+	}
+
+	markdown := loaded.Markdown()
+	if !strings.Contains(markdown, "- request `/query/limit`: invalid integer (2)") || !strings.Contains(markdown, "not exercised") {
+		t.Errorf("Expected violations and unexercised operations in Markdown, got %s", markdown)
+	}
+	if !strings.Contains(markdown, "- GET (2)\n- OTHER (1)") {
+		t.Errorf("Expected unmatched requests by method in Markdown, got %s", markdown)
+	}
+
+	legacy, err := LoadConformanceReport(doc, []byte(`{"operations": {}, "unmatched": {"GET /a": 1, "GET /b": 2, "DELETE /a": 1}}`))
+	if err != nil {
+		t.Fatalf("Failed to load report: %v", err)
+	}
+	if len(legacy.Unmatched) != 2 || legacy.Unmatched["GET"] != 3 || legacy.Unmatched["DELETE"] != 1 {
+		t.Errorf("Expected unmatched requests by path merged by method, got %v", legacy.Unmatched)
+	}
+}
+
+func TestToTerraform(t *testing.T) {
+	doc := NewDocument("Pet Store API", "1.0.0")
+	doc.AddServer("https://{env}.example.com/v1", "Production")
+	doc.Servers[0].Variables["env"] = ServerVariable{Default: "api"}
+	doc.AddOperation("/pets/{petId}", "GET", NewOperation("getPet", "", "Expands ${petId} and %{if} literally").
+		WithPathParameter("petId", "", Int64Schema()))
+
+	decode := func(data []byte) map[string]interface{} {
+		t.Helper()
+		var config map[string]interface{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			t.Fatalf("Expected a JSON configuration, got %v", err)
+		}
+		return config
+	}
+	lookup := func(v interface{}, keys ...string) interface{} {
+		for _, key := range keys {
+			m, _ := v.(map[string]interface{})
+			v = m[key]
+		}
+		return v
+	}
+
+	data, err := doc.ToTerraform(TerraformOptions{Target: TerraformAWS, Integrations: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	config := decode(data)
+	resources := config["resource"]
+	if name := lookup(resources, "aws_api_gateway_rest_api", "pet_store_api", "name"); name != "Pet Store API" {
+		t.Errorf("Expected the API named after the title, got %v", name)
+	}
+	if body := lookup(resources, "aws_api_gateway_rest_api", "pet_store_api", "body"); body != "${local.pet_store_api_openapi}" {
+		t.Errorf("Expected the body read from the local, got %v", body)
+	}
+	if id := lookup(resources, "aws_api_gateway_deployment", "pet_store_api", "rest_api_id"); id != "${aws_api_gateway_rest_api.pet_store_api.id}" {
+		t.Errorf("Expected the deployment to reference the API, got %v", id)
+	}
+	stage := lookup(resources, "aws_api_gateway_stage", "pet_store_api")
+	if lookup(stage, "stage_name") != "prod" || lookup(stage, "variables", "env") != "api" {
+		t.Errorf("Expected the prod stage with server variable defaults, got %v", stage)
+	}
+
+	spec, _ := lookup(config, "locals", "pet_store_api_openapi").(string)
+	if !strings.Contains(spec, "$${petId}") || !strings.Contains(spec, "%%{if}") {
+		t.Errorf("Expected template sequences escaped, got %s", spec)
+	}
+	unescaped := strings.NewReplacer("$${", "${", "%%{", "%{").Replace(spec)
+	inlined, err := FromJSON([]byte(unescaped))
+	if err != nil {
+		t.Fatalf("Expected the unescaped body to be the document, got %v", err)
+	}
+	_, _, op := inlined.FindOperation("getPet")
+	if op == nil || op.Description != "Expands ${petId} and %{if} literally" {
+		t.Fatalf("Expected the description to survive escaping, got %+v", op)
+	}
+	integration := op.Extensions["x-amazon-apigateway-integration"]
+	if uri := lookup(integration, "uri"); uri != "https://${stageVariables.env}.example.com/v1/pets/{petId}" {
+		t.Errorf("Expected the upstream templated with stage variables, got %v", uri)
+	}
+	if mapping := lookup(integration, "requestParameters", "integration.request.path.petId"); mapping != "method.request.path.petId" {
+		t.Errorf("Expected the path parameter mapped, got %v", mapping)
+	}
+
+	if _, err := doc.ToTerraform(TerraformOptions{Target: TerraformGCP}); err == nil {
+		t.Error("Expected an error without a GCP project")
+	}
+	data, err = doc.ToTerraform(TerraformOptions{Target: TerraformGCP, Project: "pets-prod", Name: "Pets", StageName: "live"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	config = decode(data)
+	resources = config["resource"]
+	if id := lookup(resources, "google_api_gateway_api", "pets", "api_id"); id != "pets" {
+		t.Errorf("Expected api_id 'pets', got %v", id)
+	}
+	documents, _ := lookup(resources, "google_api_gateway_api_config", "pets", "openapi_documents").([]interface{})
+	if len(documents) != 1 || lookup(documents[0], "document", "contents") != "${base64encode(local.pets_openapi)}" {
+		t.Errorf("Expected the document inlined from the local, got %v", documents)
+	}
+	gateway := lookup(resources, "google_api_gateway_gateway", "pets")
+	if lookup(gateway, "gateway_id") != "pets-live" || lookup(gateway, "region") != "us-central1" || lookup(gateway, "project") != "pets-prod" {
+		t.Errorf("Expected the live gateway in the default region, got %v", gateway)
+	}
+	if spec, _ := lookup(config, "locals", "pets_openapi").(string); !strings.Contains(spec, "$${petId}") || strings.Contains(spec, "x-amazon-apigateway-integration") {
+		t.Errorf("Expected an escaped body without AWS integrations, got %s", spec)
+	}
+
+	if _, err := doc.ToTerraform(TerraformOptions{Target: "azure"}); err == nil {
+		t.Error("Expected an error for an unknown target")
+	}
+}
+
+func TestToHTTPRoutes(t *testing.T) {
+	pets := BackendRef{Name: "pets", Port: 8080}
+	doc := NewDocument("Pet Store API", "1.0.0")
+	doc.AddServer("https://api.example.com/v1", "Production")
+	doc.AddServer("https://staging.example.com/v1", "Staging")
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").WithBackendRef(pets))
+	doc.AddOperation("/pets/{petId}", "GET", NewOperation("getPet", "", "").WithBackendRef(pets))
+	doc.AddOperation("/owners/{ownerId}/pets", "GET", NewOperation("listOwnerPets", "", ""))
+
+	decode := func(data []byte) []httpRoute {
+		t.Helper()
+		var routes []httpRoute
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		for {
+			var route httpRoute
+			if err := dec.Decode(&route); err == io.EOF {
+				return routes
+			} else if err != nil {
+				t.Fatalf("Expected a YAML stream of routes, got %v", err)
+			}
+			routes = append(routes, route)
+		}
+	}
+
+	if _, err := doc.ToHTTPRoutes(HTTPRouteOptions{}); err == nil || !strings.Contains(err.Error(), "GET /owners/{ownerId}/pets") {
+		t.Errorf("Expected an error for the operation without backend, got %v", err)
+	}
+
+	owners := BackendRef{Name: "owners", Namespace: "crm", Port: 80}
+	data, err := doc.ToHTTPRoutes(HTTPRouteOptions{Namespace: "shop", Gateway: "infra/public", Backend: owners})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	routes := decode(data)
+	if len(routes) != 1 {
+		t.Fatalf("Expected 1 route, got %d", len(routes))
+	}
+	route := routes[0]
+	if route.APIVersion != "gateway.networking.k8s.io/v1" || route.Kind != "HTTPRoute" {
+		t.Errorf("Expected a v1 HTTPRoute, got %s %s", route.APIVersion, route.Kind)
+	}
+	if route.Metadata != (httpRouteMetadata{Name: "pet-store-api", Namespace: "shop"}) {
+		t.Errorf("Expected the route named after the title, got %+v", route.Metadata)
+	}
+	if len(route.Spec.ParentRefs) != 1 || route.Spec.ParentRefs[0] != (httpRouteParent{Name: "public", Namespace: "infra"}) {
+		t.Errorf("Expected the infra/public gateway as parent, got %+v", route.Spec.ParentRefs)
+	}
+	if !slices.Equal(route.Spec.Hostnames, []string{"api.example.com", "staging.example.com"}) {
+		t.Errorf("Expected the server hosts, got %v", route.Spec.Hostnames)
+	}
+
+	expected := []httpRouteRule{
+		{
+			Matches: []httpRouteMatch{
+				{Path: httpRoutePath{Type: "RegularExpression", Value: "^/v1/owners/[^/]+/pets$"}, Method: "GET"},
+			},
+			BackendRefs: []BackendRef{owners},
+		},
+		{
+			Matches: []httpRouteMatch{
+				{Path: httpRoutePath{Type: "Exact", Value: "/v1/pets"}, Method: "GET"},
+				{Path: httpRoutePath{Type: "RegularExpression", Value: "^/v1/pets/[^/]+$"}, Method: "GET"},
+			},
+			BackendRefs: []BackendRef{pets},
+		},
+	}
+	if len(route.Spec.Rules) != len(expected) {
+		t.Fatalf("Expected %d rules, got %+v", len(expected), route.Spec.Rules)
+	}
+	for i, rule := range route.Spec.Rules {
+		if !slices.Equal(rule.Matches, expected[i].Matches) || !slices.Equal(rule.BackendRefs, expected[i].BackendRefs) {
+			t.Errorf("Expected rule %d to be %+v, got %+v", i, expected[i], rule)
+		}
+	}
+
+	data, err = doc.ToHTTPRoutes(HTTPRouteOptions{Backend: owners, PrefixMatch: true, BasePath: "/api", Hostnames: []string{}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	route = decode(data)[0]
+	if path := route.Spec.Rules[1].Matches[1].Path; path != (httpRoutePath{Type: "PathPrefix", Value: "/api/pets"}) {
+		t.Errorf("Expected a prefix match on the literal prefix, got %+v", path)
+	}
+	if path := route.Spec.Rules[0].Matches[0].Path; path != (httpRoutePath{Type: "PathPrefix", Value: "/api/owners"}) {
+		t.Errorf("Expected a prefix match on the literal prefix, got %+v", path)
+	}
+	if len(route.Spec.Hostnames) != 0 || len(route.Spec.ParentRefs) != 0 {
+		t.Errorf("Expected no hostnames or parents, got %+v", route.Spec)
+	}
+
+	many := NewDocument("Many", "1.0.0")
+	for i := 0; i <= maxHTTPRouteRules; i++ {
+		name := fmt.Sprintf("service%d", i)
+		many.AddOperation("/"+name, "GET", NewOperation(name, "", "").WithBackendRef(BackendRef{Name: name}))
+	}
+	data, err = many.ToHTTPRoutes(HTTPRouteOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	routes = decode(data)
+	if len(routes) != 2 || routes[0].Metadata.Name != "many-1" || routes[1].Metadata.Name != "many-2" || len(routes[1].Spec.Rules) != 1 {
+		t.Errorf("Expected the rules split over many-1 and many-2, got %+v", routes)
+	}
+}
@@ -0,0 +1,147 @@
+// Package ginopenapi integrates documents with the Gin web framework
+// (github.com/gin-gonic/gin): it scaffolds operations from the routes of an
+// engine, documents routes as they are registered through a Router and
+// mounts endpoints serving the document and its reference docs
+package ginopenapi
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nyxstack/openapi"
+)
+
+// contextKey is the Gin context key under which Router handlers find their operation
+const contextKey = "openapi.operation"
+
+// methods are the HTTP methods an OpenAPI path item can describe
+var methods = map[string]bool{
+	http.MethodGet: true, http.MethodPut: true, http.MethodPost: true, http.MethodDelete: true,
+	http.MethodOptions: true, http.MethodHead: true, http.MethodPatch: true, http.MethodTrace: true,
+}
+
+// Path converts a Gin route path into an OpenAPI path template: ":id" and
+// "*filepath" segments become "{id}" and "{filepath}"
+func Path(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Scaffold documents the routes of an engine that the document doesn't
+// describe yet with placeholder operations (see Document.ScaffoldOperation).
+// Routes mounted by Mount are skipped.
+func Scaffold(doc *openapi.Document, engine *gin.Engine) {
+	for _, route := range engine.Routes() {
+		if !methods[route.Method] || route.Handler == mountedHandlerName {
+			continue
+		}
+		doc.ScaffoldOperation(route.Method, Path(route.Path))
+	}
+}
+
+// Router registers routes on a Gin router group and documents each of them
+// with its operation at the same time
+type Router struct {
+	doc   *openapi.Document
+	group *gin.RouterGroup
+}
+
+// NewRouter creates a router documenting the routes it registers on group,
+// e.g. &engine.RouterGroup
+func NewRouter(doc *openapi.Document, group *gin.RouterGroup) *Router {
+	return &Router{doc: doc, group: group}
+}
+
+// Group creates a router for a sub-group sharing the path prefix and handlers
+func (r *Router) Group(relativePath string, handlers ...gin.HandlerFunc) *Router {
+	return &Router{doc: r.doc, group: r.group.Group(relativePath, handlers...)}
+}
+
+// Handle registers handlers for a method and path and documents the route with
+// the operation. Path parameters of the route that the operation leaves out
+// are declared as required strings. Handlers read the operation with Operation.
+func (r *Router) Handle(method, relativePath string, op openapi.Operation, handlers ...gin.HandlerFunc) gin.IRoutes {
+	templ := Path(joinPaths(r.group.BasePath(), relativePath))
+	r.doc.AddOperation(templ, method, op.WithPathTemplateParameters(templ))
+	var registered *openapi.Operation
+	if item, ok := r.doc.Paths[templ]; ok {
+		registered = item.Operation(method)
+	}
+	describe := func(c *gin.Context) {
+		c.Set(contextKey, registered)
+	}
+	return r.group.Handle(method, relativePath, append([]gin.HandlerFunc{describe}, handlers...)...)
+}
+
+// GET registers and documents a GET route
+func (r *Router) GET(relativePath string, op openapi.Operation, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return r.Handle(http.MethodGet, relativePath, op, handlers...)
+}
+
+// POST registers and documents a POST route
+func (r *Router) POST(relativePath string, op openapi.Operation, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return r.Handle(http.MethodPost, relativePath, op, handlers...)
+}
+
+// PUT registers and documents a PUT route
+func (r *Router) PUT(relativePath string, op openapi.Operation, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return r.Handle(http.MethodPut, relativePath, op, handlers...)
+}
+
+// PATCH registers and documents a PATCH route
+func (r *Router) PATCH(relativePath string, op openapi.Operation, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return r.Handle(http.MethodPatch, relativePath, op, handlers...)
+}
+
+// DELETE registers and documents a DELETE route
+func (r *Router) DELETE(relativePath string, op openapi.Operation, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return r.Handle(http.MethodDelete, relativePath, op, handlers...)
+}
+
+// Operation returns the operation of the route handling the request when it
+// was registered through a Router, or nil
+func Operation(c *gin.Context) *openapi.Operation {
+	op, _ := c.Get(contextKey)
+	registered, _ := op.(*openapi.Operation)
+	return registered
+}
+
+// Mount registers endpoints under prefix serving the document as JSON at
+// "openapi.json" and an HTML reference page rendering it at "docs"
+func Mount(routes gin.IRoutes, prefix string, doc *openapi.Document) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	routes.GET(prefix+"/openapi.json", mountHandler(doc.Handler()))
+	routes.GET(prefix+"/docs", mountHandler(openapi.DocsHandler(doc.Info.Title, "openapi.json")))
+}
+
+// mountHandler adapts the handlers registered by Mount. Every closure it
+// returns shares one function name, by which Scaffold recognizes them.
+func mountHandler(h http.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+var mountedHandlerName = runtime.FuncForPC(reflect.ValueOf(mountHandler(nil)).Pointer()).Name()
+
+// joinPaths joins a group prefix and a relative route path like Gin does,
+// keeping a trailing slash of the relative path
+func joinPaths(base, relative string) string {
+	if relative == "" {
+		return base
+	}
+	joined := path.Join(base, relative)
+	if strings.HasSuffix(relative, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
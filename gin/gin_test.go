@@ -0,0 +1,65 @@
+package ginopenapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nyxstack/openapi"
+)
+
+func TestPath(t *testing.T) {
+	for ginPath, want := range map[string]string{
+		"/pets":             "/pets",
+		"/pets/:id":         "/pets/{id}",
+		"/static/*filepath": "/static/{filepath}",
+		"/owners/:id/pets/": "/owners/{id}/pets/",
+	} {
+		if got := Path(ginPath); got != want {
+			t.Errorf("Expected '%s' for '%s', got '%s'", want, ginPath, got)
+		}
+	}
+}
+
+func TestRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	doc := openapi.NewDocument("Pet API", "1.0.0")
+	engine := gin.New()
+
+	var seen *openapi.Operation
+	handler := func(c *gin.Context) {
+		seen = Operation(c)
+		c.Status(http.StatusNoContent)
+	}
+	api := NewRouter(doc, engine.Group("/v1"))
+	api.GET("/pets/:id", openapi.NewOperation("getPet", "Get a pet", ""), handler)
+	engine.DELETE("/v1/pets/:id", handler)
+	Mount(engine, "/", doc)
+	Scaffold(doc, engine)
+
+	item := doc.Paths["/v1/pets/{id}"]
+	if item.Get == nil || len(item.Get.Parameters) != 1 || item.Get.Parameters[0].Name != "id" {
+		t.Fatalf("Expected registered GET with 'id' parameter, got %+v", item.Get)
+	}
+	if item.Delete == nil || item.Delete.OperationID != "deleteV1PetsById" {
+		t.Errorf("Expected scaffolded DELETE operation, got %+v", item.Delete)
+	}
+	if len(doc.Paths) != 1 {
+		t.Errorf("Expected mounted routes not to be scaffolded, got %d paths", len(doc.Paths))
+	}
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/pets/1", nil))
+	if seen == nil || seen.OperationID != "getPet" {
+		t.Errorf("Expected handler to see getPet, got %+v", seen)
+	}
+
+	for path, want := range map[string]string{"/openapi.json": `"getPet"`, "/docs": "openapi.json"} {
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("Expected %s to serve '%s', got %d: %s", path, want, rec.Code, rec.Body)
+		}
+	}
+}
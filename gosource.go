@@ -0,0 +1,50 @@
+package openapi
+
+// ExtensionGoSource is the schema extension recording the Go type a schema was
+// derived from by reflection
+const ExtensionGoSource = "x-go-source"
+
+// GoSource identifies a Go type. The hashes let Regenerate tell whether the
+// type changed since the schema was generated and whether the schema was
+// edited by hand since.
+type GoSource struct {
+	Package string `json:"package"`
+	Type    string `json:"type"`
+	// TypeHash fingerprints the fields, types and tags of the type
+	TypeHash string `json:"typeHash,omitempty"`
+	// SchemaHash fingerprints the schema as generated
+	SchemaHash string `json:"schemaHash,omitempty"`
+}
+
+// String returns the qualified type name, e.g. "github.com/acme/pets.Pet"
+func (s GoSource) String() string {
+	if s.Package == "" {
+		return s.Type
+	}
+	return s.Package + "." + s.Type
+}
+
+// GoSource returns the Go type the schema was derived from by SchemaFor
+func (s *Schema) GoSource() (GoSource, bool) {
+	var source GoSource
+	ok := decodeExtension(s.Extensions[ExtensionGoSource], &source)
+	return source, ok
+}
+
+// Provenance maps the names of component schemas derived from Go types to
+// those types, so reviewers can trace schemas back to the code defining them
+func (d *Document) Provenance() map[string]GoSource {
+	provenance := make(map[string]GoSource)
+	if d.Components == nil {
+		return provenance
+	}
+	for name, schema := range d.Components.Schemas {
+		if schema == nil {
+			continue
+		}
+		if source, ok := schema.GoSource(); ok {
+			provenance[name] = source
+		}
+	}
+	return provenance
+}
@@ -0,0 +1,217 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// HTTPError is an error carrying the HTTP status of the response reporting it
+type HTTPError struct {
+	Status  int
+	Message string
+}
+
+// NewHTTPError creates an error reported with the given status
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+// Handle registers a typed handler on a ServeMux and documents it as an
+// operation at the same time, so the handler and the specification can't
+// drift. The pattern uses ServeMux syntax and must include the method, e.g.
+// "POST /pets" or "GET /pets/{id}".
+//
+// Req must be a struct. Its fields tagged `path:""`, `query:""`, `header:""`
+// or `cookie:""` become parameters, and its field tagged `body:""` becomes the
+// JSON request body. Without a body field, the remaining fields of Req form
+// the body of POST, PUT and PATCH requests. Resp is documented as the JSON
+// body of the 200 response. Schemas are derived with SchemaFor.
+//
+// Requests are bound and validated against the generated operation before the
+// handler runs; invalid requests get a 400 response listing the problems.
+// Errors returned by the handler produce their *HTTPError status or 500.
+// The returned operation can be refined, e.g. with a summary and tags.
+func Handle[Req, Resp any](d *Document, mux *http.ServeMux, pattern string, handler func(context.Context, Req) (Resp, error)) *Operation {
+	method, path, ok := parsePattern(pattern)
+	if !ok {
+		panic(fmt.Sprintf("openapi: Handle pattern %q must have the form \"METHOD /path\"", pattern))
+	}
+	reqType := reflect.TypeFor[Req]()
+	if reqType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("openapi: Handle request type %s must be a struct", reqType))
+	}
+
+	op := NewOperation(patternOperationID(method, path), "", "")
+	bodyType, hasBody := d.collectParameters(reqType, &op)
+	if !hasBody && (method == "POST" || method == "PUT" || method == "PATCH") && hasBodyFields(reqType) {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: d.requestBodySchema(reqType)}},
+		}
+	} else if hasBody {
+		op.RequestBody = &RequestBody{
+			Required: bodyType.Kind() != reflect.Pointer,
+			Content:  map[string]MediaType{"application/json": {Schema: d.SchemaFor(bodyType)}},
+		}
+	}
+	op.Responses["200"] = Response{
+		Description: "OK",
+		Content:     map[string]MediaType{"application/json": {Schema: d.SchemaFor(reflect.TypeFor[Resp]())}},
+	}
+	op.Responses["400"] = Response{Description: "Invalid request"}
+
+	d.AddOperation(path, method, op)
+	item := d.Paths[path]
+	registered := item.Operation(method)
+	binder, err := d.NewBinder(op.OperationID)
+	if err != nil {
+		panic(err)
+	}
+
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+		if err := binder.Bind(r, &req); err != nil {
+			var reqErr *RequestError
+			if errors.As(err, &reqErr) {
+				writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": reqErr.Errors})
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		resp, err := handler(r.Context(), req)
+		if err != nil {
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) {
+				writeJSON(w, httpErr.Status, map[string]string{"error": httpErr.Message})
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+	return registered
+}
+
+// parsePattern splits a ServeMux pattern into its method and an OpenAPI path
+// template, dropping the host, "{$}" anchors and "..." wildcard suffixes
+func parsePattern(pattern string) (method, path string, ok bool) {
+	method, rest, ok := strings.Cut(strings.TrimSpace(pattern), " ")
+	if !ok {
+		return "", "", false
+	}
+	rest = strings.TrimSpace(rest)
+	slash := strings.Index(rest, "/")
+	if slash < 0 {
+		return "", "", false
+	}
+	path = strings.ReplaceAll(rest[slash:], "...}", "}")
+	path = strings.TrimSuffix(path, "{$}")
+	return strings.ToUpper(method), path, true
+}
+
+// patternOperationID derives an operationId such as "getPetsById" from a method and path
+func patternOperationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			b.WriteString("By" + exportedName(strings.TrimSuffix(name, "}")))
+			continue
+		}
+		b.WriteString(exportedName(segment))
+	}
+	return b.String()
+}
+
+// collectParameters documents the parameter fields of a request struct and
+// returns the type of its body field, if any
+func (d *Document) collectParameters(t reflect.Type, op *Operation) (bodyType reflect.Type, hasBody bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if bt, ok := d.collectParameters(field.Type, op); ok {
+				bodyType, hasBody = bt, true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		for _, in := range []string{"path", "query", "header", "cookie"} {
+			name, ok := field.Tag.Lookup(in)
+			if !ok {
+				continue
+			}
+			if name == "" {
+				panic(fmt.Sprintf("openapi: field %s of %s must name its %s parameter", field.Name, t, in))
+			}
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:        name,
+				In:          in,
+				Description: field.Tag.Get("description"),
+				Required:    in == "path",
+				Schema:      d.SchemaFor(field.Type),
+			})
+		}
+		if _, ok := field.Tag.Lookup("body"); ok {
+			bodyType, hasBody = field.Type, true
+		}
+	}
+	return bodyType, hasBody
+}
+
+// isParameterField reports whether a struct field is bound to a parameter or the body
+func isParameterField(field reflect.StructField) bool {
+	for _, tag := range []string{"path", "query", "header", "cookie", "body"} {
+		if _, ok := field.Tag.Lookup(tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBodyFields reports whether a request struct has fields outside parameters
+func hasBodyFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if hasBodyFields(field.Type) {
+				return true
+			}
+			continue
+		}
+		if field.IsExported() && !isParameterField(field) && field.Tag.Get("json") != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// requestBodySchema documents the fields of a request struct that aren't parameters
+func (d *Document) requestBodySchema(t reflect.Type) *Schema {
+	schema := NewObjectSchema()
+	schema.Properties = make(map[string]*Schema)
+	d.collectStructFields(t, schema, true)
+	return schema
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
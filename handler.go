@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Handler returns an http.Handler serving the document as JSON. The document
+// is rendered on every request, so later changes to it are served.
+//
+// The "tag" and "path" query parameters, which may be repeated, serve a
+// filtered view of the document as by Filter, as in ?tag=pet or
+// ?path=/admin/*. Filtered views are cached until the document changes.
+func (d *Document) Handler() http.Handler {
+	cache := &viewCache{views: make(map[string]cachedView)}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		opts := FilterOptions{Tags: query["tag"], Paths: query["path"]}
+		var data []byte
+		var err error
+		if len(opts.Tags) == 0 && len(opts.Paths) == 0 {
+			data, err = d.ToJSON()
+		} else {
+			data, err = cache.view(d, opts)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	})
+}
+
+// maxCachedViews bounds the filtered views a Handler keeps, since clients
+// choose the filters
+const maxCachedViews = 64
+
+// viewCache holds the filtered views served by a Handler, along with the
+// JSON of the document they were filtered from
+type viewCache struct {
+	mu    sync.Mutex
+	views map[string]cachedView
+}
+
+type cachedView struct {
+	source []byte
+	data   []byte
+}
+
+// view returns the JSON of a filtered view of the document, filtering it
+// again when the document changed since the view was cached
+func (c *viewCache) view(d *Document, opts FilterOptions) ([]byte, error) {
+	source, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%q %q", opts.Tags, opts.Paths)
+	c.mu.Lock()
+	cached, ok := c.views[key]
+	c.mu.Unlock()
+	if ok && bytes.Equal(cached.source, source) {
+		return cached.data, nil
+	}
+
+	filtered, err := d.Filter(opts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := filtered.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if _, ok := c.views[key]; !ok && len(c.views) >= maxCachedViews {
+		clear(c.views)
+	}
+	c.views[key] = cachedView{source: source, data: data}
+	c.mu.Unlock()
+	return data, nil
+}
+
+// DocsHandler returns an http.Handler serving an HTML page that renders the
+// document found at specURL, which may be relative to the page, as API
+// reference documentation. The page loads Swagger UI from a CDN.
+func DocsHandler(title, specURL string) http.Handler {
+	page := fmt.Sprintf(docsPage, html.EscapeString(title), strconv.Quote(specURL))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, page)
+	})
+}
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="docs"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: %s, dom_id: "#docs"});</script>
+</body>
+</html>
+`
@@ -1,19 +1,52 @@
 package openapi
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 // Header represents a header in OpenAPI
 type Header struct {
-	Ref             string               `json:"$ref,omitempty"`
-	Description     string               `json:"description,omitempty"`
-	Required        bool                 `json:"required,omitempty"`
-	Deprecated      bool                 `json:"deprecated,omitempty"`
-	AllowEmptyValue bool                 `json:"allowEmptyValue,omitempty"`
-	Style           string               `json:"style,omitempty"`
-	Explode         bool                 `json:"explode,omitempty"`
-	AllowReserved   bool                 `json:"allowReserved,omitempty"`
-	Schema          *Schema              `json:"schema,omitempty"`
-	Example         interface{}          `json:"example,omitempty"`
-	Examples        map[string]Example   `json:"examples,omitempty"`
-	Content         map[string]MediaType `json:"content,omitempty"`
+	Ref             *Reference             `json:"-"`
+	Description     string                 `json:"description,omitempty"`
+	Required        bool                   `json:"required,omitempty"`
+	Deprecated      bool                   `json:"deprecated,omitempty"`
+	AllowEmptyValue bool                   `json:"allowEmptyValue,omitempty"`
+	Style           string                 `json:"style,omitempty"`
+	Explode         bool                   `json:"explode,omitempty"`
+	AllowReserved   bool                   `json:"allowReserved,omitempty"`
+	Schema          *Schema                `json:"schema,omitempty"`
+	Example         interface{}            `json:"example,omitempty"`
+	Examples        map[string]Example     `json:"examples,omitempty"`
+	Content         map[string]MediaType   `json:"content,omitempty"`
+	Extensions      map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification
+// extensions. A reference is marshaled as the Reference Object alone.
+func (h Header) MarshalJSON() ([]byte, error) {
+	if h.Ref != nil {
+		return json.Marshal(h.Ref)
+	}
+	type header Header
+	return marshalWithExtensions(header(h), h.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification
+// extensions and decode Reference Objects
+func (h *Header) UnmarshalJSON(data []byte) error {
+	ref, err := unmarshalReference(data)
+	if err != nil || ref != nil {
+		*h = Header{Ref: ref}
+		return err
+	}
+	type header Header
+	if err := json.Unmarshal(data, (*header)(h)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	h.Extensions = ext
+	return err
 }
 
 // NewHeader creates a new header
@@ -86,3 +119,102 @@ func (h Header) WithExamples(name string, example Example) Header {
 	h.Examples[name] = example
 	return h
 }
+
+// NewHeaderFromParameter creates a header from a parameter definition.
+// Name and In are dropped because a header's name is the key of the map holding it.
+func NewHeaderFromParameter(p Parameter) Header {
+	h := Header{
+		Ref:             p.Ref,
+		Description:     p.Description,
+		Required:        p.Required,
+		Deprecated:      p.Deprecated,
+		AllowEmptyValue: p.AllowEmptyValue,
+		Style:           p.Style,
+		AllowReserved:   p.AllowReserved,
+		Schema:          p.Schema,
+		Example:         p.Example,
+		Examples:        p.Examples,
+		Content:         p.Content,
+	}
+	if p.Explode != nil {
+		h.Explode = *p.Explode
+	}
+	return h
+}
+
+// ToParameter converts the header into a header parameter with the given name
+func (h Header) ToParameter(name string) Parameter {
+	p := Parameter{
+		Ref:             h.Ref,
+		Name:            name,
+		In:              "header",
+		Description:     h.Description,
+		Required:        h.Required,
+		Deprecated:      h.Deprecated,
+		AllowEmptyValue: h.AllowEmptyValue,
+		Style:           h.Style,
+		AllowReserved:   h.AllowReserved,
+		Schema:          h.Schema,
+		Example:         h.Example,
+		Examples:        h.Examples,
+		Content:         h.Content,
+	}
+	if h.Explode {
+		explode := true
+		p.Explode = &explode
+	}
+	return p
+}
+
+// ignoredResponseHeaders lists response header names that are described elsewhere
+// in the document and must not be declared in a response's headers map
+var ignoredResponseHeaders = map[string]string{
+	"content-type":  "Content-Type is described by the response content map",
+	"authorization": "Authorization is described by security schemes",
+}
+
+// ignoredHeaderParameters lists header parameter names the specification ignores
+var ignoredHeaderParameters = map[string]string{
+	"accept":        "Accept is derived from response content types",
+	"content-type":  "Content-Type is described by the request body content map",
+	"authorization": "Authorization is described by security schemes",
+}
+
+// validateHeaders flags header declarations that the specification ignores
+func (v *validator) validateHeaders() {
+	v.doc.walkResponses(func(pointer string, r Response) {
+		for _, name := range sortedKeys(r.Headers) {
+			if reason, ok := ignoredResponseHeaders[strings.ToLower(name)]; ok {
+				v.warnf(pointer+"/headers/"+escapePointer(name), "header %q is ignored: %s", name, reason)
+			}
+		}
+	})
+
+	v.doc.walkParameters(func(pointer string, p Parameter) {
+		if p.In != "header" {
+			return
+		}
+		if reason, ok := ignoredHeaderParameters[strings.ToLower(p.Name)]; ok {
+			v.warnf(pointer, "header parameter %q is ignored: %s", p.Name, reason)
+		}
+	})
+
+	v.doc.walkMediaTypes(func(pointer, _ string, mt MediaType, _ bool) {
+		for _, prop := range sortedKeys(mt.Encoding) {
+			for _, name := range sortedKeys(mt.Encoding[prop].Headers) {
+				if strings.EqualFold(name, "Content-Type") {
+					v.warnf(pointer+"/encoding/"+escapePointer(prop)+"/headers/"+escapePointer(name), "header %q is ignored: use the encoding contentType instead", name)
+				}
+			}
+		}
+	})
+}
+
+// WithExtension sets a specification extension; the name must start with "x-"
+func (h Header) WithExtension(name string, value interface{}) Header {
+	if h.Extensions == nil {
+		h.Extensions = make(map[string]interface{})
+	}
+	h.Extensions[name] = value
+	return h
+}
@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HeaderRule requires a header on responses
+type HeaderRule struct {
+	// Header is the header name, matched case-insensitively; a trailing "*"
+	// matches any header with the prefix, e.g. "RateLimit-*"
+	Header string
+	// Codes restricts the rule to response codes: exact codes such as
+	// "429", ranges such as "2XX" covering the codes they contain, and
+	// "default". Empty applies the rule to every response.
+	Codes []string
+}
+
+// HeaderProfile checks that the responses of every operation document the
+// headers of its rules, reporting each gap as rule "headers/required"
+type HeaderProfile struct {
+	Rules []HeaderRule
+}
+
+// Name returns "headers"
+func (HeaderProfile) Name() string {
+	return "headers"
+}
+
+// Validate reports the responses missing a required header
+func (p HeaderProfile) Validate(d *Document) []ValidationError {
+	var errs []ValidationError
+	d.walkOperations(func(path, method string, op *Operation) {
+		for _, code := range sortedKeys(op.Responses) {
+			response := d.resolveResponse(op.Responses[code])
+			for _, rule := range p.Rules {
+				if !rule.applies(code) || rule.documented(response.Headers) {
+					continue
+				}
+				errs = append(errs, ValidationError{
+					Path:     operationPointer(path, method) + "/responses/" + escapePointer(code) + "/headers",
+					Message:  fmt.Sprintf("%s %s response %s does not document header %s", method, path, code, rule.Header),
+					Severity: SeverityError,
+					Rule:     "headers/required",
+				})
+			}
+		}
+	})
+	return errs
+}
+
+// applies reports whether the rule covers a response code
+func (r HeaderRule) applies(code string) bool {
+	if len(r.Codes) == 0 {
+		return true
+	}
+	for _, c := range r.Codes {
+		if strings.EqualFold(c, code) {
+			return true
+		}
+		if len(c) == 3 && strings.EqualFold(c[1:], "XX") && len(code) == 3 && code[0] == c[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// documented reports whether a headers map documents the rule's header
+func (r HeaderRule) documented(headers map[string]Header) bool {
+	prefix, wildcard := strings.CutSuffix(r.Header, "*")
+	for name := range headers {
+		if wildcard && len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+			return true
+		}
+		if !wildcard && strings.EqualFold(name, r.Header) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// sampleRequest is one operation's sample request. Variables are written in
+// the {{name}} syntax of REST Client files and translated for other formats.
+type sampleRequest struct {
+	name    string
+	title   string
+	tags    []string
+	method  string
+	path    string
+	query   url.Values
+	headers [][2]string
+	auth    []sampleAuth
+	// contentType and body are empty without a request body
+	contentType string
+	body        string
+}
+
+// sampleAuth is a security scheme a sample request must satisfy
+type sampleAuth struct {
+	// kind is "bearer", "basic" or "apiKey"
+	kind string
+	// in and name locate API keys
+	in, name string
+	// variable holds the credentials
+	variable string
+}
+
+// sampleVariable is a placeholder the user fills in before sending requests
+type sampleVariable struct {
+	name  string
+	value string
+}
+
+// target returns the URL of the request, with API keys passed in the query
+func (r sampleRequest) target() string {
+	query := url.Values{}
+	for name, values := range r.query {
+		query[name] = values
+	}
+	for _, auth := range r.auth {
+		if auth.kind == "apiKey" && auth.in == "query" {
+			query.Set(auth.name, "{{"+auth.variable+"}}")
+		}
+	}
+	target := "{{base_url}}" + r.path
+	if len(query) > 0 {
+		// Keep variables readable rather than percent-encoded
+		target += "?" + strings.NewReplacer("%7B%7B", "{{", "%7D%7D", "}}").Replace(query.Encode())
+	}
+	return target
+}
+
+// headerLines returns the "Name: value" lines of the request, with
+// credentials and the content type
+func (r sampleRequest) headerLines() []string {
+	var lines []string
+	for _, header := range r.headers {
+		lines = append(lines, header[0]+": "+header[1])
+	}
+	for _, auth := range r.auth {
+		switch {
+		case auth.kind == "bearer":
+			lines = append(lines, "Authorization: Bearer {{"+auth.variable+"}}")
+		case auth.kind == "basic":
+			lines = append(lines, "Authorization: Basic {{"+auth.variable+"}}")
+		case auth.in == "cookie":
+			lines = append(lines, "Cookie: "+auth.name+"={{"+auth.variable+"}}")
+		case auth.in == "header":
+			lines = append(lines, auth.name+": {{"+auth.variable+"}}")
+		}
+	}
+	if r.contentType != "" {
+		lines = append(lines, "Content-Type: "+r.contentType)
+	}
+	return lines
+}
+
+// ToHTTPFile generates a .http file in the format of the VS Code REST Client
+// (and the JetBrains HTTP client) with one sample request per operation.
+// Parameters and bodies are filled from examples or synthesized samples; the
+// base URL and credentials are variables at the top of the file.
+func (d *Document) ToHTTPFile() []byte {
+	variables, requests := d.sampleRequests()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Sample requests for %s %s\n\n", d.Info.Title, d.Info.Version)
+	for _, v := range variables {
+		fmt.Fprintf(&buf, "@%s = %s\n", v.name, v.value)
+	}
+	for _, r := range requests {
+		fmt.Fprintf(&buf, "\n### %s\n# @name %s\n", r.title, r.name)
+		fmt.Fprintf(&buf, "%s %s\n", r.method, r.target())
+		for _, header := range r.headerLines() {
+			buf.WriteString(header + "\n")
+		}
+		if r.body != "" {
+			buf.WriteString("\n" + r.body + "\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// ToCurlScript generates a shell script with one curl command per operation,
+// filled like ToHTTPFile. The base URL and credentials are read from
+// environment variables, falling back to placeholders.
+func (d *Document) ToCurlScript() []byte {
+	variables, requests := d.sampleRequests()
+	var buf bytes.Buffer
+	buf.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&buf, "# Sample requests for %s %s\n\n", d.Info.Title, d.Info.Version)
+	for _, v := range variables {
+		name := strings.ToUpper(v.name)
+		fmt.Fprintf(&buf, "%s=\"${%s:-%s}\"\n", name, name, shellDoubleQuoted(v.value))
+	}
+	for _, r := range requests {
+		fmt.Fprintf(&buf, "\n# %s\n", r.title)
+		fmt.Fprintf(&buf, "curl -X %s %s", r.method, shellTemplate(r.target()))
+		for _, header := range r.headerLines() {
+			fmt.Fprintf(&buf, " \\\n  -H %s", shellTemplate(header))
+		}
+		if r.body != "" {
+			fmt.Fprintf(&buf, " \\\n  --data '%s'", strings.ReplaceAll(r.body, "'", `'\''`))
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// sampleRequests builds the sample request of every operation, ordered by
+// path and method, along with the variables they use
+func (d *Document) sampleRequests() ([]sampleVariable, []sampleRequest) {
+	baseURL := "http://localhost"
+	if len(d.Servers) > 0 {
+		baseURL = strings.TrimSuffix(d.Servers[0].Expand(nil), "/")
+	}
+	variables := []sampleVariable{{name: "base_url", value: baseURL}}
+	declared := make(map[string]bool)
+
+	var requests []sampleRequest
+	d.walkOperations(func(path, method string, op *Operation) {
+		r := sampleRequest{
+			name:   op.OperationID,
+			title:  op.Summary,
+			tags:   op.Tags,
+			method: method,
+			query:  url.Values{},
+		}
+		if r.name == "" {
+			r.name = patternOperationID(method, path)
+		}
+		if r.title == "" {
+			r.title = method + " " + path
+		}
+
+		for _, p := range d.OperationParameters(path, op) {
+			if !p.Required && p.In != "path" {
+				continue
+			}
+			value := d.sampleParameter(p)
+			switch p.In {
+			case "path":
+				path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(value))
+			case "query":
+				r.query.Set(p.Name, value)
+			case "header":
+				r.headers = append(r.headers, [2]string{p.Name, value})
+			case "cookie":
+				r.headers = append(r.headers, [2]string{"Cookie", p.Name + "=" + value})
+			}
+		}
+		r.path = path
+
+		if requirements := d.EffectiveSecurity(op); len(requirements) > 0 && d.Components != nil {
+			for _, name := range sortedKeys(requirements[0]) {
+				scheme, ok := d.Components.SecuritySchemes[name]
+				if !ok {
+					continue
+				}
+				auth := sampleAuth{kind: "bearer", variable: protoFieldName(name)}
+				placeholder := "<token>"
+				switch {
+				case scheme.Type == "apiKey":
+					auth = sampleAuth{kind: "apiKey", in: scheme.In, name: scheme.Name, variable: auth.variable}
+					placeholder = "<api key>"
+				case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+					auth.kind = "basic"
+					placeholder = "<base64 credentials>"
+				}
+				r.auth = append(r.auth, auth)
+				if !declared[auth.variable] {
+					declared[auth.variable] = true
+					variables = append(variables, sampleVariable{name: auth.variable, value: placeholder})
+				}
+			}
+		}
+
+		contentType, body := d.sampleRequestBody(d.resolveRequestBody(op.RequestBody))
+		if contentType != "" {
+			var indented bytes.Buffer
+			if isJSONMediaType(contentType) && json.Indent(&indented, []byte(body), "", "  ") == nil {
+				body = indented.String()
+			}
+			r.contentType, r.body = contentType, body
+		}
+		requests = append(requests, r)
+	})
+	return variables, requests
+}
+
+// expandTemplate rewrites a string with {{name}} variables, escaping the
+// literal text and replacing each variable
+func expandTemplate(s string, literal, variable func(string) string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "{{")
+		end := strings.Index(s, "}}")
+		if start < 0 || end < start {
+			break
+		}
+		b.WriteString(literal(s[:start]))
+		b.WriteString(variable(s[start+2 : end]))
+		s = s[end+2:]
+	}
+	b.WriteString(literal(s))
+	return b.String()
+}
+
+// shellTemplate double-quotes a string for the shell, turning {{name}}
+// variables into ${NAME} expansions
+func shellTemplate(s string) string {
+	return `"` + expandTemplate(s, shellDoubleQuoted, func(name string) string {
+		return "${" + strings.ToUpper(name) + "}"
+	}) + `"`
+}
+
+// shellDoubleQuoted escapes the characters that are special within double quotes
+func shellDoubleQuoted(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s)
+}
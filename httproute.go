@@ -0,0 +1,220 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExtensionBackendRef is the operation or document extension naming the
+// Kubernetes Service that serves an operation
+const ExtensionBackendRef = "x-kubernetes-backend"
+
+// BackendRef identifies a Kubernetes Service port
+type BackendRef struct {
+	Name      string `json:"name" yaml:"name"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Port      int    `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// WithBackendRef sets the Kubernetes Service serving the operation
+func (o Operation) WithBackendRef(ref BackendRef) Operation {
+	return o.WithExtension(ExtensionBackendRef, ref)
+}
+
+// HTTPRouteOptions configures ToHTTPRoutes
+type HTTPRouteOptions struct {
+	// Name of the HTTPRoute; defaults to the sanitized document title.
+	// Routes that must be split get numeric suffixes.
+	Name      string
+	Namespace string
+	// Gateway is the name of the parent Gateway, optionally "namespace/name"
+	Gateway string
+	// Hostnames defaults to the hosts of the document's servers
+	Hostnames []string
+	// Backend is used for operations without an x-kubernetes-backend extension
+	// when the document doesn't define one either
+	Backend BackendRef
+	// BasePath is prepended to every path; defaults to the path of the first server URL
+	BasePath string
+	// PrefixMatch matches templated paths by their literal prefix instead of a
+	// regular expression, for implementations without regex support
+	PrefixMatch bool
+}
+
+// Gateway API HTTPRoute manifest types, limited to the fields the export sets
+
+type httpRoute struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   httpRouteMetadata `yaml:"metadata"`
+	Spec       httpRouteSpec     `yaml:"spec"`
+}
+
+type httpRouteMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type httpRouteSpec struct {
+	ParentRefs []httpRouteParent `yaml:"parentRefs,omitempty"`
+	Hostnames  []string          `yaml:"hostnames,omitempty"`
+	Rules      []httpRouteRule   `yaml:"rules"`
+}
+
+type httpRouteParent struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type httpRouteRule struct {
+	Matches     []httpRouteMatch `yaml:"matches"`
+	BackendRefs []BackendRef     `yaml:"backendRefs"`
+}
+
+type httpRouteMatch struct {
+	Path   httpRoutePath `yaml:"path"`
+	Method string        `yaml:"method"`
+}
+
+type httpRoutePath struct {
+	Type  string `yaml:"type"`
+	Value string `yaml:"value"`
+}
+
+// The Gateway API caps rules per route and matches per rule
+const (
+	maxHTTPRouteRules   = 16
+	maxHTTPRouteMatches = 64
+)
+
+var templateVariable = regexp.MustCompile(`\{[^}/]+\}`)
+
+// ToHTTPRoutes renders Kubernetes Gateway API HTTPRoute manifests (as a
+// multi-document YAML stream) routing every operation's path and method to its
+// backend. Concrete paths use Exact matches; templated paths use regular
+// expressions, or literal prefixes with PrefixMatch.
+func (d *Document) ToHTTPRoutes(opts HTTPRouteOptions) ([]byte, error) {
+	if opts.Name == "" {
+		opts.Name = strings.Trim(regexp.MustCompile(`[^a-z0-9-]+`).ReplaceAllString(strings.ToLower(d.Info.Title), "-"), "-")
+	}
+	if opts.BasePath == "" && len(d.Servers) > 0 {
+		if u, err := url.Parse(d.Servers[0].Expand(nil)); err == nil {
+			opts.BasePath = strings.TrimSuffix(u.Path, "/")
+		}
+	}
+	if opts.Hostnames == nil {
+		seen := make(map[string]bool)
+		for _, server := range d.Servers {
+			if u, err := url.Parse(server.Expand(nil)); err == nil && u.Hostname() != "" && !seen[u.Hostname()] {
+				seen[u.Hostname()] = true
+				opts.Hostnames = append(opts.Hostnames, u.Hostname())
+			}
+		}
+	}
+
+	var defaultBackend BackendRef
+	if !decodeExtension(d.Extensions[ExtensionBackendRef], &defaultBackend) {
+		defaultBackend = opts.Backend
+	}
+
+	// Group matches by backend, preserving the order backends first appear in
+	var backends []BackendRef
+	matches := make(map[BackendRef][]httpRouteMatch)
+	var err error
+	d.walkOperations(func(path, method string, op *Operation) {
+		var backend BackendRef
+		if !decodeExtension(op.Extensions[ExtensionBackendRef], &backend) {
+			backend = defaultBackend
+		}
+		if backend.Name == "" {
+			err = fmt.Errorf("openapi: no backend for %s %s", method, path)
+			return
+		}
+		if _, ok := matches[backend]; !ok {
+			backends = append(backends, backend)
+		}
+		matches[backend] = append(matches[backend], httpRouteMatch{
+			Path:   httpRouteMatchPath(opts.BasePath+path, opts.PrefixMatch),
+			Method: method,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []httpRouteRule
+	for _, backend := range backends {
+		all := matches[backend]
+		for start := 0; start < len(all); start += maxHTTPRouteMatches {
+			end := min(start+maxHTTPRouteMatches, len(all))
+			rules = append(rules, httpRouteRule{Matches: all[start:end], BackendRefs: []BackendRef{backend}})
+		}
+	}
+
+	var parents []httpRouteParent
+	if opts.Gateway != "" {
+		parent := httpRouteParent{Name: opts.Gateway}
+		if namespace, name, ok := strings.Cut(opts.Gateway, "/"); ok {
+			parent = httpRouteParent{Name: name, Namespace: namespace}
+		}
+		parents = append(parents, parent)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for start := 0; start < len(rules); start += maxHTTPRouteRules {
+		end := min(start+maxHTTPRouteRules, len(rules))
+		name := opts.Name
+		if len(rules) > maxHTTPRouteRules {
+			name = fmt.Sprintf("%s-%d", opts.Name, start/maxHTTPRouteRules+1)
+		}
+		err := enc.Encode(httpRoute{
+			APIVersion: "gateway.networking.k8s.io/v1",
+			Kind:       "HTTPRoute",
+			Metadata:   httpRouteMetadata{Name: name, Namespace: opts.Namespace},
+			Spec: httpRouteSpec{
+				ParentRefs: parents,
+				Hostnames:  opts.Hostnames,
+				Rules:      rules[start:end],
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// httpRouteMatchPath converts a path template into an HTTPRoute path match
+func httpRouteMatchPath(path string, prefix bool) httpRoutePath {
+	if !strings.Contains(path, "{") {
+		return httpRoutePath{Type: "Exact", Value: path}
+	}
+	if prefix {
+		literal := path[:strings.Index(path, "{")]
+		if i := strings.LastIndex(literal, "/"); i > 0 {
+			literal = literal[:i]
+		}
+		return httpRoutePath{Type: "PathPrefix", Value: literal}
+	}
+	return httpRoutePath{Type: "RegularExpression", Value: pathRegexp(path)}
+}
+
+// pathRegexp converts a path template into an anchored regular expression
+// matching each variable with one path segment
+func pathRegexp(path string) string {
+	parts := templateVariable.Split(path, -1)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return "^" + strings.Join(parts, "[^/]+") + "$"
+}
@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExtensionIdempotency is the specification extension overriding the
+// idempotency an operation's method implies
+const ExtensionIdempotency = "x-idempotency"
+
+// Idempotency classifies what repeating a request does
+type Idempotency string
+
+const (
+	// IdempotencySafe requests don't change state, so they can be repeated
+	// and prefetched freely
+	IdempotencySafe Idempotency = "safe"
+	// IdempotencyIdempotent requests change state, but repeating them has the
+	// effect of a single one
+	IdempotencyIdempotent Idempotency = "idempotent"
+	// IdempotencyNonIdempotent requests may take effect again when repeated
+	IdempotencyNonIdempotent Idempotency = "non-idempotent"
+)
+
+// MethodIdempotency returns the idempotency RFC 9110 defines for a method
+func MethodIdempotency(method string) Idempotency {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS", "TRACE":
+		return IdempotencySafe
+	case "PUT", "DELETE":
+		return IdempotencyIdempotent
+	}
+	return IdempotencyNonIdempotent
+}
+
+// WithIdempotency overrides the idempotency the method implies, as for a
+// search sent with POST that is safe
+func (o Operation) WithIdempotency(idempotency Idempotency) Operation {
+	return o.WithExtension(ExtensionIdempotency, idempotency)
+}
+
+// OperationIdempotency is the idempotency of one operation
+type OperationIdempotency struct {
+	// Path is the JSON pointer of the operation
+	Path        string      `json:"path"`
+	Method      string      `json:"method"`
+	OperationID string      `json:"operationId,omitempty"`
+	Idempotency Idempotency `json:"idempotency"`
+	// Overridden tells whether the idempotency comes from the x-idempotency
+	// extension rather than the method
+	Overridden bool `json:"overridden,omitempty"`
+}
+
+// IdempotencyAnalysis classifies every operation as safe, idempotent or
+// non-idempotent, from its method unless the x-idempotency extension
+// overrides it
+func (d *Document) IdempotencyAnalysis() []OperationIdempotency {
+	var analysis []OperationIdempotency
+	d.walkOperations(func(path, method string, op *Operation) {
+		idempotency, overridden := operationIdempotency(method, op)
+		analysis = append(analysis, OperationIdempotency{
+			Path:        operationPointer(path, method),
+			Method:      method,
+			OperationID: op.OperationID,
+			Idempotency: idempotency,
+			Overridden:  overridden,
+		})
+	})
+	return analysis
+}
+
+// operationIdempotency returns the idempotency of an operation and whether
+// the extension overrides the method's
+func operationIdempotency(method string, op *Operation) (Idempotency, bool) {
+	var idempotency Idempotency
+	if decodeExtension(op.Extensions[ExtensionIdempotency], &idempotency) {
+		switch idempotency {
+		case IdempotencySafe, IdempotencyIdempotent, IdempotencyNonIdempotent:
+			return idempotency, true
+		}
+	}
+	return MethodIdempotency(method), false
+}
+
+// IdempotencyProfile checks that operations behave as their method implies,
+// as warnings under these rules:
+//   - idempotency/unknown: an x-idempotency value that isn't a known class
+//   - idempotency/get-request-body: a GET or HEAD operation with a request
+//     body, which servers and proxies may drop
+//   - idempotency/delete-response: a DELETE operation answering 204 with a
+//     body, or 200 without one where 204 is meant
+type IdempotencyProfile struct{}
+
+// Name returns "idempotency"
+func (IdempotencyProfile) Name() string {
+	return "idempotency"
+}
+
+// Validate returns a warning for every operation at odds with its method
+func (IdempotencyProfile) Validate(d *Document) []ValidationError {
+	var errs []ValidationError
+	warn := func(pointer, rule, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Path: pointer, Message: fmt.Sprintf(format, args...), Severity: SeverityWarning, Rule: rule})
+	}
+	d.walkOperations(func(path, method string, op *Operation) {
+		pointer := operationPointer(path, method)
+		if value, ok := op.Extensions[ExtensionIdempotency]; ok {
+			if _, overridden := operationIdempotency(method, op); !overridden {
+				warn(pointer+"/"+ExtensionIdempotency, "idempotency/unknown", "unknown idempotency %v, expected safe, idempotent or non-idempotent", value)
+			}
+		}
+		if (method == "GET" || method == "HEAD") && op.RequestBody != nil {
+			warn(pointer+"/requestBody", "idempotency/get-request-body", "%s requests have no defined body semantics, so servers and proxies may drop it", method)
+		}
+		if method != "DELETE" {
+			return
+		}
+		if response, ok := op.Responses["204"]; ok && len(d.resolveResponse(response).Content) > 0 {
+			warn(pointer+"/responses/204", "idempotency/delete-response", "204 responses have no body")
+		}
+		if response, ok := op.Responses["200"]; ok && len(d.resolveResponse(response).Content) == 0 {
+			warn(pointer+"/responses/200", "idempotency/delete-response", "200 response without a body, use 204 No Content")
+		}
+	})
+	return errs
+}
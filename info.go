@@ -1,11 +1,34 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // Info represents the info section of OpenAPI document
 type Info struct {
-	Title          string   `json:"title"`
-	Description    string   `json:"description,omitempty"`
-	TermsOfService string   `json:"termsOfService,omitempty"`
-	Contact        *Contact `json:"contact,omitempty"`
-	License        *License `json:"license,omitempty"`
-	Version        string   `json:"version"`
+	Title          string                 `json:"title"`
+	Summary        string                 `json:"summary,omitempty"`
+	Description    string                 `json:"description,omitempty"`
+	TermsOfService string                 `json:"termsOfService,omitempty"`
+	Contact        *Contact               `json:"contact,omitempty"`
+	License        *License               `json:"license,omitempty"`
+	Version        string                 `json:"version"`
+	Extensions     map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (i Info) MarshalJSON() ([]byte, error) {
+	type info Info
+	return marshalWithExtensions(info(i), i.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (i *Info) UnmarshalJSON(data []byte) error {
+	type info Info
+	if err := json.Unmarshal(data, (*info)(i)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	i.Extensions = ext
+	return err
 }
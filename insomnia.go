@@ -0,0 +1,186 @@
+package openapi
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Insomnia v4 export types, limited to the fields the export sets
+
+type insomniaExport struct {
+	Type      string             `json:"_type"`
+	Format    int                `json:"__export_format"`
+	Source    string             `json:"__export_source"`
+	Resources []insomniaResource `json:"resources"`
+}
+
+type insomniaResource struct {
+	ID             string            `json:"_id"`
+	Type           string            `json:"_type"`
+	ParentID       string            `json:"parentId"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description,omitempty"`
+	Scope          string            `json:"scope,omitempty"`
+	Data           map[string]string `json:"data,omitempty"`
+	Method         string            `json:"method,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Body           *insomniaBody     `json:"body,omitempty"`
+	Headers        []insomniaHeader  `json:"headers,omitempty"`
+	Authentication map[string]string `json:"authentication,omitempty"`
+}
+
+type insomniaBody struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type insomniaHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+var sampleTemplateVariable = regexp.MustCompile(`\{\{(\w+)\}\}`)
+
+// ToInsomniaCollection generates an Insomnia v4 export with the sample
+// request of every operation, filled like ToHTTPFile. Requests are grouped in
+// a folder per first tag. The base environment holds the base URL and
+// credential placeholders, and every server gets a sub-environment setting
+// the base URL. The first security scheme of a request becomes its
+// authentication; any further ones are sent as headers or query parameters.
+func (d *Document) ToInsomniaCollection() ([]byte, error) {
+	variables, requests := d.sampleRequests()
+	workspace := "wrk_" + protoFieldName(d.Info.Title)
+	resources := []insomniaResource{{
+		ID:          workspace,
+		Type:        "workspace",
+		ParentID:    "",
+		Name:        d.Info.Title,
+		Description: d.Info.Description,
+		Scope:       "collection",
+	}}
+
+	basic := make(map[string]bool)
+	for _, r := range requests {
+		if len(r.auth) > 0 && r.auth[0].kind == "basic" {
+			basic[r.auth[0].variable] = true
+		}
+	}
+	data := make(map[string]string)
+	for _, v := range variables {
+		if basic[v.name] {
+			data[v.name+"_username"] = "<username>"
+			data[v.name+"_password"] = "<password>"
+		}
+		data[v.name] = v.value
+	}
+	resources = append(resources, insomniaResource{
+		ID:       "env_base",
+		Type:     "environment",
+		ParentID: workspace,
+		Name:     "Base Environment",
+		Data:     data,
+	})
+	for i, server := range d.Servers {
+		name := server.Description
+		if name == "" {
+			name = server.URL
+		}
+		resources = append(resources, insomniaResource{
+			ID:       "env_server_" + strconv.Itoa(i+1),
+			Type:     "environment",
+			ParentID: "env_base",
+			Name:     name,
+			Data:     map[string]string{"base_url": strings.TrimSuffix(server.Expand(nil), "/")},
+		})
+	}
+
+	folders := make(map[string]bool)
+	for _, r := range requests {
+		parent := workspace
+		if len(r.tags) > 0 {
+			parent = "fld_" + protoFieldName(r.tags[0])
+			if !folders[parent] {
+				folders[parent] = true
+				resources = append(resources, insomniaResource{
+					ID:       parent,
+					Type:     "request_group",
+					ParentID: workspace,
+					Name:     r.tags[0],
+				})
+			}
+		}
+
+		rest := r
+		var authentication map[string]string
+		if len(r.auth) > 0 {
+			authentication = insomniaAuthentication(r.auth[0])
+			rest.auth = r.auth[1:]
+		}
+		rest.contentType = ""
+		var headers []insomniaHeader
+		for _, line := range rest.headerLines() {
+			name, value, _ := strings.Cut(line, ": ")
+			headers = append(headers, insomniaHeader{Name: name, Value: insomniaTemplate(value)})
+		}
+		var body *insomniaBody
+		if r.contentType != "" {
+			body = &insomniaBody{MimeType: r.contentType, Text: r.body}
+			headers = append(headers, insomniaHeader{Name: "Content-Type", Value: r.contentType})
+		}
+		resources = append(resources, insomniaResource{
+			ID:             "req_" + protoFieldName(r.name),
+			Type:           "request",
+			ParentID:       parent,
+			Name:           r.title,
+			Method:         r.method,
+			URL:            insomniaTemplate(rest.target()),
+			Body:           body,
+			Headers:        headers,
+			Authentication: authentication,
+		})
+	}
+
+	return json.MarshalIndent(insomniaExport{
+		Type:      "export",
+		Format:    4,
+		Source:    "github.com/nyxstack/openapi",
+		Resources: resources,
+	}, "", "  ")
+}
+
+// insomniaAuthentication maps a security scheme to an Insomnia authentication
+func insomniaAuthentication(auth sampleAuth) map[string]string {
+	switch auth.kind {
+	case "basic":
+		return map[string]string{
+			"type":     "basic",
+			"username": "{{ _." + auth.variable + "_username }}",
+			"password": "{{ _." + auth.variable + "_password }}",
+		}
+	case "apiKey":
+		addTo := "header"
+		switch auth.in {
+		case "query":
+			addTo = "queryParams"
+		case "cookie":
+			addTo = "cookie"
+		}
+		return map[string]string{
+			"type":  "apikey",
+			"key":   auth.name,
+			"value": "{{ _." + auth.variable + " }}",
+			"addTo": addTo,
+		}
+	}
+	return map[string]string{
+		"type":  "bearer",
+		"token": "{{ _." + auth.variable + " }}",
+	}
+}
+
+// insomniaTemplate translates {{name}} variables to Insomnia's {{ _.name }}
+func insomniaTemplate(s string) string {
+	return sampleTemplateVariable.ReplaceAllString(s, "{{ _.$1 }}")
+}
@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// WithConst restricts the schema to a single value
+func (s Schema) WithConst(value interface{}) Schema {
+	s.Const = value
+	return s
+}
+
+// WithExamples sets the examples of the schema, the 3.1 successor of example
+func (s Schema) WithExamples(examples ...interface{}) Schema {
+	s.Examples = examples
+	return s
+}
+
+// WithComment sets a comment for schema maintainers; it isn't meant for API consumers
+func (s Schema) WithComment(comment string) Schema {
+	s.Comment = comment
+	return s
+}
+
+// WithDef adds a schema definition local to the schema, referenced as "#/$defs/name"
+// from within it
+func (s Schema) WithDef(name string, schema *Schema) Schema {
+	s.Defs = cloneSchemaMap(s.Defs)
+	s.Defs[name] = schema
+	return s
+}
+
+// WithPrefixItems sets the schemas of the leading array items, one per
+// position; items then applies to the items after them
+func (s Schema) WithPrefixItems(schemas ...*Schema) Schema {
+	s.PrefixItems = schemas
+	return s
+}
+
+// WithContains requires array items to match a schema; by default at least
+// one item must match
+func (s Schema) WithContains(schema *Schema) Schema {
+	s.Contains = schema
+	return s
+}
+
+// WithMinContains sets the minimum number of items matching contains
+func (s Schema) WithMinContains(min int) Schema {
+	s.MinContains = &min
+	return s
+}
+
+// WithMaxContains sets the maximum number of items matching contains
+func (s Schema) WithMaxContains(max int) Schema {
+	s.MaxContains = &max
+	return s
+}
+
+// WithPatternProperty sets the schema of the properties whose names match a
+// regular expression
+func (s Schema) WithPatternProperty(pattern string, schema *Schema) Schema {
+	s.PatternProperties = cloneSchemaMap(s.PatternProperties)
+	s.PatternProperties[pattern] = schema
+	return s
+}
+
+// WithPropertyNames sets the schema every property name must match
+func (s Schema) WithPropertyNames(schema *Schema) Schema {
+	s.PropertyNames = schema
+	return s
+}
+
+// WithUnevaluatedProperties allows or forbids the properties no other keyword evaluated
+func (s Schema) WithUnevaluatedProperties(allowed bool) Schema {
+	s.UnevaluatedProperties = &AdditionalProperties{Bool: &allowed}
+	return s
+}
+
+// WithUnevaluatedPropertiesSchema sets the schema of the properties no other
+// keyword evaluated
+func (s Schema) WithUnevaluatedPropertiesSchema(schema *Schema) Schema {
+	s.UnevaluatedProperties = &AdditionalProperties{Schema: schema}
+	return s
+}
+
+// WithIf sets the condition selecting between the then and else schemas
+func (s Schema) WithIf(schema *Schema) Schema {
+	s.If = schema
+	return s
+}
+
+// WithThen sets the schema applied when the value matches if
+func (s Schema) WithThen(schema *Schema) Schema {
+	s.Then = schema
+	return s
+}
+
+// WithElse sets the schema applied when the value doesn't match if
+func (s Schema) WithElse(schema *Schema) Schema {
+	s.Else = schema
+	return s
+}
+
+// WithDependentSchema sets the schema the object must also match when a property is present
+func (s Schema) WithDependentSchema(property string, schema *Schema) Schema {
+	s.DependentSchemas = cloneSchemaMap(s.DependentSchemas)
+	s.DependentSchemas[property] = schema
+	return s
+}
+
+// cloneSchemaMap copies a schema map so builders don't modify the map of the
+// schema they were called on
+func cloneSchemaMap(m map[string]*Schema) map[string]*Schema {
+	clone := make(map[string]*Schema, len(m)+1)
+	for name, schema := range m {
+		clone[name] = schema
+	}
+	return clone
+}
+
+// validateApplicators checks the value against the 2020-12 keywords that
+// apply regardless of the value's type
+func (v *valueValidator) validateApplicators(pointer string, s *Schema, value interface{}, depth int) {
+	if s.Const != nil && !containsValue([]interface{}{s.Const}, value) {
+		v.errorf(pointer, "value must be %v", s.Const)
+	}
+	if s.If != nil {
+		if v.matches(pointer, s.If, value, depth+1) {
+			v.validate(pointer, s.Then, value, depth+1)
+		} else {
+			v.validate(pointer, s.Else, value, depth+1)
+		}
+	}
+}
+
+// validateContains checks the prefixItems and contains keywords of an array
+func (v *valueValidator) validateContains(pointer string, s *Schema, items []interface{}, depth int) {
+	for i, prefix := range s.PrefixItems {
+		if i < len(items) {
+			v.validate(pointer+"/"+strconv.Itoa(i), prefix, items[i], depth+1)
+		}
+	}
+	if s.Contains == nil {
+		return
+	}
+	count := 0
+	for i, item := range items {
+		if v.matches(pointer+"/"+strconv.Itoa(i), s.Contains, item, depth+1) {
+			count++
+		}
+	}
+	min := 1
+	if s.MinContains != nil {
+		min = *s.MinContains
+	}
+	if count < min {
+		v.errorf(pointer, "array has %d items matching contains, expected at least %d", count, min)
+	}
+	if s.MaxContains != nil && count > *s.MaxContains {
+		v.errorf(pointer, "array has %d items matching contains, expected at most %d", count, *s.MaxContains)
+	}
+}
+
+// validatePatternProperties checks the propertyNames, patternProperties and
+// dependentSchemas keywords of an object. It returns the names of the
+// properties matching a pattern.
+func (v *valueValidator) validatePatternProperties(pointer string, s *Schema, obj map[string]interface{}, depth int) map[string]bool {
+	names := sortedKeys(obj)
+	if s.PropertyNames != nil {
+		for _, name := range names {
+			if !v.matches(pointer, s.PropertyNames, name, depth+1) {
+				v.errorf(pointer, "property name %q does not match propertyNames", name)
+			}
+		}
+	}
+
+	matched := make(map[string]bool)
+	for _, pattern := range sortedKeys(s.PatternProperties) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			if re.MatchString(name) {
+				matched[name] = true
+				v.validate(pointer+"/"+escapePointer(name), s.PatternProperties[pattern], obj[name], depth+1)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(s.DependentSchemas) {
+		if _, ok := obj[name]; ok {
+			v.validate(pointer, s.DependentSchemas[name], obj, depth+1)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// K6Options configures ToK6Script
+type K6Options struct {
+	// Operations lists the operationIds to cover; defaults to every GET and
+	// HEAD operation so that a baseline test never changes data
+	Operations []string
+	// Weights sets the relative frequency of operations by operationId; the
+	// default weight is 1
+	Weights map[string]int
+	// VUs is the number of virtual users; defaults to 10
+	VUs int
+	// Duration is the test duration in k6 syntax; defaults to "30s"
+	Duration string
+}
+
+// ToK6Script generates a k6 load test script sending the sample requests of
+// the selected operations, filled like ToHTTPFile, in random order according
+// to their weights and checking for successful responses. The base URL and
+// credentials are read from environment variables, falling back to
+// placeholders.
+func (d *Document) ToK6Script(opts K6Options) ([]byte, error) {
+	if opts.VUs == 0 {
+		opts.VUs = 10
+	}
+	if opts.Duration == "" {
+		opts.Duration = "30s"
+	}
+	variables, requests := d.sampleRequests()
+	var selected []sampleRequest
+	for _, r := range requests {
+		if opts.Operations == nil && (r.method == "GET" || r.method == "HEAD") || slices.Contains(opts.Operations, r.name) {
+			selected = append(selected, r)
+		}
+	}
+	for _, id := range opts.Operations {
+		if !slices.ContainsFunc(selected, func(r sampleRequest) bool { return r.name == id }) {
+			return nil, fmt.Errorf("openapi: no operation %q", id)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("openapi: no operations to load test")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Load test for %s %s\n", d.Info.Title, d.Info.Version)
+	buf.WriteString("import http from 'k6/http';\nimport { check } from 'k6';\n\n")
+	fmt.Fprintf(&buf, "export const options = { vus: %d, duration: %s };\n\n", opts.VUs, jsString(opts.Duration))
+	for _, v := range variables {
+		name := strings.ToUpper(v.name)
+		fmt.Fprintf(&buf, "const %s = __ENV.%s || %s;\n", name, name, jsString(v.value))
+	}
+
+	buf.WriteString("\nconst requests = [\n")
+	for _, r := range selected {
+		weight := 1
+		if w, ok := opts.Weights[r.name]; ok {
+			weight = w
+		}
+		body := "null"
+		if r.body != "" {
+			body = jsString(r.body)
+		}
+		var headers []string
+		for _, line := range r.headerLines() {
+			name, value, _ := strings.Cut(line, ": ")
+			headers = append(headers, jsString(name)+": "+jsTemplate(value))
+		}
+		fmt.Fprintf(&buf, "  {\n    name: %s,\n    weight: %d,\n    method: %s,\n    url: %s,\n    body: %s,\n    headers: { %s },\n  },\n",
+			jsString(r.name), weight, jsString(r.method), jsTemplate(r.target()), body, strings.Join(headers, ", "))
+	}
+	buf.WriteString("];\n")
+	buf.WriteString("const total = requests.reduce((sum, r) => sum + r.weight, 0);\n\n")
+	buf.WriteString("export default function () {\n")
+	buf.WriteString("  let pick = Math.random() * total;\n")
+	buf.WriteString("  const r = requests.find((r) => (pick -= r.weight) < 0) || requests[requests.length - 1];\n")
+	buf.WriteString("  const res = http.request(r.method, r.url, r.body, { headers: r.headers, tags: { name: r.name } });\n")
+	buf.WriteString("  check(res, { 'status is 2xx': (res) => res.status >= 200 && res.status < 300 });\n")
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// jsString quotes a string as a JavaScript string literal
+func jsString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// jsTemplate quotes a string as a JavaScript template literal, turning
+// {{name}} variables into ${NAME} substitutions
+func jsTemplate(s string) string {
+	escape := strings.NewReplacer(`\`, `\\`, "`", "\\`", "${", "\\${")
+	return "`" + expandTemplate(s, escape.Replace, func(name string) string {
+		return "${" + strings.ToUpper(name) + "}"
+	}) + "`"
+}
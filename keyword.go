@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Keyword describes a custom schema keyword, such as an organization-specific
+// "x-unit". Registered keywords round-trip through schema marshaling even when
+// their name doesn't start with "x-", are checked by Document.Validate and
+// take part in ValidateValue.
+type Keyword struct {
+	// Name is the keyword as it appears in schemas
+	Name string
+	// Check validates the keyword's value where it appears in a document
+	Check func(value interface{}) error
+	// Validate validates an instance against the keyword's value and the
+	// schema carrying it. It is called for null instances too.
+	Validate func(value, instance interface{}, s *Schema) error
+}
+
+var (
+	keywordsMu sync.RWMutex
+	keywords   = make(map[string]Keyword)
+)
+
+// RegisterKeyword registers a custom schema keyword, replacing any keyword
+// registered under the same name. It panics when the name is empty.
+func RegisterKeyword(keyword Keyword) {
+	if keyword.Name == "" {
+		panic("openapi: registering a keyword without a name")
+	}
+	keywordsMu.Lock()
+	defer keywordsMu.Unlock()
+	keywords[keyword.Name] = keyword
+}
+
+// UnregisterKeyword removes a registered keyword
+func UnregisterKeyword(name string) {
+	keywordsMu.Lock()
+	defer keywordsMu.Unlock()
+	delete(keywords, name)
+}
+
+// LookupKeyword returns the keyword registered under a name
+func LookupKeyword(name string) (Keyword, bool) {
+	keywordsMu.RLock()
+	defer keywordsMu.RUnlock()
+	keyword, ok := keywords[name]
+	return keyword, ok
+}
+
+// isExtensionKey reports whether a key is carried in an Extensions map:
+// specification extensions and registered keywords
+func isExtensionKey(key string) bool {
+	if strings.HasPrefix(key, "x-") {
+		return true
+	}
+	_, ok := LookupKeyword(key)
+	return ok
+}
+
+// WithKeyword sets the value of a custom keyword
+func (s Schema) WithKeyword(name string, value interface{}) Schema {
+	return s.WithExtension(name, value)
+}
+
+// Keyword returns the value of a custom keyword
+func (s *Schema) Keyword(name string) (interface{}, bool) {
+	value, ok := s.Extensions[name]
+	return value, ok
+}
+
+// unmarshalKeywords adds the registered keywords without the "x-" prefix
+// found in a JSON schema to its extensions
+func unmarshalKeywords(data []byte, ext map[string]interface{}) (map[string]interface{}, error) {
+	keywordsMu.RLock()
+	defer keywordsMu.RUnlock()
+	if len(keywords) == 0 {
+		return ext, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ext, err
+	}
+	for key, raw := range fields {
+		if _, ok := keywords[key]; !ok || strings.HasPrefix(key, "x-") {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return ext, err
+		}
+		if ext == nil {
+			ext = make(map[string]interface{})
+		}
+		ext[key] = value
+	}
+	return ext, nil
+}
+
+// validateKeywords checks the values of registered keywords in the document's schemas
+func (v *validator) validateKeywords() {
+	v.doc.walkSchemas(func(pointer string, s *Schema) {
+		for _, name := range sortedKeys(s.Extensions) {
+			keyword, ok := LookupKeyword(name)
+			if !ok || keyword.Check == nil {
+				continue
+			}
+			if err := keyword.Check(s.Extensions[name]); err != nil {
+				v.errorf(pointer+"/"+escapePointer(name), "invalid %s: %v", name, err)
+			}
+		}
+	})
+}
+
+// validateKeywords validates a value against the registered keywords of a schema
+func (v *valueValidator) validateKeywords(pointer string, s *Schema, value interface{}) {
+	for _, name := range sortedKeys(s.Extensions) {
+		keyword, ok := LookupKeyword(name)
+		if !ok || keyword.Validate == nil {
+			continue
+		}
+		if err := keyword.Validate(s.Extensions[name], value, s); err != nil {
+			v.errs = append(v.errs, ValidationError{Path: pointer, Message: fmt.Sprintf("%s: %v", name, err), Severity: SeverityError, Rule: name})
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Package kinopenapi converts documents between this module and
+// github.com/getkin/kin-openapi, so projects can build documents with the
+// openapi builders while keeping kin-openapi based validation and routing
+package kinopenapi
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nyxstack/openapi"
+)
+
+// ToKin converts a document to a kin-openapi document. References are
+// resolved by the kin-openapi loader, so the result is ready for
+// openapi3filter validation and routers.
+func ToKin(doc *openapi.Document) (*openapi3.T, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	loader := openapi3.NewLoader()
+	loader.Context = context.Background()
+	return loader.LoadFromData(data)
+}
+
+// FromKin converts a kin-openapi document to a document, keeping
+// specification extensions
+func FromKin(t *openapi3.T) (*openapi.Document, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	doc := &openapi.Document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// SchemaToKin converts a schema to a kin-openapi schema
+func SchemaToKin(schema *openapi.Schema) (*openapi3.Schema, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	kin := openapi3.NewSchema()
+	if err := json.Unmarshal(data, kin); err != nil {
+		return nil, err
+	}
+	return kin, nil
+}
+
+// SchemaFromKin converts a kin-openapi schema to a schema
+func SchemaFromKin(schema *openapi3.Schema) (*openapi.Schema, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	s := &openapi.Schema{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,37 @@
+package kinopenapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nyxstack/openapi"
+)
+
+func TestRoundTrip(t *testing.T) {
+	doc := openapi.NewDocument("Test API", "1.0.0").WithExtension("x-team", "pets")
+	doc.AddSchema("Pet", openapi.Schema{Type: openapi.Types{"object"}, Properties: map[string]*openapi.Schema{"name": {Type: openapi.Types{"string"}}}})
+	doc.AddOperation("/pets", "GET", openapi.NewOperation("listPets", "List pets", "").
+		WithJSONResponse("200", "OK", &openapi.Schema{Ref: "#/components/schemas/Pet"}))
+
+	kin, err := ToKin(doc)
+	if err != nil {
+		t.Fatalf("Error converting to kin-openapi: %v", err)
+	}
+	if err := kin.Validate(context.Background()); err != nil {
+		t.Errorf("Expected kin-openapi document to validate, got %v", err)
+	}
+	if op := kin.Paths.Find("/pets").Get; op == nil || op.OperationID != "listPets" {
+		t.Fatalf("Expected listPets operation, got %+v", op)
+	}
+
+	back, err := FromKin(kin)
+	if err != nil {
+		t.Fatalf("Error converting from kin-openapi: %v", err)
+	}
+	if back.Extensions["x-team"] != "pets" {
+		t.Errorf("Expected extension to survive the round-trip, got %v", back.Extensions)
+	}
+	if _, _, op := back.FindOperation("listPets"); op == nil {
+		t.Error("Expected listPets operation after round-trip")
+	}
+}
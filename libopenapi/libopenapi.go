@@ -0,0 +1,69 @@
+// Package libopenapi bridges documents between this module and
+// github.com/pb33f/libopenapi, so the libopenapi changelog, linting and
+// indexing tools can be used on documents built with the openapi builders.
+// Documents cross the bridge as canonical JSON, which keeps specification
+// extensions intact in both directions.
+package libopenapi
+
+import (
+	"encoding/json"
+
+	"github.com/nyxstack/openapi"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/what-changed/model"
+)
+
+// ToLibOpenAPI converts a document to a libopenapi document
+func ToLibOpenAPI(doc *openapi.Document) (libopenapi.Document, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return libopenapi.NewDocument(data)
+}
+
+// ToV3Model converts a document to a libopenapi high-level v3 model
+func ToV3Model(doc *openapi.Document) (*libopenapi.DocumentModel[v3.Document], error) {
+	lib, err := ToLibOpenAPI(doc)
+	if err != nil {
+		return nil, err
+	}
+	return lib.BuildV3Model()
+}
+
+// FromLibOpenAPI converts a libopenapi document to a document
+func FromLibOpenAPI(lib libopenapi.Document) (*openapi.Document, error) {
+	m, err := lib.BuildV3Model()
+	if err != nil {
+		return nil, err
+	}
+	return FromV3Model(&m.Model)
+}
+
+// FromV3Model converts a libopenapi high-level v3 model to a document
+func FromV3Model(m *v3.Document) (*openapi.Document, error) {
+	data, err := m.RenderJSON("")
+	if err != nil {
+		return nil, err
+	}
+	doc := &openapi.Document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// CompareDocuments reports the changes between two documents with the
+// libopenapi what-changed engine. The result is nil when nothing changed.
+func CompareDocuments(oldDoc, newDoc *openapi.Document) (*model.DocumentChanges, error) {
+	original, err := ToLibOpenAPI(oldDoc)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := ToLibOpenAPI(newDoc)
+	if err != nil {
+		return nil, err
+	}
+	return libopenapi.CompareDocuments(original, updated)
+}
@@ -0,0 +1,49 @@
+package libopenapi
+
+import (
+	"testing"
+
+	"github.com/nyxstack/openapi"
+)
+
+func testDocument() *openapi.Document {
+	doc := openapi.NewDocument("Test API", "1.0.0").WithExtension("x-team", "pets")
+	doc.AddOperation("/pets", "GET", openapi.NewOperation("listPets", "List pets", "").
+		WithJSONResponse("200", "OK", openapi.StringSchema("")))
+	return doc
+}
+
+func TestRoundTrip(t *testing.T) {
+	m, err := ToV3Model(testDocument())
+	if err != nil {
+		t.Fatalf("Error converting to libopenapi: %v", err)
+	}
+	if m.Model.Info.Title != "Test API" {
+		t.Errorf("Expected title 'Test API', got '%s'", m.Model.Info.Title)
+	}
+
+	back, err := FromV3Model(&m.Model)
+	if err != nil {
+		t.Fatalf("Error converting from libopenapi: %v", err)
+	}
+	if back.Extensions["x-team"] != "pets" {
+		t.Errorf("Expected extension to survive the round-trip, got %v", back.Extensions)
+	}
+	if _, _, op := back.FindOperation("listPets"); op == nil {
+		t.Error("Expected listPets operation after round-trip")
+	}
+}
+
+func TestCompareDocuments(t *testing.T) {
+	newDoc := testDocument()
+	newDoc.AddOperation("/pets", "POST", openapi.NewOperation("createPet", "Create pet", "").
+		WithJSONResponse("201", "Created", openapi.StringSchema("")))
+
+	changes, err := CompareDocuments(testDocument(), newDoc)
+	if err != nil {
+		t.Fatalf("Error comparing documents: %v", err)
+	}
+	if changes == nil || changes.TotalChanges() == 0 {
+		t.Error("Expected changes to be reported")
+	}
+}
@@ -0,0 +1,252 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Library is a document maintained as a shared component library, such as the
+// company-wide error and pagination schemas of a platform team. Service
+// documents import its components on demand under namespaced names, so
+// "Error" in the "common" library becomes "CommonError".
+type Library struct {
+	Document  *Document
+	Namespace string
+	// Location is the URL or path the library document is published at,
+	// which references to its components point to
+	Location string
+}
+
+// NewLibrary creates a library of the components of doc
+func NewLibrary(namespace string, doc *Document) *Library {
+	return &Library{Document: doc, Namespace: namespace}
+}
+
+// NewLibraryDocument creates a document that only carries components, for
+// maintaining a shared component library. It has no paths, which OpenAPI 3.1
+// allows when components are present.
+func NewLibraryDocument(title, version string) *Document {
+	d := &Document{
+		OpenAPI: string(V31),
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+	}
+	d.AddComponents()
+	return d
+}
+
+// IsLibrary reports whether the document is a component library: it has
+// components but no paths or webhooks
+func (d *Document) IsLibrary() bool {
+	return d.Components != nil && len(d.Paths) == 0 && len(d.Webhooks) == 0
+}
+
+// ReferenceSchema returns a reference to a library schema at the library's
+// Location, leaving the schema in the library. Bundle folds such references
+// into a self-contained document when it is published.
+func (l *Library) ReferenceSchema(name string) (*Schema, error) {
+	ref, err := l.reference("schemas/" + escapePointer(name))
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{Ref: ref}, nil
+}
+
+// ReferenceResponse returns a reference to a library response at the
+// library's Location, like ReferenceSchema
+func (l *Library) ReferenceResponse(name string) (Response, error) {
+	ref, err := l.reference("responses/" + escapePointer(name))
+	return Response{Ref: NewReference(ref)}, err
+}
+
+// ReferenceParameter returns a reference to a library parameter at the
+// library's Location, like ReferenceSchema
+func (l *Library) ReferenceParameter(name string) (Parameter, error) {
+	ref, err := l.reference("parameters/" + escapePointer(name))
+	return Parameter{Ref: NewReference(ref)}, err
+}
+
+// reference returns the external reference to the library component at a
+// pointer relative to "#/components/"
+func (l *Library) reference(key string) (string, error) {
+	if l.Location == "" {
+		return "", fmt.Errorf("openapi: library %s has no location to reference", l.Namespace)
+	}
+	if _, ok := l.Document.component(key); !ok {
+		return "", fmt.Errorf("openapi: library %s has no component %s", l.Namespace, key)
+	}
+	return l.Location + "#/components/" + key, nil
+}
+
+// Name returns the name a library component gets once imported
+func (l *Library) Name(name string) string {
+	return prefixedName(l.Namespace, name)
+}
+
+// ImportSchema copies a library schema into the document, along with the
+// components it references, and returns a reference to the copy
+func (d *Document) ImportSchema(lib *Library, name string) (*Schema, error) {
+	ref, err := d.importComponent(lib, "schemas/"+escapePointer(name))
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{Ref: ref}, nil
+}
+
+// ImportResponse copies a library response into the document, along with the
+// components it references, and returns a reference to the copy
+func (d *Document) ImportResponse(lib *Library, name string) (Response, error) {
+	ref, err := d.importComponent(lib, "responses/"+escapePointer(name))
+	return Response{Ref: NewReference(ref)}, err
+}
+
+// ImportParameter copies a library parameter into the document, along with
+// the components it references, and returns a reference to the copy
+func (d *Document) ImportParameter(lib *Library, name string) (Parameter, error) {
+	ref, err := d.importComponent(lib, "parameters/"+escapePointer(name))
+	return Parameter{Ref: NewReference(ref)}, err
+}
+
+// importComponent copies the library component at a pointer relative to
+// "#/components/" and the components it references transitively, renaming
+// them and rewriting their references. Components imported before are left
+// as they are; a different component already using an imported name is an
+// error. It returns the reference to the imported component.
+func (d *Document) importComponent(lib *Library, key string) (string, error) {
+	rename := func(key string) string {
+		kind, name, _ := strings.Cut(key, "/")
+		return kind + "/" + escapePointer(lib.Name(unescapePointer(name)))
+	}
+
+	copies := make(map[string][]byte)
+	queue := []string{key}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, done := copies[current]; done {
+			continue
+		}
+		component, ok := lib.Document.component(current)
+		if !ok {
+			return "", fmt.Errorf("openapi: library %s has no component %s", lib.Namespace, current)
+		}
+		data, err := json.Marshal(component)
+		if err != nil {
+			return "", err
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return "", err
+		}
+		value = mapRefs(value, func(ref string) string {
+			target, ok := strings.CutPrefix(ref, "#/components/")
+			if !ok {
+				return ref
+			}
+			queue = append(queue, target)
+			return "#/components/" + rename(target)
+		})
+		if copies[current], err = json.Marshal(value); err != nil {
+			return "", err
+		}
+	}
+
+	for current, data := range copies {
+		if existing, ok := d.component(rename(current)); ok {
+			existingData, err := json.Marshal(existing)
+			if err != nil {
+				return "", err
+			}
+			if !bytes.Equal(canonicalJSON(existingData), canonicalJSON(data)) {
+				return "", fmt.Errorf("openapi: component %s already exists and differs from %s in library %s", rename(current), current, lib.Namespace)
+			}
+		}
+	}
+	for current, data := range copies {
+		if err := d.setComponent(rename(current), data); err != nil {
+			return "", err
+		}
+	}
+	return "#/components/" + rename(key), nil
+}
+
+// setComponent decodes a component into the document at a pointer relative
+// to "#/components/"
+func (d *Document) setComponent(key string, data []byte) error {
+	kind, name, _ := strings.Cut(key, "/")
+	name = unescapePointer(name)
+	c := d.AddComponents()
+	var err error
+	switch kind {
+	case "schemas":
+		err = decodeInto(data, &c.Schemas, name)
+	case "responses":
+		err = decodeInto(data, &c.Responses, name)
+	case "parameters":
+		err = decodeInto(data, &c.Parameters, name)
+	case "examples":
+		err = decodeInto(data, &c.Examples, name)
+	case "requestBodies":
+		err = decodeInto(data, &c.RequestBodies, name)
+	case "headers":
+		err = decodeInto(data, &c.Headers, name)
+	case "securitySchemes":
+		err = decodeInto(data, &c.SecuritySchemes, name)
+	case "links":
+		err = decodeInto(data, &c.Links, name)
+	case "callbacks":
+		err = decodeInto(data, &c.Callbacks, name)
+	default:
+		err = fmt.Errorf("openapi: unknown component type %q", kind)
+	}
+	return err
+}
+
+// decodeInto decodes JSON into a new entry of a component map, creating the map if needed
+func decodeInto[V any](data []byte, m *map[string]V, name string) error {
+	var value V
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if *m == nil {
+		*m = make(map[string]V)
+	}
+	(*m)[name] = value
+	return nil
+}
+
+// mapRefs replaces every "$ref" value in a decoded JSON value
+func mapRefs(value interface{}, fn func(ref string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				v[key] = fn(ref)
+				continue
+			}
+			v[key] = mapRefs(child, fn)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = mapRefs(child, fn)
+		}
+	}
+	return value
+}
+
+// canonicalJSON re-encodes JSON with sorted keys and no insignificant whitespace
+func canonicalJSON(data []byte) []byte {
+	var value interface{}
+	if json.Unmarshal(data, &value) != nil {
+		return data
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return data
+	}
+	return canonical
+}
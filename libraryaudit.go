@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// LibraryProfile checks documents maintained as component libraries, see
+// NewLibraryDocument. Libraries must have components ("library/components")
+// and must not describe operations ("library/operations"), and their internal
+// references must resolve ("library/unresolved-ref"). References to other
+// documents are flagged since importers must be able to load them
+// ("library/external-ref"), as are top-level security requirements, which
+// have no operations to apply to ("library/security").
+type LibraryProfile struct{}
+
+// Name returns "library"
+func (LibraryProfile) Name() string {
+	return "library"
+}
+
+// Validate reports the parts of the document a component library shouldn't have
+func (LibraryProfile) Validate(d *Document) []ValidationError {
+	var errs []ValidationError
+	for _, path := range sortedKeys(d.Paths) {
+		errs = append(errs, ValidationError{
+			Path:     "/paths/" + escapePointer(path),
+			Message:  fmt.Sprintf("component library must not describe path %s", path),
+			Severity: SeverityError,
+			Rule:     "library/operations",
+		})
+	}
+	for _, name := range sortedKeys(d.Webhooks) {
+		errs = append(errs, ValidationError{
+			Path:     "/webhooks/" + escapePointer(name),
+			Message:  fmt.Sprintf("component library must not describe webhook %s", name),
+			Severity: SeverityError,
+			Rule:     "library/operations",
+		})
+	}
+	if len(d.Security) > 0 {
+		errs = append(errs, ValidationError{
+			Path:     "/security",
+			Message:  "component library has no operations for security requirements to apply to",
+			Severity: SeverityWarning,
+			Rule:     "library/security",
+		})
+	}
+	if d.Components == nil {
+		errs = append(errs, ValidationError{
+			Path:     "/components",
+			Message:  "component library must have components",
+			Severity: SeverityError,
+			Rule:     "library/components",
+		})
+		return errs
+	}
+
+	data, err := json.Marshal(d.Components)
+	if err != nil {
+		return errs
+	}
+	var tree interface{}
+	if json.Unmarshal(data, &tree) != nil {
+		return errs
+	}
+	walkRefPointers("/components", tree, func(pointer, ref string) {
+		switch {
+		case strings.HasPrefix(ref, "#/components/"):
+			kind, rest, _ := strings.Cut(strings.TrimPrefix(ref, "#/components/"), "/")
+			name, _, _ := strings.Cut(rest, "/")
+			if _, ok := d.component(kind + "/" + name); !ok {
+				errs = append(errs, ValidationError{
+					Path:     pointer,
+					Message:  fmt.Sprintf("reference %s does not resolve within the library", ref),
+					Severity: SeverityError,
+					Rule:     "library/unresolved-ref",
+				})
+			}
+		case !strings.HasPrefix(ref, "#"):
+			errs = append(errs, ValidationError{
+				Path:     pointer,
+				Message:  fmt.Sprintf("reference %s points outside the library", ref),
+				Severity: SeverityWarning,
+				Rule:     "library/external-ref",
+			})
+		}
+	})
+	return errs
+}
+
+// walkRefPointers calls fn with the pointer and value of every "$ref" in a
+// decoded JSON value, in sorted order
+func walkRefPointers(pointer string, value interface{}, fn func(pointer, ref string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			if ref, ok := v[key].(string); ok && key == "$ref" {
+				fn(pointer+"/$ref", ref)
+				continue
+			}
+			walkRefPointers(pointer+"/"+escapePointer(key), v[key], fn)
+		}
+	case []interface{}:
+		for i, child := range v {
+			walkRefPointers(fmt.Sprintf("%s/%d", pointer, i), child, fn)
+		}
+	}
+}
@@ -1,7 +1,30 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // License information for the API
 type License struct {
-	Name string `json:"name"`
-	URL  string `json:"url,omitempty"`
+	Name       string                 `json:"name"`
+	Identifier string                 `json:"identifier,omitempty"`
+	URL        string                 `json:"url,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (l License) MarshalJSON() ([]byte, error) {
+	type license License
+	return marshalWithExtensions(license(l), l.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (l *License) UnmarshalJSON(data []byte) error {
+	type license License
+	if err := json.Unmarshal(data, (*license)(l)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	l.Extensions = ext
+	return err
 }
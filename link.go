@@ -1,13 +1,46 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // Link represents a link in OpenAPI
 type Link struct {
+	Ref          *Reference             `json:"-"`
 	OperationRef string                 `json:"operationRef,omitempty"`
 	OperationID  string                 `json:"operationId,omitempty"`
 	Parameters   map[string]interface{} `json:"parameters,omitempty"`
 	RequestBody  interface{}            `json:"requestBody,omitempty"`
 	Description  string                 `json:"description,omitempty"`
 	Server       *Server                `json:"server,omitempty"`
+	Extensions   map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification
+// extensions. A reference is marshaled as the Reference Object alone.
+func (l Link) MarshalJSON() ([]byte, error) {
+	if l.Ref != nil {
+		return json.Marshal(l.Ref)
+	}
+	type link Link
+	return marshalWithExtensions(link(l), l.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification
+// extensions and decode Reference Objects
+func (l *Link) UnmarshalJSON(data []byte) error {
+	ref, err := unmarshalReference(data)
+	if err != nil || ref != nil {
+		*l = Link{Ref: ref}
+		return err
+	}
+	type link Link
+	if err := json.Unmarshal(data, (*link)(l)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	l.Extensions = ext
+	return err
 }
 
 // NewLink creates a new link
@@ -55,3 +88,12 @@ func (l Link) WithServer(server *Server) Link {
 	l.Server = server
 	return l
 }
+
+// WithExtension sets a specification extension; the name must start with "x-"
+func (l Link) WithExtension(name string, value interface{}) Link {
+	if l.Extensions == nil {
+		l.Extensions = make(map[string]interface{})
+	}
+	l.Extensions[name] = value
+	return l
+}
@@ -0,0 +1,179 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ExtensionSensitive is the default schema extension marking fields that must
+// be masked in logs
+const ExtensionSensitive = "x-sensitive"
+
+// LogOptions configures the logging middleware
+type LogOptions struct {
+	// Logger receives one record per request; defaults to slog.Default()
+	Logger *slog.Logger
+	// SensitiveExtension names the schema extension marking sensitive fields;
+	// defaults to ExtensionSensitive
+	SensitiveExtension string
+	// Mask replaces the values of sensitive fields; defaults to "***"
+	Mask string
+	// MaxBodyBytes limits how much of each body is captured; defaults to 64 KiB.
+	// Larger bodies are logged by size only.
+	MaxBodyBytes int
+}
+
+// WithSensitive marks a schema as sensitive so its values are masked by LogRequests
+func (s Schema) WithSensitive() Schema {
+	return s.WithExtension(ExtensionSensitive, true)
+}
+
+// LogRequests returns middleware logging each request and response together
+// with their JSON bodies. Fields whose schemas are writeOnly, have the password
+// format or carry the sensitive extension are masked, so logs can include
+// payloads without leaking secrets. Bodies of requests that match no operation
+// are not logged because their shape is unknown.
+func LogRequests(doc *Document, opts LogOptions) func(http.Handler) http.Handler {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.SensitiveExtension == "" {
+		opts.SensitiveExtension = ExtensionSensitive
+	}
+	if opts.Mask == "" {
+		opts.Mask = "***"
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = 64 << 10
+	}
+	router := NewRouter(doc)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			match, r := matchRequest(router, r)
+			attrs := []slog.Attr{slog.String("method", r.Method)}
+			if match == nil {
+				attrs = append(attrs, slog.String("path", UnmatchedRoute))
+			} else {
+				attrs = append(attrs,
+					slog.String("operationId", match.Operation.OperationID),
+					slog.String("path", match.Path))
+			}
+
+			var requestBody []byte
+			if match != nil && r.Body != nil {
+				requestBody, _ = io.ReadAll(io.LimitReader(r.Body, int64(opts.MaxBodyBytes)+1))
+				r.Body = readCloser{io.MultiReader(bytes.NewReader(requestBody), r.Body), r.Body}
+			}
+
+			start := time.Now()
+			cw := &captureWriter{statusWriter: statusWriter{ResponseWriter: w}, limit: opts.MaxBodyBytes}
+			next.ServeHTTP(cw, r)
+
+			attrs = append(attrs,
+				slog.Int("status", cw.Status()),
+				slog.Duration("duration", time.Since(start)))
+
+			if match != nil {
+				if body := match.Operation.RequestBody; body != nil {
+					body = doc.resolveRequestBody(body)
+					if _, mt, ok := matchMediaType(body.Content, r.Header.Get("Content-Type")); ok {
+						attrs = append(attrs, doc.bodyAttr("request_body", mt.Schema, requestBody, opts))
+					}
+				}
+				if _, response, ok := match.Operation.ResponseFor(cw.Status()); ok {
+					response = doc.resolveResponse(response)
+					if _, mt, ok := matchMediaType(response.Content, cw.Header().Get("Content-Type")); ok {
+						attrs = append(attrs, doc.bodyAttr("response_body", mt.Schema, cw.body.Bytes(), opts))
+					}
+				}
+			}
+
+			opts.Logger.LogAttrs(r.Context(), slog.LevelInfo, "http request", attrs...)
+		})
+	}
+}
+
+// bodyAttr renders a captured body as a log attribute with sensitive fields masked
+func (d *Document) bodyAttr(key string, schema *Schema, body []byte, opts LogOptions) slog.Attr {
+	if len(body) > opts.MaxBodyBytes {
+		return slog.Int(key+"_bytes", len(body))
+	}
+	var value interface{}
+	if len(body) == 0 || json.Unmarshal(body, &value) != nil {
+		return slog.Int(key+"_bytes", len(body))
+	}
+	return slog.Any(key, d.redact(schema, value, opts, 0))
+}
+
+// redact returns a copy of value with the fields described by sensitive schemas masked
+func (d *Document) redact(s *Schema, value interface{}, opts LogOptions, depth int) interface{} {
+	s = d.resolveSchema(s)
+	if s == nil || depth > 32 {
+		return value
+	}
+	if isSensitiveSchema(s, opts.SensitiveExtension) {
+		return opts.Mask
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := d.schemaProperties(s)
+		for _, sub := range append(append([]*Schema{}, s.OneOf...), s.AnyOf...) {
+			subProperties, _ := d.schemaProperties(sub)
+			for name, prop := range subProperties {
+				if _, ok := properties[name]; !ok {
+					properties[name] = prop
+				}
+			}
+		}
+		out := make(map[string]interface{}, len(v))
+		for name, field := range v {
+			prop, ok := properties[name]
+			if !ok && s.AdditionalProperties != nil {
+				prop = s.AdditionalProperties.Schema
+			}
+			out[name] = d.redact(prop, field, opts, depth+1)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = d.redact(s.Items, item, opts, depth+1)
+		}
+		return out
+	}
+	return value
+}
+
+func isSensitiveSchema(s *Schema, extension string) bool {
+	if s.WriteOnly || s.Format == "password" {
+		return true
+	}
+	sensitive, _ := s.Extensions[extension].(bool)
+	return sensitive
+}
+
+// captureWriter records the status code and the beginning of the response body
+type captureWriter struct {
+	statusWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if remaining := w.limit + 1 - w.body.Len(); remaining > 0 {
+		w.body.Write(b[:min(len(b), remaining)])
+	}
+	return w.statusWriter.Write(b)
+}
+
+// readCloser combines a replacement reader with the original body's Close
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogRequestsRedactsSensitiveFields(t *testing.T) {
+	doc := NewDocument("User API", "1.0.0")
+	ssn := StringSchema("").WithSensitive()
+	user := NewObjectSchema().
+		WithRequiredProperty("email", EmailSchema()).
+		WithProperty("password", PasswordSchema()).
+		WithProperty("ssn", &ssn)
+	doc.AddOperation("/users", "POST", NewOperation("createUser", "", "").
+		WithJSONRequestBody("User", true, &user).
+		WithCreatedResponse("Created", &user))
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	handler := LogRequests(doc, LogOptions{Logger: logger})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"email": "a@example.com", "ssn": "123-45-6789"}`))
+	}))
+
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"email": "a@example.com", "password": "hunter2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	output := logs.String()
+	if strings.Contains(output, "hunter2") || strings.Contains(output, "123-45-6789") {
+		t.Errorf("Expected sensitive values to be masked, got %s", output)
+	}
+
+	if !strings.Contains(output, `"operationId":"createUser"`) || !strings.Contains(output, "a@example.com") {
+		t.Errorf("Expected operation and non-sensitive fields in log, got %s", output)
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddOperation("/pets/{petId}", "GET", NewOperation("getPet", "", "").
+		WithPathParameter("petId", "", Int64Schema()).
+		WithNoContentResponse())
+
+	var observed []RequestObservation
+	handler := Instrument(doc, ObserverFunc(func(obs RequestObservation) {
+		observed = append(observed, obs)
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		method   string
+		target   string
+		expected RequestObservation
+	}{
+		{"GET", "/pets/42", RequestObservation{OperationID: "getPet", Method: "GET", Path: "/pets/{petId}", Status: 204}},
+		{"GET", "/pets/42/toys", RequestObservation{Method: "GET", Path: UnmatchedRoute, Status: 204}},
+		{"delete", "/pets/42", RequestObservation{Method: "DELETE", Path: UnmatchedRoute, Status: 204}},
+		{"PROPFIND", "/pets/42", RequestObservation{Method: OtherMethod, Path: UnmatchedRoute, Status: 204}},
+		{"X-RANDOM-1234", "/anything", RequestObservation{Method: OtherMethod, Path: UnmatchedRoute, Status: 204}},
+	}
+	for _, tt := range tests {
+		observed = nil
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.target, nil))
+		if len(observed) != 1 {
+			t.Fatalf("%s %s: Expected 1 observation, got %d", tt.method, tt.target, len(observed))
+		}
+		obs := observed[0]
+		obs.Duration = 0
+		if obs != tt.expected {
+			t.Errorf("%s %s: Expected %+v, got %+v", tt.method, tt.target, tt.expected, obs)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddSecurityScheme("bearer", *NewBearerSecurityScheme())
+	doc.AddSecurityRequirement(SecurityRequirement{"bearer": {}})
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", ""))
+	createPet := NewOperation("createPet", "", "")
+	createPet.Security = []SecurityRequirement{{"bearer": {"pets:write"}}}
+	doc.AddOperation("/pets", "POST", createPet)
+	health := NewOperation("health", "", "")
+	health.Security = []SecurityRequirement{}
+	doc.AddOperation("/health", "GET", health)
+
+	var requests []AuthorizationRequest
+	authorizer := func(req AuthorizationRequest) error {
+		requests = append(requests, req)
+		token := req.Request.Header.Get("Authorization")
+		if token == "" {
+			return ErrUnauthenticated
+		}
+		for _, requirement := range req.Requirements {
+			for _, scope := range requirement["bearer"] {
+				if token != "Bearer "+scope {
+					return errors.New("missing scope " + scope)
+				}
+			}
+		}
+		return nil
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := Authorize(doc, authorizer, AuthorizeOptions{DenyUnmatched: true})(next)
+
+	tests := []struct {
+		method string
+		target string
+		token  string
+		status int
+		allow  string
+	}{
+		{"GET", "/health", "", http.StatusNoContent, ""},
+		{"GET", "/pets", "", http.StatusUnauthorized, ""},
+		{"GET", "/pets", "Bearer reader", http.StatusNoContent, ""},
+		{"POST", "/pets", "Bearer reader", http.StatusForbidden, ""},
+		{"POST", "/pets", "Bearer pets:write", http.StatusNoContent, ""},
+		{"DELETE", "/pets", "Bearer pets:write", http.StatusMethodNotAllowed, "GET, POST"},
+		{"GET", "/unknown", "Bearer pets:write", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", tt.token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: Expected status %d, got %d", tt.method, tt.target, tt.status, rec.Code)
+		}
+		if allow := rec.Header().Get("Allow"); allow != tt.allow {
+			t.Errorf("%s %s: Expected Allow '%s', got '%s'", tt.method, tt.target, tt.allow, allow)
+		}
+	}
+
+	for _, req := range requests {
+		if req.Route.Operation.OperationID == "health" {
+			t.Error("Expected operations without requirements not to be authorized")
+		}
+		if req.Route.Operation.OperationID == "listPets" {
+			if len(req.Requirements) != 1 || req.Requirements[0]["bearer"] == nil || len(req.Requirements[0]["bearer"]) != 0 {
+				t.Errorf("Expected the document requirements for listPets, got %v", req.Requirements)
+			}
+			if _, ok := req.Schemes["bearer"]; !ok {
+				t.Errorf("Expected the bearer scheme passed along, got %v", req.Schemes)
+			}
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	Authorize(doc, authorizer, AuthorizeOptions{})(next).ServeHTTP(rec, httptest.NewRequest("DELETE", "/pets", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected unmatched requests passed through by default, got %d", rec.Code)
+	}
+}
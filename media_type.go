@@ -1,11 +1,33 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // MediaType represents a media type in OpenAPI
 type MediaType struct {
-	Schema   *Schema             `json:"schema,omitempty"`
-	Example  interface{}         `json:"example,omitempty"`
-	Examples map[string]Example  `json:"examples,omitempty"`
-	Encoding map[string]Encoding `json:"encoding,omitempty"`
+	Schema     *Schema                `json:"schema,omitempty"`
+	Example    interface{}            `json:"example,omitempty"`
+	Examples   map[string]Example     `json:"examples,omitempty"`
+	Encoding   map[string]Encoding    `json:"encoding,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (m MediaType) MarshalJSON() ([]byte, error) {
+	type mediaType MediaType
+	return marshalWithExtensions(mediaType(m), m.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (m *MediaType) UnmarshalJSON(data []byte) error {
+	type mediaType MediaType
+	if err := json.Unmarshal(data, (*mediaType)(m)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	m.Extensions = ext
+	return err
 }
 
 // NewMediaType creates a new media type
@@ -52,3 +74,12 @@ func (m MediaType) WithEncoding(property string, encoding Encoding) MediaType {
 	m.Encoding[property] = encoding
 	return m
 }
+
+// WithExtension sets a specification extension; the name must start with "x-"
+func (m MediaType) WithExtension(name string, value interface{}) MediaType {
+	if m.Extensions == nil {
+		m.Extensions = make(map[string]interface{})
+	}
+	m.Extensions[name] = value
+	return m
+}
@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CollisionStrategy tells Merge what to do with names both documents define
+// differently
+type CollisionStrategy string
+
+const (
+	// CollisionError fails the merge on any collision
+	CollisionError CollisionStrategy = "error"
+	// CollisionRename adds the colliding components and security schemes of
+	// the source under its prefix, as by PrefixNamespace, and rewrites its
+	// references to them. Colliding operations and tags can't be renamed and
+	// fail the merge.
+	CollisionRename CollisionStrategy = "rename"
+	// CollisionPreferDst keeps the destination's definition of every
+	// colliding name
+	CollisionPreferDst CollisionStrategy = "prefer-dst"
+)
+
+// MergeOptions configures Merge
+type MergeOptions struct {
+	// Collision is the strategy for names both documents define
+	// differently; the default is CollisionError
+	Collision CollisionStrategy
+	// Prefix names the source service for CollisionRename, as in "orders"
+	// turning a colliding "Pet" into "OrdersPet"
+	Prefix string
+}
+
+// Merge combines the paths, components, tags and security schemes of src
+// into those of dst, such as service documents aggregated into a gateway
+// document. Names defined identically by both are merged; names defined
+// differently are handled by opts.Collision. The info object, servers and
+// top-level security requirements are taken from dst. It returns the merged
+// document and the collisions found, leaving dst and src untouched; on a
+// failed merge the report lists the collisions at fault.
+func Merge(dst, src *Document, opts MergeOptions) (*Document, *MergeReport, error) {
+	var resolve MergeResolver
+	switch opts.Collision {
+	case "", CollisionError:
+	case CollisionPreferDst:
+		resolve = func(MergeConflict) MergeStrategy { return MergeKeepOurs }
+	case CollisionRename:
+		if opts.Prefix == "" {
+			return nil, nil, errors.New("openapi: merging documents: renaming collisions needs a prefix")
+		}
+		renamed, err := src.WithNamespace(collisionNamespace(dst, opts.Prefix))
+		if err != nil {
+			return nil, nil, fmt.Errorf("openapi: merging documents: %w", err)
+		}
+		src = renamed
+	default:
+		return nil, nil, fmt.Errorf("openapi: merging documents: unknown collision strategy %q", opts.Collision)
+	}
+
+	merged, report, err := MergeDocuments(nil, dst, src, resolve)
+	if err != nil {
+		return nil, nil, err
+	}
+	if unresolved := report.Unresolved(); len(unresolved) > 0 {
+		errs := make([]error, len(unresolved))
+		for i, c := range unresolved {
+			errs[i] = c
+		}
+		return nil, report, errors.Join(errs...)
+	}
+	return merged, report, nil
+}
+
+// collisionNamespace prefixes the components of a document that dst defines
+// differently under the same name
+func collisionNamespace(dst *Document, prefix string) Namespace {
+	return func(src *Document) map[string]string {
+		renames := make(map[string]string)
+		for _, key := range src.componentKeys() {
+			theirs, _ := src.component(key)
+			ours, ok := dst.component(key)
+			if !ok || sameJSON(ours, theirs) {
+				continue
+			}
+			_, name, _ := strings.Cut(key, "/")
+			renames[key] = prefixedName(prefix, unescapePointer(name))
+		}
+		return renames
+	}
+}
+
+// sameJSON reports whether two values marshal to the same JSON
+func sameJSON(a, b interface{}) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(canonicalJSON(dataA), canonicalJSON(dataB))
+}
@@ -0,0 +1,443 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MergeConflictKind classifies a merge conflict
+type MergeConflictKind string
+
+const (
+	// MergeConflictOperation is the same path and method defined differently
+	MergeConflictOperation MergeConflictKind = "operation"
+	// MergeConflictPath is a path item field, such as shared parameters, defined differently
+	MergeConflictPath MergeConflictKind = "path"
+	// MergeConflictComponent is the same component name used for different shapes
+	MergeConflictComponent MergeConflictKind = "component"
+	// MergeConflictSecurityScheme is the same security scheme name defined differently
+	MergeConflictSecurityScheme MergeConflictKind = "securityScheme"
+	// MergeConflictTag is the same tag name described differently
+	MergeConflictTag MergeConflictKind = "tag"
+)
+
+// MergeStrategy is a way to resolve a merge conflict
+type MergeStrategy string
+
+const (
+	// MergeKeepOurs keeps our side of the conflict
+	MergeKeepOurs MergeStrategy = "keep-ours"
+	// MergeKeepTheirs keeps their side of the conflict
+	MergeKeepTheirs MergeStrategy = "keep-theirs"
+	// MergeRename keeps both sides, adding theirs under a new name and
+	// rewriting their references to it. It applies to components and
+	// security schemes defined on both sides.
+	MergeRename MergeStrategy = "rename"
+	// MergeHashSuffix is MergeRename with their side named after a short hash
+	// of its content, such as "Pet_1a2b3c4d", so the name is stable across merges
+	MergeHashSuffix MergeStrategy = "hash-suffix"
+)
+
+// MergeConflict is a value both merged documents define differently, and
+// that the common base, if any, can't settle
+type MergeConflict struct {
+	Kind MergeConflictKind
+	// Pointer is the JSON pointer of the value in the merged document
+	Pointer string
+	// Name is the component, security scheme or tag name, or "METHOD path" for operations
+	Name    string
+	Message string
+	// Base, Ours and Theirs hold the decoded JSON values; nil when the value is absent
+	Base   interface{}
+	Ours   interface{}
+	Theirs interface{}
+	// Strategies lists the strategies that can resolve the conflict
+	Strategies []MergeStrategy
+	// Resolution is the strategy applied, empty when the conflict was left
+	// unresolved and our side kept
+	Resolution MergeStrategy
+	// RenamedTo is the new name of their side when resolved by renaming
+	RenamedTo string
+}
+
+// Error describes the conflict
+func (c MergeConflict) Error() string {
+	return fmt.Sprintf("openapi: merge conflict at %s: %s", c.Pointer, c.Message)
+}
+
+// MergeResolver chooses the strategy resolving a conflict; returning an empty
+// strategy leaves the conflict unresolved
+type MergeResolver func(conflict MergeConflict) MergeStrategy
+
+// MergeReport lists the conflicts found while merging documents
+type MergeReport struct {
+	Conflicts []MergeConflict
+}
+
+// Unresolved returns the conflicts no strategy resolved
+func (r *MergeReport) Unresolved() []MergeConflict {
+	var unresolved []MergeConflict
+	for _, c := range r.Conflicts {
+		if c.Resolution == "" {
+			unresolved = append(unresolved, c)
+		}
+	}
+	return unresolved
+}
+
+// Markdown renders the report as a Markdown table
+func (r *MergeReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString("| Kind | Name | Conflict | Strategies | Resolution |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, c := range r.Conflicts {
+		strategies := make([]string, len(c.Strategies))
+		for i, s := range c.Strategies {
+			strategies[i] = string(s)
+		}
+		resolution := string(c.Resolution)
+		switch {
+		case resolution == "":
+			resolution = "unresolved"
+		case c.RenamedTo != "":
+			resolution += " to " + c.RenamedTo
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s | %s |\n", c.Kind, c.Name, c.Message, strings.Join(strategies, ", "), resolution)
+	}
+	return b.String()
+}
+
+// MergeDocuments merges two documents with three-way semantics, such as two
+// services aggregated into one gateway document. base is the common ancestor
+// of ours and theirs, or nil when they have none. Operations, path items,
+// components, security schemes and tags are merged one by one: a value only
+// one side changed since base is taken from that side, and a value both sides
+// define differently is a conflict. resolve chooses how each conflict is
+// resolved; without it, or when it returns no strategy, our side is kept and
+// the conflict left unresolved in the report. The info object, servers and
+// top-level security requirements are taken from ours.
+func MergeDocuments(base, ours, theirs *Document, resolve MergeResolver) (*Document, *MergeReport, error) {
+	var trees [3]map[string]interface{}
+	for i, doc := range []*Document{base, ours, theirs} {
+		if doc == nil {
+			continue
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(data, &trees[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	if trees[1] == nil {
+		trees[1] = make(map[string]interface{})
+	}
+
+	m := &merger{base: trees[0], ours: trees[1], theirs: trees[2], resolve: resolve, report: &MergeReport{}}
+	if err := m.mergeComponents(); err != nil {
+		return nil, nil, err
+	}
+	if err := m.mergePaths(); err != nil {
+		return nil, nil, err
+	}
+	if err := m.mergeTags(); err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(m.ours)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &Document{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, nil, err
+	}
+	return result, m.report, nil
+}
+
+// merger holds the decoded documents of a three-way merge; the merge is
+// written into ours
+type merger struct {
+	base, ours, theirs map[string]interface{}
+	resolve            MergeResolver
+	report             *MergeReport
+}
+
+// mergeComponents merges the components of every kind. Conflicts resolved by
+// renaming add their side under a new name, and their references to it are
+// rewritten once all components are merged.
+func (m *merger) mergeComponents() error {
+	refs := make(map[string]string)
+	schemes := make(map[string]string)
+	base, ours, theirs := objectAt(m.base, "components"), objectAt(m.ours, "components"), objectAt(m.theirs, "components")
+	for _, kind := range unionKeys(ours, theirs) {
+		baseKind, oursKind, theirsKind := objectAt(base, kind), objectAt(ours, kind), objectAt(theirs, kind)
+		if oursKind == nil || theirsKind == nil {
+			if _, ok := ours[kind]; !ok {
+				setMember(m.ours, "components", kind, theirs[kind])
+			}
+			continue
+		}
+		for _, name := range unionKeys(oursKind, theirsKind) {
+			c := MergeConflict{
+				Kind:    MergeConflictComponent,
+				Pointer: "#/components/" + kind + "/" + escapePointer(name),
+				Name:    name,
+				Message: fmt.Sprintf("component %s/%s has different shapes", kind, name),
+			}
+			if kind == "securitySchemes" {
+				c.Kind = MergeConflictSecurityScheme
+				c.Message = fmt.Sprintf("security scheme %s is defined differently", name)
+				if reason := incompatibleSchemes(oursKind[name], theirsKind[name]); reason != "" {
+					c.Message = fmt.Sprintf("security scheme %s is incompatible: %s", name, reason)
+				}
+			}
+			merged, keep, err := m.mergeValue(&c, baseKind, oursKind, theirsKind, name)
+			if err != nil {
+				return err
+			}
+			if c.RenamedTo != "" {
+				oursKind[c.RenamedTo] = theirsKind[name]
+				if kind == "securitySchemes" {
+					schemes[name] = c.RenamedTo
+				} else {
+					refs[c.Pointer] = "#/components/" + kind + "/" + escapePointer(c.RenamedTo)
+				}
+			}
+			if keep {
+				oursKind[name] = merged
+			} else {
+				delete(oursKind, name)
+			}
+		}
+	}
+
+	if len(refs) > 0 {
+		mapRefs(m.theirs, func(ref string) string {
+			if renamed, ok := refs[ref]; ok {
+				return renamed
+			}
+			return ref
+		})
+	}
+	if len(schemes) > 0 {
+		renameSecurityRequirements(m.theirs["security"], schemes)
+		for _, path := range objectAt(m.theirs, "paths") {
+			for _, method := range httpMethods {
+				renameSecurityRequirements(objectAt(path, strings.ToLower(method))["security"], schemes)
+			}
+		}
+	}
+	return nil
+}
+
+// mergePaths merges the operations and path item fields of every path
+func (m *merger) mergePaths() error {
+	base, ours, theirs := objectAt(m.base, "paths"), objectAt(m.ours, "paths"), objectAt(m.theirs, "paths")
+	for _, path := range unionKeys(ours, theirs) {
+		if _, ok := ours[path]; !ok {
+			setMember(m.ours, "paths", path, theirs[path])
+			continue
+		}
+		baseItem, oursItem, theirsItem := objectAt(base, path), objectAt(ours, path), objectAt(theirs, path)
+		if oursItem == nil || theirsItem == nil {
+			continue
+		}
+		for _, key := range unionKeys(oursItem, theirsItem) {
+			c := MergeConflict{
+				Kind:    MergeConflictPath,
+				Pointer: "#/paths/" + escapePointer(path) + "/" + key,
+				Name:    path,
+				Message: fmt.Sprintf("path %s defines %s differently", path, key),
+			}
+			if method := strings.ToUpper(key); slices.Contains(httpMethods, method) {
+				c.Kind = MergeConflictOperation
+				c.Name = method + " " + path
+				c.Message = fmt.Sprintf("%s %s is defined twice", method, path)
+			}
+			merged, keep, err := m.mergeValue(&c, baseItem, oursItem, theirsItem, key)
+			if err != nil {
+				return err
+			}
+			if keep {
+				oursItem[key] = merged
+			} else {
+				delete(oursItem, key)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeTags merges tags by name, keeping our order and appending their new tags
+func (m *merger) mergeTags() error {
+	byName := func(tags interface{}) (map[string]interface{}, []string) {
+		named := make(map[string]interface{})
+		var order []string
+		list, _ := tags.([]interface{})
+		for _, tag := range list {
+			if name, ok := objectAt(tag)["name"].(string); ok {
+				named[name] = tag
+				order = append(order, name)
+			}
+		}
+		return named, order
+	}
+	base, _ := byName(m.base["tags"])
+	ours, order := byName(m.ours["tags"])
+	theirs, theirOrder := byName(m.theirs["tags"])
+	for _, name := range theirOrder {
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+
+	var tags []interface{}
+	for _, name := range order {
+		c := MergeConflict{
+			Kind:    MergeConflictTag,
+			Pointer: "#/tags",
+			Name:    name,
+			Message: fmt.Sprintf("tag %s is described differently", name),
+		}
+		merged, keep, err := m.mergeValue(&c, base, ours, theirs, name)
+		if err != nil {
+			return err
+		}
+		if keep {
+			tags = append(tags, merged)
+		}
+	}
+	if tags != nil {
+		m.ours["tags"] = tags
+	}
+	return nil
+}
+
+// mergeValue merges the member key of three objects, reporting a conflict
+// described by c when the sides can't be reconciled. It returns the merged
+// value and whether the member is kept.
+func (m *merger) mergeValue(c *MergeConflict, base, ours, theirs map[string]interface{}, key string) (interface{}, bool, error) {
+	b, inBase := base[key]
+	o, inOurs := ours[key]
+	t, inTheirs := theirs[key]
+	same := func(a, b interface{}, inA, inB bool) bool {
+		return inA == inB && reflect.DeepEqual(a, b)
+	}
+	switch {
+	case !inTheirs && !inBase, same(o, t, inOurs, inTheirs), same(b, t, inBase, inTheirs):
+		return o, inOurs, nil
+	case same(b, o, inBase, inOurs):
+		return t, inTheirs, nil
+	}
+
+	c.Base, c.Ours, c.Theirs = b, o, t
+	c.Strategies = []MergeStrategy{MergeKeepOurs, MergeKeepTheirs}
+	if inOurs && inTheirs && (c.Kind == MergeConflictComponent || c.Kind == MergeConflictSecurityScheme) {
+		c.Strategies = append(c.Strategies, MergeRename, MergeHashSuffix)
+	}
+	if m.resolve != nil {
+		c.Resolution = m.resolve(*c)
+	}
+	if c.Resolution != "" && !slices.Contains(c.Strategies, c.Resolution) {
+		return nil, false, fmt.Errorf("openapi: strategy %q can't resolve %s", c.Resolution, c.Pointer)
+	}
+	switch c.Resolution {
+	case MergeRename:
+		c.RenamedTo = freeName(key, base, ours, theirs)
+	case MergeHashSuffix:
+		c.RenamedTo = hashedName(key, t)
+		if _, taken := ours[c.RenamedTo]; taken {
+			c.RenamedTo = freeName(c.RenamedTo, base, ours, theirs)
+		}
+	}
+	m.report.Conflicts = append(m.report.Conflicts, *c)
+	if c.Resolution == MergeKeepTheirs {
+		return t, inTheirs, nil
+	}
+	return o, inOurs, nil
+}
+
+// incompatibleSchemes describes why two security schemes can't be used
+// interchangeably, or returns an empty string when they can
+func incompatibleSchemes(ours, theirs interface{}) string {
+	o, t := objectAt(ours), objectAt(theirs)
+	if o == nil || t == nil {
+		return ""
+	}
+	for _, field := range []string{"type", "scheme", "in", "name"} {
+		if !reflect.DeepEqual(o[field], t[field]) {
+			return fmt.Sprintf("%s %v differs from %v", field, o[field], t[field])
+		}
+	}
+	return ""
+}
+
+// renameSecurityRequirements renames security schemes in a decoded list of
+// security requirements
+func renameSecurityRequirements(security interface{}, names map[string]string) {
+	list, _ := security.([]interface{})
+	for _, requirement := range list {
+		object := objectAt(requirement)
+		for name, renamed := range names {
+			if scopes, ok := object[name]; ok {
+				delete(object, name)
+				object[renamed] = scopes
+			}
+		}
+	}
+}
+
+// freeName returns name with the lowest numeric suffix used by none of the objects
+func freeName(name string, objects ...map[string]interface{}) string {
+	for i := 2; ; i++ {
+		candidate := name + strconv.Itoa(i)
+		taken := false
+		for _, object := range objects {
+			if _, ok := object[candidate]; ok {
+				taken = true
+			}
+		}
+		if !taken {
+			return candidate
+		}
+	}
+}
+
+// objectAt follows keys through decoded JSON objects and returns the object
+// found, or nil
+func objectAt(value interface{}, keys ...string) map[string]interface{} {
+	for _, key := range keys {
+		object, _ := value.(map[string]interface{})
+		value = object[key]
+	}
+	object, _ := value.(map[string]interface{})
+	return object
+}
+
+// setMember sets tree[parent][key], creating the parent object if needed
+func setMember(tree map[string]interface{}, parent, key string, value interface{}) {
+	object := objectAt(tree, parent)
+	if object == nil {
+		object = make(map[string]interface{})
+		tree[parent] = object
+	}
+	object[key] = value
+}
+
+// unionKeys returns the keys of two objects in sorted order
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := sortedKeys(a)
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
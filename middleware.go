@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// routeContextKey is the context key under which middleware stores the matched route
+type routeContextKey struct{}
+
+// RouteFromContext returns the route matched by one of the package's middleware,
+// or nil when the request didn't match an operation
+func RouteFromContext(ctx context.Context) *RouteMatch {
+	match, _ := ctx.Value(routeContextKey{}).(*RouteMatch)
+	return match
+}
+
+// matchRequest returns the route already stored in the request context, or
+// matches the request with the router and stores the result in a derived request
+func matchRequest(router *Router, r *http.Request) (*RouteMatch, *http.Request) {
+	if match := RouteFromContext(r.Context()); match != nil {
+		return match, r
+	}
+	match, err := router.Match(r.Method, r.URL.EscapedPath())
+	if err != nil {
+		return nil, r
+	}
+	return match, r.WithContext(context.WithValue(r.Context(), routeContextKey{}, match))
+}
+
+// statusWriter records the status code and size of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the recorded status code, defaulting to 200
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
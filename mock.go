@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MockOptions configures NewMockHandler
+type MockOptions struct {
+	// ScenarioHeader names the request header selecting an example set;
+	// defaults to "X-Mock-Scenario"
+	ScenarioHeader string
+	// ScenarioParameter names the query parameter selecting an example set;
+	// defaults to "mockScenario"
+	ScenarioParameter string
+	// Faults injects latency, errors and throttling per operationId; the "*"
+	// entry applies to operations without an entry of their own
+	Faults map[string]MockFaults
+	// Rand is the random source of fault injection; defaults to a randomly
+	// seeded one. Set it for reproducible runs.
+	Rand *rand.Rand
+	// Store makes the mock stateful: resources created by POST on a collection
+	// path can be read, replaced, updated and deleted on the item path below
+	// it, and listed by GET on the collection path
+	Store *MockStore
+	// IDProperty names the property identifying stored resources; defaults to
+	// "id". Resources created without one get sequential ids.
+	IDProperty string
+}
+
+// NewMockHandler returns a handler answering requests with the responses
+// documented for their operation, before the real service exists. Without a
+// scenario it answers with the first successful response, using its example,
+// its first named example or a sample of its schema. A scenario names an
+// example set (see ExampleSet), selected with the scenario header or query
+// parameter, so frontends can exercise error paths: the handler answers with
+// the response documenting an example of that name. Unknown scenarios are
+// answered with 400 Bad Request listing the available ones. Faults apply to
+// every matched request, scenarios included. With a Store, requests without
+// a scenario are answered from the store when they are CRUD operations.
+func NewMockHandler(doc *Document, opts MockOptions) http.Handler {
+	if opts.ScenarioHeader == "" {
+		opts.ScenarioHeader = "X-Mock-Scenario"
+	}
+	if opts.ScenarioParameter == "" {
+		opts.ScenarioParameter = "mockScenario"
+	}
+	if opts.IDProperty == "" {
+		opts.IDProperty = "id"
+	}
+	router := NewRouter(doc)
+	injector := newFaultInjector(opts.Faults, opts.Rand)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		match, err := router.Match(r.Method, r.URL.EscapedPath())
+		if errors.Is(err, ErrMethodNotAllowed) {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		if faults, ok := injector.lookup(match.Operation); ok {
+			if faults.Latency != nil {
+				select {
+				case <-time.After(injector.delay(faults.Latency)):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if faults.BytesPerSecond > 0 {
+				w = &throttledWriter{ResponseWriter: w, rate: faults.BytesPerSecond}
+			}
+			if code, ok := injector.fail(match.Operation, faults.ErrorRate); ok {
+				if code == "" {
+					http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+					return
+				}
+				doc.writeMockResponse(w, mockStatus(code), doc.resolveResponse(match.Operation.Responses[code]), "")
+				return
+			}
+		}
+
+		scenario := r.Header.Get(opts.ScenarioHeader)
+		if scenario == "" {
+			scenario = r.URL.Query().Get(opts.ScenarioParameter)
+		}
+		if scenario == "" && opts.Store != nil && opts.Store.serve(doc, match, w, r, opts.IDProperty) {
+			return
+		}
+		code, response, ok := doc.mockResponse(match.Operation, scenario)
+		if !ok {
+			var names []string
+			for _, set := range match.Operation.ExampleSets() {
+				names = append(names, set.Name)
+			}
+			http.Error(w, "unknown mock scenario "+strconv.Quote(scenario)+"; available: "+strings.Join(names, ", "), http.StatusBadRequest)
+			return
+		}
+		doc.writeMockResponse(w, code, response, scenario)
+	})
+}
+
+// mockResponse picks the response to mock: the first one with an example
+// named after the scenario, or without a scenario the first successful one
+func (d *Document) mockResponse(op *Operation, scenario string) (int, Response, bool) {
+	codes := sortedKeys(op.Responses)
+	// Prefer explicit codes over ranges, and ranges over the default response
+	sort.SliceStable(codes, func(i, j int) bool {
+		return mockCodeRank(codes[i]) < mockCodeRank(codes[j])
+	})
+	for _, code := range codes {
+		response := d.resolveResponse(op.Responses[code])
+		if scenario == "" {
+			if strings.HasPrefix(code, "2") || code == "default" {
+				return mockStatus(code), response, true
+			}
+			continue
+		}
+		for _, mt := range response.Content {
+			if _, ok := mt.Examples[scenario]; ok {
+				return mockStatus(code), response, true
+			}
+		}
+	}
+	if scenario == "" {
+		return http.StatusOK, Response{}, true
+	}
+	return 0, Response{}, false
+}
+
+// writeMockResponse writes a documented response, preferring a JSON media type
+func (d *Document) writeMockResponse(w http.ResponseWriter, code int, response Response, scenario string) {
+	names := sortedKeys(response.Content)
+	if len(names) == 0 {
+		w.WriteHeader(code)
+		return
+	}
+	contentType := names[0]
+	for _, name := range names {
+		if isJSONMediaType(name) {
+			contentType = name
+			break
+		}
+	}
+	mt := response.Content[contentType]
+
+	value, ok := mt.Examples[scenario]
+	body := value.Value
+	if !ok {
+		body = mt.Example
+		if body == nil {
+			for _, name := range sortedKeys(mt.Examples) {
+				body = mt.Examples[name].Value
+				break
+			}
+		}
+		if body == nil {
+			body = d.SampleValue(mt.Schema)
+		}
+	}
+
+	var data []byte
+	if s, isString := body.(string); isString && !isJSONMediaType(contentType) {
+		data = []byte(s)
+	} else {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// mockCodeRank orders explicit status codes before ranges and the default response
+func mockCodeRank(code string) int {
+	switch {
+	case code == "default":
+		return 2
+	case strings.HasSuffix(strings.ToUpper(code), "XX"):
+		return 1
+	}
+	return 0
+}
+
+// mockStatus converts a response key such as "201", "4XX" or "default" to a status code
+func mockStatus(code string) int {
+	if status, err := strconv.Atoi(code); err == nil {
+		return status
+	}
+	if code != "default" && len(code) == 3 {
+		if class, err := strconv.Atoi(code[:1]); err == nil {
+			return class * 100
+		}
+	}
+	return http.StatusOK
+}
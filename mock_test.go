@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockDocument() *Document {
+	doc := NewDocument("Pet API", "1.0.0")
+	pet := NewObjectSchema().WithRequiredProperty("name", StringSchema(""))
+	doc.AddOperation("/pets", "POST", NewOperation("createPet", "", "").
+		WithRequestBody("", true, map[string]MediaType{"application/json": {Schema: NewObjectSchema()}}).
+		WithJSONResponse("201", "Created", &pet).
+		WithExampleSet(NewExampleSet("validation-error", "A pet without a name").
+			WithRequest(map[string]interface{}{}).
+			WithResponse("400", map[string]interface{}{"error": "name is required"})))
+	return doc
+}
+
+func TestMockScenarios(t *testing.T) {
+	handler := NewMockHandler(mockDocument(), MockOptions{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/pets", nil))
+	if rec.Code != http.StatusCreated || rec.Body.String() != `{"name":"string"}` {
+		t.Errorf("Expected a sampled 201 response, got %d %s", rec.Code, rec.Body)
+	}
+
+	req := httptest.NewRequest("POST", "/pets", nil)
+	req.Header.Set("X-Mock-Scenario", "validation-error")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || rec.Body.String() != `{"error":"name is required"}` {
+		t.Errorf("Expected the validation-error example, got %d %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/pets?mockScenario=teapot", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "available: validation-error") {
+		t.Errorf("Expected unknown scenarios to be rejected, got %d %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("DELETE", "/pets", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for undocumented methods, got %d", rec.Code)
+	}
+}
+
+func TestMockFaults(t *testing.T) {
+	doc := mockDocument()
+	doc.Paths["/pets"].Post.Responses["503"] = Response{
+		Description: "Unavailable",
+		Content:     map[string]MediaType{"application/json": {Example: map[string]interface{}{"error": "try again"}}},
+	}
+	handler := NewMockHandler(doc, MockOptions{
+		Faults: map[string]MockFaults{
+			"createPet": {ErrorRate: 1},
+			"*":         {Latency: FixedLatency(20 * time.Millisecond)},
+		},
+		Rand: rand.New(rand.NewPCG(1, 2)),
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/pets", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Body.String() != `{"error":"try again"}` {
+		t.Errorf("Expected the documented 503 response, got %d %s", rec.Code, rec.Body)
+	}
+
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").WithJSONResponse("200", "Pets", NewArraySchema(StringSchema(""))))
+	handler = NewMockHandler(doc, MockOptions{
+		Faults: map[string]MockFaults{"*": {Latency: FixedLatency(20 * time.Millisecond)}},
+	})
+	start := time.Now()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/pets", nil))
+	if elapsed := time.Since(start); rec.Code != http.StatusOK || elapsed < 20*time.Millisecond {
+		t.Errorf("Expected a delayed 200 response, got %d after %s", rec.Code, elapsed)
+	}
+}
+
+func TestMockStore(t *testing.T) {
+	doc := mockDocument()
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").WithJSONResponse("200", "Pets", NewArraySchema(NewObjectSchema())))
+	doc.AddOperation("/pets/{petId}", "GET", NewOperation("getPet", "", "").WithJSONResponse("200", "Pet", NewObjectSchema()))
+	doc.AddOperation("/pets/{petId}", "PATCH", NewOperation("updatePet", "", "").WithJSONResponse("200", "Pet", NewObjectSchema()))
+	doc.AddOperation("/pets/{petId}", "DELETE", NewOperation("deletePet", "", "").WithResponse("204", "Deleted", Response{Description: "Deleted"}))
+	handler := NewMockHandler(doc, MockOptions{Store: NewMockStore()})
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := send("POST", "/pets", `{"name":"Rex"}`); rec.Code != http.StatusCreated || rec.Body.String() != `{"id":1,"name":"Rex"}` {
+		t.Errorf("Expected the created pet with an id, got %d %s", rec.Code, rec.Body)
+	}
+	if rec := send("PATCH", "/pets/1", `{"name":"Max"}`); rec.Code != http.StatusOK || rec.Body.String() != `{"id":1,"name":"Max"}` {
+		t.Errorf("Expected the updated pet, got %d %s", rec.Code, rec.Body)
+	}
+	if rec := send("GET", "/pets", ""); rec.Body.String() != `[{"id":1,"name":"Max"}]` {
+		t.Errorf("Expected the list of stored pets, got %s", rec.Body)
+	}
+	if rec := send("DELETE", "/pets/1", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 on delete, got %d", rec.Code)
+	}
+	if rec := send("GET", "/pets/1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a deleted pet, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MockStore holds the resources of a stateful mock. Resources are kept per
+// collection path, e.g. "/pets" or "/owners/1/pets", and keyed by their id.
+type MockStore struct {
+	mu          sync.Mutex
+	collections map[string]*mockCollection
+}
+
+type mockCollection struct {
+	ids    []string
+	items  map[string]map[string]interface{}
+	nextID int
+}
+
+// NewMockStore creates an empty store
+func NewMockStore() *MockStore {
+	return &MockStore{collections: make(map[string]*mockCollection)}
+}
+
+// Reset removes every resource
+func (s *MockStore) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections = make(map[string]*mockCollection)
+}
+
+func (s *MockStore) collection(path string) *mockCollection {
+	c, ok := s.collections[path]
+	if !ok {
+		c = &mockCollection{items: make(map[string]map[string]interface{})}
+		s.collections[path] = c
+	}
+	return c
+}
+
+// serve handles CRUD requests against the store: POST to a collection path
+// creates a resource, GET lists them, and GET, PUT, PATCH and DELETE on the
+// item path below it read, replace, update and remove one. It reports false
+// for requests that aren't CRUD operations on a collection.
+func (s *MockStore) serve(doc *Document, match *RouteMatch, w http.ResponseWriter, r *http.Request, idProperty string) bool {
+	template := match.Path
+	segments := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+	last := template[strings.LastIndex(template, "/")+1:]
+
+	var collection, id string
+	if name, ok := strings.CutPrefix(last, "{"); ok && strings.HasSuffix(name, "}") {
+		collection = strings.Join(segments[:len(segments)-1], "/")
+		id = match.PathParams[strings.TrimSuffix(name, "}")]
+	} else {
+		isCollection := false
+		for path := range doc.Paths {
+			rest, ok := strings.CutPrefix(path, strings.TrimSuffix(template, "/")+"/{")
+			if ok && strings.Count(rest, "/") == 0 {
+				isCollection = true
+			}
+		}
+		if !isCollection || (r.Method != http.MethodPost && r.Method != http.MethodGet) {
+			return false
+		}
+		collection = strings.Join(segments, "/")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(collection)
+	code, _, _ := doc.mockResponse(match.Operation, "")
+
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		items := make([]interface{}, 0, len(c.ids))
+		for _, key := range c.ids {
+			items = append(items, c.items[key])
+		}
+		writeMockJSON(w, code, items)
+	case id == "" && r.Method == http.MethodPost:
+		var item map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+			http.Error(w, "request body must be a JSON object: "+err.Error(), http.StatusBadRequest)
+			return true
+		}
+		value, ok := item[idProperty]
+		if !ok || value == nil {
+			c.nextID++
+			value = c.nextID
+			if doc.mockIDIsString(match.Operation, idProperty) {
+				value = strconv.Itoa(c.nextID)
+			}
+			item[idProperty] = value
+		}
+		key := fmt.Sprint(value)
+		if _, exists := c.items[key]; !exists {
+			c.ids = append(c.ids, key)
+		}
+		c.items[key] = item
+		writeMockJSON(w, code, item)
+	default:
+		item, ok := c.items[id]
+		if !ok {
+			if response, documented := match.Operation.Responses["404"]; documented {
+				doc.writeMockResponse(w, http.StatusNotFound, doc.resolveResponse(response), "")
+			} else {
+				http.NotFound(w, r)
+			}
+			return true
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeMockJSON(w, code, item)
+		case http.MethodPut, http.MethodPatch:
+			var update map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				http.Error(w, "request body must be a JSON object: "+err.Error(), http.StatusBadRequest)
+				return true
+			}
+			if r.Method == http.MethodPatch {
+				for key, value := range update {
+					item[key] = value
+				}
+				update = item
+			}
+			update[idProperty] = item[idProperty]
+			c.items[id] = update
+			writeMockJSON(w, code, update)
+		case http.MethodDelete:
+			delete(c.items, id)
+			for i, key := range c.ids {
+				if key == id {
+					c.ids = append(c.ids[:i], c.ids[i+1:]...)
+					break
+				}
+			}
+			w.WriteHeader(code)
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// mockIDIsString reports whether the id property of the created resource is
+// documented as a string
+func (d *Document) mockIDIsString(op *Operation, idProperty string) bool {
+	for code, response := range op.Responses {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		for _, mt := range d.resolveResponse(response).Content {
+			if s := d.resolveSchema(mt.Schema); s != nil {
+				if id := d.resolveSchema(s.Properties[idProperty]); id != nil {
+					return id.Type.Is("string")
+				}
+			}
+		}
+	}
+	return false
+}
+
+// writeMockJSON writes a stored value as a JSON response
+func writeMockJSON(w http.ResponseWriter, code int, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// decimalPattern matches the decimal strings of the "decimal" format
+var decimalPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+// monetaryWords are the name words suggesting a field holds an amount of money
+var monetaryWords = []string{"amount", "balance", "cost", "fee", "fees", "price", "salary", "subtotal", "tax", "total"}
+
+// MoneySchema creates the standard money object: an amount as a decimal
+// string, which keeps its exact value unlike floating-point numbers, and an
+// ISO 4217 currency code, restricted to the given currencies if any
+func MoneySchema(currencies ...string) *Schema {
+	amount := StringSchema("decimal").
+		WithPattern(decimalPattern.String()).
+		WithDescription("Amount as a decimal number, e.g. \"12.50\"")
+	currency := StringSchema("").
+		WithPattern(currencyCode.String()).
+		WithDescription("ISO 4217 currency code")
+	if len(currencies) > 0 {
+		values := make([]interface{}, len(currencies))
+		for i, c := range currencies {
+			values[i] = c
+		}
+		currency = currency.WithEnum(values...)
+	}
+	schema := NewObjectSchema().
+		WithRequiredProperty("amount", &amount).
+		WithRequiredProperty("currency", &currency)
+	return &schema
+}
+
+// MoneyProfile warns about floating-point numbers in fields whose name
+// suggests an amount of money, such as "price" or "totalAmount", as rule
+// "money/float-amount": binary floating point can't represent most decimal
+// amounts exactly. Use MoneySchema or a decimal string instead.
+type MoneyProfile struct{}
+
+// Name returns "money"
+func (MoneyProfile) Name() string {
+	return "money"
+}
+
+// Validate reports monetary fields typed as floating-point numbers
+func (MoneyProfile) Validate(d *Document) []ValidationError {
+	var errs []ValidationError
+	check := func(pointer, name string, s *Schema) {
+		resolved := d.resolveSchema(s)
+		if resolved == nil || !resolved.Type.Is("number") || !monetaryName(name) {
+			return
+		}
+		errs = append(errs, ValidationError{
+			Path:     pointer,
+			Message:  name + " looks like an amount of money but is a floating-point number; use a decimal string",
+			Severity: SeverityWarning,
+			Rule:     "money/float-amount",
+		})
+	}
+	d.walkSchemas(func(pointer string, s *Schema) {
+		for _, name := range sortedKeys(s.Properties) {
+			check(pointer+"/properties/"+escapePointer(name), name, s.Properties[name])
+		}
+	})
+	d.walkParameters(func(pointer string, param Parameter) {
+		check(pointer+"/schema", param.Name, param.Schema)
+	})
+	return errs
+}
+
+// monetaryName reports whether a field name contains a monetary word, e.g.
+// "unit_price" or "totalAmount"
+func monetaryName(name string) bool {
+	for _, word := range strings.Split(protoFieldName(name), "_") {
+		if slices.Contains(monetaryWords, word) {
+			return true
+		}
+	}
+	return false
+}
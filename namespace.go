@@ -0,0 +1,192 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Namespace names the components of a document before it is merged into or
+// imported by another, so components of different services don't collide. It
+// returns the new names, keyed by the component pointer relative to
+// "#/components/" such as "schemas/Pet"; components left out keep their name.
+type Namespace func(d *Document) map[string]string
+
+// PrefixNamespace prefixes every component with a service name, so "Pet" of
+// the "orders" service becomes "OrdersPet". Components already carrying the
+// prefix are left as they are.
+func PrefixNamespace(prefix string) Namespace {
+	return func(d *Document) map[string]string {
+		renames := make(map[string]string)
+		for _, key := range d.componentKeys() {
+			_, name, _ := strings.Cut(key, "/")
+			name = unescapePointer(name)
+			if !strings.HasPrefix(name, exportedName(prefix)) {
+				renames[key] = prefixedName(prefix, name)
+			}
+		}
+		return renames
+	}
+}
+
+// TagNamespace prefixes components with the tag of the operations using them,
+// so "Invoice" used by operations tagged "billing" becomes "BillingInvoice".
+// Operations are represented by their first tag; components used under
+// several tags or by no tagged operation keep their name.
+func TagNamespace() Namespace {
+	return func(d *Document) map[string]string {
+		tags := make(map[string]map[string]bool)
+		d.walkOperations(func(path, method string, op *Operation) {
+			if len(op.Tags) == 0 {
+				return
+			}
+			var item PathItem
+			item.SetOperation(method, op)
+			single := &Document{Components: d.Components, Paths: map[string]PathItem{path: item}}
+			for key := range single.referencedComponents() {
+				if tags[key] == nil {
+					tags[key] = make(map[string]bool)
+				}
+				tags[key][op.Tags[0]] = true
+			}
+		})
+
+		renames := make(map[string]string)
+		for _, key := range d.componentKeys() {
+			if len(tags[key]) != 1 {
+				continue
+			}
+			for tag := range tags[key] {
+				_, name, _ := strings.Cut(key, "/")
+				renames[key] = prefixedName(tag, unescapePointer(name))
+			}
+		}
+		return renames
+	}
+}
+
+// WithNamespace returns a copy of the document with its components renamed
+// by ns and every reference to them, including the security schemes named by
+// security requirements, rewritten accordingly. Renaming two components to
+// the same name, or to the name of a component that isn't renamed, is an
+// error. The document itself is left untouched.
+func (d *Document) WithNamespace(ns Namespace) (*Document, error) {
+	renames := ns(d)
+	existing := make(map[string]bool)
+	for _, key := range d.componentKeys() {
+		if _, renamed := renames[key]; !renamed {
+			existing[key] = true
+		}
+	}
+	keys := sortedKeys(renames)
+	for _, key := range keys {
+		kind, _, _ := strings.Cut(key, "/")
+		target := kind + "/" + escapePointer(renames[key])
+		if existing[target] {
+			return nil, fmt.Errorf("openapi: namespacing %s collides with component %s", key, target)
+		}
+		existing[target] = true
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	schemes := make(map[string]string)
+	components := objectAt(tree, "components")
+	for _, key := range keys {
+		kind, name, _ := strings.Cut(key, "/")
+		name = unescapePointer(name)
+		object := objectAt(components, kind)
+		value, ok := object[name]
+		if !ok {
+			continue
+		}
+		delete(object, name)
+		object[renames[key]] = value
+		if kind == "securitySchemes" {
+			schemes[name] = renames[key]
+		} else {
+			refs["#/components/"+key] = "#/components/" + kind + "/" + escapePointer(renames[key])
+		}
+	}
+	// Moved components keep their maps, so one pass rewrites references
+	// inside components and elsewhere alike
+	mapRefs(tree, func(ref string) string {
+		for old, renamed := range refs {
+			if ref == old || strings.HasPrefix(ref, old+"/") {
+				return renamed + strings.TrimPrefix(ref, old)
+			}
+		}
+		return ref
+	})
+	if len(schemes) > 0 {
+		renameSecurityRequirements(tree["security"], schemes)
+		for _, path := range objectAt(tree, "paths") {
+			for _, method := range httpMethods {
+				renameSecurityRequirements(objectAt(path, strings.ToLower(method))["security"], schemes)
+			}
+		}
+	}
+
+	if data, err = json.Marshal(tree); err != nil {
+		return nil, err
+	}
+	namespaced := &Document{}
+	if err := json.Unmarshal(data, namespaced); err != nil {
+		return nil, err
+	}
+	return namespaced, nil
+}
+
+// componentKeys returns the pointers of all components relative to
+// "#/components/", in sorted order
+func (d *Document) componentKeys() []string {
+	if d.Components == nil {
+		return nil
+	}
+	data, err := json.Marshal(d.Components)
+	if err != nil {
+		return nil
+	}
+	var components map[string]json.RawMessage
+	if json.Unmarshal(data, &components) != nil {
+		return nil
+	}
+	var keys []string
+	for kind, raw := range components {
+		var named map[string]json.RawMessage
+		if isExtensionKey(kind) || json.Unmarshal(raw, &named) != nil {
+			continue
+		}
+		for name := range named {
+			keys = append(keys, kind+"/"+escapePointer(name))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// prefixedName prepends a namespace to a component name, as in "CommonError"
+func prefixedName(prefix, name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return exportedName(prefix) + string(unicode.ToUpper(first)) + name[size:]
+}
+
+// hashedName suffixes a component name with a short hash of the component,
+// so the same component always gets the same name
+func hashedName(name string, component interface{}) string {
+	data, _ := json.Marshal(component)
+	sum := sha256.Sum256(canonicalJSON(data))
+	return name + "_" + hex.EncodeToString(sum[:])[:8]
+}
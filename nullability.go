@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Nullability is how a property may be absent or null
+type Nullability string
+
+const (
+	// NullabilityRequired properties are always present and never null
+	NullabilityRequired Nullability = "required"
+	// NullabilityRequiredNullable properties are always present but may be null
+	NullabilityRequiredNullable Nullability = "required-nullable"
+	// NullabilityOptional properties may be absent but are never null
+	NullabilityOptional Nullability = "optional"
+	// NullabilityOptionalNullable properties may be absent or null
+	NullabilityOptionalNullable Nullability = "optional-nullable"
+)
+
+// NullabilityField is a property and how it may be absent or null
+type NullabilityField struct {
+	// Path is the JSON pointer of the property schema
+	Path        string      `json:"path"`
+	Property    string      `json:"property"`
+	Nullability Nullability `json:"nullability"`
+	// Warning explains why the combination is suspicious, if it is
+	Warning string `json:"warning,omitempty"`
+	// Rule names the rule reporting the warning
+	Rule string `json:"rule,omitempty"`
+}
+
+// NullabilityReport lists the properties of a document's object schemas
+type NullabilityReport struct {
+	Fields []NullabilityField `json:"fields"`
+}
+
+// NullabilityReport classifies every property of the document's object
+// schemas as required or optional and nullable or not, flagging the
+// combinations that commonly cause client bugs: required nullable strings,
+// where clients can't tell null from empty, and optional booleans without a
+// default, where clients must guess what absence means.
+func (d *Document) NullabilityReport() NullabilityReport {
+	var report NullabilityReport
+	d.walkSchemas(func(pointer string, s *Schema) {
+		for _, name := range sortedKeys(s.Properties) {
+			prop := d.resolveSchema(s.Properties[name])
+			if prop == nil {
+				continue
+			}
+			field := NullabilityField{
+				Path:        pointer + "/properties/" + escapePointer(name),
+				Property:    name,
+				Nullability: nullability(slices.Contains(s.Required, name), prop.AllowsNull()),
+			}
+			switch {
+			case field.Nullability == NullabilityRequiredNullable && prop.Type.Is("string"):
+				field.Rule = "nullability/required-nullable-string"
+				field.Warning = "required nullable string: clients can't tell null from an empty string"
+			case !slices.Contains(s.Required, name) && prop.Type.Is("boolean") && prop.Default == nil:
+				field.Rule = "nullability/optional-boolean-default"
+				field.Warning = "optional boolean without a default: absence has no documented meaning"
+			}
+			report.Fields = append(report.Fields, field)
+		}
+	})
+	return report
+}
+
+func nullability(required, nullable bool) Nullability {
+	switch {
+	case required && nullable:
+		return NullabilityRequiredNullable
+	case required:
+		return NullabilityRequired
+	case nullable:
+		return NullabilityOptionalNullable
+	}
+	return NullabilityOptional
+}
+
+// Warnings returns the suspicious fields of the report as validation warnings
+func (r NullabilityReport) Warnings() []ValidationError {
+	var errs []ValidationError
+	for _, field := range r.Fields {
+		if field.Warning != "" {
+			errs = append(errs, ValidationError{Path: field.Path, Message: field.Warning, Severity: SeverityWarning, Rule: field.Rule})
+		}
+	}
+	return errs
+}
+
+// Markdown renders the report as a table of fields
+func (r NullabilityReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString("| Field | Property | Nullability | Warning |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, field := range r.Fields {
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", field.Path, field.Property, field.Nullability, field.Warning)
+	}
+	return b.String()
+}
+
+// NullabilityProfile reports the suspicious fields of the nullability report
+// as warnings, under the rules "nullability/required-nullable-string" and
+// "nullability/optional-boolean-default"
+type NullabilityProfile struct{}
+
+// Name returns "nullability"
+func (NullabilityProfile) Name() string {
+	return "nullability"
+}
+
+// Validate returns the warnings of the document's nullability report
+func (NullabilityProfile) Validate(d *Document) []ValidationError {
+	return d.NullabilityReport().Warnings()
+}
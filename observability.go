@@ -0,0 +1,88 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// UnmatchedRoute is the path label reported for requests that match no operation,
+// keeping metric cardinality bounded
+const UnmatchedRoute = "unmatched"
+
+// OtherMethod is the method label reported for unmatched requests using a
+// method no operation can have, keeping metric cardinality bounded
+const OtherMethod = "OTHER"
+
+// RequestObservation describes a request for instrumentation. Path is the path
+// template rather than the raw URL, so it is safe to use as a metric label.
+type RequestObservation struct {
+	OperationID string
+	Method      string
+	Path        string
+	Status      int
+	Duration    time.Duration
+	// Bytes is the size of the response body
+	Bytes int
+}
+
+// Observer receives instrumentation events. Adapters for Prometheus,
+// OpenTelemetry or other systems implement it; StartRequest may return a
+// context carrying a span that EndRequest later finishes.
+type Observer interface {
+	StartRequest(ctx context.Context, obs RequestObservation) context.Context
+	EndRequest(ctx context.Context, obs RequestObservation)
+}
+
+// ObserverFunc adapts a function to an Observer that only records finished requests
+type ObserverFunc func(obs RequestObservation)
+
+// StartRequest implements Observer
+func (f ObserverFunc) StartRequest(ctx context.Context, _ RequestObservation) context.Context {
+	return ctx
+}
+
+// EndRequest implements Observer
+func (f ObserverFunc) EndRequest(_ context.Context, obs RequestObservation) {
+	f(obs)
+}
+
+// Instrument returns middleware that reports every request to the observer,
+// labeled with the operationId and path template of the matched operation.
+// The matched route is also made available through RouteFromContext.
+func Instrument(doc *Document, observer Observer) func(http.Handler) http.Handler {
+	router := NewRouter(doc)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			match, r := matchRequest(router, r)
+			obs := RequestObservation{Method: observedMethod(r.Method), Path: UnmatchedRoute}
+			if match != nil {
+				obs.OperationID = match.Operation.OperationID
+				obs.Method = match.Method
+				obs.Path = match.Path
+			}
+
+			start := time.Now()
+			ctx := observer.StartRequest(r.Context(), obs)
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			obs.Status = sw.Status()
+			obs.Duration = time.Since(start)
+			obs.Bytes = sw.bytes
+			observer.EndRequest(ctx, obs)
+		})
+	}
+}
+
+// observedMethod returns the upper-cased method if an operation can have it,
+// else OtherMethod
+func observedMethod(method string) string {
+	method = strings.ToUpper(method)
+	if slices.Contains(httpMethods, method) {
+		return method
+	}
+	return OtherMethod
+}
@@ -0,0 +1,202 @@
+package openapitest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nyxstack/openapi"
+)
+
+var coverageMethods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// Coverage records which operations and documented responses of a document
+// are exercised during a test run, reporting "spec coverage" alongside code
+// coverage. Wrap the handler under test with Handler, or the client with
+// Transport, and check the result once the tests ran:
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		if err := coverage.Check(0.8); err != nil {
+//			fmt.Println(err)
+//			code = 1
+//		}
+//		os.Exit(code)
+//	}
+type Coverage struct {
+	doc  *openapi.Document
+	mu   sync.Mutex
+	hits map[string]map[string]int
+}
+
+// NewCoverage creates a coverage recorder for a document
+func NewCoverage(doc *openapi.Document) *Coverage {
+	return &Coverage{doc: doc, hits: make(map[string]map[string]int)}
+}
+
+// Record records a response to a request, matching it against the document.
+// Requests matching no operation and undocumented status codes are ignored.
+func (c *Coverage) Record(method, urlPath string, status int) {
+	match, err := c.doc.Match(method, urlPath)
+	if err != nil || match == nil {
+		return
+	}
+	key, _, ok := match.Operation.ResponseFor(status)
+	if !ok {
+		key = ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	op := match.Method + " " + match.Path
+	if c.hits[op] == nil {
+		c.hits[op] = make(map[string]int)
+	}
+	c.hits[op][key]++
+}
+
+// Handler wraps a handler, recording the status of every response it writes
+func (c *Coverage) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		c.Record(r.Method, r.URL.Path, sw.status)
+	})
+}
+
+// Transport wraps a round tripper, recording the status of every response it
+// receives. A nil base uses http.DefaultTransport.
+func (c *Coverage) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		resp, err := base.RoundTrip(r)
+		if err == nil {
+			c.Record(r.Method, r.URL.Path, resp.StatusCode)
+		}
+		return resp, err
+	})
+}
+
+// CoverageReport summarizes the coverage of a document
+type CoverageReport struct {
+	Operations        int
+	CoveredOperations int
+	Responses         int
+	CoveredResponses  int
+	// Missing lists the responses never exercised, e.g. "GET /pets/{petId} 404"
+	Missing []string
+}
+
+// Ratio returns the share of documented responses exercised, 1 when the
+// document has none
+func (r CoverageReport) Ratio() float64 {
+	if r.Responses == 0 {
+		return 1
+	}
+	return float64(r.CoveredResponses) / float64(r.Responses)
+}
+
+// String summarizes the report on one line
+func (r CoverageReport) String() string {
+	return fmt.Sprintf("spec coverage: %.1f%% of responses (%d/%d), %d/%d operations",
+		r.Ratio()*100, r.CoveredResponses, r.Responses, r.CoveredOperations, r.Operations)
+}
+
+// Report computes the coverage recorded so far
+func (c *Coverage) Report() CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var report CoverageReport
+	paths := make([]string, 0, len(c.doc.Paths))
+	for path := range c.doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := c.doc.Paths[path]
+		for _, method := range coverageMethods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			hits := c.hits[method+" "+path]
+			report.Operations++
+			if len(hits) > 0 {
+				report.CoveredOperations++
+			}
+			codes := make([]string, 0, len(op.Responses))
+			for code := range op.Responses {
+				codes = append(codes, code)
+			}
+			sort.Strings(codes)
+			for _, code := range codes {
+				report.Responses++
+				if hits[code] > 0 {
+					report.CoveredResponses++
+				} else {
+					report.Missing = append(report.Missing, method+" "+path+" "+code)
+				}
+			}
+		}
+	}
+	return report
+}
+
+// Check returns an error listing the missing responses when less than the
+// minimum ratio of documented responses was exercised
+func (c *Coverage) Check(minimum float64) error {
+	report := c.Report()
+	if report.Ratio() >= minimum {
+		return nil
+	}
+	return fmt.Errorf("openapitest: %s, below %.1f%%; not exercised:\n  %s",
+		report, minimum*100, strings.Join(report.Missing, "\n  "))
+}
+
+// Require fails the test when less than the minimum ratio of documented
+// responses was exercised
+func (c *Coverage) Require(t testing.TB, minimum float64) {
+	t.Helper()
+	if err := c.Check(minimum); err != nil {
+		t.Error(err)
+	}
+}
+
+// Warn logs the coverage, and the missing responses when below the minimum
+// ratio, without failing the test
+func (c *Coverage) Warn(t testing.TB, minimum float64) {
+	t.Helper()
+	if err := c.Check(minimum); err != nil {
+		t.Log(err)
+		return
+	}
+	t.Log(c.Report())
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,181 @@
+// Package openapitest provides helpers for testing code that builds OpenAPI documents
+package openapitest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nyxstack/openapi"
+)
+
+// MaxDiffs limits how many differences AssertGolden reports
+var MaxDiffs = 50
+
+// AssertGolden compares a document against the golden JSON file at path and
+// fails the test with a structural diff, listing changed values by JSON pointer,
+// when they differ. Golden files are written in canonical form with sorted keys.
+//
+// Golden files are rewritten instead of compared when the test binary has an
+// -update flag set to true or the UPDATE_GOLDEN environment variable is "1".
+// The flag is not registered by this package; declare it in the test package:
+//
+//	var update = flag.Bool("update", false, "update golden files")
+func AssertGolden(t testing.TB, doc *openapi.Document, path string) {
+	t.Helper()
+
+	got, err := Canonical(doc)
+	if err != nil {
+		t.Fatalf("openapitest: marshaling document: %v", err)
+	}
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("openapitest: creating golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("openapitest: writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("openapitest: reading golden file (run with -update to create it): %v", err)
+	}
+	diffs, err := Diff(want, got)
+	if err != nil {
+		t.Fatalf("openapitest: comparing with golden file: %v", err)
+	}
+	if len(diffs) == 0 {
+		return
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "document differs from %s (run with -update to accept):\n", path)
+	for i, d := range diffs {
+		if i == MaxDiffs {
+			fmt.Fprintf(&msg, "  ... and %d more\n", len(diffs)-i)
+			break
+		}
+		fmt.Fprintf(&msg, "  %s\n", d)
+	}
+	t.Error(msg.String())
+}
+
+// Canonical returns the document as indented JSON with object keys sorted
+func Canonical(doc *openapi.Document) ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	out, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// Diff compares two JSON documents structurally and describes each difference
+// on one line, prefixed with the JSON pointer of the changed value: "+" marks
+// added values, "-" removed values and "~" changed values
+func Diff(want, got []byte) ([]string, error) {
+	var w, g interface{}
+	if err := json.Unmarshal(want, &w); err != nil {
+		return nil, fmt.Errorf("want: %w", err)
+	}
+	if err := json.Unmarshal(got, &g); err != nil {
+		return nil, fmt.Errorf("got: %w", err)
+	}
+	var diffs []string
+	diff("", w, g, &diffs)
+	return diffs, nil
+}
+
+func diff(pointer string, want, got interface{}, diffs *[]string) {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		if g, ok := got.(map[string]interface{}); ok {
+			keys := make(map[string]bool, len(w)+len(g))
+			for k := range w {
+				keys[k] = true
+			}
+			for k := range g {
+				keys[k] = true
+			}
+			sorted := make([]string, 0, len(keys))
+			for k := range keys {
+				sorted = append(sorted, k)
+			}
+			sort.Strings(sorted)
+			for _, k := range sorted {
+				child := pointer + "/" + escape(k)
+				wv, inWant := w[k]
+				gv, inGot := g[k]
+				switch {
+				case !inGot:
+					*diffs = append(*diffs, fmt.Sprintf("- %s: %s", child, summarize(wv)))
+				case !inWant:
+					*diffs = append(*diffs, fmt.Sprintf("+ %s: %s", child, summarize(gv)))
+				default:
+					diff(child, wv, gv, diffs)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if g, ok := got.([]interface{}); ok {
+			for i := 0; i < len(w) || i < len(g); i++ {
+				child := fmt.Sprintf("%s/%d", pointer, i)
+				switch {
+				case i >= len(g):
+					*diffs = append(*diffs, fmt.Sprintf("- %s: %s", child, summarize(w[i])))
+				case i >= len(w):
+					*diffs = append(*diffs, fmt.Sprintf("+ %s: %s", child, summarize(g[i])))
+				default:
+					diff(child, w[i], g[i], diffs)
+				}
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(want, got) {
+		if pointer == "" {
+			pointer = "/"
+		}
+		*diffs = append(*diffs, fmt.Sprintf("~ %s: %s => %s", pointer, summarize(want), summarize(got)))
+	}
+}
+
+// summarize renders a value compactly, shortening large objects and arrays
+func summarize(v interface{}) string {
+	data, _ := json.Marshal(v)
+	if len(data) > 80 {
+		return string(data[:77]) + "..."
+	}
+	return string(data)
+}
+
+func escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func updating() bool {
+	if os.Getenv("UPDATE_GOLDEN") == "1" {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		return f.Value.String() == "true"
+	}
+	return false
+}
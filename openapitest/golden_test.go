@@ -0,0 +1,85 @@
+package openapitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nyxstack/openapi"
+)
+
+func TestDiff(t *testing.T) {
+	want := []byte(`{"info":{"title":"A","version":"1"},"paths":{"/users":{}},"tags":[{"name":"a"}]}`)
+	got := []byte(`{"info":{"title":"B","version":"1"},"paths":{"/pets":{}},"tags":[{"name":"a"},{"name":"b"}]}`)
+
+	diffs, err := Diff(want, got)
+	if err != nil {
+		t.Fatalf("Error diffing: %v", err)
+	}
+
+	expected := []string{
+		`~ /info/title: "A" => "B"`,
+		`+ /paths/~1pets: {}`,
+		`- /paths/~1users: {}`,
+		`+ /tags/1: {"name":"b"}`,
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("Expected %d differences, got %d: %v", len(expected), len(diffs), diffs)
+	}
+	for i := range expected {
+		if diffs[i] != expected[i] {
+			t.Errorf("Expected difference %q, got %q", expected[i], diffs[i])
+		}
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	doc := openapi.NewDocument("Test API", "1.0.0")
+	path := filepath.Join(t.TempDir(), "api.json")
+
+	data, err := Canonical(doc)
+	if err != nil {
+		t.Fatalf("Error canonicalizing: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("Error writing golden file: %v", err)
+	}
+
+	AssertGolden(t, doc, path)
+}
+
+func TestCoverage(t *testing.T) {
+	doc := openapi.NewDocument("Pet API", "1.0.0")
+	doc.AddOperation("/pets", "GET", openapi.NewOperation("listPets", "", "").
+		WithResponse("200", "Pets", openapi.Response{}))
+	doc.AddOperation("/pets/{petId}", "GET", openapi.NewOperation("getPet", "", "").
+		WithResponse("200", "Pet", openapi.Response{}).
+		WithResponse("404", "Not found", openapi.Response{}))
+
+	coverage := NewCoverage(doc)
+	handler := coverage.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pets/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	for _, path := range []string{"/pets", "/pets/missing", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	report := coverage.Report()
+	if report.CoveredOperations != 2 || report.CoveredResponses != 2 || report.Responses != 3 {
+		t.Errorf("Expected 2/2 operations and 2/3 responses covered, got %+v", report)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "GET /pets/{petId} 200" {
+		t.Errorf("Expected the missing getPet 200 response, got %v", report.Missing)
+	}
+	if err := coverage.Check(0.5); err != nil {
+		t.Errorf("Expected coverage above 50%%, got %v", err)
+	}
+	if err := coverage.Check(0.9); err == nil || !strings.Contains(err.Error(), "GET /pets/{petId} 200") {
+		t.Errorf("Expected a coverage error listing the missing response, got %v", err)
+	}
+}
@@ -1,19 +1,42 @@
 package openapi
 
+import (
+	"encoding/json"
+	"strconv"
+)
+
 // Operation represents an operation in OpenAPI
 type Operation struct {
-	Tags         []string              `json:"tags,omitempty"`
-	Summary      string                `json:"summary,omitempty"`
-	Description  string                `json:"description,omitempty"`
-	ExternalDocs *ExternalDocs         `json:"externalDocs,omitempty"`
-	OperationID  string                `json:"operationId,omitempty"`
-	Parameters   []Parameter           `json:"parameters,omitempty"`
-	RequestBody  *RequestBody          `json:"requestBody,omitempty"`
-	Responses    map[string]Response   `json:"responses"`
-	Callbacks    map[string]Callback   `json:"callbacks,omitempty"`
-	Deprecated   bool                  `json:"deprecated,omitempty"`
-	Security     []SecurityRequirement `json:"security,omitempty"`
-	Servers      []Server              `json:"servers,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+	Summary      string                 `json:"summary,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	ExternalDocs *ExternalDocs          `json:"externalDocs,omitempty"`
+	OperationID  string                 `json:"operationId,omitempty"`
+	Parameters   []Parameter            `json:"parameters,omitempty"`
+	RequestBody  *RequestBody           `json:"requestBody,omitempty"`
+	Responses    map[string]Response    `json:"responses"`
+	Callbacks    map[string]Callback    `json:"callbacks,omitempty"`
+	Deprecated   bool                   `json:"deprecated,omitempty"`
+	Security     []SecurityRequirement  `json:"security,omitempty"`
+	Servers      []Server               `json:"servers,omitempty"`
+	Extensions   map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	return marshalWithExtensions(operation(o), o.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	type operation Operation
+	if err := json.Unmarshal(data, (*operation)(o)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	o.Extensions = ext
+	return err
 }
 
 // NewOperation creates a new operation with basic settings
@@ -105,8 +128,12 @@ func (o Operation) WithJSONRequestBody(description string, required bool, schema
 	return o.WithRequestBody(description, required, content)
 }
 
-// WithResponse adds a response to an operation
+// WithResponse adds a response to an operation; the description applies when
+// the response has none
 func (o Operation) WithResponse(code, description string, response Response) Operation {
+	if response.Description == "" && response.Ref == nil {
+		response.Description = description
+	}
 	o.Responses[code] = response
 	return o
 }
@@ -184,3 +211,25 @@ func (o Operation) WithExternalDocs(url, description string) Operation {
 	}
 	return o
 }
+
+// WithExtension sets a specification extension; the name must start with "x-"
+func (o Operation) WithExtension(name string, value interface{}) Operation {
+	if o.Extensions == nil {
+		o.Extensions = make(map[string]interface{})
+	}
+	o.Extensions[name] = value
+	return o
+}
+
+// ResponseFor returns the response documented for an HTTP status code, trying
+// the exact code first, then the range wildcard (e.g. "4XX"), then "default".
+// The returned key is the responses map entry that matched.
+func (o Operation) ResponseFor(status int) (key string, response Response, ok bool) {
+	exact := strconv.Itoa(status)
+	for _, key := range []string{exact, exact[:1] + "XX", exact[:1] + "xx", "default"} {
+		if response, ok := o.Responses[key]; ok {
+			return key, response, true
+		}
+	}
+	return "", Response{}, false
+}
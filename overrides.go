@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// OverrideConflict is a value that both generation and manual editing
+// changed since the base document
+type OverrideConflict struct {
+	// Pointer is the JSON pointer of the value in the document
+	Pointer string
+	// Base, Generated and Override hold the conflicting values; nil when the value is absent
+	Base      interface{}
+	Generated interface{}
+	Override  interface{}
+}
+
+// Error describes the conflict
+func (c OverrideConflict) Error() string {
+	return fmt.Sprintf("openapi: %s changed by both generation and manual overrides", c.Pointer)
+}
+
+// MergeOverrides merges manual edits into a regenerated document. base is the
+// previously generated document, generated the new one, and overrides base
+// with human edits such as descriptions and examples. Values only generation
+// changed are taken from generated, values only overrides changed are taken
+// from overrides, so edits survive regeneration while structural changes are
+// adopted. Objects are merged member by member; arrays and scalars are merged
+// as a whole. A value both sides changed differently is a conflict: the
+// override is kept and the conflict reported.
+func MergeOverrides(base, generated, overrides *Document) (*Document, []OverrideConflict, error) {
+	var trees [3]interface{}
+	for i, doc := range []*Document{base, generated, overrides} {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(data, &trees[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var conflicts []OverrideConflict
+	merged, _ := mergeOverride("", trees[0], trees[1], trees[2], true, true, true, &conflicts)
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &Document{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, nil, err
+	}
+	return result, conflicts, nil
+}
+
+// mergeOverride merges one value three ways; the in* flags tell whether the
+// value is present on each side, and the result is absent when keep is false
+func mergeOverride(pointer string, base, generated, override interface{}, inBase, inGenerated, inOverride bool, conflicts *[]OverrideConflict) (merged interface{}, keep bool) {
+	same := func(a, b interface{}, inA, inB bool) bool {
+		return inA == inB && reflect.DeepEqual(a, b)
+	}
+	switch {
+	case same(base, override, inBase, inOverride):
+		return generated, inGenerated
+	case same(base, generated, inBase, inGenerated), same(generated, override, inGenerated, inOverride):
+		return override, inOverride
+	}
+
+	generatedObject, ok1 := generated.(map[string]interface{})
+	overrideObject, ok2 := override.(map[string]interface{})
+	baseObject, ok3 := base.(map[string]interface{})
+	if ok1 && ok2 && (ok3 || !inBase) {
+		result := make(map[string]interface{})
+		keys := make(map[string]bool)
+		for _, object := range []map[string]interface{}{baseObject, generatedObject, overrideObject} {
+			for key := range object {
+				keys[key] = true
+			}
+		}
+		for _, key := range sortedKeys(keys) {
+			b, inB := baseObject[key]
+			g, inG := generatedObject[key]
+			o, inO := overrideObject[key]
+			if value, ok := mergeOverride(pointer+"/"+escapePointer(key), b, g, o, inB, inG, inO, conflicts); ok {
+				result[key] = value
+			}
+		}
+		return result, true
+	}
+
+	*conflicts = append(*conflicts, OverrideConflict{
+		Pointer:   pointer,
+		Base:      base,
+		Generated: generated,
+		Override:  override,
+	})
+	return override, inOverride
+}
@@ -0,0 +1,91 @@
+package openapi
+
+// Pagination describes how a list operation pages through results so that
+// next/prev/first/last Link objects can be generated for its responses.
+// Each relation value is either a runtime expression (e.g.
+// "$response.body#/meta/next_cursor") or a constant; nil omits the relation.
+type Pagination struct {
+	// Parameter is the query parameter carrying the page position
+	Parameter string
+	Next      interface{}
+	Prev      interface{}
+	First     interface{}
+	Last      interface{}
+}
+
+// CursorPagination creates cursor-based pagination where the next and previous
+// cursors are read from the response with the given runtime expressions
+func CursorPagination(parameter, nextExpression, prevExpression string) Pagination {
+	p := Pagination{Parameter: parameter, Next: nextExpression}
+	if prevExpression != "" {
+		p.Prev = prevExpression
+	}
+	return p
+}
+
+// OffsetPagination creates offset-based pagination. The first page starts at
+// offset 0; next, previous and last offsets are read from the response with
+// the given runtime expressions, and empty expressions omit the relation.
+func OffsetPagination(parameter, nextExpression, prevExpression, lastExpression string) Pagination {
+	p := Pagination{Parameter: parameter, Next: nextExpression, First: 0}
+	if prevExpression != "" {
+		p.Prev = prevExpression
+	}
+	if lastExpression != "" {
+		p.Last = lastExpression
+	}
+	return p
+}
+
+// Links builds the pagination links of an operation. Every link targets the
+// operation itself, sets the pagination parameter and carries over the
+// operation's other path and query parameters from the current request.
+func (p Pagination) Links(op Operation) map[string]Link {
+	relations := []struct {
+		name  string
+		value interface{}
+		text  string
+	}{
+		{"next", p.Next, "Fetch the next page"},
+		{"prev", p.Prev, "Fetch the previous page"},
+		{"first", p.First, "Fetch the first page"},
+		{"last", p.Last, "Fetch the last page"},
+	}
+
+	links := make(map[string]Link)
+	for _, rel := range relations {
+		if rel.value == nil {
+			continue
+		}
+		link := NewLink().
+			WithOperationID(op.OperationID).
+			WithDescription(rel.text).
+			WithParameter(p.Parameter, rel.value)
+		for _, param := range op.Parameters {
+			if param.Name == p.Parameter || (param.In != "query" && param.In != "path") {
+				continue
+			}
+			link = link.WithParameter(param.Name, "$request."+param.In+"."+param.Name)
+		}
+		links[rel.name] = link
+	}
+	return links
+}
+
+// WithPagination adds next/prev/first/last links to the response documented for
+// code, wired back to the same operation. The operation needs an operationId
+// (or the links need an operationRef set afterwards) for the links to resolve.
+func (o Operation) WithPagination(code string, pagination Pagination) Operation {
+	response, ok := o.Responses[code]
+	if !ok {
+		response = NewResponse("Paginated result")
+	}
+	for name, link := range pagination.Links(o) {
+		response = response.WithLink(name, link)
+	}
+	if o.Responses == nil {
+		o.Responses = make(map[string]Response)
+	}
+	o.Responses[code] = response
+	return o
+}
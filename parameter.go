@@ -1,21 +1,54 @@
 package openapi
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 // Parameter represents a parameter in OpenAPI
 type Parameter struct {
-	Ref             string               `json:"$ref,omitempty"`
-	Name            string               `json:"name"`
-	In              string               `json:"in"`
-	Description     string               `json:"description,omitempty"`
-	Required        bool                 `json:"required,omitempty"`
-	Deprecated      bool                 `json:"deprecated,omitempty"`
-	AllowEmptyValue bool                 `json:"allowEmptyValue,omitempty"`
-	Style           string               `json:"style,omitempty"`
-	Explode         *bool                `json:"explode,omitempty"`
-	AllowReserved   bool                 `json:"allowReserved,omitempty"`
-	Schema          *Schema              `json:"schema,omitempty"`
-	Example         interface{}          `json:"example,omitempty"`
-	Examples        map[string]Example   `json:"examples,omitempty"`
-	Content         map[string]MediaType `json:"content,omitempty"`
+	Ref             *Reference             `json:"-"`
+	Name            string                 `json:"name"`
+	In              string                 `json:"in"`
+	Description     string                 `json:"description,omitempty"`
+	Required        bool                   `json:"required,omitempty"`
+	Deprecated      bool                   `json:"deprecated,omitempty"`
+	AllowEmptyValue bool                   `json:"allowEmptyValue,omitempty"`
+	Style           string                 `json:"style,omitempty"`
+	Explode         *bool                  `json:"explode,omitempty"`
+	AllowReserved   bool                   `json:"allowReserved,omitempty"`
+	Schema          *Schema                `json:"schema,omitempty"`
+	Example         interface{}            `json:"example,omitempty"`
+	Examples        map[string]Example     `json:"examples,omitempty"`
+	Content         map[string]MediaType   `json:"content,omitempty"`
+	Extensions      map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification
+// extensions. A reference is marshaled as the Reference Object alone.
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	if p.Ref != nil {
+		return json.Marshal(p.Ref)
+	}
+	type parameter Parameter
+	return marshalWithExtensions(parameter(p), p.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification
+// extensions and decode Reference Objects
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	ref, err := unmarshalReference(data)
+	if err != nil || ref != nil {
+		*p = Parameter{Ref: ref}
+		return err
+	}
+	type parameter Parameter
+	if err := json.Unmarshal(data, (*parameter)(p)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	p.Extensions = ext
+	return err
 }
 
 // NewParameter creates a new parameter
@@ -112,3 +145,55 @@ func (p Parameter) WithExample(example interface{}) Parameter {
 	p.Example = example
 	return p
 }
+
+// WithExtension sets a specification extension; the name must start with "x-"
+func (p Parameter) WithExtension(name string, value interface{}) Parameter {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]interface{})
+	}
+	p.Extensions[name] = value
+	return p
+}
+
+// OperationParameters returns the effective parameters of an operation: path
+// item parameters overridden by operation parameters with the same name and
+// location, with component references resolved
+func (d *Document) OperationParameters(path string, op *Operation) []Parameter {
+	var params []Parameter
+	index := make(map[string]int)
+	add := func(p Parameter) {
+		p = d.resolveParameter(p)
+		key := p.In + ":" + p.Name
+		if i, ok := index[key]; ok {
+			params[i] = p
+			return
+		}
+		index[key] = len(params)
+		params = append(params, p)
+	}
+	if item, ok := d.Paths[path]; ok {
+		for _, p := range item.Parameters {
+			add(p)
+		}
+	}
+	for _, p := range op.Parameters {
+		add(p)
+	}
+	return params
+}
+
+// resolveParameter follows a local parameter component reference
+func (d *Document) resolveParameter(p Parameter) Parameter {
+	for i := 0; p.Ref != nil && i < 16; i++ {
+		name, ok := strings.CutPrefix(p.Ref.String(), "#/components/parameters/")
+		if !ok || d.Components == nil {
+			return p
+		}
+		resolved, ok := d.Components.Parameters[unescapePointer(name)]
+		if !ok {
+			return p
+		}
+		p = resolved
+	}
+	return p
+}
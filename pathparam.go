@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// IDKind is the representation of identifiers
+type IDKind string
+
+const (
+	IDUUID  IDKind = "uuid"
+	IDInt64 IDKind = "int64"
+	// IDString identifiers are opaque strings, e.g. order numbers or slugs
+	IDString IDKind = "string"
+)
+
+// IDPolicy centralizes how identifiers are represented, so that the same
+// {userId} has the same schema on every path
+type IDPolicy struct {
+	// Default is the kind of identifiers; defaults to IDUUID
+	Default IDKind
+	// Overrides sets the kind of specific parameters, e.g. {"orderNumber": IDString}
+	Overrides map[string]IDKind
+}
+
+// Kind returns the kind of the identifier parameter with the given name
+func (p IDPolicy) Kind(name string) IDKind {
+	if kind, ok := p.Overrides[name]; ok {
+		return kind
+	}
+	if p.Default == "" {
+		return IDUUID
+	}
+	return p.Default
+}
+
+// Schema returns a new schema for the identifier parameter with the given name
+func (p IDPolicy) Schema(name string) *Schema {
+	switch p.Kind(name) {
+	case IDInt64:
+		return Int64Schema()
+	case IDString:
+		return NewStringSchema()
+	}
+	return UUIDSchema()
+}
+
+// PathParameter returns a required path parameter for the identifier with the given name
+func (p IDPolicy) PathParameter(name, description string) Parameter {
+	return NewPathParameter(name, description, p.Schema(name))
+}
+
+// WithIDPathParameters adds a path parameter following the policy for every
+// variable of the path template the operation doesn't declare yet
+func (o Operation) WithIDPathParameters(path string, policy IDPolicy) Operation {
+	for _, name := range templateVariables(path) {
+		declared := slices.ContainsFunc(o.Parameters, func(p Parameter) bool {
+			return p.In == "path" && p.Name == name
+		})
+		if !declared {
+			o = o.WithParameter(policy.PathParameter(name, ""))
+		}
+	}
+	return o
+}
+
+// isIDName reports whether a parameter name denotes an identifier, e.g.
+// "id", "userId", "user_id" or "userID"
+func isIDName(name string) bool {
+	return strings.EqualFold(name, "id") || strings.HasSuffix(name, "Id") ||
+		strings.HasSuffix(name, "ID") || strings.HasSuffix(name, "_id")
+}
+
+// ApplyIDPolicy coerces the schemas of the identifier path parameters of path
+// items, operations and components, named like "id", "userId" or "user_id"
+// or listed in the policy overrides, to the policy. Descriptions are kept.
+func (d *Document) ApplyIDPolicy(policy IDPolicy) *Document {
+	if !d.mutable("ApplyIDPolicy") {
+		return d
+	}
+	coerce := func(params []Parameter) []Parameter {
+		params = slices.Clone(params)
+		for i, p := range params {
+			if p.In != "path" || p.Ref != nil {
+				continue
+			}
+			if _, ok := policy.Overrides[p.Name]; ok || isIDName(p.Name) {
+				params[i].Schema = policy.Schema(p.Name)
+				if p.Schema != nil && p.Schema.Description != "" {
+					params[i].Schema.Description = p.Schema.Description
+				}
+			}
+		}
+		return params
+	}
+	d.ownOperations()
+	for path, item := range d.Paths {
+		item.Parameters = coerce(item.Parameters)
+		d.Paths[path] = item
+	}
+	d.walkOperations(func(_, _ string, op *Operation) {
+		op.Parameters = coerce(op.Parameters)
+	})
+	if d.Components != nil {
+		d.ownComponents()
+		for name, p := range d.Components.Parameters {
+			d.Components.Parameters[name] = coerce([]Parameter{p})[0]
+		}
+	}
+	return d
+}
+
+// PathParameterProfile checks path parameters:
+//
+//   - path-parameters/schema: every path parameter has a schema
+//   - path-parameters/scalar: path parameters aren't objects or arrays
+//   - path-parameters/consistent: parameters with the same name have the
+//     same type and format on every path
+type PathParameterProfile struct{}
+
+// Name returns "path-parameters"
+func (PathParameterProfile) Name() string {
+	return "path-parameters"
+}
+
+// Validate checks the path parameters of the document
+func (PathParameterProfile) Validate(d *Document) []ValidationError {
+	var errs []ValidationError
+	report := func(rule, pointer, message string) {
+		errs = append(errs, ValidationError{Path: pointer, Message: message, Severity: SeverityError, Rule: rule})
+	}
+	type declaration struct{ pointer, shape string }
+	first := make(map[string]declaration)
+	d.walkParameters(func(pointer string, p Parameter) {
+		// References are checked where their component is declared
+		if p.Ref != nil || p.In != "path" {
+			return
+		}
+		s := d.resolveSchema(p.Schema)
+		if s == nil {
+			report("path-parameters/schema", pointer, fmt.Sprintf("path parameter %q has no schema", p.Name))
+			return
+		}
+		if s.Type.Is("object") || s.Type.Is("array") {
+			report("path-parameters/scalar", pointer+"/schema", fmt.Sprintf("path parameter %q is an %s", p.Name, s.Type))
+		}
+		shape := s.Type.String()
+		if s.Format != "" {
+			shape += " (" + s.Format + ")"
+		}
+		if prev, ok := first[p.Name]; !ok {
+			first[p.Name] = declaration{pointer, shape}
+		} else if prev.shape != shape {
+			report("path-parameters/consistent", pointer+"/schema",
+				fmt.Sprintf("path parameter %q is %s here but %s at %s", p.Name, shape, prev.shape, prev.pointer))
+		}
+	})
+	return errs
+}
@@ -1,18 +1,121 @@
 package openapi
 
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
 // PathItem represents a path item in OpenAPI
 type PathItem struct {
-	Ref         string      `json:"$ref,omitempty"`
-	Summary     string      `json:"summary,omitempty"`
-	Description string      `json:"description,omitempty"`
-	Get         *Operation  `json:"get,omitempty"`
-	Put         *Operation  `json:"put,omitempty"`
-	Post        *Operation  `json:"post,omitempty"`
-	Delete      *Operation  `json:"delete,omitempty"`
-	Options     *Operation  `json:"options,omitempty"`
-	Head        *Operation  `json:"head,omitempty"`
-	Patch       *Operation  `json:"patch,omitempty"`
-	Trace       *Operation  `json:"trace,omitempty"`
-	Servers     []Server    `json:"servers,omitempty"`
-	Parameters  []Parameter `json:"parameters,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Get         *Operation             `json:"get,omitempty"`
+	Put         *Operation             `json:"put,omitempty"`
+	Post        *Operation             `json:"post,omitempty"`
+	Delete      *Operation             `json:"delete,omitempty"`
+	Options     *Operation             `json:"options,omitempty"`
+	Head        *Operation             `json:"head,omitempty"`
+	Patch       *Operation             `json:"patch,omitempty"`
+	Trace       *Operation             `json:"trace,omitempty"`
+	Servers     []Server               `json:"servers,omitempty"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	Extensions  map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (p PathItem) MarshalJSON() ([]byte, error) {
+	type pathItem PathItem
+	return marshalWithExtensions(pathItem(p), p.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	type pathItem PathItem
+	if err := json.Unmarshal(data, (*pathItem)(p)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	p.Extensions = ext
+	return err
+}
+
+// matchPathTemplate matches a request path against a path template such as
+// "/pets/{petId}" and returns the values of the template variables. Variables
+// match a single, non-empty path segment.
+func matchPathTemplate(template, path string) (map[string]string, bool) {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(templateSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	values := make(map[string]string)
+	for i, segment := range templateSegments {
+		if !matchTemplateSegment(segment, pathSegments[i], values) {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// matchTemplateSegment matches one path segment, which may mix literal text and
+// variables (e.g. "{name}.{ext}"), recording variable values
+func matchTemplateSegment(segment, value string, values map[string]string) bool {
+	for segment != "" {
+		start := strings.Index(segment, "{")
+		if start < 0 {
+			return segment == value
+		}
+		if !strings.HasPrefix(value, segment[:start]) {
+			return false
+		}
+		value = value[start:]
+		end := strings.Index(segment, "}")
+		if end < start {
+			return false
+		}
+		name := segment[start+1 : end]
+		segment = segment[end+1:]
+
+		// A variable extends up to the next literal of the template, or to the end
+		next := len(value)
+		if segment != "" {
+			literal := segment
+			if i := strings.Index(literal, "{"); i >= 0 {
+				literal = literal[:i]
+			}
+			if literal != "" {
+				next = strings.Index(value, literal)
+			}
+		}
+		if next <= 0 {
+			return false
+		}
+		decoded, err := url.PathUnescape(value[:next])
+		if err != nil {
+			return false
+		}
+		values[name] = decoded
+		value = value[next:]
+	}
+	return value == ""
+}
+
+// templateVariables returns the names of the variables of a path template in order
+func templateVariables(template string) []string {
+	var names []string
+	for {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			return names
+		}
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			return names
+		}
+		names = append(names, template[start+1:start+end])
+		template = template[start+end+1:]
+	}
 }
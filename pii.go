@@ -0,0 +1,149 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExtensionPII is the schema extension marking personal data
+const ExtensionPII = "x-pii"
+
+// PII describes the personal data a schema holds
+type PII struct {
+	// Category is the kind of personal data, e.g. "contact", "financial" or "health"
+	Category string `json:"category"`
+}
+
+// WithPII marks the schema as holding personal data of the given category
+func (s Schema) WithPII(category string) Schema {
+	return s.WithExtension(ExtensionPII, PII{Category: category})
+}
+
+// PII returns the personal data annotation of the schema
+func (s *Schema) PII() (PII, bool) {
+	var pii PII
+	ok := decodeExtension(s.Extensions[ExtensionPII], &pii)
+	return pii, ok
+}
+
+// DataMapField is a personal data field and the operations handling it
+type DataMapField struct {
+	// Field locates the field: the component schema or, for inline schemas,
+	// the operationId, followed by the property path, e.g. "Owner.address.city".
+	// Array items are marked with "[]"; parameters are "<in>.<name>".
+	Field    string `json:"field"`
+	Category string `json:"category"`
+	// Reads lists the operations returning the field as "METHOD path"
+	Reads []string `json:"reads,omitempty"`
+	// Writes lists the operations accepting the field as "METHOD path"
+	Writes []string `json:"writes,omitempty"`
+}
+
+// DataMap lists the personal data fields of a document
+type DataMap struct {
+	Fields []DataMapField `json:"fields"`
+}
+
+// DataMap lists every field annotated with WithPII along with the operations
+// reading it, through their responses, and writing it, through their
+// parameters and request bodies. Fields are ordered by name.
+func (d *Document) DataMap() DataMap {
+	fields := make(map[string]*DataMapField)
+	record := func(field string, pii PII, operation string, write bool) {
+		entry, ok := fields[field]
+		if !ok {
+			entry = &DataMapField{Field: field, Category: pii.Category}
+			fields[field] = entry
+		}
+		list := &entry.Reads
+		if write {
+			list = &entry.Writes
+		}
+		if len(*list) == 0 || (*list)[len(*list)-1] != operation {
+			*list = append(*list, operation)
+		}
+	}
+
+	d.walkOperations(func(path, method string, op *Operation) {
+		operation := method + " " + path
+		id := op.OperationID
+		if id == "" {
+			id = patternOperationID(method, path)
+		}
+		visit := func(s *Schema, root string, write bool) {
+			d.walkPII(s, root, map[string]bool{}, func(field string, pii PII) {
+				record(field, pii, operation, write)
+			})
+		}
+
+		for _, p := range d.OperationParameters(path, op) {
+			visit(p.Schema, p.In+"."+p.Name, true)
+		}
+		if body := d.resolveRequestBody(op.RequestBody); body != nil {
+			for _, name := range sortedKeys(body.Content) {
+				visit(body.Content[name].Schema, id, true)
+			}
+		}
+		for _, code := range sortedKeys(op.Responses) {
+			response := d.resolveResponse(op.Responses[code])
+			for _, name := range sortedKeys(response.Content) {
+				visit(response.Content[name].Schema, id, false)
+			}
+		}
+	})
+
+	var dataMap DataMap
+	for _, name := range sortedKeys(fields) {
+		entry := fields[name]
+		sort.Strings(entry.Reads)
+		sort.Strings(entry.Writes)
+		dataMap.Fields = append(dataMap.Fields, *entry)
+	}
+	return dataMap
+}
+
+// walkPII calls fn with the annotated fields within a schema. Fields of
+// component schemas are named after the component.
+func (d *Document) walkPII(s *Schema, field string, visiting map[string]bool, fn func(field string, pii PII)) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		name = unescapePointer(name)
+		if !ok || visiting[name] || d.Components == nil {
+			return
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		d.walkPII(d.Components.Schemas[name], name, visiting, fn)
+		return
+	}
+	if pii, ok := s.PII(); ok {
+		fn(field, pii)
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		d.walkPII(s.Properties[name], field+"."+name, visiting, fn)
+	}
+	d.walkPII(s.Items, field+"[]", visiting, fn)
+	if s.AdditionalProperties != nil {
+		d.walkPII(s.AdditionalProperties.Schema, field+".*", visiting, fn)
+	}
+	for _, members := range [][]*Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, member := range members {
+			d.walkPII(member, field, visiting, fn)
+		}
+	}
+}
+
+// Markdown renders the data map as a Markdown table
+func (m DataMap) Markdown() string {
+	var b strings.Builder
+	b.WriteString("| Field | Category | Read by | Written by |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, f := range m.Fields {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", f.Field, f.Category, strings.Join(f.Reads, "<br>"), strings.Join(f.Writes, "<br>"))
+	}
+	return b.String()
+}
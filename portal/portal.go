@@ -0,0 +1,140 @@
+// Package portal publishes documents to developer portals and API catalogs
+// such as SwaggerHub, ReadMe, Stoplight and Backstage. Publishers share a
+// Client that retries transient failures and supports dry runs, so CI jobs
+// can publish without bespoke scripts.
+package portal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nyxstack/openapi"
+)
+
+// Publisher pushes a document to one destination
+type Publisher interface {
+	// Name identifies the destination in logs and errors
+	Name() string
+	// Publish sends the document through the client
+	Publish(ctx context.Context, doc *openapi.Document, client *Client) error
+}
+
+// Client performs the side effects of publishers. HTTP requests failing with
+// network errors, 429 or 5xx responses are retried with exponential backoff.
+// In dry-run mode nothing is sent or written; the client logs what would
+// happen instead.
+type Client struct {
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+	// Retries is the number of retries after a failed attempt
+	Retries int
+	// Backoff is the delay before the first retry, doubled for every next one
+	Backoff time.Duration
+	DryRun  bool
+	// Log receives dry-run and retry messages; nil discards them
+	Log io.Writer
+}
+
+// NewClient creates a client retrying 3 times, starting with a 1s backoff
+func NewClient() *Client {
+	return &Client{Retries: 3, Backoff: time.Second}
+}
+
+// Publish publishes a document with every publisher, continuing after
+// failures, and returns the failures joined
+func Publish(ctx context.Context, doc *openapi.Document, client *Client, publishers ...Publisher) error {
+	var errs []error
+	for _, p := range publishers {
+		if err := p.Publish(ctx, doc, client); err != nil {
+			errs = append(errs, fmt.Errorf("portal: %s: %w", p.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StatusError reports a response with an unexpected status
+type StatusError struct {
+	Status int
+	Body   string
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.Status, e.Body)
+}
+
+// Do sends a request, retrying transient failures, and fails on responses
+// that aren't 2xx. Requests with a body must be replayable (GetBody set, as
+// http.NewRequest does for in-memory bodies). In dry-run mode the request is
+// logged and nil is returned.
+func (c *Client) Do(req *http.Request) error {
+	if c.DryRun {
+		c.logf("dry run: %s %s\n", req.Method, req.URL.Redacted())
+		return nil
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	backoff := c.Backoff
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			req.Body = body
+		}
+		err := send(client, req)
+		var status *StatusError
+		retryable := err != nil && (!errors.As(err, &status) || status.Status == http.StatusTooManyRequests || status.Status >= 500)
+		if !retryable || attempt >= c.Retries || req.Context().Err() != nil {
+			return err
+		}
+		c.logf("retrying %s %s in %s: %v\n", req.Method, req.URL.Redacted(), backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+		backoff *= 2
+	}
+}
+
+// WriteFile writes a file, creating its directory, or logs it in dry-run mode
+func (c *Client) WriteFile(path string, data []byte) error {
+	if c.DryRun {
+		c.logf("dry run: write %s (%d bytes)\n", path, len(data))
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{Status: resp.StatusCode, Body: string(body)}
+	}
+	return nil
+}
+
+func (c *Client) logf(format string, args ...interface{}) {
+	if c.Log != nil {
+		fmt.Fprintf(c.Log, format, args...)
+	}
+}
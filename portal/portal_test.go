@@ -0,0 +1,83 @@
+package portal
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nyxstack/openapi"
+)
+
+func TestSwaggerHubRetries(t *testing.T) {
+	attempts := 0
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+		if r.Header.Get("Authorization") != "key" || r.URL.Query().Get("version") != "1.0.0" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	doc := openapi.NewDocument("Pet API", "1.0.0")
+	client := &Client{Retries: 2}
+	err := Publish(context.Background(), doc, client, SwaggerHub{BaseURL: server.URL, APIKey: "key", Owner: "acme", API: "pets"})
+	if err != nil {
+		t.Fatalf("Error publishing: %v", err)
+	}
+	if attempts != 2 || !bytes.Contains(body, []byte(`"Pet API"`)) {
+		t.Errorf("Expected the document to be sent on the second attempt, got %d attempts: %s", attempts, body)
+	}
+}
+
+func TestPublishErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := Publish(context.Background(), openapi.NewDocument("Pet API", "1.0.0"), NewClient(), ReadMe{BaseURL: server.URL})
+	if err == nil || !strings.Contains(err.Error(), "readme") || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected unretried 401 error from readme, got %v", err)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	var log bytes.Buffer
+	client := &Client{DryRun: true, Log: &log}
+	doc := openapi.NewDocument("Pet API", "1.0.0")
+
+	err := Publish(context.Background(), doc, client,
+		SwaggerHub{BaseURL: "http://swaggerhub.invalid", Owner: "acme", API: "pets"},
+		Backstage{Path: filepath.Join(dir, "catalog-info.yaml"), Metadata: openapi.BackstageMetadata{Owner: "team-pets"}})
+	if err != nil {
+		t.Fatalf("Error in dry run: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "catalog-info.yaml")); !os.IsNotExist(err) {
+		t.Error("Expected dry run not to write files")
+	}
+	if !strings.Contains(log.String(), "POST http://swaggerhub.invalid/apis/acme/pets") || !strings.Contains(log.String(), "catalog-info.yaml") {
+		t.Errorf("Expected dry run to log the request and the file, got:\n%s", log.String())
+	}
+
+	client.DryRun = false
+	if err := Publish(context.Background(), doc, client, Backstage{Path: filepath.Join(dir, "catalog-info.yaml"), Metadata: openapi.BackstageMetadata{Owner: "team-pets"}}); err != nil {
+		t.Fatalf("Error writing entity: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "catalog-info.yaml"))
+	if !strings.Contains(string(data), "kind: API") || !strings.Contains(string(data), "name: pet-api") {
+		t.Errorf("Unexpected entity:\n%s", data)
+	}
+}
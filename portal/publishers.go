@@ -0,0 +1,172 @@
+package portal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nyxstack/openapi"
+)
+
+// SwaggerHub publishes documents to SwaggerHub through its registry API
+type SwaggerHub struct {
+	// BaseURL defaults to https://api.swaggerhub.com, override it for on-premise instances
+	BaseURL string
+	APIKey  string
+	Owner   string
+	API     string
+	// Version defaults to the document version
+	Version string
+	Private bool
+	// Force overwrites an existing published version
+	Force bool
+}
+
+// Name implements Publisher
+func (s SwaggerHub) Name() string {
+	return "swaggerhub"
+}
+
+// Publish implements Publisher
+func (s SwaggerHub) Publish(ctx context.Context, doc *openapi.Document, client *Client) error {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	base := s.BaseURL
+	if base == "" {
+		base = "https://api.swaggerhub.com"
+	}
+	version := s.Version
+	if version == "" {
+		version = doc.Info.Version
+	}
+	query := url.Values{
+		"version":   {version},
+		"isPrivate": {strconv.FormatBool(s.Private)},
+		"force":     {strconv.FormatBool(s.Force)},
+	}
+	endpoint := strings.TrimSuffix(base, "/") + "/apis/" + url.PathEscape(s.Owner) + "/" + url.PathEscape(s.API) + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", s.APIKey)
+	return client.Do(req)
+}
+
+// ReadMe publishes documents to ReadMe through its API specification endpoint
+type ReadMe struct {
+	// BaseURL defaults to https://dash.readme.com/api/v1
+	BaseURL string
+	APIKey  string
+	// ID is the API specification to update; a new one is created when empty
+	ID string
+	// Version is the ReadMe project version to publish to; empty means the main one
+	Version string
+}
+
+// Name implements Publisher
+func (r ReadMe) Name() string {
+	return "readme"
+}
+
+// Publish implements Publisher
+func (r ReadMe) Publish(ctx context.Context, doc *openapi.Document, client *Client) error {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("spec", "openapi.json")
+	if err != nil {
+		return err
+	}
+	part.Write(spec)
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	base := r.BaseURL
+	if base == "" {
+		base = "https://dash.readme.com/api/v1"
+	}
+	method, endpoint := http.MethodPost, strings.TrimSuffix(base, "/")+"/api-specification"
+	if r.ID != "" {
+		method, endpoint = http.MethodPut, endpoint+"/"+url.PathEscape(r.ID)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.SetBasicAuth(r.APIKey, "")
+	if r.Version != "" {
+		req.Header.Set("x-readme-version", r.Version)
+	}
+	return client.Do(req)
+}
+
+// Stoplight publishes documents to a Git-synced Stoplight project by writing
+// them into the project's working tree; Stoplight picks them up once the
+// change is committed and pushed, which CI does after publishing
+type Stoplight struct {
+	// Dir is the root of the project's working tree
+	Dir string
+	// File is the path of the document in the project; defaults to
+	// "reference/openapi.json"
+	File string
+}
+
+// Name implements Publisher
+func (s Stoplight) Name() string {
+	return "stoplight"
+}
+
+// Publish implements Publisher
+func (s Stoplight) Publish(ctx context.Context, doc *openapi.Document, client *Client) error {
+	file := s.File
+	if file == "" {
+		file = "reference/openapi.json"
+	}
+	data, err := doc.ToJSON()
+	if err != nil {
+		return err
+	}
+	return client.WriteFile(filepath.Join(s.Dir, file), append(data, '\n'))
+}
+
+// Backstage registers documents in a Backstage software catalog by writing
+// their catalog-info.yaml API entity (see Document.ToBackstageAPIEntity), for
+// catalogs that discover entities from repositories
+type Backstage struct {
+	// Path of the entity file; defaults to "catalog-info.yaml"
+	Path     string
+	Metadata openapi.BackstageMetadata
+}
+
+// Name implements Publisher
+func (b Backstage) Name() string {
+	return "backstage"
+}
+
+// Publish implements Publisher
+func (b Backstage) Publish(ctx context.Context, doc *openapi.Document, client *Client) error {
+	path := b.Path
+	if path == "" {
+		path = "catalog-info.yaml"
+	}
+	data, err := doc.ToBackstageAPIEntity(b.Metadata)
+	if err != nil {
+		return err
+	}
+	return client.WriteFile(path, data)
+}
@@ -0,0 +1,203 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalPreserving renders the document in the format and style of the
+// source it was loaded from, so that version control diffs show only real
+// changes. JSON sources keep their indentation; YAML sources keep their
+// indentation, flow and block styles, quoting and comments. In both, members
+// keep their original order, new members follow in the usual order, and
+// numbers equal to the original keep their original spelling.
+func (d *Document) MarshalPreserving(original []byte) ([]byte, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var node, source yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(original, &source); err != nil {
+		return nil, err
+	}
+
+	trimmed := bytes.TrimSpace(original)
+	isJSON := len(trimmed) > 0 && trimmed[0] == '{'
+	if !isJSON {
+		blockStyle(&node)
+	}
+	preserveStyle(&node, &source, !isJSON)
+	indent := sourceIndent(original)
+
+	var out []byte
+	if isJSON {
+		var compact bytes.Buffer
+		if err := writeJSONNode(&compact, &node); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, compact.Bytes(), "", indent); err != nil {
+			return nil, err
+		}
+		out = buf.Bytes()
+	} else {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(max(len(indent), 2))
+		if err := enc.Encode(&node); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		out = buf.Bytes()
+	}
+
+	out = bytes.TrimRight(out, "\n")
+	if bytes.HasSuffix(original, []byte("\n")) {
+		out = append(out, '\n')
+	}
+	return out, nil
+}
+
+// preserveStyle reorders the mapping keys of node after those of source and,
+// for YAML, copies the styles and comments of the matching source nodes
+func preserveStyle(node, source *yaml.Node, styles bool) {
+	if node.Kind != source.Kind {
+		return
+	}
+	if styles {
+		node.HeadComment = source.HeadComment
+		node.LineComment = source.LineComment
+		node.FootComment = source.FootComment
+		if node.Kind != yaml.ScalarNode || !yaml11Booleans[node.Value] || source.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+			node.Style = source.Style
+		}
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for i := 0; i < len(node.Content) && i < len(source.Content); i++ {
+			preserveStyle(node.Content[i], source.Content[i], styles)
+		}
+	case yaml.MappingNode:
+		pairs := make(map[string][2]*yaml.Node, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			pairs[node.Content[i].Value] = [2]*yaml.Node{node.Content[i], node.Content[i+1]}
+		}
+		content := make([]*yaml.Node, 0, len(node.Content))
+		for i := 0; i+1 < len(source.Content); i += 2 {
+			pair, ok := pairs[source.Content[i].Value]
+			if !ok {
+				continue
+			}
+			delete(pairs, source.Content[i].Value)
+			preserveStyle(pair[0], source.Content[i], styles)
+			preserveStyle(pair[1], source.Content[i+1], styles)
+			content = append(content, pair[0], pair[1])
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if _, added := pairs[node.Content[i].Value]; added {
+				content = append(content, node.Content[i], node.Content[i+1])
+			}
+		}
+		node.Content = content
+	case yaml.ScalarNode:
+		if isNumberTag(node.Tag) && isNumberTag(source.Tag) {
+			a, errA := strconv.ParseFloat(node.Value, 64)
+			b, errB := strconv.ParseFloat(source.Value, 64)
+			if errA == nil && errB == nil && a == b {
+				node.Value = source.Value
+				node.Tag = source.Tag
+			}
+		}
+	}
+}
+
+func isNumberTag(tag string) bool {
+	return tag == "!!int" || tag == "!!float"
+}
+
+// sourceIndent returns the smallest indentation of the source, defaulting to two spaces
+func sourceIndent(data []byte) string {
+	indent := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		content := strings.TrimLeft(line, " \t")
+		if content == "" || content == line || strings.HasPrefix(content, "#") {
+			continue
+		}
+		if whitespace := line[:len(line)-len(content)]; indent == "" || len(whitespace) < len(indent) {
+			indent = whitespace
+		}
+	}
+	if indent == "" {
+		return "  "
+	}
+	return indent
+}
+
+// writeJSONNode writes a node parsed from JSON back as compact JSON, in node order
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return writeJSONNode(buf, node.Content[0])
+	case yaml.MappingNode, yaml.SequenceNode:
+		open, close := byte('['), byte(']')
+		if node.Kind == yaml.MappingNode {
+			open, close = '{', '}'
+		}
+		buf.WriteByte(open)
+		for i, child := range node.Content {
+			if i > 0 {
+				if node.Kind == yaml.MappingNode && i%2 == 1 {
+					buf.WriteByte(':')
+				} else {
+					buf.WriteByte(',')
+				}
+			}
+			if err := writeJSONNode(buf, child); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(close)
+	default:
+		if node.Tag != "!!str" {
+			buf.WriteString(node.Value)
+			return nil
+		}
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(node.Value); err != nil {
+			return err
+		}
+		buf.Truncate(buf.Len() - 1)
+	}
+	return nil
+}
+
+// yaml11Booleans are plain scalars that YAML 1.1 parsers read as booleans
+var yaml11Booleans = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true,
+	"n": true, "N": true, "no": true, "No": true, "NO": true,
+	"on": true, "On": true, "ON": true, "off": true, "Off": true, "OFF": true,
+}
+
+// blockStyle clears the flow and quoting styles inherited from the JSON source,
+// letting the encoder pick idiomatic YAML styles. Strings that YAML 1.1 tools
+// would misread as booleans stay quoted.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && yaml11Booleans[node.Value] {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
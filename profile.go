@@ -0,0 +1,28 @@
+package openapi
+
+// Profile imposes domain-specific rules on documents on top of the
+// specification, such as the naming conventions, mandatory headers and error
+// formats of an industry interoperability guide. Implementations set the Rule
+// of their findings so that reports can tell which rule failed.
+type Profile interface {
+	// Name identifies the profile, e.g. "fhir"
+	Name() string
+	// Validate returns the findings of the profile's rules
+	Validate(d *Document) []ValidationError
+}
+
+// ValidateProfiles checks the document against profiles, returning their
+// findings in profile order. Rules without a name are reported under the
+// profile name.
+func (d *Document) ValidateProfiles(profiles ...Profile) []ValidationError {
+	var errs []ValidationError
+	for _, profile := range profiles {
+		for _, err := range profile.Validate(d) {
+			if err.Rule == "" {
+				err.Rule = profile.Name()
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
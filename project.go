@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Selector selects the parts of a document Project keeps. Empty fields keep
+// everything they select from.
+type Selector struct {
+	// Members lists the top-level members of the document kept besides
+	// openapi and info, such as "paths", "components" or "tags"
+	Members []string
+	// Methods keeps only the operations and webhooks of these HTTP methods,
+	// as "GET"
+	Methods []string
+	// Components lists the kinds of components kept, such as "schemas"
+	Components []string
+	// DropExamples removes every example and examples
+	DropExamples bool
+}
+
+// documentMembers are the top-level members a Selector may keep
+var documentMembers = []string{"jsonSchemaDialect", "servers", "paths", "webhooks", "components", "security", "tags", "externalDocs"}
+
+// componentKinds are the kinds of components a Selector may keep
+var componentKinds = []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "securitySchemes", "links", "callbacks"}
+
+// Project returns a reduced copy of the document with only the parts the
+// selector keeps, for consumers that need a fraction of it, such as SDK
+// generators for a single platform. Components only the left out operations
+// referenced are left out too; references into left out members or kinds of
+// components are kept as they are. The document itself is left untouched.
+func (d *Document) Project(selector Selector) (*Document, error) {
+	for _, member := range selector.Members {
+		if !slices.Contains(documentMembers, member) && !isExtensionKey(member) {
+			return nil, fmt.Errorf("openapi: projecting document: unknown member %q", member)
+		}
+	}
+	for _, kind := range selector.Components {
+		if !slices.Contains(componentKinds, kind) {
+			return nil, fmt.Errorf("openapi: projecting document: unknown component kind %q", kind)
+		}
+	}
+
+	projected, err := d.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if len(selector.Methods) > 0 {
+		before := projected.referencedComponents()
+		keep := func(_, method string, _ *Operation) bool {
+			return slices.ContainsFunc(selector.Methods, func(m string) bool { return strings.EqualFold(m, method) })
+		}
+		filterOperations(projected.Paths, keep)
+		filterOperations(projected.Webhooks, keep)
+		after := projected.referencedComponents()
+		for key := range before {
+			if !after[key] {
+				projected.removeComponent(key)
+			}
+		}
+	}
+
+	data, err := json.Marshal(projected)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	if len(selector.Members) > 0 {
+		for member := range tree {
+			if member != "openapi" && member != "info" && !slices.Contains(selector.Members, member) {
+				delete(tree, member)
+			}
+		}
+	}
+	if components := objectAt(tree, "components"); components != nil && len(selector.Components) > 0 {
+		for kind := range components {
+			if !slices.Contains(selector.Components, kind) && !isExtensionKey(kind) {
+				delete(components, kind)
+			}
+		}
+	}
+	if selector.DropExamples {
+		walkObjects("", tree, func(pointer string, object map[string]interface{}) {
+			if !namesMembers(pointer) {
+				delete(object, "example")
+				delete(object, "examples")
+			}
+		})
+	}
+
+	if data, err = json.Marshal(tree); err != nil {
+		return nil, err
+	}
+	return FromJSON(data)
+}
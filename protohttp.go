@@ -0,0 +1,225 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// HTTPRule is the google.api.http binding of an operation, the annotation
+// that maps a gRPC or Connect method onto a REST route
+type HTTPRule struct {
+	Path   string `json:"path"`
+	Method string `json:"method"`
+	// RPC is the method name, derived from the operationId
+	RPC string `json:"rpc"`
+	// Pattern is the annotation field: get, put, post, delete, patch or custom
+	Pattern string `json:"pattern"`
+	// Template is the route with path variables renamed to proto field names
+	Template string `json:"template"`
+	// Body is "body" when the operation has a request body
+	Body string `json:"body,omitempty"`
+	// Fields maps proto field names to the parameters they carry, "in:name"
+	Fields map[string]string `json:"fields,omitempty"`
+	// Notes lists what doesn't translate to the annotation
+	Notes []string `json:"notes,omitempty"`
+}
+
+// ProtoOptions configures ToProto
+type ProtoOptions struct {
+	// Package is the proto package; defaults to the sanitized document title
+	Package string
+	// Service is the service name; defaults to the document title in CamelCase
+	Service string
+	// GoPackage sets the go_package option when not empty
+	GoPackage string
+}
+
+var protoIdentifier = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// HTTPRules maps every operation to a google.api.http binding, ordered by path
+// and method. Path variables and query parameters become snake_case fields of
+// the request message; the request body is bound to a "body" field.
+// Operations whose shape has no annotation equivalent, such as header or
+// cookie parameters, carry notes explaining what was left out.
+func (d *Document) HTTPRules() []HTTPRule {
+	var rules []HTTPRule
+	d.walkOperations(func(path, method string, op *Operation) {
+		id := op.OperationID
+		if id == "" {
+			id = patternOperationID(method, path)
+		}
+		rule := HTTPRule{
+			Path:     path,
+			Method:   method,
+			RPC:      exportedName(id),
+			Pattern:  strings.ToLower(method),
+			Template: path,
+			Fields:   make(map[string]string),
+		}
+		switch method {
+		case "HEAD", "OPTIONS", "TRACE":
+			rule.Pattern = "custom"
+		}
+		for _, p := range d.OperationParameters(path, op) {
+			field := protoFieldName(p.Name)
+			switch p.In {
+			case "path":
+				rule.Template = strings.ReplaceAll(rule.Template, "{"+p.Name+"}", "{"+field+"}")
+			case "query":
+			default:
+				rule.Notes = append(rule.Notes, fmt.Sprintf("%s parameter %q has no annotation equivalent; read it from the request metadata", p.In, p.Name))
+				continue
+			}
+			rule.Fields[field] = p.In + ":" + p.Name
+		}
+		if op.RequestBody != nil {
+			rule.Body = "body"
+		}
+		rules = append(rules, rule)
+	})
+	return rules
+}
+
+// ToProto renders a proto3 file declaring a service with one method per
+// operation, each annotated with its google.api.http binding. Request
+// messages hold the path and query parameters and the request body; bodies
+// and responses are google.protobuf.Struct or google.protobuf.Empty, stubs to
+// be replaced by proper messages when migrating to a proto-first workflow.
+func (d *Document) ToProto(opts ProtoOptions) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = strings.Trim(protoIdentifier.ReplaceAllString(strings.ToLower(d.Info.Title), "_"), "_")
+	}
+	if opts.Service == "" {
+		opts.Service = exportedName(d.Info.Title)
+	}
+	rules := d.HTTPRules()
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if seen[rule.RPC] {
+			return nil, fmt.Errorf("openapi: operations %s %s and another one both map to rpc %s", rule.Method, rule.Path, rule.RPC)
+		}
+		seen[rule.RPC] = true
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&buf, "package %s;\n\n", opts.Package)
+	buf.WriteString("import \"google/api/annotations.proto\";\n")
+	buf.WriteString("import \"google/protobuf/empty.proto\";\n")
+	buf.WriteString("import \"google/protobuf/struct.proto\";\n")
+	if opts.GoPackage != "" {
+		fmt.Fprintf(&buf, "\noption go_package = %q;\n", opts.GoPackage)
+	}
+
+	fmt.Fprintf(&buf, "\nservice %s {\n", opts.Service)
+	for _, rule := range rules {
+		path, method := rule.Path, rule.Method
+		item := d.Paths[path]
+		op := item.Operation(method)
+		if op.Summary != "" {
+			fmt.Fprintf(&buf, "  // %s\n", op.Summary)
+		}
+		for _, note := range rule.Notes {
+			fmt.Fprintf(&buf, "  // Note: %s\n", note)
+		}
+		fmt.Fprintf(&buf, "  rpc %s(%sRequest) returns (%s) {\n", rule.RPC, rule.RPC, d.protoResponseType(op))
+		buf.WriteString("    option (google.api.http) = {\n")
+		if rule.Pattern == "custom" {
+			fmt.Fprintf(&buf, "      custom: { kind: %q path: %q }\n", method, rule.Template)
+		} else {
+			fmt.Fprintf(&buf, "      %s: %q\n", rule.Pattern, rule.Template)
+		}
+		if rule.Body != "" {
+			fmt.Fprintf(&buf, "      body: %q\n", rule.Body)
+		}
+		buf.WriteString("    };\n  }\n")
+	}
+	buf.WriteString("}\n")
+
+	for _, rule := range rules {
+		item := d.Paths[rule.Path]
+		params := d.OperationParameters(rule.Path, item.Operation(rule.Method))
+		fmt.Fprintf(&buf, "\nmessage %sRequest {\n", rule.RPC)
+		number := 1
+		for _, p := range params {
+			field := protoFieldName(p.Name)
+			if _, ok := rule.Fields[field]; !ok {
+				continue
+			}
+			fmt.Fprintf(&buf, "  %s %s = %d;\n", d.protoType(p.Schema), field, number)
+			number++
+		}
+		if rule.Body != "" {
+			fmt.Fprintf(&buf, "  google.protobuf.Struct body = %d;\n", number)
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// protoResponseType is Empty for operations whose success responses have no content
+func (d *Document) protoResponseType(op *Operation) string {
+	for code, response := range op.Responses {
+		if strings.HasPrefix(code, "2") && len(d.resolveResponse(response).Content) > 0 {
+			return "google.protobuf.Struct"
+		}
+	}
+	return "google.protobuf.Empty"
+}
+
+// protoType maps a parameter schema to a proto3 scalar or repeated scalar type
+func (d *Document) protoType(s *Schema) string {
+	s = d.resolveSchema(s)
+	if s == nil {
+		return "string"
+	}
+	switch s.Type.Primary() {
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float"
+		}
+		return "double"
+	case "boolean":
+		return "bool"
+	case "array":
+		// Proto has no nested repeated fields, so inner arrays stay serialized
+		if item := d.protoType(s.Items); !strings.HasPrefix(item, "repeated ") {
+			return "repeated " + item
+		}
+		return "repeated string"
+	}
+	return "string"
+}
+
+// protoFieldName converts a parameter name such as "petId" or "X-Request-ID"
+// to a snake_case proto field name
+func protoFieldName(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteRune('_')
+		}
+	}
+	field := strings.Trim(b.String(), "_")
+	if field == "" || unicode.IsDigit(rune(field[0])) {
+		field = "field_" + field
+	}
+	return field
+}
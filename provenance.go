@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// ExtensionBuildInfo is the document extension carrying build provenance
+const ExtensionBuildInfo = "x-build-info"
+
+// BuildInfo records which build produced a document
+type BuildInfo struct {
+	GitSHA           string    `json:"gitSha,omitempty"`
+	BuildTime        time.Time `json:"buildTime,omitzero"`
+	GeneratorVersion string    `json:"generatorVersion,omitempty"`
+	// Builder identifies the CI system or host that ran the build
+	Builder string `json:"builder,omitempty"`
+}
+
+// RuntimeBuildInfo collects provenance from the running binary: the VCS
+// revision and commit time stamped by the Go toolchain, and the version of this
+// package as the generator version
+func RuntimeBuildInfo() BuildInfo {
+	var info BuildInfo
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.GitSHA = setting.Value
+		case "vcs.time":
+			info.BuildTime, _ = time.Parse(time.RFC3339, setting.Value)
+		}
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == "github.com/nyxstack/openapi" {
+			info.GeneratorVersion = dep.Version
+		}
+	}
+	return info
+}
+
+// WithBuildInfo embeds build provenance in the x-build-info extension
+func (d *Document) WithBuildInfo(info BuildInfo) *Document {
+	return d.WithExtension(ExtensionBuildInfo, info)
+}
+
+// BuildInfo returns the provenance embedded in the document
+func (d *Document) BuildInfo() (BuildInfo, bool) {
+	var info BuildInfo
+	ok := decodeExtension(d.Extensions[ExtensionBuildInfo], &info)
+	return info, ok
+}
+
+// DetachedSignature is published alongside a document so consumers can verify
+// which build produced it and that it wasn't altered
+type DetachedSignature struct {
+	// Digest is the SHA-256 digest of the published bytes, as "sha256:<hex>"
+	Digest string `json:"digest"`
+	// Algorithm is "ed25519", "ecdsa-sha256" or "rsa-sha256" when signed
+	Algorithm string `json:"algorithm,omitempty"`
+	// Signature is the base64 encoded signature over the published bytes
+	Signature string     `json:"signature,omitempty"`
+	BuildInfo *BuildInfo `json:"buildInfo,omitempty"`
+}
+
+// ToSignedJSON marshals the document like ToJSON and produces a detached
+// signature file for the exact bytes returned. With a nil signer only the
+// digest is recorded. Ed25519, ECDSA and RSA (PKCS #1 v1.5) signers are supported.
+func (d *Document) ToSignedJSON(signer crypto.Signer) (spec []byte, signature []byte, err error) {
+	spec, err = d.ToJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	digest := sha256.Sum256(spec)
+	sig := DetachedSignature{Digest: "sha256:" + hex.EncodeToString(digest[:])}
+	if info, ok := d.BuildInfo(); ok {
+		sig.BuildInfo = &info
+	}
+
+	if signer != nil {
+		var raw []byte
+		switch signer.Public().(type) {
+		case ed25519.PublicKey:
+			sig.Algorithm = "ed25519"
+			raw, err = signer.Sign(rand.Reader, spec, crypto.Hash(0))
+		case *ecdsa.PublicKey:
+			sig.Algorithm = "ecdsa-sha256"
+			raw, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		case *rsa.PublicKey:
+			sig.Algorithm = "rsa-sha256"
+			raw, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		default:
+			err = fmt.Errorf("openapi: unsupported signer key type %T", signer.Public())
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		sig.Signature = base64.StdEncoding.EncodeToString(raw)
+	}
+
+	signature, err = json.MarshalIndent(sig, "", "  ")
+	return spec, signature, err
+}
+
+// VerifySignedJSON checks published document bytes against a detached
+// signature file. The digest is always checked; the signature is checked with
+// the public key when one is given.
+func VerifySignedJSON(spec, signature []byte, publicKey crypto.PublicKey) error {
+	var sig DetachedSignature
+	if err := json.Unmarshal(signature, &sig); err != nil {
+		return fmt.Errorf("openapi: invalid signature file: %w", err)
+	}
+	digest := sha256.Sum256(spec)
+	if sig.Digest != "sha256:"+hex.EncodeToString(digest[:]) {
+		return errors.New("openapi: document digest mismatch")
+	}
+	if publicKey == nil {
+		return nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil || sig.Signature == "" {
+		return errors.New("openapi: missing or malformed signature")
+	}
+	valid := false
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		valid = sig.Algorithm == "ed25519" && ed25519.Verify(key, spec, raw)
+	case *ecdsa.PublicKey:
+		valid = sig.Algorithm == "ecdsa-sha256" && ecdsa.VerifyASN1(key, digest[:], raw)
+	case *rsa.PublicKey:
+		valid = sig.Algorithm == "rsa-sha256" && rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], raw) == nil
+	default:
+		return fmt.Errorf("openapi: unsupported public key type %T", publicKey)
+	}
+	if !valid {
+		return errors.New("openapi: signature verification failed")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package openapi
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+)
+
+func TestSignedJSON(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	doc := NewDocument("Test API", "1.0.0").
+		WithBuildInfo(BuildInfo{GitSHA: "abc123", BuildTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+
+	spec, signature, err := doc.ToSignedJSON(private)
+	if err != nil {
+		t.Fatalf("Error signing document: %v", err)
+	}
+
+	if err := VerifySignedJSON(spec, signature, public); err != nil {
+		t.Errorf("Expected signature to verify, got %v", err)
+	}
+
+	tampered := append([]byte{}, spec...)
+	tampered[len(tampered)-2] = ' '
+	if err := VerifySignedJSON(tampered, signature, public); err == nil {
+		t.Error("Expected verification of tampered document to fail")
+	}
+
+	info, ok := doc.BuildInfo()
+	if !ok || info.GitSHA != "abc123" {
+		t.Errorf("Expected build info to be embedded, got %+v", info)
+	}
+}
@@ -0,0 +1,150 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// ProxyExchange describes a request forwarded by the verifying proxy
+type ProxyExchange struct {
+	Method string
+	// URLPath is the path of the request
+	URLPath string
+	// Match is the operation the request matched, nil when none did
+	Match *RouteMatch
+	// Status is the status code of the upstream response, or 502 when the
+	// upstream couldn't be reached
+	Status int
+	// RequestErrors and ResponseErrors list the violations of the contract.
+	// Request error paths locate the offending part of the request, e.g.
+	// "/query/limit"; response error paths point into the document.
+	RequestErrors  []ValidationError
+	ResponseErrors []ValidationError
+}
+
+// ProxyOptions configures NewVerifyingProxy
+type ProxyOptions struct {
+	// Logger receives a warning per violation; defaults to slog.Default()
+	Logger *slog.Logger
+	// OnExchange, when set, is called after every forwarded request, with or
+	// without violations, e.g. to build a conformance report
+	OnExchange func(exchange ProxyExchange)
+	// Transport forwards requests; defaults to http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+// proxyStateKey is the context key of the state of a proxied request
+type proxyStateKey struct{}
+
+// NewVerifyingProxy returns a reverse proxy forwarding every request to the
+// upstream while checking requests and responses against the document. Traffic
+// is never altered or blocked: violations are logged with their pointers, so
+// the proxy can certify in staging that an implementation honours its
+// published contract. Requests matching no operation are reported as
+// violations too.
+func NewVerifyingProxy(doc *Document, upstream *url.URL, opts ProxyOptions) http.Handler {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	router := NewRouter(doc)
+	proxy := httputil.NewSingleHostReverseProxy(upstream)
+	proxy.Transport = opts.Transport
+
+	finish := func(exchange *ProxyExchange) {
+		for _, direction := range []string{"request", "response"} {
+			errs := exchange.RequestErrors
+			if direction == "response" {
+				errs = exchange.ResponseErrors
+			}
+			for _, err := range errs {
+				attrs := []any{"direction", direction, "method", exchange.Method, "path", exchange.URLPath, "pointer", err.Path, "message", err.Message, "severity", err.Severity}
+				if exchange.Match != nil {
+					attrs = append(attrs, "operation", operationPointer(exchange.Match.Path, exchange.Match.Method))
+				}
+				opts.Logger.Warn("openapi: contract violation", attrs...)
+			}
+		}
+		if opts.OnExchange != nil {
+			opts.OnExchange(*exchange)
+		}
+	}
+
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		exchange, _ := resp.Request.Context().Value(proxyStateKey{}).(*ProxyExchange)
+		if exchange == nil {
+			return nil
+		}
+		exchange.Status = resp.StatusCode
+		if exchange.Match != nil {
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			exchange.ResponseErrors = doc.checkResponse(exchange.Match.Path, exchange.Match.Method, exchange.Match.Operation, resp.StatusCode, resp.Header, body)
+		}
+		finish(exchange)
+		return nil
+	}
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		opts.Logger.Error("openapi: proxy error", "method", r.Method, "path", r.URL.Path, "error", err)
+		if exchange, _ := r.Context().Value(proxyStateKey{}).(*ProxyExchange); exchange != nil {
+			exchange.Status = http.StatusBadGateway
+			finish(exchange)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchange := &ProxyExchange{Method: r.Method, URLPath: r.URL.Path}
+		match, err := router.Match(r.Method, r.URL.EscapedPath())
+		if err != nil {
+			exchange.RequestErrors = []ValidationError{{Path: "", Message: err.Error(), Severity: SeverityError}}
+		} else {
+			exchange.Match = match
+			var body []byte
+			if r.Body != nil {
+				if body, err = io.ReadAll(r.Body); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				r.Body.Close()
+			}
+			check := r.Clone(r.Context())
+			check.Body = io.NopCloser(bytes.NewReader(body))
+			for name, value := range match.PathParams {
+				check.SetPathValue(name, value)
+			}
+			exchange.RequestErrors = doc.checkRequest(match, check)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r = r.WithContext(context.WithValue(r.Context(), routeContextKey{}, match))
+		}
+		proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), proxyStateKey{}, exchange)))
+	})
+}
+
+// checkRequest validates the parameters and body of a request against the
+// matched operation
+func (d *Document) checkRequest(match *RouteMatch, r *http.Request) []ValidationError {
+	b := &Binder{
+		doc:    d,
+		path:   match.Path,
+		method: match.Method,
+		op:     match.Operation,
+		params: d.OperationParameters(match.Path, match.Operation),
+	}
+	var dst struct {
+		Body json.RawMessage `body:""`
+	}
+	if err, ok := b.Bind(r, &dst).(*RequestError); ok {
+		return err.Errors
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// referencedComponents returns the components reachable from the document
+// outside the components section, following references transitively, keyed
+// by their pointer relative to "#/components/", e.g. "schemas/Pet". Security
+// schemes named by security requirements count as referenced.
+func (d *Document) referencedComponents() map[string]bool {
+	refs := make(map[string]bool)
+	var queue []string
+	add := func(key string) {
+		if !refs[key] {
+			refs[key] = true
+			queue = append(queue, key)
+		}
+	}
+	collect := func(v interface{}) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return
+		}
+		var value interface{}
+		if json.Unmarshal(data, &value) != nil {
+			return
+		}
+		collectRefs(value, func(ref string) {
+			if key, ok := strings.CutPrefix(ref, "#/components/"); ok {
+				add(key)
+			}
+		})
+	}
+	requirements := func(reqs []SecurityRequirement) {
+		for _, req := range reqs {
+			for name := range req {
+				add("securitySchemes/" + escapePointer(name))
+			}
+		}
+	}
+
+	outside := *d
+	outside.Components = nil
+	collect(&outside)
+	requirements(d.Security)
+	d.walkOperations(func(_, _ string, op *Operation) {
+		requirements(op.Security)
+	})
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if component, ok := d.component(key); ok {
+			collect(component)
+		}
+	}
+	return refs
+}
+
+// component returns the component at a pointer relative to "#/components/"
+func (d *Document) component(key string) (interface{}, bool) {
+	kind, name, _ := strings.Cut(key, "/")
+	name = unescapePointer(name)
+	c := d.Components
+	if c == nil {
+		return nil, false
+	}
+	var value interface{}
+	var ok bool
+	switch kind {
+	case "schemas":
+		value, ok = c.Schemas[name]
+	case "responses":
+		value, ok = c.Responses[name]
+	case "parameters":
+		value, ok = c.Parameters[name]
+	case "examples":
+		value, ok = c.Examples[name]
+	case "requestBodies":
+		value, ok = c.RequestBodies[name]
+	case "headers":
+		value, ok = c.Headers[name]
+	case "securitySchemes":
+		value, ok = c.SecuritySchemes[name]
+	case "links":
+		value, ok = c.Links[name]
+	case "callbacks":
+		value, ok = c.Callbacks[name]
+	}
+	return value, ok
+}
+
+// removeComponent deletes the component at a pointer relative to "#/components/"
+func (d *Document) removeComponent(key string) {
+	kind, name, _ := strings.Cut(key, "/")
+	name = unescapePointer(name)
+	c := d.Components
+	if c == nil {
+		return
+	}
+	switch kind {
+	case "schemas":
+		delete(c.Schemas, name)
+	case "responses":
+		delete(c.Responses, name)
+	case "parameters":
+		delete(c.Parameters, name)
+	case "examples":
+		delete(c.Examples, name)
+	case "requestBodies":
+		delete(c.RequestBodies, name)
+	case "headers":
+		delete(c.Headers, name)
+	case "securitySchemes":
+		delete(c.SecuritySchemes, name)
+	case "links":
+		delete(c.Links, name)
+	case "callbacks":
+		delete(c.Callbacks, name)
+	}
+}
+
+// collectRefs calls fn with every "$ref" value in a decoded JSON value
+func collectRefs(value interface{}, fn func(ref string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				fn(ref)
+				continue
+			}
+			collectRefs(child, fn)
+		}
+	case []interface{}:
+		for _, child := range v {
+			collectRefs(child, fn)
+		}
+	}
+}
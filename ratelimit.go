@@ -0,0 +1,207 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExtensionRateLimit is the specification extension holding a rate limit policy
+const ExtensionRateLimit = "x-ratelimit"
+
+// RateLimitPolicy describes how requests are throttled. A policy can be attached
+// to the document, to a tag or to a single operation; the most specific one applies.
+type RateLimitPolicy struct {
+	Name string `json:"name,omitempty"`
+	// Limit is the number of requests allowed per window
+	Limit int `json:"limit"`
+	// Window is the length of the window in seconds
+	Window int `json:"window"`
+	// Scope tells what the limit is counted against, e.g. "api key", "ip" or "user"
+	Scope string `json:"scope,omitempty"`
+	// Burst is the number of requests allowed above the limit in short spikes
+	Burst int `json:"burst,omitempty"`
+}
+
+// NewRateLimitPolicy creates a policy allowing limit requests per window seconds
+func NewRateLimitPolicy(limit, window int) RateLimitPolicy {
+	return RateLimitPolicy{
+		Limit:  limit,
+		Window: window,
+	}
+}
+
+// WithName sets the policy name
+func (p RateLimitPolicy) WithName(name string) RateLimitPolicy {
+	p.Name = name
+	return p
+}
+
+// WithScope sets what the limit is counted against
+func (p RateLimitPolicy) WithScope(scope string) RateLimitPolicy {
+	p.Scope = scope
+	return p
+}
+
+// WithBurst sets the burst allowance
+func (p RateLimitPolicy) WithBurst(burst int) RateLimitPolicy {
+	p.Burst = burst
+	return p
+}
+
+// Description renders the policy as human-readable text
+func (p RateLimitPolicy) Description() string {
+	text := fmt.Sprintf("Rate limit: %d requests per %d seconds", p.Limit, p.Window)
+	if p.Scope != "" {
+		text += " per " + p.Scope
+	}
+	if p.Burst > 0 {
+		text += fmt.Sprintf(", with bursts of up to %d additional requests", p.Burst)
+	}
+	return text + "."
+}
+
+// WithRateLimit attaches a rate limit policy to the operation
+func (o Operation) WithRateLimit(policy RateLimitPolicy) Operation {
+	return o.WithExtension(ExtensionRateLimit, policy)
+}
+
+// WithRateLimit attaches a rate limit policy applying to every operation in the document
+func (d *Document) WithRateLimit(policy RateLimitPolicy) *Document {
+	return d.WithExtension(ExtensionRateLimit, policy)
+}
+
+// SetTagRateLimit attaches a rate limit policy to a tag, adding the tag if needed.
+// It applies to every operation carrying the tag.
+func (d *Document) SetTagRateLimit(tag string, policy RateLimitPolicy) *Document {
+	if !d.mutable("SetTagRateLimit") {
+		return d
+	}
+	d.ownTags()
+	for i := range d.Tags {
+		if d.Tags[i].Name == tag {
+			if d.Tags[i].Extensions == nil {
+				d.Tags[i].Extensions = make(map[string]interface{})
+			}
+			d.Tags[i].Extensions[ExtensionRateLimit] = policy
+			return d
+		}
+	}
+	d.Tags = append(d.Tags, Tag{
+		Name:       tag,
+		Extensions: map[string]interface{}{ExtensionRateLimit: policy},
+	})
+	return d
+}
+
+// EffectiveRateLimit returns the policy that applies to an operation: its own
+// policy, else the policy of its first rate-limited tag, else the document policy
+func (d *Document) EffectiveRateLimit(op *Operation) (RateLimitPolicy, bool) {
+	var policy RateLimitPolicy
+	if decodeExtension(op.Extensions[ExtensionRateLimit], &policy) {
+		return policy, true
+	}
+	for _, name := range op.Tags {
+		for _, tag := range d.Tags {
+			if tag.Name == name && decodeExtension(tag.Extensions[ExtensionRateLimit], &policy) {
+				return policy, true
+			}
+		}
+	}
+	if decodeExtension(d.Extensions[ExtensionRateLimit], &policy) {
+		return policy, true
+	}
+	return policy, false
+}
+
+// RateLimitHeaders returns the RateLimit-* response headers documenting a policy
+func RateLimitHeaders(policy RateLimitPolicy) map[string]Header {
+	return map[string]Header{
+		"RateLimit-Limit": NewHeader().
+			WithDescription(fmt.Sprintf("Request quota for the current window (%d)", policy.Limit)).
+			WithSchema(Int32Schema()),
+		"RateLimit-Remaining": NewHeader().
+			WithDescription("Requests remaining in the current window").
+			WithSchema(Int32Schema()),
+		"RateLimit-Reset": NewHeader().
+			WithDescription("Seconds until the quota resets").
+			WithSchema(Int32Schema()),
+	}
+}
+
+// ApplyRateLimits documents the effective rate limit policy on every operation:
+// the policy description is appended to the operation description, successful
+// responses gain RateLimit-* headers and a 429 response with Retry-After is added.
+// Existing headers and 429 responses are kept, so the call is idempotent.
+func (d *Document) ApplyRateLimits() *Document {
+	if !d.mutable("ApplyRateLimits") {
+		return d
+	}
+	d.ownOperations()
+	d.walkOperations(func(path, method string, op *Operation) {
+		policy, ok := d.EffectiveRateLimit(op)
+		if !ok {
+			return
+		}
+
+		text := policy.Description()
+		if !strings.Contains(op.Description, text) {
+			if op.Description != "" {
+				op.Description += "\n\n"
+			}
+			op.Description += text
+		}
+
+		headers := RateLimitHeaders(policy)
+		responses := make(map[string]Response, len(op.Responses)+1)
+		for code, response := range op.Responses {
+			if strings.HasPrefix(code, "2") && response.Ref == nil {
+				response = withMissingHeaders(response, headers)
+			}
+			responses[code] = response
+		}
+
+		tooMany, exists := responses["429"]
+		if !exists {
+			tooMany = Response{Description: "Too Many Requests. " + text}
+		}
+		if tooMany.Ref != nil {
+			op.Responses = responses
+			return
+		}
+		tooMany = withMissingHeaders(tooMany, headers)
+		tooMany = withMissingHeaders(tooMany, map[string]Header{
+			"Retry-After": NewHeader().
+				WithDescription("Seconds to wait before retrying").
+				WithSchema(Int32Schema()),
+		})
+		responses["429"] = tooMany
+		op.Responses = responses
+	})
+	return d
+}
+
+// withMissingHeaders returns a copy of the response with the headers it doesn't
+// declare yet. Header names are case-insensitive.
+func withMissingHeaders(r Response, headers map[string]Header) Response {
+	merged := make(map[string]Header, len(r.Headers)+len(headers))
+	for name, h := range r.Headers {
+		merged[name] = h
+	}
+	for name, h := range headers {
+		if !hasHeader(r.Headers, name) {
+			merged[name] = h
+		}
+	}
+	r.Headers = merged
+	return r
+}
+
+// hasHeader reports whether headers declares name, ignoring case
+func hasHeader(headers map[string]Header, name string) bool {
+	for declared := range headers {
+		if strings.EqualFold(declared, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ResolveRef returns the component an internal reference such as
+// "#/components/schemas/Pet" points to: a *Schema, Response, Parameter,
+// Example, RequestBody, Header, SecurityScheme, Link or callback, following
+// references between components. It returns an error for unknown
+// components, cyclic references and references outside the components.
+func (d *Document) ResolveRef(ref string) (interface{}, error) {
+	seen := make(map[string]bool)
+	for {
+		key, ok := strings.CutPrefix(ref, "#/components/")
+		if !ok {
+			return nil, fmt.Errorf("openapi: reference %q does not point to a component", ref)
+		}
+		if seen[ref] {
+			return nil, fmt.Errorf("openapi: reference %q is cyclic", ref)
+		}
+		seen[ref] = true
+		component, ok := d.component(key)
+		if !ok {
+			return nil, fmt.Errorf("openapi: reference %q not found", ref)
+		}
+		next := componentRef(component)
+		if next == "" {
+			return component, nil
+		}
+		ref = next
+	}
+}
+
+// componentRef returns the reference a component consists of, if any
+func componentRef(component interface{}) string {
+	switch c := component.(type) {
+	case *Schema:
+		if c != nil {
+			return c.Ref
+		}
+	case Response:
+		return c.Ref.String()
+	case Parameter:
+		return c.Ref.String()
+	case RequestBody:
+		return c.Ref.String()
+	case Header:
+		return c.Ref.String()
+	case Example:
+		return c.Ref.String()
+	case Link:
+		return c.Ref.String()
+	case Callback:
+		return c.Ref.String()
+	}
+	return ""
+}
+
+// Dereference returns a copy of the document in which every internal
+// reference is replaced by an inline copy of its target, for consumers that
+// don't follow references. Non-empty properties next to a reference, such as
+// a description, override those of the target. References that would recurse into themselves, as in tree
+// schemas, are kept, and so are the components they point to.
+func (d *Document) Dereference() (*Document, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	var inline func(value interface{}, stack []string) interface{}
+	inline = func(value interface{}, stack []string) interface{} {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#/") && !slices.Contains(stack, ref) {
+				if target, ok := pointerValue(root, ref[1:]); ok {
+					resolved := inline(target, append(stack, ref))
+					if obj, ok := resolved.(map[string]interface{}); ok {
+						for key, sibling := range v {
+							if key != "$ref" && !emptyJSON(sibling) {
+								obj[key] = inline(sibling, stack)
+							}
+						}
+					}
+					return resolved
+				}
+			}
+			obj := make(map[string]interface{}, len(v))
+			for key, child := range v {
+				obj[key] = inline(child, stack)
+			}
+			return obj
+		case []interface{}:
+			items := make([]interface{}, len(v))
+			for i, child := range v {
+				items[i] = inline(child, stack)
+			}
+			return items
+		}
+		return value
+	}
+
+	data, err = json.Marshal(inline(root, nil))
+	if err != nil {
+		return nil, err
+	}
+	return FromJSON(data)
+}
+
+// emptyJSON reports whether a decoded JSON value is null, false, zero or empty
+func emptyJSON(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package openapi
+
+import (
+	"encoding/json"
+)
+
+// Reference is a Reference Object standing in for a component, such as
+// "#/components/responses/NotFound". OpenAPI 3.1 lets it override the
+// summary and description of the component; OpenAPI 3.0 ignores them.
+type Reference struct {
+	Ref         string `json:"$ref"`
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// NewReference creates a reference to ref
+func NewReference(ref string) *Reference {
+	return &Reference{Ref: ref}
+}
+
+// WithSummary overrides the summary of the referenced component
+func (r *Reference) WithSummary(summary string) *Reference {
+	r.Summary = summary
+	return r
+}
+
+// WithDescription overrides the description of the referenced component
+func (r *Reference) WithDescription(description string) *Reference {
+	r.Description = description
+	return r
+}
+
+// String returns the reference, or "" for a nil reference
+func (r *Reference) String() string {
+	if r == nil {
+		return ""
+	}
+	return r.Ref
+}
+
+// unmarshalReference decodes a Reference Object, returning nil when the
+// object isn't one
+func unmarshalReference(data []byte) (*Reference, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["$ref"]; !ok {
+		return nil, nil
+	}
+	ref := &Reference{}
+	if err := json.Unmarshal(data, ref); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Regeneration reports the outcome of Regenerate by component schema name
+type Regeneration struct {
+	// Added schemas are new in the generated document
+	Added []string
+	// Updated schemas were replaced because their Go type changed
+	Updated []string
+	// Unchanged schemas were kept, with any manual edits, because their Go type didn't change
+	Unchanged []string
+	// Conflicts were kept as they are for manual resolution
+	Conflicts []RegenerationConflict
+}
+
+// RegenerationConflict is a schema whose Go type changed after it was edited by hand
+type RegenerationConflict struct {
+	Name   string
+	Source GoSource
+	Reason string
+}
+
+// Regenerate updates the component schemas derived from Go types with those
+// of a freshly generated document, replacing only the schemas whose Go type
+// changed. Schemas whose type didn't change are kept along with any manual
+// edits. A schema whose type changed after it was edited by hand is a
+// conflict: it is kept and reported so the edit can be redone on the new
+// schema. Schemas not derived from Go types are left untouched.
+func (d *Document) Regenerate(generated *Document) Regeneration {
+	var result Regeneration
+	if generated.Components == nil {
+		return result
+	}
+	names := make([]string, 0, len(generated.Components.Schemas))
+	for name := range generated.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	components := d.AddComponents()
+	if components.Schemas == nil {
+		components.Schemas = make(map[string]*Schema)
+	}
+	for _, name := range names {
+		fresh := generated.Components.Schemas[name]
+		source, ok := fresh.GoSource()
+		if !ok {
+			continue
+		}
+		current, exists := components.Schemas[name]
+		if !exists || current == nil {
+			components.Schemas[name] = fresh
+			result.Added = append(result.Added, name)
+			continue
+		}
+		previous, _ := current.GoSource()
+		switch {
+		case previous.TypeHash == source.TypeHash:
+			result.Unchanged = append(result.Unchanged, name)
+		case previous.SchemaHash != "" && previous.SchemaHash != schemaHash(current):
+			result.Conflicts = append(result.Conflicts, RegenerationConflict{
+				Name:   name,
+				Source: source,
+				Reason: fmt.Sprintf("%s changed but the schema was edited since it was generated", source),
+			})
+		default:
+			components.Schemas[name] = fresh
+			result.Updated = append(result.Updated, name)
+		}
+	}
+	return result
+}
+
+// typeHash fingerprints the shape of a struct type: the names, types and tags
+// of its fields, descending into embedded and anonymous structs. Named field
+// types are identified by name, as they have schemas of their own.
+func typeHash(t reflect.Type) string {
+	var b strings.Builder
+	var describe func(t reflect.Type)
+	describe = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fmt.Fprintf(&b, "%s %s %q;", field.Name, field.Type, field.Tag)
+			ft := field.Type
+			for ft.Kind() == reflect.Pointer || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array || ft.Kind() == reflect.Map {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && (field.Anonymous || ft.Name() == "") {
+				b.WriteString("{")
+				describe(ft)
+				b.WriteString("}")
+			}
+		}
+	}
+	describe(t)
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// schemaHash fingerprints a schema, ignoring its x-go-source extension
+func schemaHash(s *Schema) string {
+	stripped := *s
+	if _, ok := s.Extensions[ExtensionGoSource]; ok {
+		stripped.Extensions = make(map[string]interface{}, len(s.Extensions))
+		for name, value := range s.Extensions {
+			if name != ExtensionGoSource {
+				stripped.Extensions[name] = value
+			}
+		}
+	}
+	data, err := json.Marshal(stripped)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(canonicalJSON(data))
+	return hex.EncodeToString(sum[:])
+}
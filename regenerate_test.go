@@ -0,0 +1,45 @@
+package openapi
+
+import "testing"
+
+func TestRegenerate(t *testing.T) {
+	generated := NewDocument("Pet API", "1.0.0")
+	SchemaOf[createdPet](generated)
+	SchemaOf[createPetRequest](generated)
+
+	// A stale type hash stands in for a Go type changed since the last generation
+	stale := func(s *Schema) {
+		source, _ := s.GoSource()
+		source.TypeHash = "stale"
+		*s = s.WithExtension(ExtensionGoSource, source)
+	}
+
+	doc := NewDocument("Pet API", "1.0.0")
+	SchemaOf[createdPet](doc)
+	SchemaOf[createPetRequest](doc)
+	doc.Components.Schemas["CreatedPet"].Description = "A pet, edited by hand"
+	stale(doc.Components.Schemas["CreatedPet"])
+	stale(doc.Components.Schemas["CreatePetRequest"])
+
+	result := doc.Regenerate(generated)
+	if len(result.Updated) != 1 || result.Updated[0] != "CreatePetRequest" {
+		t.Errorf("Expected CreatePetRequest to be updated, got %v", result.Updated)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Name != "CreatedPet" {
+		t.Errorf("Expected a conflict on CreatedPet, got %v", result.Conflicts)
+	}
+	if doc.Components.Schemas["CreatedPet"].Description != "A pet, edited by hand" {
+		t.Error("Expected the edited schema to be kept")
+	}
+
+	result = doc.Regenerate(generated)
+	if len(result.Unchanged) != 1 || result.Unchanged[0] != "CreatePetRequest" {
+		t.Errorf("Expected CreatePetRequest to be unchanged, got %v", result.Unchanged)
+	}
+
+	partial := NewDocument("Pet API", "1.0.0")
+	partial.Components = &Components{SecuritySchemes: map[string]SecurityScheme{"bearer": {Type: "http", Scheme: "bearer"}}}
+	if result := partial.Regenerate(generated); len(result.Added) != 2 || len(partial.Components.Schemas) != 2 {
+		t.Errorf("Expected both schemas added next to the security scheme, got %v", result.Added)
+	}
+}
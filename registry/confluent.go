@@ -0,0 +1,126 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Confluent is a Registry backed by the REST API of a Confluent-compatible
+// schema registry, registering schemas with the JSON schema type
+type Confluent struct {
+	// URL is the base URL of the registry
+	URL string
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+	// Username and Password enable basic authentication, e.g. with API keys
+	Username string
+	Password string
+}
+
+// NewConfluent creates a registry client for the registry at baseURL
+func NewConfluent(baseURL string) *Confluent {
+	return &Confluent{URL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// confluentSchema is the schema payload of the registry API
+type confluentSchema struct {
+	Subject    string `json:"subject,omitempty"`
+	ID         int    `json:"id,omitempty"`
+	Version    int    `json:"version,omitempty"`
+	SchemaType string `json:"schemaType,omitempty"`
+	Schema     string `json:"schema,omitempty"`
+}
+
+// confluentError is the error payload of the registry API
+type confluentError struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *confluentError) Error() string {
+	return fmt.Sprintf("schema registry error %d: %s", e.ErrorCode, e.Message)
+}
+
+// Confluent error codes for unknown subjects and versions
+const (
+	confluentSubjectNotFound = 40401
+	confluentVersionNotFound = 40402
+)
+
+// Latest implements Registry
+func (c *Confluent) Latest(ctx context.Context, subject string) (*Version, error) {
+	var latest confluentSchema
+	err := c.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/latest", nil, &latest)
+	var apiErr *confluentError
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode == confluentSubjectNotFound || apiErr.ErrorCode == confluentVersionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Version{Subject: subject, ID: latest.ID, Version: latest.Version, Schema: []byte(latest.Schema)}, nil
+}
+
+// Register implements Registry. The returned version carries the schema ID;
+// the registry doesn't report the version number on registration.
+func (c *Confluent) Register(ctx context.Context, subject string, schema []byte) (*Version, error) {
+	var registered confluentSchema
+	body := confluentSchema{SchemaType: "JSON", Schema: string(schema)}
+	if err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", body, &registered); err != nil {
+		return nil, err
+	}
+	return &Version{Subject: subject, ID: registered.ID, Schema: schema}, nil
+}
+
+// do sends a request to the registry API and decodes the response into out
+func (c *Confluent) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := &confluentError{}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.ErrorCode == 0 {
+			apiErr = &confluentError{ErrorCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		}
+		return apiErr
+	}
+	return json.Unmarshal(data, out)
+}
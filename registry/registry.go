@@ -0,0 +1,276 @@
+// Package registry publishes the component schemas of documents to schema
+// registries as JSON Schema, checking that every new version is compatible
+// with the latest registered one before publishing it
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nyxstack/openapi"
+)
+
+// ErrIncompatible is returned by Publish when schemas were rejected by the
+// compatibility check
+var ErrIncompatible = errors.New("registry: incompatible schema")
+
+// Version is a schema registered under a subject
+type Version struct {
+	Subject string
+	// ID identifies the schema in the registry
+	ID int
+	// Version numbers the versions of a subject
+	Version int
+	// Schema is the registered JSON Schema
+	Schema []byte
+}
+
+// Registry stores versions of JSON Schemas under subjects. Implement it to
+// publish to internal registries; Confluent talks to Confluent-compatible ones.
+type Registry interface {
+	// Latest returns the latest version registered under a subject, or nil
+	// when the subject has no versions
+	Latest(ctx context.Context, subject string) (*Version, error)
+	// Register adds a schema as the new version of a subject
+	Register(ctx context.Context, subject string, schema []byte) (*Version, error)
+}
+
+// Publisher publishes component schemas to a registry
+type Publisher struct {
+	Registry Registry
+	// Mode is the compatibility required between consecutive versions
+	Mode openapi.CompatibilityMode
+	// Subject maps a component name to its subject; defaults to the name
+	Subject func(name string) string
+	// DryRun runs the compatibility checks without registering anything
+	DryRun bool
+}
+
+// NewPublisher creates a publisher requiring backward compatible versions
+func NewPublisher(registry Registry) *Publisher {
+	return &Publisher{Registry: registry, Mode: openapi.CompatibilityBackward}
+}
+
+// Result reports the publication of one component schema
+type Result struct {
+	Name    string
+	Subject string
+	// Version is the registered version, the latest one when the schema is
+	// unchanged, and nil when nothing was registered
+	Version *Version
+	// Unchanged tells that the schema equals the latest registered version
+	Unchanged bool
+	// Errors explain why the schema is incompatible with the latest version
+	Errors []openapi.ValidationError
+}
+
+// Publish publishes the named component schemas of a document, or all of
+// them when no name is given, in name order. Each schema is published as a
+// self-contained JSON Schema whose component references point into "$defs".
+// Schemas equal to their latest version are skipped, and schemas incompatible
+// with it are not registered; Publish then reports ErrIncompatible once all
+// schemas have been processed.
+func (p *Publisher) Publish(ctx context.Context, doc *openapi.Document, names ...string) ([]Result, error) {
+	var schemas map[string]*openapi.Schema
+	if doc.Components != nil {
+		schemas = doc.Components.Schemas
+	}
+	if len(names) == 0 {
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var results []Result
+	var rejected []string
+	for _, name := range names {
+		if _, ok := schemas[name]; !ok {
+			return results, fmt.Errorf("registry: unknown component schema %q", name)
+		}
+		result := Result{Name: name, Subject: name}
+		if p.Subject != nil {
+			result.Subject = p.Subject(name)
+		}
+
+		data, err := JSONSchema(doc, name)
+		if err != nil {
+			return results, err
+		}
+		latest, err := p.Registry.Latest(ctx, result.Subject)
+		if err != nil {
+			return results, fmt.Errorf("registry: reading %s: %w", result.Subject, err)
+		}
+		if latest != nil {
+			if equalJSON(latest.Schema, data) {
+				result.Version = latest
+				result.Unchanged = true
+				results = append(results, result)
+				continue
+			}
+			ok, errs, err := p.compatible(data, latest.Schema)
+			if err != nil {
+				return results, fmt.Errorf("registry: reading latest %s: %w", result.Subject, err)
+			}
+			if !ok {
+				result.Errors = errs
+				rejected = append(rejected, result.Subject)
+				results = append(results, result)
+				continue
+			}
+		}
+
+		if !p.DryRun {
+			result.Version, err = p.Registry.Register(ctx, result.Subject, data)
+			if err != nil {
+				return results, fmt.Errorf("registry: registering %s: %w", result.Subject, err)
+			}
+		}
+		results = append(results, result)
+	}
+	if len(rejected) > 0 {
+		return results, fmt.Errorf("%w: %s", ErrIncompatible, strings.Join(rejected, ", "))
+	}
+	return results, nil
+}
+
+// compatible checks a new JSON Schema against an old one with the publisher's mode
+func (p *Publisher) compatible(newData, oldData []byte) (bool, []openapi.ValidationError, error) {
+	newDoc, newSchema, err := fromJSONSchema(newData)
+	if err != nil {
+		return false, nil, err
+	}
+	oldDoc, oldSchema, err := fromJSONSchema(oldData)
+	if err != nil {
+		return false, nil, err
+	}
+	ok, errs := newDoc.IsCompatible(newSchema, oldDoc, oldSchema, p.Mode)
+	return ok, errs, nil
+}
+
+// JSONSchema renders a component schema as a self-contained JSON Schema: the
+// component schemas it references, directly or not, are copied into "$defs"
+// and references are rewritten to point there
+func JSONSchema(doc *openapi.Document, name string) ([]byte, error) {
+	if doc.Components == nil || doc.Components.Schemas[name] == nil {
+		return nil, fmt.Errorf("registry: unknown component schema %q", name)
+	}
+	root, err := toValue(doc.Components.Schemas[name])
+	if err != nil {
+		return nil, err
+	}
+
+	defs := make(map[string]interface{})
+	pending := rewriteRefs(root, "#/components/schemas/", "#/$defs/")
+	for len(pending) > 0 {
+		ref := pending[0]
+		pending = pending[1:]
+		if _, done := defs[ref]; done {
+			continue
+		}
+		schema, ok := doc.Components.Schemas[ref]
+		if !ok {
+			return nil, fmt.Errorf("registry: %s references unknown component schema %q", name, ref)
+		}
+		value, err := toValue(schema)
+		if err != nil {
+			return nil, err
+		}
+		defs[ref] = value
+		pending = append(pending, rewriteRefs(value, "#/components/schemas/", "#/$defs/")...)
+	}
+
+	object, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("registry: component schema %q is not an object", name)
+	}
+	object["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	if len(defs) > 0 {
+		object["$defs"] = defs
+	}
+	return json.Marshal(object)
+}
+
+// fromJSONSchema reads a JSON Schema published by JSONSchema back, as a
+// schema and a document holding its "$defs" as component schemas
+func fromJSONSchema(data []byte) (*openapi.Document, *openapi.Schema, error) {
+	var value map[string]interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, nil, err
+	}
+	rewriteRefs(value, "#/$defs/", "#/components/schemas/")
+	defs, _ := value["$defs"].(map[string]interface{})
+	delete(value, "$defs")
+	delete(value, "$schema")
+
+	doc := &openapi.Document{Components: &openapi.Components{Schemas: make(map[string]*openapi.Schema)}}
+	for name, def := range defs {
+		schema := &openapi.Schema{}
+		if err := fromValue(def, schema); err != nil {
+			return nil, nil, err
+		}
+		doc.Components.Schemas[name] = schema
+	}
+	schema := &openapi.Schema{}
+	if err := fromValue(value, schema); err != nil {
+		return nil, nil, err
+	}
+	return doc, schema, nil
+}
+
+// rewriteRefs replaces the prefix of every "$ref" in a decoded JSON value and
+// returns the names referenced under it
+func rewriteRefs(value interface{}, from, to string) []string {
+	var names []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				if name, ok := strings.CutPrefix(ref, from); ok {
+					v[key] = to + name
+					names = append(names, strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~"))
+				}
+				continue
+			}
+			names = append(names, rewriteRefs(child, from, to)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			names = append(names, rewriteRefs(child, from, to)...)
+		}
+	}
+	return names
+}
+
+func toValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+func fromValue(value interface{}, dst interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// equalJSON compares JSON documents regardless of formatting and key order
+func equalJSON(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	ca, _ := json.Marshal(va)
+	cb, _ := json.Marshal(vb)
+	return string(ca) == string(cb)
+}
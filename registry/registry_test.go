@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nyxstack/openapi"
+)
+
+// fakeRegistry emulates the subject endpoints of a Confluent schema registry
+type fakeRegistry struct {
+	mu       sync.Mutex
+	subjects map[string][]string
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	subject := strings.TrimPrefix(r.URL.Path, "/subjects/")
+	subject, _, _ = strings.Cut(subject, "/versions")
+	switch r.Method {
+	case http.MethodGet:
+		versions := f.subjects[subject]
+		if len(versions) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error_code": 40401, "message": "Subject not found"})
+			return
+		}
+		json.NewEncoder(w).Encode(confluentSchema{Subject: subject, ID: len(versions), Version: len(versions), Schema: versions[len(versions)-1]})
+	case http.MethodPost:
+		var body confluentSchema
+		json.NewDecoder(r.Body).Decode(&body)
+		f.subjects[subject] = append(f.subjects[subject], body.Schema)
+		json.NewEncoder(w).Encode(map[string]int{"id": len(f.subjects[subject])})
+	}
+}
+
+func petDocument(required ...string) *openapi.Document {
+	doc := openapi.NewDocument("Pet API", "1.0.0")
+	tag := openapi.NewObjectSchema().WithProperty("name", openapi.StringSchema(""))
+	pet := openapi.NewObjectSchema().
+		WithProperty("name", openapi.StringSchema("")).
+		WithProperty("tag", &openapi.Schema{Ref: "#/components/schemas/Tag"}).
+		WithRequired(required...)
+	doc.AddComponents().Schemas = map[string]*openapi.Schema{"Pet": &pet, "Tag": &tag}
+	return doc
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema(petDocument(), "Pet")
+	if err != nil {
+		t.Fatalf("Error rendering JSON Schema: %v", err)
+	}
+	for _, want := range []string{`"$ref":"#/$defs/Tag"`, `"$defs":{"Tag":`, `"$schema":`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected JSON Schema to contain '%s', got %s", want, data)
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	server := httptest.NewServer(&fakeRegistry{subjects: make(map[string][]string)})
+	defer server.Close()
+
+	publisher := NewPublisher(NewConfluent(server.URL))
+	publisher.Subject = func(name string) string { return "pets." + name }
+	ctx := context.Background()
+
+	results, err := publisher.Publish(ctx, petDocument(), "Pet")
+	if err != nil {
+		t.Fatalf("Error publishing: %v", err)
+	}
+	if len(results) != 1 || results[0].Subject != "pets.Pet" || results[0].Version == nil || results[0].Version.ID != 1 {
+		t.Fatalf("Expected Pet to be registered, got %+v", results)
+	}
+
+	results, err = publisher.Publish(ctx, petDocument(), "Pet")
+	if err != nil || !results[0].Unchanged {
+		t.Errorf("Expected unchanged schema to be skipped, got %+v, %v", results, err)
+	}
+
+	// Requiring a field the old version made optional breaks old data
+	results, err = publisher.Publish(ctx, petDocument("name"), "Pet")
+	if !errors.Is(err, ErrIncompatible) {
+		t.Fatalf("Expected ErrIncompatible, got %v", err)
+	}
+	if len(results[0].Errors) == 0 || results[0].Version != nil {
+		t.Errorf("Expected compatibility errors and no registration, got %+v", results[0])
+	}
+
+	publisher.DryRun = true
+	publisher.Mode = openapi.CompatibilityForward
+	results, err = publisher.Publish(ctx, petDocument("name"), "Pet")
+	if err != nil || results[0].Version != nil {
+		t.Errorf("Expected dry run to pass without registering, got %+v, %v", results, err)
+	}
+}
@@ -0,0 +1,269 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RenderFormat selects how Render lays out a schema
+type RenderFormat string
+
+const (
+	// RenderTree renders an indented tree of properties, for terminals and
+	// error messages
+	RenderTree RenderFormat = "tree"
+	// RenderTable renders a Markdown table of properties, nested properties
+	// named by their path such as "owner.name" or "tags[].id"
+	RenderTable RenderFormat = "table"
+)
+
+// RenderOptions configures Render
+type RenderOptions struct {
+	// Document resolves component references; without it references are
+	// rendered by component name and not expanded
+	Document *Document
+	// Depth limits how many levels of nested properties are expanded, 0
+	// meaning no limit. A reference to a schema being expanded is never
+	// expanded again, so recursive schemas terminate either way.
+	Depth int
+}
+
+// Render describes the properties of the schema for humans: their name,
+// type, whether they are required, their constraints and description
+func (s *Schema) Render(format RenderFormat, opts RenderOptions) string {
+	r := &schemaRenderer{opts: opts}
+	r.expand("", 0, s)
+
+	var b strings.Builder
+	if format == RenderTable {
+		b.WriteString("| Name | Type | Required | Constraints | Description |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for _, row := range r.rows {
+			required := ""
+			if row.required {
+				required = "yes"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", row.path, tableCell(row.label), required,
+				tableCell(strings.Join(row.constraints, ", ")), tableCell(row.description))
+		}
+		return b.String()
+	}
+
+	b.WriteString(r.label(s) + "\n")
+	for _, row := range r.rows {
+		b.WriteString(strings.Repeat("  ", row.level+1) + row.name + ": " + row.label)
+		if row.required {
+			b.WriteString(", required")
+		}
+		for _, constraint := range row.constraints {
+			b.WriteString(", " + constraint)
+		}
+		if row.description != "" {
+			b.WriteString(" - " + row.description)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// schemaRow is one rendered property
+type schemaRow struct {
+	path        string
+	name        string
+	level       int
+	label       string
+	required    bool
+	constraints []string
+	description string
+}
+
+// schemaRenderer collects the properties of a schema in rendering order
+type schemaRenderer struct {
+	opts RenderOptions
+	rows []schemaRow
+	// expanding holds the references being expanded
+	expanding []string
+}
+
+// resolve follows the references of a schema, returning nil when they can't
+// be resolved
+func (r *schemaRenderer) resolve(s *Schema) *Schema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+	if r.opts.Document == nil {
+		return nil
+	}
+	return r.opts.Document.resolveSchema(s)
+}
+
+// expand adds rows for the properties of a schema, or those of the items of
+// an array, nested under path
+func (r *schemaRenderer) expand(path string, level int, s *Schema) {
+	if s != nil && s.Ref != "" {
+		if slices.Contains(r.expanding, s.Ref) {
+			return
+		}
+		r.expanding = append(r.expanding, s.Ref)
+		defer func() { r.expanding = r.expanding[:len(r.expanding)-1] }()
+	}
+	resolved := r.resolve(s)
+	if resolved == nil || (r.opts.Depth > 0 && level >= r.opts.Depth) {
+		return
+	}
+	if resolved.Items != nil && len(resolved.Properties) == 0 {
+		r.expand(path+"[]", level, resolved.Items)
+		return
+	}
+
+	properties := make(map[string]*Schema)
+	var required []string
+	for _, part := range append([]*Schema{resolved}, resolved.AllOf...) {
+		if part = r.resolve(part); part == nil {
+			continue
+		}
+		for name, property := range part.Properties {
+			properties[name] = property
+		}
+		required = append(required, part.Required...)
+	}
+	if path != "" {
+		path += "."
+	}
+	for _, name := range sortedKeys(properties) {
+		property := properties[name]
+		row := schemaRow{
+			path:     path + name,
+			name:     name,
+			level:    level,
+			label:    r.label(property),
+			required: slices.Contains(required, name),
+		}
+		if target := r.resolve(property); target != nil {
+			row.constraints = schemaConstraints(target)
+			row.description = strings.Join(strings.Fields(target.Description), " ")
+		}
+		if property.Description != "" {
+			row.description = strings.Join(strings.Fields(property.Description), " ")
+		}
+		r.rows = append(r.rows, row)
+		r.expand(path+name, level+1, property)
+	}
+}
+
+// label describes the type of a schema; references are named after their
+// component
+func (r *schemaRenderer) label(s *Schema) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+	variants := func(schemas []*Schema) string {
+		var labels []string
+		for _, variant := range schemas {
+			labels = append(labels, r.label(variant))
+		}
+		return strings.Join(labels, " | ")
+	}
+	var label string
+	switch {
+	case len(s.OneOf) > 0:
+		label = "one of " + variants(s.OneOf)
+	case len(s.AnyOf) > 0:
+		label = "any of " + variants(s.AnyOf)
+	case s.Type.Is("array") && s.Items != nil:
+		label = "array of " + r.label(s.Items)
+	case len(s.Type) > 0:
+		label = s.Type.String()
+	case len(s.Properties) > 0 || len(s.AllOf) > 0:
+		label = "object"
+	default:
+		label = "any"
+	}
+	if s.Format != "" {
+		label += " (" + s.Format + ")"
+	}
+	if s.Nullable && !s.Type.Is("null") {
+		label += " or null"
+	}
+	return label
+}
+
+// schemaConstraints describes the validation keywords of a schema
+func schemaConstraints(s *Schema) []string {
+	var constraints []string
+	bound := func(op string, value *float64) {
+		if value != nil {
+			constraints = append(constraints, fmt.Sprintf("%s %v", op, *value))
+		}
+	}
+	if min, exclusive := s.LowerBound(); exclusive {
+		bound(">", min)
+	} else {
+		bound(">=", min)
+	}
+	if max, exclusive := s.UpperBound(); exclusive {
+		bound("<", max)
+	} else {
+		bound("<=", max)
+	}
+	if s.MultipleOf != nil {
+		constraints = append(constraints, fmt.Sprintf("multiple of %v", *s.MultipleOf))
+	}
+	if r := lengthRange(s.MinLength, s.MaxLength); r != "" {
+		constraints = append(constraints, "length "+r)
+	}
+	if s.Pattern != "" {
+		constraints = append(constraints, "pattern "+s.Pattern)
+	}
+	if r := lengthRange(s.MinItems, s.MaxItems); r != "" {
+		constraints = append(constraints, "items "+r)
+	}
+	if s.UniqueItems {
+		constraints = append(constraints, "unique items")
+	}
+	if len(s.Enum) > 0 {
+		var values []string
+		for _, value := range s.Enum {
+			values = append(values, fmt.Sprint(value))
+		}
+		constraints = append(constraints, "one of "+strings.Join(values, ", "))
+	}
+	if s.Const != nil {
+		constraints = append(constraints, fmt.Sprintf("equals %v", s.Const))
+	}
+	if s.Default != nil {
+		constraints = append(constraints, fmt.Sprintf("default %v", s.Default))
+	}
+	if s.ReadOnly {
+		constraints = append(constraints, "read-only")
+	}
+	if s.WriteOnly {
+		constraints = append(constraints, "write-only")
+	}
+	if s.Deprecated {
+		constraints = append(constraints, "deprecated")
+	}
+	return constraints
+}
+
+// lengthRange describes a range of lengths or counts, such as "1..64" or ">= 1"
+func lengthRange(min, max *int) string {
+	switch {
+	case min != nil && max != nil:
+		return fmt.Sprintf("%d..%d", *min, *max)
+	case min != nil:
+		return fmt.Sprintf(">= %d", *min)
+	case max != nil:
+		return fmt.Sprintf("<= %d", *max)
+	}
+	return ""
+}
+
+// tableCell escapes text for a Markdown table cell
+func tableCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -1,11 +1,43 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // RequestBody represents a request body in OpenAPI
 type RequestBody struct {
-	Ref         string               `json:"$ref,omitempty"`
-	Description string               `json:"description,omitempty"`
-	Content     map[string]MediaType `json:"content"`
-	Required    bool                 `json:"required,omitempty"`
+	Ref         *Reference             `json:"-"`
+	Description string                 `json:"description,omitempty"`
+	Content     map[string]MediaType   `json:"content"`
+	Required    bool                   `json:"required,omitempty"`
+	Extensions  map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification
+// extensions. A reference is marshaled as the Reference Object alone.
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	if r.Ref != nil {
+		return json.Marshal(r.Ref)
+	}
+	type requestBody RequestBody
+	return marshalWithExtensions(requestBody(r), r.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification
+// extensions and decode Reference Objects
+func (r *RequestBody) UnmarshalJSON(data []byte) error {
+	ref, err := unmarshalReference(data)
+	if err != nil || ref != nil {
+		*r = RequestBody{Ref: ref}
+		return err
+	}
+	type requestBody RequestBody
+	if err := json.Unmarshal(data, (*requestBody)(r)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	r.Extensions = ext
+	return err
 }
 
 // NewRequestBody creates a new request body
@@ -51,3 +83,12 @@ func (r RequestBody) WithRequired(required bool) RequestBody {
 	r.Required = required
 	return r
 }
+
+// WithExtension sets a specification extension; the name must start with "x-"
+func (r RequestBody) WithExtension(name string, value interface{}) RequestBody {
+	if r.Extensions == nil {
+		r.Extensions = make(map[string]interface{})
+	}
+	r.Extensions[name] = value
+	return r
+}
@@ -1,12 +1,44 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // Response represents a response in OpenAPI
 type Response struct {
-	Ref         string               `json:"$ref,omitempty"`
-	Description string               `json:"description"`
-	Headers     map[string]Header    `json:"headers,omitempty"`
-	Content     map[string]MediaType `json:"content,omitempty"`
-	Links       map[string]Link      `json:"links,omitempty"`
+	Ref         *Reference             `json:"-"`
+	Description string                 `json:"description"`
+	Headers     map[string]Header      `json:"headers,omitempty"`
+	Content     map[string]MediaType   `json:"content,omitempty"`
+	Links       map[string]Link        `json:"links,omitempty"`
+	Extensions  map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification
+// extensions. A reference is marshaled as the Reference Object alone.
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.Ref != nil {
+		return json.Marshal(r.Ref)
+	}
+	type response Response
+	return marshalWithExtensions(response(r), r.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification
+// extensions and decode Reference Objects
+func (r *Response) UnmarshalJSON(data []byte) error {
+	ref, err := unmarshalReference(data)
+	if err != nil || ref != nil {
+		*r = Response{Ref: ref}
+		return err
+	}
+	type response Response
+	if err := json.Unmarshal(data, (*response)(r)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	r.Extensions = ext
+	return err
 }
 
 // NewResponse creates a new response
@@ -64,3 +96,12 @@ func (r Response) WithLink(name string, link Link) Response {
 	r.Links[name] = link
 	return r
 }
+
+// WithExtension sets a specification extension; the name must start with "x-"
+func (r Response) WithExtension(name string, value interface{}) Response {
+	if r.Extensions == nil {
+		r.Extensions = make(map[string]interface{})
+	}
+	r.Extensions[name] = value
+	return r
+}
@@ -0,0 +1,205 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExtensionClientGuidance is the specification extension holding the
+// timeout and retry guidance for clients
+const ExtensionClientGuidance = "x-client-guidance"
+
+// IdempotencyKeyHeader is the request header carrying an idempotency key,
+// which makes retrying a non-idempotent operation safe
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// BackoffStrategy tells how the delay between retries grows
+type BackoffStrategy string
+
+const (
+	// BackoffConstant waits the initial delay between all retries
+	BackoffConstant BackoffStrategy = "constant"
+	// BackoffLinear adds the initial delay with every retry
+	BackoffLinear BackoffStrategy = "linear"
+	// BackoffExponential doubles the delay with every retry
+	BackoffExponential BackoffStrategy = "exponential"
+)
+
+// Backoff describes how clients space out retries
+type Backoff struct {
+	Strategy BackoffStrategy `json:"strategy"`
+	// InitialDelay is the delay before the first retry in milliseconds
+	InitialDelay int `json:"initialDelay,omitempty"`
+	// MaxDelay caps the delay between retries in milliseconds
+	MaxDelay int `json:"maxDelay,omitempty"`
+	// MaxAttempts is the number of attempts, the first one included
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Jitter randomizes the delays so clients don't retry in lockstep
+	Jitter bool `json:"jitter,omitempty"`
+}
+
+// NewBackoff creates a backoff of a strategy starting at initialDelay, for
+// at most maxAttempts attempts
+func NewBackoff(strategy BackoffStrategy, initialDelay time.Duration, maxAttempts int) Backoff {
+	return Backoff{
+		Strategy:     strategy,
+		InitialDelay: int(initialDelay.Milliseconds()),
+		MaxAttempts:  maxAttempts,
+	}
+}
+
+// WithMaxDelay caps the delay between retries
+func (b Backoff) WithMaxDelay(maxDelay time.Duration) Backoff {
+	b.MaxDelay = int(maxDelay.Milliseconds())
+	return b
+}
+
+// WithJitter randomizes the delays between retries
+func (b Backoff) WithJitter(jitter bool) Backoff {
+	b.Jitter = jitter
+	return b
+}
+
+// ClientGuidance tells clients how long to wait for an operation and whether
+// and how to retry it. It can be attached to the document or to a single
+// operation; the operation's guidance applies over the document's.
+type ClientGuidance struct {
+	// Timeout is the recommended client timeout in milliseconds
+	Timeout int `json:"timeout,omitempty"`
+	// RetrySafe tells whether failed requests may be retried; when unset it
+	// is derived from the method and idempotency key support
+	RetrySafe *bool    `json:"retrySafe,omitempty"`
+	Backoff   *Backoff `json:"backoff,omitempty"`
+}
+
+// NewClientGuidance creates guidance recommending a client timeout
+func NewClientGuidance(timeout time.Duration) ClientGuidance {
+	return ClientGuidance{Timeout: int(timeout.Milliseconds())}
+}
+
+// WithRetrySafe states whether failed requests may be retried
+func (g ClientGuidance) WithRetrySafe(retrySafe bool) ClientGuidance {
+	g.RetrySafe = &retrySafe
+	return g
+}
+
+// WithBackoff sets how clients space out retries
+func (g ClientGuidance) WithBackoff(backoff Backoff) ClientGuidance {
+	g.Backoff = &backoff
+	return g
+}
+
+// Description renders the guidance as human-readable text
+func (g ClientGuidance) Description() string {
+	var sentences []string
+	if g.Timeout > 0 {
+		sentences = append(sentences, fmt.Sprintf("Recommended timeout: %s.", time.Duration(g.Timeout)*time.Millisecond))
+	}
+	switch {
+	case g.RetrySafe != nil && !*g.RetrySafe:
+		sentences = append(sentences, "Not safe to retry.")
+	case g.Backoff != nil:
+		b := g.Backoff
+		text := fmt.Sprintf("Safe to retry with %s backoff", b.Strategy)
+		if b.InitialDelay > 0 {
+			text += fmt.Sprintf(" starting at %s", time.Duration(b.InitialDelay)*time.Millisecond)
+		}
+		if b.MaxDelay > 0 {
+			text += fmt.Sprintf(", at most %s apart", time.Duration(b.MaxDelay)*time.Millisecond)
+		}
+		if b.MaxAttempts > 0 {
+			text += fmt.Sprintf(", up to %d attempts", b.MaxAttempts)
+		}
+		if b.Jitter {
+			text += ", with jitter"
+		}
+		sentences = append(sentences, text+".")
+	case g.RetrySafe != nil:
+		sentences = append(sentences, "Safe to retry.")
+	}
+	return strings.Join(sentences, " ")
+}
+
+// WithClientGuidance attaches timeout and retry guidance to the operation
+func (o Operation) WithClientGuidance(guidance ClientGuidance) Operation {
+	return o.WithExtension(ExtensionClientGuidance, guidance)
+}
+
+// WithClientGuidance attaches timeout and retry guidance applying to every
+// operation in the document
+func (d *Document) WithClientGuidance(guidance ClientGuidance) *Document {
+	return d.WithExtension(ExtensionClientGuidance, guidance)
+}
+
+// WithIdempotencyKey adds the Idempotency-Key request header, with which
+// clients can safely retry the operation
+func (o Operation) WithIdempotencyKey(required bool) Operation {
+	return o.WithParameter(NewHeaderParameter(IdempotencyKeyHeader,
+		"Unique key making retries of the request safe: requests repeating a key are applied once", required, StringSchema("")))
+}
+
+// EffectiveClientGuidance returns the guidance that applies to an operation:
+// its own guidance, else the document's. Unless the guidance states it,
+// RetrySafe is derived: safe and idempotent operations, as classified by
+// IdempotencyAnalysis, and operations accepting an Idempotency-Key header
+// are safe to retry.
+func (d *Document) EffectiveClientGuidance(path, method string, op *Operation) ClientGuidance {
+	guidance := d.declaredClientGuidance(op)
+	if guidance.RetrySafe == nil {
+		idempotency, _ := operationIdempotency(method, op)
+		retrySafe := idempotency != IdempotencyNonIdempotent || d.acceptsIdempotencyKey(path, op)
+		guidance.RetrySafe = &retrySafe
+	}
+	return guidance
+}
+
+// declaredClientGuidance returns the guidance of an operation, else the
+// document's, as declared
+func (d *Document) declaredClientGuidance(op *Operation) ClientGuidance {
+	var guidance ClientGuidance
+	if !decodeExtension(op.Extensions[ExtensionClientGuidance], &guidance) {
+		decodeExtension(d.Extensions[ExtensionClientGuidance], &guidance)
+	}
+	return guidance
+}
+
+// acceptsIdempotencyKey reports whether an operation accepts the
+// Idempotency-Key header
+func (d *Document) acceptsIdempotencyKey(path string, op *Operation) bool {
+	return slices.ContainsFunc(d.OperationParameters(path, op), func(p Parameter) bool {
+		return p.In == "header" && strings.EqualFold(p.Name, IdempotencyKeyHeader)
+	})
+}
+
+// RetryProfile reports the non-idempotent operations that don't
+// accept an Idempotency-Key header, which clients can't safely retry after a
+// timeout, as warnings under the rule "retry/idempotency-key". Operations
+// whose guidance explicitly states whether they are safe to retry aren't
+// reported.
+type RetryProfile struct{}
+
+// Name returns "retry"
+func (RetryProfile) Name() string {
+	return "retry"
+}
+
+// Validate returns a warning for every non-idempotent operation lacking
+// idempotency key support
+func (RetryProfile) Validate(d *Document) []ValidationError {
+	var errs []ValidationError
+	d.walkOperations(func(path, method string, op *Operation) {
+		idempotency, _ := operationIdempotency(method, op)
+		if d.declaredClientGuidance(op).RetrySafe != nil || idempotency != IdempotencyNonIdempotent || d.acceptsIdempotencyKey(path, op) {
+			return
+		}
+		errs = append(errs, ValidationError{
+			Path:     operationPointer(path, method),
+			Message:  fmt.Sprintf("%s %s isn't idempotent and doesn't accept an %s header, so clients can't safely retry it", method, path, IdempotencyKeyHeader),
+			Severity: SeverityWarning,
+			Rule:     "retry/idempotency-key",
+		})
+	})
+	return errs
+}
@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrRouteNotFound is returned when no path template matches a request path
+	ErrRouteNotFound = errors.New("openapi: no path matches the request")
+	// ErrMethodNotAllowed is returned when a path matches but has no operation for the method
+	ErrMethodNotAllowed = errors.New("openapi: method not allowed for path")
+)
+
+// RouteMatch is the result of matching a request against a document
+type RouteMatch struct {
+	// Path is the matched path template, e.g. "/pets/{petId}"
+	Path       string
+	Method     string
+	Operation  *Operation
+	PathParams map[string]string
+}
+
+// Router matches requests to the operations of a document. Concrete path
+// segments take precedence over templated ones, so "/pets/mine" is matched
+// before "/pets/{petId}" regardless of declaration order.
+type Router struct {
+	doc       *Document
+	templates []string
+	basePaths []string
+}
+
+// NewRouter creates a router for the document's paths. The document should not
+// be modified while the router is in use; create a new router instead.
+func NewRouter(doc *Document) *Router {
+	r := &Router{doc: doc, templates: sortedKeys(doc.Paths)}
+	sort.SliceStable(r.templates, func(i, j int) bool {
+		return templateBefore(r.templates[i], r.templates[j])
+	})
+
+	for _, server := range doc.Servers {
+		if strings.Contains(server.URL, "{") {
+			continue
+		}
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			continue
+		}
+		if base := strings.TrimSuffix(u.Path, "/"); base != "" {
+			r.basePaths = append(r.basePaths, base)
+		}
+	}
+	return r
+}
+
+// Match finds the operation handling method and urlPath, which may be escaped.
+// Server base paths (e.g. "/api/v3" from "https://example.com/api/v3") are
+// stripped when the path doesn't match as is. It returns ErrRouteNotFound or
+// ErrMethodNotAllowed when nothing matches.
+func (r *Router) Match(method, urlPath string) (*RouteMatch, error) {
+	err := ErrRouteNotFound
+	for _, candidate := range r.candidatePaths(urlPath) {
+		for _, template := range r.templates {
+			params, ok := matchPathTemplate(template, candidate)
+			if !ok {
+				continue
+			}
+			item := r.doc.Paths[template]
+			op := item.Operation(method)
+			if op == nil {
+				err = ErrMethodNotAllowed
+				continue
+			}
+			return &RouteMatch{
+				Path:       template,
+				Method:     strings.ToUpper(method),
+				Operation:  op,
+				PathParams: params,
+			}, nil
+		}
+	}
+	return nil, err
+}
+
+// candidatePaths returns urlPath and its remainders after every server base path
+// it starts with
+func (r *Router) candidatePaths(urlPath string) []string {
+	candidates := []string{urlPath}
+	for _, base := range r.basePaths {
+		if rest, ok := strings.CutPrefix(urlPath, base); ok && (rest == "" || rest[0] == '/') {
+			candidates = append(candidates, "/"+strings.TrimPrefix(rest, "/"))
+		}
+	}
+	return candidates
+}
+
+// allowedMethods returns the methods of the operations on the path templates
+// matching urlPath, in canonical order
+func (r *Router) allowedMethods(urlPath string) []string {
+	allowed := make(map[string]bool)
+	for _, candidate := range r.candidatePaths(urlPath) {
+		for _, template := range r.templates {
+			if _, ok := matchPathTemplate(template, candidate); !ok {
+				continue
+			}
+			item := r.doc.Paths[template]
+			for _, method := range httpMethods {
+				if item.Operation(method) != nil {
+					allowed[method] = true
+				}
+			}
+		}
+	}
+	var methods []string
+	for _, method := range httpMethods {
+		if allowed[method] {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// Match finds the operation handling method and urlPath. It builds a new Router
+// on every call; use NewRouter when matching many requests.
+func (d *Document) Match(method, urlPath string) (*RouteMatch, error) {
+	return NewRouter(d).Match(method, urlPath)
+}
+
+// templateBefore orders path templates so that, segment by segment, literal
+// segments come before partially templated ones ("{id}.json"), which come
+// before segments consisting of a single variable
+func templateBefore(a, b string) bool {
+	as := strings.Split(strings.Trim(a, "/"), "/")
+	bs := strings.Split(strings.Trim(b, "/"), "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if ra, rb := segmentRank(as[i]), segmentRank(bs[i]); ra != rb {
+			return ra < rb
+		}
+	}
+	if len(as) != len(bs) {
+		return len(as) > len(bs)
+	}
+	return a < b
+}
+
+func segmentRank(segment string) int {
+	switch {
+	case !strings.Contains(segment, "{"):
+		return 0
+	case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && strings.Count(segment, "{") == 1:
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,39 @@
+package openapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRouterPrecedence(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddServer("https://api.example.com/v1", "Production")
+	doc.AddOperation("/pets/{petId}", "GET", NewOperation("getPet", "", ""))
+	doc.AddOperation("/pets/mine", "GET", NewOperation("getMyPets", "", ""))
+	doc.AddOperation("/pets/{petId}.json", "GET", NewOperation("getPetJSON", "", ""))
+
+	router := NewRouter(doc)
+
+	match, err := router.Match("GET", "/pets/mine")
+	if err != nil || match.Operation.OperationID != "getMyPets" {
+		t.Errorf("Expected concrete path to win, got %v %v", match, err)
+	}
+
+	match, err = router.Match("GET", "/pets/42.json")
+	if err != nil || match.Operation.OperationID != "getPetJSON" || match.PathParams["petId"] != "42" {
+		t.Errorf("Expected partial template to match, got %v %v", match, err)
+	}
+
+	match, err = router.Match("get", "/v1/pets/42")
+	if err != nil || match.Path != "/pets/{petId}" || match.PathParams["petId"] != "42" {
+		t.Errorf("Expected templated path under server base path to match, got %v %v", match, err)
+	}
+
+	if _, err := router.Match("DELETE", "/pets/42"); !errors.Is(err, ErrMethodNotAllowed) {
+		t.Errorf("Expected ErrMethodNotAllowed, got %v", err)
+	}
+
+	if _, err := router.Match("GET", "/owners"); !errors.Is(err, ErrRouteNotFound) {
+		t.Errorf("Expected ErrRouteNotFound, got %v", err)
+	}
+}
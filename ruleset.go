@@ -0,0 +1,297 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleSet is a style guide described as data, for governance teams to encode
+// naming and structural rules without writing Go. It is a Profile, so its
+// rules run through ValidateProfiles:
+//
+//	name: acme
+//	rules:
+//	  - name: operation-id-casing
+//	    description: operationIds must be camelCase
+//	    given: operations
+//	    field: operationId
+//	    required: true
+//	    casing: camel
+//	  - name: no-trailing-slash
+//	    given: paths
+//	    field: "@key"
+//	    notPattern: ".+/$"
+//	    severity: error
+type RuleSet struct {
+	// ID names the rule set; findings are reported under "<id>/<rule>"
+	ID    string `yaml:"name" json:"name"`
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// Rule checks a field of every element a selector picks out of a document.
+// Several constraints can be combined; when the field is missing only
+// required is checked. Constraints apply to each element of array fields.
+type Rule struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Severity is "error" or "warning", the default
+	Severity Severity `yaml:"severity,omitempty" json:"severity,omitempty"`
+	// Given selects the elements to check: document, paths, operations,
+	// parameters, responses, schemas, properties or tags
+	Given string `yaml:"given" json:"given"`
+	// Field is the dotted path of the checked field within each element,
+	// "@key" for the element's name, or empty for the element itself
+	Field string `yaml:"field,omitempty" json:"field,omitempty"`
+
+	// Required fields must be present and not empty
+	Required bool `yaml:"required,omitempty" json:"required,omitempty"`
+	// Forbidden fields must be absent
+	Forbidden bool `yaml:"forbidden,omitempty" json:"forbidden,omitempty"`
+	// Pattern and NotPattern are regular expressions the value must and must not match
+	Pattern    string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	NotPattern string `yaml:"notPattern,omitempty" json:"notPattern,omitempty"`
+	// Casing is camel, pascal, snake, kebab or macro
+	Casing string `yaml:"casing,omitempty" json:"casing,omitempty"`
+	// Enum lists the allowed values
+	Enum      []string `yaml:"enum,omitempty" json:"enum,omitempty"`
+	MinLength *int     `yaml:"minLength,omitempty" json:"minLength,omitempty"`
+	MaxLength *int     `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
+}
+
+// ruleCasings maps casing names to the patterns of identifiers following them
+var ruleCasings = map[string]*regexp.Regexp{
+	"camel":  regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	"pascal": regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+	"snake":  regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+	"kebab":  regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+	"macro":  regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`),
+}
+
+// ruleSelectors list the JSON pointers of the elements a Given selects
+var ruleSelectors = map[string]func(d *Document) []string{
+	"document": func(d *Document) []string {
+		return []string{""}
+	},
+	"paths": func(d *Document) []string {
+		var pointers []string
+		for _, path := range sortedKeys(d.Paths) {
+			pointers = append(pointers, "/paths/"+escapePointer(path))
+		}
+		return pointers
+	},
+	"operations": func(d *Document) []string {
+		var pointers []string
+		d.walkOperations(func(path, method string, _ *Operation) {
+			pointers = append(pointers, operationPointer(path, method))
+		})
+		return pointers
+	},
+	"parameters": func(d *Document) []string {
+		var pointers []string
+		d.walkParameters(func(pointer string, _ Parameter) {
+			pointers = append(pointers, pointer)
+		})
+		return pointers
+	},
+	"responses": func(d *Document) []string {
+		var pointers []string
+		d.walkResponses(func(pointer string, _ Response) {
+			pointers = append(pointers, pointer)
+		})
+		return pointers
+	},
+	"schemas": func(d *Document) []string {
+		var pointers []string
+		d.walkSchemas(func(pointer string, _ *Schema) {
+			pointers = append(pointers, pointer)
+		})
+		return pointers
+	},
+	"properties": func(d *Document) []string {
+		var pointers []string
+		d.walkSchemas(func(pointer string, s *Schema) {
+			for _, name := range sortedKeys(s.Properties) {
+				pointers = append(pointers, pointer+"/properties/"+escapePointer(name))
+			}
+		})
+		return pointers
+	},
+	"tags": func(d *Document) []string {
+		var pointers []string
+		for i := range d.Tags {
+			pointers = append(pointers, "/tags/"+strconv.Itoa(i))
+		}
+		return pointers
+	},
+}
+
+// LoadRuleSet parses a rule set from YAML (or JSON) and checks its rules
+func LoadRuleSet(data []byte) (*RuleSet, error) {
+	var set RuleSet
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("openapi: parsing rule set: %w", err)
+	}
+	for i, rule := range set.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("openapi: rule %d has no name", i)
+		}
+		if _, ok := ruleSelectors[rule.Given]; !ok {
+			return nil, fmt.Errorf("openapi: rule %s: unknown given %q", rule.Name, rule.Given)
+		}
+		switch rule.Severity {
+		case "":
+			set.Rules[i].Severity = SeverityWarning
+		case SeverityError, SeverityWarning:
+		default:
+			return nil, fmt.Errorf("openapi: rule %s: unknown severity %q", rule.Name, rule.Severity)
+		}
+		if _, ok := ruleCasings[rule.Casing]; rule.Casing != "" && !ok {
+			return nil, fmt.Errorf("openapi: rule %s: unknown casing %q", rule.Name, rule.Casing)
+		}
+		for _, pattern := range []string{rule.Pattern, rule.NotPattern} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("openapi: rule %s: %w", rule.Name, err)
+			}
+		}
+	}
+	return &set, nil
+}
+
+// Name returns the name of the rule set
+func (s *RuleSet) Name() string {
+	return s.ID
+}
+
+// Validate checks the document against every rule of the set
+func (s *RuleSet) Validate(d *Document) []ValidationError {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return []ValidationError{{Path: "", Message: err.Error(), Severity: SeverityError, Rule: s.ID}}
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return []ValidationError{{Path: "", Message: err.Error(), Severity: SeverityError, Rule: s.ID}}
+	}
+
+	var errs []ValidationError
+	for _, rule := range s.Rules {
+		name := rule.Name
+		if s.ID != "" {
+			name = s.ID + "/" + rule.Name
+		}
+		selector, ok := ruleSelectors[rule.Given]
+		if !ok {
+			continue
+		}
+		for _, pointer := range selector(d) {
+			element, ok := pointerValue(tree, pointer)
+			if !ok {
+				continue
+			}
+			for _, message := range rule.check(pointer, element) {
+				if rule.Description != "" {
+					message = rule.Description + ": " + message
+				}
+				errs = append(errs, ValidationError{Path: pointer, Message: message, Severity: rule.Severity, Rule: name})
+			}
+		}
+	}
+	return errs
+}
+
+// check applies the rule to one element, returning what's wrong with it
+func (r Rule) check(pointer string, element interface{}) []string {
+	value, present := element, true
+	field := r.Field
+	switch {
+	case field == "@key":
+		value = unescapePointer(pointer[strings.LastIndex(pointer, "/")+1:])
+		field = "name"
+	case field != "":
+		for _, name := range strings.Split(field, ".") {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				present = false
+				break
+			}
+			if value, present = object[name]; !present {
+				break
+			}
+		}
+	default:
+		field = "element"
+	}
+
+	var problems []string
+	if !present || value == nil || value == "" {
+		if r.Required {
+			problems = append(problems, field+" is required")
+		}
+		return problems
+	}
+	if r.Forbidden {
+		problems = append(problems, field+" is not allowed")
+	}
+
+	values := []interface{}{value}
+	if items, ok := value.([]interface{}); ok {
+		values = items
+	}
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if r.Pattern != "" && !regexp.MustCompile(r.Pattern).MatchString(s) {
+			problems = append(problems, fmt.Sprintf("%s %q must match %s", field, s, r.Pattern))
+		}
+		if r.NotPattern != "" && regexp.MustCompile(r.NotPattern).MatchString(s) {
+			problems = append(problems, fmt.Sprintf("%s %q must not match %s", field, s, r.NotPattern))
+		}
+		if casing, ok := ruleCasings[r.Casing]; ok && !casing.MatchString(s) {
+			problems = append(problems, fmt.Sprintf("%s %q must be %s case", field, s, r.Casing))
+		}
+		if len(r.Enum) > 0 && !slices.Contains(r.Enum, s) {
+			problems = append(problems, fmt.Sprintf("%s %q must be one of %s", field, s, strings.Join(r.Enum, ", ")))
+		}
+		if r.MinLength != nil && len(s) < *r.MinLength {
+			problems = append(problems, fmt.Sprintf("%s %q must be at least %d characters long", field, s, *r.MinLength))
+		}
+		if r.MaxLength != nil && len(s) > *r.MaxLength {
+			problems = append(problems, fmt.Sprintf("%s %q must be at most %d characters long", field, s, *r.MaxLength))
+		}
+	}
+	return problems
+}
+
+// pointerValue returns the value at a JSON pointer within a decoded JSON value
+func pointerValue(value interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return value, true
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			child, ok := v[unescapePointer(token)]
+			if !ok {
+				return nil, false
+			}
+			value = child
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
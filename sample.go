@@ -0,0 +1,151 @@
+package openapi
+
+import (
+	"math"
+	"strings"
+)
+
+// sampleStrings maps string formats to representative values
+var sampleStrings = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01",
+	"time":      "12:00:00Z",
+	"duration":  "PT1H30M",
+	"email":     "user@example.com",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"password":  "secret123",
+	"byte":      "U3dhZ2dlciByb2Nrcw==",
+	"binary":    "",
+}
+
+// SampleValue builds a representative value for a schema, suitable for use as
+// an example, mock response or fuzzing seed. Explicit examples, defaults and
+// enum values take precedence; otherwise a value satisfying the schema's type,
+// format and bounds is synthesized. References are resolved against the
+// document and recursive schemas are cut off with nil.
+func (d *Document) SampleValue(s *Schema) interface{} {
+	return d.sampleValue(s, map[*Schema]bool{})
+}
+
+func (d *Document) sampleValue(s *Schema, visiting map[*Schema]bool) interface{} {
+	s = d.resolveSchema(s)
+	if s == nil || visiting[s] {
+		return nil
+	}
+	visiting[s] = true
+	defer delete(visiting, s)
+
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	}
+
+	if len(s.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, sub := range s.AllOf {
+			if obj, ok := d.sampleValue(sub, visiting).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		if obj, ok := d.sampleObject(s, visiting).(map[string]interface{}); ok {
+			for k, v := range obj {
+				merged[k] = v
+			}
+		}
+		return merged
+	}
+	if len(s.OneOf) > 0 {
+		return d.sampleValue(s.OneOf[0], visiting)
+	}
+	if len(s.AnyOf) > 0 {
+		return d.sampleValue(s.AnyOf[0], visiting)
+	}
+
+	switch s.Type.Primary() {
+	case "string":
+		return sampleString(s)
+	case "integer":
+		return int64(sampleNumber(s, true))
+	case "number":
+		return sampleNumber(s, false)
+	case "boolean":
+		return true
+	case "array":
+		count := 1
+		if s.MinItems != nil && *s.MinItems > count {
+			count = *s.MinItems
+		}
+		if s.MaxItems != nil && *s.MaxItems < count {
+			count = *s.MaxItems
+		}
+		items := make([]interface{}, 0, count)
+		for i := 0; i < count; i++ {
+			items = append(items, d.sampleValue(s.Items, visiting))
+		}
+		return items
+	case "object", "":
+		if len(s.Type) == 0 && len(s.Properties) == 0 {
+			return nil
+		}
+		return d.sampleObject(s, visiting)
+	}
+	return nil
+}
+
+func (d *Document) sampleObject(s *Schema, visiting map[*Schema]bool) interface{} {
+	obj := make(map[string]interface{}, len(s.Properties))
+	for _, name := range sortedKeys(s.Properties) {
+		obj[name] = d.sampleValue(s.Properties[name], visiting)
+	}
+	return obj
+}
+
+func sampleString(s *Schema) string {
+	value, ok := sampleStrings[s.Format]
+	if !ok {
+		value = "string"
+	}
+	if s.MinLength != nil && len(value) < *s.MinLength {
+		value += strings.Repeat("x", *s.MinLength-len(value))
+	}
+	if s.MaxLength != nil && len(value) > *s.MaxLength {
+		value = value[:*s.MaxLength]
+	}
+	return value
+}
+
+func sampleNumber(s *Schema, integer bool) float64 {
+	value := 0.0
+	min, minExclusive := s.LowerBound()
+	max, maxExclusive := s.UpperBound()
+	switch {
+	case min != nil:
+		value = *min
+		if minExclusive {
+			value++
+		}
+	case max != nil && *max < 0:
+		value = *max
+		if maxExclusive {
+			value--
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		value = math.Ceil(value / *s.MultipleOf) * *s.MultipleOf
+	}
+	if integer {
+		value = math.Ceil(value)
+	}
+	return value
+}
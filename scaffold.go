@@ -0,0 +1,44 @@
+package openapi
+
+// ScaffoldOperation returns the operation registered for a method and path,
+// adding a placeholder when there is none: an operation with an operationId
+// derived from the method and path, the given path parameters, a required
+// string parameter for every other path template variable and a 200 response.
+// Framework integrations use it to document the routes of an application
+// before they are described in detail.
+func (d *Document) ScaffoldOperation(method, path string, params ...Parameter) *Operation {
+	if item, ok := d.Paths[path]; ok {
+		if op := item.Operation(method); op != nil {
+			return op
+		}
+	}
+	op := NewOperation(patternOperationID(method, path), "", "")
+	op.Parameters = append(op.Parameters, params...)
+	op = op.WithPathTemplateParameters(path).
+		WithResponse("200", "", Response{Description: "OK"})
+	d.AddOperation(path, method, op)
+	if item, ok := d.Paths[path]; ok {
+		if registered := item.Operation(method); registered != nil {
+			return registered
+		}
+	}
+	return &Operation{}
+}
+
+// WithPathTemplateParameters adds a required string path parameter for every
+// variable of the path template the operation doesn't declare yet
+func (o Operation) WithPathTemplateParameters(path string) Operation {
+	for _, name := range templateVariables(path) {
+		declared := false
+		for _, p := range o.Parameters {
+			if p.In == "path" && p.Name == name {
+				declared = true
+				break
+			}
+		}
+		if !declared {
+			o = o.WithPathParameter(name, "", NewStringSchema())
+		}
+	}
+	return o
+}
@@ -2,47 +2,136 @@ package openapi
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 )
 
 // Schema represents a schema in OpenAPI
 type Schema struct {
-	Ref                  string                 `json:"$ref,omitempty"`
-	Title                string                 `json:"title,omitempty"`
-	MultipleOf           *float64               `json:"multipleOf,omitempty"`
-	Maximum              *float64               `json:"maximum,omitempty"`
-	ExclusiveMaximum     bool                   `json:"exclusiveMaximum,omitempty"`
-	Minimum              *float64               `json:"minimum,omitempty"`
-	ExclusiveMinimum     bool                   `json:"exclusiveMinimum,omitempty"`
-	MaxLength            *int                   `json:"maxLength,omitempty"`
-	MinLength            *int                   `json:"minLength,omitempty"`
-	Pattern              string                 `json:"pattern,omitempty"`
-	MaxItems             *int                   `json:"maxItems,omitempty"`
-	MinItems             *int                   `json:"minItems,omitempty"`
-	UniqueItems          bool                   `json:"uniqueItems,omitempty"`
-	MaxProperties        *int                   `json:"maxProperties,omitempty"`
-	MinProperties        *int                   `json:"minProperties,omitempty"`
-	Required             []string               `json:"required,omitempty"`
-	Enum                 []interface{}          `json:"enum,omitempty"`
-	Type                 string                 `json:"type,omitempty"`
-	AllOf                []*Schema              `json:"allOf,omitempty"`
-	OneOf                []*Schema              `json:"oneOf,omitempty"`
-	AnyOf                []*Schema              `json:"anyOf,omitempty"`
-	Not                  *Schema                `json:"not,omitempty"`
-	Items                *Schema                `json:"items,omitempty"`
-	Properties           map[string]*Schema     `json:"properties,omitempty"`
-	AdditionalProperties *AdditionalProperties  `json:"additionalProperties,omitempty"`
-	Description          string                 `json:"description,omitempty"`
-	Format               string                 `json:"format,omitempty"`
-	Default              interface{}            `json:"default,omitempty"`
-	Nullable             bool                   `json:"nullable,omitempty"`
-	Discriminator        *Discriminator         `json:"discriminator,omitempty"`
-	ReadOnly             bool                   `json:"readOnly,omitempty"`
-	WriteOnly            bool                   `json:"writeOnly,omitempty"`
-	XML                  *XML                   `json:"xml,omitempty"`
-	ExternalDocs         *ExternalDocs          `json:"externalDocs,omitempty"`
-	Example              interface{}            `json:"example,omitempty"`
-	Deprecated           bool                   `json:"deprecated,omitempty"`
-	Extensions           map[string]interface{} `json:"-"`
+	Dialect               string                 `json:"$schema,omitempty"`
+	Ref                   string                 `json:"$ref,omitempty"`
+	Comment               string                 `json:"$comment,omitempty"`
+	Defs                  map[string]*Schema     `json:"$defs,omitempty"`
+	Title                 string                 `json:"title,omitempty"`
+	MultipleOf            *float64               `json:"multipleOf,omitempty"`
+	Maximum               *float64               `json:"maximum,omitempty"`
+	ExclusiveMaximum      ExclusiveBound         `json:"exclusiveMaximum,omitzero"`
+	Minimum               *float64               `json:"minimum,omitempty"`
+	ExclusiveMinimum      ExclusiveBound         `json:"exclusiveMinimum,omitzero"`
+	MaxLength             *int                   `json:"maxLength,omitempty"`
+	MinLength             *int                   `json:"minLength,omitempty"`
+	Pattern               string                 `json:"pattern,omitempty"`
+	MaxItems              *int                   `json:"maxItems,omitempty"`
+	MinItems              *int                   `json:"minItems,omitempty"`
+	UniqueItems           bool                   `json:"uniqueItems,omitempty"`
+	MaxProperties         *int                   `json:"maxProperties,omitempty"`
+	MinProperties         *int                   `json:"minProperties,omitempty"`
+	Required              []string               `json:"required,omitempty"`
+	DependentRequired     map[string][]string    `json:"dependentRequired,omitempty"`
+	DependentSchemas      map[string]*Schema     `json:"dependentSchemas,omitempty"`
+	Enum                  []interface{}          `json:"enum,omitempty"`
+	Const                 interface{}            `json:"const,omitempty"`
+	Type                  Types                  `json:"type,omitempty"`
+	AllOf                 []*Schema              `json:"allOf,omitempty"`
+	OneOf                 []*Schema              `json:"oneOf,omitempty"`
+	AnyOf                 []*Schema              `json:"anyOf,omitempty"`
+	Not                   *Schema                `json:"not,omitempty"`
+	If                    *Schema                `json:"if,omitempty"`
+	Then                  *Schema                `json:"then,omitempty"`
+	Else                  *Schema                `json:"else,omitempty"`
+	Items                 *Schema                `json:"items,omitempty"`
+	PrefixItems           []*Schema              `json:"prefixItems,omitempty"`
+	Contains              *Schema                `json:"contains,omitempty"`
+	MinContains           *int                   `json:"minContains,omitempty"`
+	MaxContains           *int                   `json:"maxContains,omitempty"`
+	Properties            map[string]*Schema     `json:"properties,omitempty"`
+	AdditionalProperties  *AdditionalProperties  `json:"additionalProperties,omitempty"`
+	PatternProperties     map[string]*Schema     `json:"patternProperties,omitempty"`
+	PropertyNames         *Schema                `json:"propertyNames,omitempty"`
+	UnevaluatedProperties *AdditionalProperties  `json:"unevaluatedProperties,omitempty"`
+	Description           string                 `json:"description,omitempty"`
+	Format                string                 `json:"format,omitempty"`
+	Default               interface{}            `json:"default,omitempty"`
+	Nullable              bool                   `json:"nullable,omitempty"`
+	Discriminator         *Discriminator         `json:"discriminator,omitempty"`
+	ReadOnly              bool                   `json:"readOnly,omitempty"`
+	WriteOnly             bool                   `json:"writeOnly,omitempty"`
+	XML                   *XML                   `json:"xml,omitempty"`
+	ExternalDocs          *ExternalDocs          `json:"externalDocs,omitempty"`
+	Example               interface{}            `json:"example,omitempty"`
+	Examples              []interface{}          `json:"examples,omitempty"`
+	Deprecated            bool                   `json:"deprecated,omitempty"`
+	Extensions            map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type schema Schema
+	return marshalWithExtensions(schema(s), s.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type schema Schema
+	if err := json.Unmarshal(data, (*schema)(s)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	if err != nil {
+		return err
+	}
+	s.Extensions, err = unmarshalKeywords(data, ext)
+	return err
+}
+
+// Types is the type of a schema: a single type or, as in OpenAPI 3.1, a list
+// of types such as ["string", "null"]. A single type is marshaled as a plain
+// string, the form OpenAPI 3.0 requires.
+type Types []string
+
+// MarshalJSON implements custom JSON marshaling for Types
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for Types, accepting a
+// single type or a list of types
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	var types []string
+	if err := json.Unmarshal(data, &types); err != nil {
+		return fmt.Errorf("type: %w", err)
+	}
+	*t = types
+	return nil
+}
+
+// Is reports whether the type list includes name
+func (t Types) Is(name string) bool {
+	return slices.Contains(t, name)
+}
+
+// Primary returns the first type besides "null", or an empty string when there is none
+func (t Types) Primary() string {
+	for _, name := range t {
+		if name != "null" {
+			return name
+		}
+	}
+	return ""
+}
+
+// String returns the types joined by " or ", as in "string or null"
+func (t Types) String() string {
+	return strings.Join(t, " or ")
 }
 
 // AdditionalProperties represents additional properties in a schema
@@ -86,51 +175,93 @@ func (ap *AdditionalProperties) UnmarshalJSON(data []byte) error {
 
 // Discriminator represents a discriminator in OpenAPI
 type Discriminator struct {
-	PropertyName string            `json:"propertyName"`
-	Mapping      map[string]string `json:"mapping,omitempty"`
+	PropertyName string                 `json:"propertyName"`
+	Mapping      map[string]string      `json:"mapping,omitempty"`
+	Extensions   map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (d Discriminator) MarshalJSON() ([]byte, error) {
+	type discriminator Discriminator
+	return marshalWithExtensions(discriminator(d), d.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (d *Discriminator) UnmarshalJSON(data []byte) error {
+	type discriminator Discriminator
+	if err := json.Unmarshal(data, (*discriminator)(d)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	d.Extensions = ext
+	return err
 }
 
 // XML represents XML metadata in OpenAPI
 type XML struct {
-	Name      string `json:"name,omitempty"`
-	Namespace string `json:"namespace,omitempty"`
-	Prefix    string `json:"prefix,omitempty"`
-	Attribute bool   `json:"attribute,omitempty"`
-	Wrapped   bool   `json:"wrapped,omitempty"`
+	Name       string                 `json:"name,omitempty"`
+	Namespace  string                 `json:"namespace,omitempty"`
+	Prefix     string                 `json:"prefix,omitempty"`
+	Attribute  bool                   `json:"attribute,omitempty"`
+	Wrapped    bool                   `json:"wrapped,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (x XML) MarshalJSON() ([]byte, error) {
+	type xml XML
+	return marshalWithExtensions(xml(x), x.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (x *XML) UnmarshalJSON(data []byte) error {
+	type xml XML
+	if err := json.Unmarshal(data, (*xml)(x)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	x.Extensions = ext
+	return err
+}
+
+// AllowsNull reports whether the schema accepts null, through the OpenAPI 3.0
+// nullable keyword or a "null" type
+func (s *Schema) AllowsNull() bool {
+	return s.Nullable || s.Type.Is("null")
 }
 
 // NewStringSchema creates a string schema
 func NewStringSchema() *Schema {
 	return &Schema{
-		Type: "string",
+		Type: Types{"string"},
 	}
 }
 
 // NewIntegerSchema creates an integer schema
 func NewIntegerSchema() *Schema {
 	return &Schema{
-		Type: "integer",
+		Type: Types{"integer"},
 	}
 }
 
 // NewNumberSchema creates a number schema
 func NewNumberSchema() *Schema {
 	return &Schema{
-		Type: "number",
+		Type: Types{"number"},
 	}
 }
 
 // NewBooleanSchema creates a boolean schema
 func NewBooleanSchema() *Schema {
 	return &Schema{
-		Type: "boolean",
+		Type: Types{"boolean"},
 	}
 }
 
 // NewArraySchema creates an array schema
 func NewArraySchema(items *Schema) *Schema {
 	return &Schema{
-		Type:  "array",
+		Type:  Types{"array"},
 		Items: items,
 	}
 }
@@ -138,7 +269,7 @@ func NewArraySchema(items *Schema) *Schema {
 // NewObjectSchema creates an object schema
 func NewObjectSchema() *Schema {
 	return &Schema{
-		Type:       "object",
+		Type:       Types{"object"},
 		Properties: make(map[string]*Schema),
 	}
 }
@@ -249,7 +380,17 @@ func (s Schema) WithRequired(fields ...string) Schema {
 	return s
 }
 
-// WithNullable makes a schema nullable
+// AddType adds a type to the schema, as in AddType("null") making a schema
+// nullable in the OpenAPI 3.1 form
+func (s Schema) AddType(name string) Schema {
+	if !s.Type.Is(name) {
+		s.Type = append(slices.Clone(s.Type), name)
+	}
+	return s
+}
+
+// WithNullable makes a schema nullable with the OpenAPI 3.0 nullable keyword;
+// use AddType("null") for the OpenAPI 3.1 form
 func (s Schema) WithNullable(nullable bool) Schema {
 	s.Nullable = nullable
 	return s
@@ -273,6 +414,15 @@ func (s Schema) WithDeprecated(deprecated bool) Schema {
 	return s
 }
 
+// WithExtension sets a specification extension; the name must start with "x-"
+func (s Schema) WithExtension(name string, value interface{}) Schema {
+	if s.Extensions == nil {
+		s.Extensions = make(map[string]interface{})
+	}
+	s.Extensions[name] = value
+	return s
+}
+
 // Common schema constructors for convenience
 
 // StringSchema creates a string schema with format
@@ -284,6 +434,14 @@ func StringSchema(format string) *Schema {
 	return schema
 }
 
+// NullableStringSchema creates a string schema accepting null, in the
+// OpenAPI 3.1 form ["string", "null"]
+func NullableStringSchema() *Schema {
+	return &Schema{
+		Type: Types{"string", "null"},
+	}
+}
+
 // EmailSchema creates an email string schema
 func EmailSchema() *Schema {
 	return StringSchema("email")
@@ -299,6 +457,16 @@ func DateSchema() *Schema {
 	return StringSchema("date")
 }
 
+// TimeSchema creates a time string schema, e.g. "14:30:00Z"
+func TimeSchema() *Schema {
+	return StringSchema("time")
+}
+
+// DurationSchema creates an ISO 8601 duration string schema, e.g. "PT1H30M"
+func DurationSchema() *Schema {
+	return StringSchema("duration")
+}
+
 // UUIDSchema creates a UUID string schema
 func UUIDSchema() *Schema {
 	return StringSchema("uuid")
@@ -1,31 +1,89 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // SecurityScheme represents a security scheme in OpenAPI
 type SecurityScheme struct {
-	Type             string      `json:"type"`
-	Description      string      `json:"description,omitempty"`
-	Name             string      `json:"name,omitempty"`
-	In               string      `json:"in,omitempty"`
-	Scheme           string      `json:"scheme,omitempty"`
-	BearerFormat     string      `json:"bearerFormat,omitempty"`
-	Flows            *OAuthFlows `json:"flows,omitempty"`
-	OpenIdConnectUrl string      `json:"openIdConnectUrl,omitempty"`
+	Type             string                 `json:"type"`
+	Description      string                 `json:"description,omitempty"`
+	Name             string                 `json:"name,omitempty"`
+	In               string                 `json:"in,omitempty"`
+	Scheme           string                 `json:"scheme,omitempty"`
+	BearerFormat     string                 `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows            `json:"flows,omitempty"`
+	OpenIdConnectUrl string                 `json:"openIdConnectUrl,omitempty"`
+	Extensions       map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	type securityScheme SecurityScheme
+	return marshalWithExtensions(securityScheme(s), s.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error {
+	type securityScheme SecurityScheme
+	if err := json.Unmarshal(data, (*securityScheme)(s)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	s.Extensions = ext
+	return err
 }
 
 // OAuthFlows represents OAuth flows in OpenAPI
 type OAuthFlows struct {
-	Implicit          *OAuthFlow `json:"implicit,omitempty"`
-	Password          *OAuthFlow `json:"password,omitempty"`
-	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
-	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+	Implicit          *OAuthFlow             `json:"implicit,omitempty"`
+	Password          *OAuthFlow             `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow             `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow             `json:"authorizationCode,omitempty"`
+	Extensions        map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (f OAuthFlows) MarshalJSON() ([]byte, error) {
+	type oAuthFlows OAuthFlows
+	return marshalWithExtensions(oAuthFlows(f), f.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (f *OAuthFlows) UnmarshalJSON(data []byte) error {
+	type oAuthFlows OAuthFlows
+	if err := json.Unmarshal(data, (*oAuthFlows)(f)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	f.Extensions = ext
+	return err
 }
 
 // OAuthFlow represents an OAuth flow in OpenAPI
 type OAuthFlow struct {
-	AuthorizationUrl string            `json:"authorizationUrl,omitempty"`
-	TokenUrl         string            `json:"tokenUrl,omitempty"`
-	RefreshUrl       string            `json:"refreshUrl,omitempty"`
-	Scopes           map[string]string `json:"scopes"`
+	AuthorizationUrl string                 `json:"authorizationUrl,omitempty"`
+	TokenUrl         string                 `json:"tokenUrl,omitempty"`
+	RefreshUrl       string                 `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string      `json:"scopes"`
+	Extensions       map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (f OAuthFlow) MarshalJSON() ([]byte, error) {
+	type oAuthFlow OAuthFlow
+	return marshalWithExtensions(oAuthFlow(f), f.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (f *OAuthFlow) UnmarshalJSON(data []byte) error {
+	type oAuthFlow OAuthFlow
+	if err := json.Unmarshal(data, (*oAuthFlow)(f)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	f.Extensions = ext
+	return err
 }
 
 // NewSecurityScheme creates a new security scheme
@@ -238,3 +296,12 @@ func RequireOAuth(schemeName string, scopes ...string) SecurityRequirement {
 		schemeName: scopes,
 	}
 }
+
+// WithExtension sets a specification extension; the name must start with "x-"
+func (s SecurityScheme) WithExtension(name string, value interface{}) SecurityScheme {
+	if s.Extensions == nil {
+		s.Extensions = make(map[string]interface{})
+	}
+	s.Extensions[name] = value
+	return s
+}
@@ -0,0 +1,209 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SequenceDiagram is a Mermaid sequence diagram of a multi-step interaction:
+// an operation, the operations its response links lead to and the callbacks
+// they trigger
+type SequenceDiagram struct {
+	// Title names the operation starting the interaction
+	Title string
+	// Mermaid is the diagram source
+	Mermaid string
+}
+
+// SequenceDiagrams renders a diagram for every interaction described by
+// links and callbacks. Interactions start at the operations with links or
+// callbacks that no other operation links to; operations only reachable
+// through a cycle of links start interactions of their own. Each diagram
+// follows a link to an operation it already shows only once.
+func (d *Document) SequenceDiagrams() []SequenceDiagram {
+	var starts []*Operation
+	targets := make(map[*Operation]bool)
+	d.walkOperations(func(path, method string, op *Operation) {
+		if !d.hasInteraction(op) {
+			return
+		}
+		starts = append(starts, op)
+		d.walkLinks(op, func(_, _ string, _ Link, target *Operation) {
+			if target != op {
+				targets[target] = true
+			}
+		})
+	})
+
+	var diagrams []SequenceDiagram
+	shown := make(map[*Operation]bool)
+	render := func(op *Operation) {
+		path, method := d.operationLocation(op)
+		w := &sequenceWriter{doc: d, shown: make(map[*Operation]bool)}
+		w.b.WriteString("sequenceDiagram\n    participant Client\n")
+		fmt.Fprintf(&w.b, "    participant API as %s\n", mermaidText(d.Info.Title))
+		w.operation(path, method, op)
+		for shownOp := range w.shown {
+			shown[shownOp] = true
+		}
+		title := op.OperationID
+		if title == "" {
+			title = method + " " + path
+		}
+		diagrams = append(diagrams, SequenceDiagram{Title: title, Mermaid: w.b.String()})
+	}
+	for _, op := range starts {
+		if !targets[op] {
+			render(op)
+		}
+	}
+	for _, op := range starts {
+		if !shown[op] {
+			render(op)
+		}
+	}
+	return diagrams
+}
+
+// SequenceDiagramMarkdown renders the sequence diagrams as Markdown sections
+// with mermaid code blocks, which GitHub and most documentation portals draw
+func (d *Document) SequenceDiagramMarkdown() string {
+	var b strings.Builder
+	for _, diagram := range d.SequenceDiagrams() {
+		fmt.Fprintf(&b, "## %s\n\n```mermaid\n%s```\n\n", diagram.Title, diagram.Mermaid)
+	}
+	return b.String()
+}
+
+// sequenceWriter renders the messages of one sequence diagram
+type sequenceWriter struct {
+	doc   *Document
+	b     strings.Builder
+	shown map[*Operation]bool
+}
+
+// operation renders the request and response of an operation, followed by
+// its callbacks and the operations its links lead to
+func (w *sequenceWriter) operation(path, method string, op *Operation) {
+	w.shown[op] = true
+	fmt.Fprintf(&w.b, "    Client->>API: %s %s\n", method, mermaidText(path))
+	if code := primaryResponseCode(op); code != "" {
+		fmt.Fprintf(&w.b, "    API-->>Client: %s %s\n", code, mermaidText(w.doc.resolveResponse(op.Responses[code]).Description))
+	}
+
+	for _, name := range sortedKeys(op.Callbacks) {
+		callback := op.Callbacks[name]
+		if callback.Ref != nil {
+			resolved, _ := w.doc.ResolveRef(callback.Ref.Ref)
+			callback, _ = resolved.(Callback)
+		}
+		for _, expression := range sortedKeys(callback.Paths) {
+			item := callback.Paths[expression]
+			for _, m := range httpMethods {
+				cb := item.Operation(m)
+				if cb == nil {
+					continue
+				}
+				fmt.Fprintf(&w.b, "    API-)Consumer: %s %s (%s)\n", m, mermaidText(expression), mermaidText(name))
+				if code := primaryResponseCode(cb); code != "" {
+					fmt.Fprintf(&w.b, "    Consumer--)API: %s\n", code)
+				}
+			}
+		}
+	}
+
+	w.doc.walkLinks(op, func(code, name string, link Link, target *Operation) {
+		var params []string
+		for _, param := range sortedKeys(link.Parameters) {
+			params = append(params, fmt.Sprintf("%s = %v", param, link.Parameters[param]))
+		}
+		note := name
+		if len(params) > 0 {
+			note += ": " + strings.Join(params, ", ")
+		}
+		targetPath, targetMethod := w.doc.operationLocation(target)
+		if w.shown[target] {
+			note += fmt.Sprintf(" (back to %s %s)", targetMethod, targetPath)
+		}
+		fmt.Fprintf(&w.b, "    Note over Client,API: %s\n", mermaidText(note))
+		if !w.shown[target] {
+			w.operation(targetPath, targetMethod, target)
+		}
+	})
+}
+
+// hasInteraction reports whether an operation has callbacks or response links
+func (d *Document) hasInteraction(op *Operation) bool {
+	if len(op.Callbacks) > 0 {
+		return true
+	}
+	for _, response := range op.Responses {
+		if len(d.resolveResponse(response).Links) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// walkLinks calls fn for every response link of an operation whose target
+// operation is found in the document, ordered by response code and link name
+func (d *Document) walkLinks(op *Operation, fn func(code, name string, link Link, target *Operation)) {
+	for _, code := range sortedKeys(op.Responses) {
+		links := d.resolveResponse(op.Responses[code]).Links
+		for _, name := range sortedKeys(links) {
+			if target := d.linkTarget(links[name]); target != nil {
+				fn(code, name, links[name], target)
+			}
+		}
+	}
+}
+
+// linkTarget returns the operation a link leads to by operationId or by a
+// local operationRef such as "#/paths/~1pets~1{id}/get", or nil
+func (d *Document) linkTarget(link Link) *Operation {
+	if link.OperationID != "" {
+		_, _, op := d.FindOperation(link.OperationID)
+		return op
+	}
+	pointer, ok := strings.CutPrefix(link.OperationRef, "#/paths/")
+	if !ok {
+		return nil
+	}
+	i := strings.LastIndex(pointer, "/")
+	if i < 0 {
+		return nil
+	}
+	item, ok := d.Paths[unescapePointer(pointer[:i])]
+	if !ok {
+		return nil
+	}
+	return item.Operation(strings.ToUpper(pointer[i+1:]))
+}
+
+// operationLocation returns the path and method an operation is registered under
+func (d *Document) operationLocation(target *Operation) (path, method string) {
+	d.walkOperations(func(p, m string, op *Operation) {
+		if op == target {
+			path, method = p, m
+		}
+	})
+	return path, method
+}
+
+// primaryResponseCode returns the first success response code of an
+// operation, or its first response code when none succeeds
+func primaryResponseCode(op *Operation) string {
+	codes := sortedKeys(op.Responses)
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			return code
+		}
+	}
+	if len(codes) > 0 {
+		return codes[0]
+	}
+	return ""
+}
+
+// mermaidText escapes the characters Mermaid treats specially in message text
+var mermaidText = strings.NewReplacer("#", "#35;", ";", "#59;", "\n", " ").Replace
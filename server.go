@@ -1,15 +1,70 @@
 package openapi
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 // Server represents a server object
 type Server struct {
 	URL         string                    `json:"url"`
 	Description string                    `json:"description,omitempty"`
 	Variables   map[string]ServerVariable `json:"variables,omitempty"`
+	Extensions  map[string]interface{}    `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (s Server) MarshalJSON() ([]byte, error) {
+	type server Server
+	return marshalWithExtensions(server(s), s.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (s *Server) UnmarshalJSON(data []byte) error {
+	type server Server
+	if err := json.Unmarshal(data, (*server)(s)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	s.Extensions = ext
+	return err
 }
 
 // ServerVariable represents a server variable
 type ServerVariable struct {
-	Enum        []string `json:"enum,omitempty"`
-	Default     string   `json:"default"`
-	Description string   `json:"description,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Default     string                 `json:"default"`
+	Description string                 `json:"description,omitempty"`
+	Extensions  map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (v ServerVariable) MarshalJSON() ([]byte, error) {
+	type serverVariable ServerVariable
+	return marshalWithExtensions(serverVariable(v), v.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (v *ServerVariable) UnmarshalJSON(data []byte) error {
+	type serverVariable ServerVariable
+	if err := json.Unmarshal(data, (*serverVariable)(v)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	v.Extensions = ext
+	return err
+}
+
+// Expand substitutes the server variables in the URL, using values when given
+// and falling back to each variable's default
+func (s Server) Expand(values map[string]string) string {
+	expanded := s.URL
+	for name, variable := range s.Variables {
+		value, ok := values[name]
+		if !ok {
+			value = variable.Default
+		}
+		expanded = strings.ReplaceAll(expanded, "{"+name+"}", value)
+	}
+	return expanded
 }
@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SharedParameter is an inline parameter repeated identically across the
+// path items and operations of a document
+type SharedParameter struct {
+	// Name is the component name the parameter is promoted under
+	Name      string
+	Parameter Parameter
+	// Pointers locates the occurrences of the parameter
+	Pointers []string
+}
+
+// SharedParameters finds the inline parameters declared identically at least
+// min times, such as an X-Tenant-ID header repeated on every operation,
+// ordered by name. Each gets a component name derived from the parameter
+// name, e.g. "XTenantID", suffixed with its location or a number when
+// another parameter component already uses the name.
+func (d *Document) SharedParameters(min int) []SharedParameter {
+	type group struct {
+		param    Parameter
+		pointers []string
+	}
+	groups := make(map[string]*group)
+	var order []string
+	add := func(pointer string, p Parameter) {
+		if p.Ref != nil {
+			return
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			return
+		}
+		key := string(canonicalJSON(data))
+		g, ok := groups[key]
+		if !ok {
+			g = &group{param: p}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.pointers = append(g.pointers, pointer)
+	}
+	for _, path := range sortedKeys(d.Paths) {
+		for i, p := range d.Paths[path].Parameters {
+			add("/paths/"+escapePointer(path)+"/parameters/"+strconv.Itoa(i), p)
+		}
+	}
+	d.walkOperations(func(path, method string, op *Operation) {
+		for i, p := range op.Parameters {
+			add(operationPointer(path, method)+"/parameters/"+strconv.Itoa(i), p)
+		}
+	})
+
+	var shared []SharedParameter
+	taken := make(map[string]bool)
+	for _, key := range order {
+		g := groups[key]
+		if len(g.pointers) < min || len(g.pointers) < 2 {
+			continue
+		}
+		shared = append(shared, SharedParameter{Name: d.sharedParameterName(g.param, taken), Parameter: g.param, Pointers: g.pointers})
+	}
+	slices.SortFunc(shared, func(a, b SharedParameter) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return shared
+}
+
+// sharedParameterName picks a component name for a parameter that no other
+// parameter component uses, or the name of an identical component
+func (d *Document) sharedParameterName(p Parameter, taken map[string]bool) string {
+	data, _ := json.Marshal(p)
+	free := func(name string) bool {
+		if taken[name] {
+			return false
+		}
+		if d.Components == nil {
+			return true
+		}
+		existing, ok := d.Components.Parameters[name]
+		if !ok {
+			return true
+		}
+		existingData, _ := json.Marshal(existing)
+		return bytes.Equal(canonicalJSON(existingData), canonicalJSON(data))
+	}
+	base := exportedName(p.Name)
+	name := base
+	if !free(name) {
+		name = base + exportedName(p.In)
+	}
+	for i := 2; !free(name); i++ {
+		name = base + exportedName(p.In) + strconv.Itoa(i)
+	}
+	taken[name] = true
+	return name
+}
+
+// PromoteSharedParameters moves the parameters SharedParameters finds into
+// the parameter components and replaces their occurrences by references
+func (d *Document) PromoteSharedParameters(min int) *Document {
+	if !d.mutable("PromoteSharedParameters") {
+		return d
+	}
+	shared := d.SharedParameters(min)
+	if len(shared) == 0 {
+		return d
+	}
+	refs := make(map[string]string)
+	components := d.AddComponents()
+	if components.Parameters == nil {
+		components.Parameters = make(map[string]Parameter)
+	}
+	for _, s := range shared {
+		components.Parameters[s.Name] = s.Parameter
+		for _, pointer := range s.Pointers {
+			refs[pointer] = "#/components/parameters/" + escapePointer(s.Name)
+		}
+	}
+
+	d.ownOperations()
+	replace := func(base string, params []Parameter) []Parameter {
+		params = slices.Clone(params)
+		for i := range params {
+			if ref, ok := refs[base+"/parameters/"+strconv.Itoa(i)]; ok {
+				params[i] = Parameter{Ref: NewReference(ref)}
+			}
+		}
+		return params
+	}
+	for path, item := range d.Paths {
+		item.Parameters = replace("/paths/"+escapePointer(path), item.Parameters)
+		d.Paths[path] = item
+	}
+	d.walkOperations(func(path, method string, op *Operation) {
+		op.Parameters = replace(operationPointer(path, method), op.Parameters)
+	})
+	return d
+}
@@ -0,0 +1,380 @@
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SizeOptions selects the reductions OptimizeSize applies. Without any, it
+// only reports what the document's size consists of.
+type SizeOptions struct {
+	// DropExamples removes every example and examples, including the
+	// example components
+	DropExamples bool
+	// StripDescriptions removes every description except those of
+	// responses, which the specification requires
+	StripDescriptions bool
+	// DedupSchemas replaces inline object schemas that occur more than once,
+	// or equal a component schema, by a reference to a component. New
+	// components are named after the title or property of the schema.
+	DedupSchemas bool
+	// Top is the number of contributors reported per category, 10 if zero
+	Top int
+}
+
+// SizeContributor is a part of the document that weighs on its size
+type SizeContributor struct {
+	// Pointer locates the part, or its first copy for duplicates
+	Pointer string `json:"pointer"`
+	// Bytes is the size of the part in compact JSON, of all copies for duplicates
+	Bytes int `json:"bytes"`
+	// Count is the number of copies of a duplicated part
+	Count int `json:"count,omitempty"`
+}
+
+// SizeReport lists the biggest contributors to the size of a document and
+// its size before and after optimization, in compact JSON and gzipped as
+// served with compression
+type SizeReport struct {
+	Bytes               int               `json:"bytes"`
+	GzipBytes           int               `json:"gzipBytes"`
+	OptimizedBytes      int               `json:"optimizedBytes"`
+	OptimizedGzipBytes  int               `json:"optimizedGzipBytes"`
+	LargestSchemas      []SizeContributor `json:"largestSchemas,omitempty"`
+	DuplicatedExamples  []SizeContributor `json:"duplicatedExamples,omitempty"`
+	LongestDescriptions []SizeContributor `json:"longestDescriptions,omitempty"`
+}
+
+// Markdown renders the report as a summary followed by a table per category
+func (r *SizeReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Size: %d bytes (%d gzipped), optimized: %d bytes (%d gzipped)\n",
+		r.Bytes, r.GzipBytes, r.OptimizedBytes, r.OptimizedGzipBytes)
+	for _, category := range []struct {
+		title        string
+		contributors []SizeContributor
+	}{
+		{"Largest schemas", r.LargestSchemas},
+		{"Duplicated examples", r.DuplicatedExamples},
+		{"Longest descriptions", r.LongestDescriptions},
+	} {
+		if len(category.contributors) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n| Pointer | Bytes | Copies |\n|---|---|---|\n", category.title)
+		for _, c := range category.contributors {
+			count := ""
+			if c.Count > 0 {
+				count = fmt.Sprint(c.Count)
+			}
+			fmt.Fprintf(&b, "| `%s` | %d | %s |\n", c.Pointer, c.Bytes, count)
+		}
+	}
+	return b.String()
+}
+
+// GzipSize returns the size of data once gzipped, to check documents
+// against the budgets of the servers and gateways serving them
+func GzipSize(data []byte) int {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Len()
+}
+
+// OptimizeSize reports the biggest contributors to the size of the document
+// and returns a copy with the reductions of opts applied. The document
+// itself is left untouched.
+func (d *Document) OptimizeSize(opts SizeOptions) (*Document, *SizeReport, error) {
+	top := opts.Top
+	if top == 0 {
+		top = 10
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, nil, err
+	}
+
+	report := &SizeReport{
+		Bytes:               len(data),
+		GzipBytes:           GzipSize(data),
+		LargestSchemas:      largest(d.topLevelSchemaSizes(), top),
+		DuplicatedExamples:  largest(duplicatedExamples(tree), top),
+		LongestDescriptions: largest(descriptionSizes(tree), top),
+	}
+
+	if opts.DedupSchemas {
+		d.dedupSchemas(tree)
+	}
+	walkObjects("", tree, func(pointer string, object map[string]interface{}) {
+		if namesMembers(pointer) {
+			return
+		}
+		if opts.DropExamples {
+			delete(object, "example")
+			delete(object, "examples")
+		}
+		if opts.StripDescriptions && !isResponsePointer(pointer) {
+			delete(object, "description")
+		}
+	})
+
+	if data, err = json.Marshal(tree); err != nil {
+		return nil, nil, err
+	}
+	optimized := &Document{}
+	if err := json.Unmarshal(data, optimized); err != nil {
+		return nil, nil, err
+	}
+	if data, err = json.Marshal(optimized); err != nil {
+		return nil, nil, err
+	}
+	report.OptimizedBytes = len(data)
+	report.OptimizedGzipBytes = GzipSize(data)
+	return optimized, report, nil
+}
+
+// topLevelSchemaSizes returns the sizes of the schemas that aren't nested in
+// another schema: component schemas and the schemas of parameters, headers
+// and media types
+func (d *Document) topLevelSchemaSizes() []SizeContributor {
+	var sizes []SizeContributor
+	pointers := make(map[string]bool)
+	d.walkSchemas(func(pointer string, s *Schema) {
+		pointers[pointer] = true
+		if hasAncestor(pointer, pointers) {
+			return
+		}
+		if data, err := json.Marshal(s); err == nil {
+			sizes = append(sizes, SizeContributor{Pointer: pointer, Bytes: len(data)})
+		}
+	})
+	return sizes
+}
+
+// duplicatedExamples returns the example values occurring more than once
+func duplicatedExamples(tree map[string]interface{}) []SizeContributor {
+	copies := make(map[string][]string)
+	add := func(pointer string, value interface{}) {
+		data, err := json.Marshal(value)
+		if err == nil {
+			copies[string(data)] = append(copies[string(data)], pointer)
+		}
+	}
+	walkObjects("", tree, func(pointer string, object map[string]interface{}) {
+		if namesMembers(pointer) {
+			return
+		}
+		if value, ok := object["example"]; ok {
+			add(pointer+"/example", value)
+		}
+		switch examples := object["examples"].(type) {
+		case []interface{}:
+			for i, value := range examples {
+				add(fmt.Sprintf("%s/examples/%d", pointer, i), value)
+			}
+		case map[string]interface{}:
+			for _, name := range sortedKeys(examples) {
+				example, _ := examples[name].(map[string]interface{})
+				if value, ok := example["value"]; ok {
+					add(pointer+"/examples/"+escapePointer(name)+"/value", value)
+				}
+			}
+		}
+	})
+
+	var duplicates []SizeContributor
+	for value, pointers := range copies {
+		if len(pointers) > 1 {
+			sort.Strings(pointers)
+			duplicates = append(duplicates, SizeContributor{Pointer: pointers[0], Bytes: len(value) * len(pointers), Count: len(pointers)})
+		}
+	}
+	return duplicates
+}
+
+// descriptionSizes returns the sizes of all descriptions
+func descriptionSizes(tree map[string]interface{}) []SizeContributor {
+	var sizes []SizeContributor
+	walkObjects("", tree, func(pointer string, object map[string]interface{}) {
+		if description, ok := object["description"].(string); ok && !namesMembers(pointer) {
+			sizes = append(sizes, SizeContributor{Pointer: pointer + "/description", Bytes: len(description)})
+		}
+	})
+	return sizes
+}
+
+// dedupSchemas replaces duplicated inline object schemas in the tree of the
+// document by references to components
+func (d *Document) dedupSchemas(tree map[string]interface{}) {
+	type occurrence struct {
+		pointer string
+		schema  *Schema
+	}
+	var order []string
+	occurrences := make(map[string][]occurrence)
+	components := make(map[string]string)
+	d.walkSchemas(func(pointer string, s *Schema) {
+		data, err := json.Marshal(s)
+		if err != nil || bytes.Contains(data, []byte(`"#/$defs/`)) {
+			return
+		}
+		key := string(canonicalJSON(data))
+		if name, ok := strings.CutPrefix(pointer, "/components/schemas/"); ok && !strings.Contains(name, "/") {
+			if _, taken := components[key]; !taken {
+				components[key] = unescapePointer(name)
+			}
+			return
+		}
+		if len(s.Properties) == 0 {
+			return
+		}
+		if _, seen := occurrences[key]; !seen {
+			order = append(order, key)
+		}
+		occurrences[key] = append(occurrences[key], occurrence{pointer, s})
+	})
+
+	schemas := objectAt(tree, "components", "schemas")
+	replaced := make(map[string]bool)
+	for _, key := range order {
+		// Occurrences inside schemas replaced before are gone from the tree
+		var found []occurrence
+		for _, o := range occurrences[key] {
+			if !hasAncestor(o.pointer, replaced) {
+				found = append(found, o)
+			}
+		}
+		name, ok := components[key]
+		if len(found) == 0 || (!ok && len(found) < 2) {
+			continue
+		}
+		var value interface{}
+		if json.Unmarshal([]byte(key), &value) != nil {
+			continue
+		}
+		if !ok {
+			name = inlineSchemaName(found[0].pointer, found[0].schema)
+			if _, taken := schemas[name]; taken {
+				name = freeName(name, schemas)
+			}
+			if schemas == nil {
+				setMember(tree, "components", "schemas", map[string]interface{}{})
+				schemas = objectAt(tree, "components", "schemas")
+			}
+			schemas[name] = value
+			components[key] = name
+		}
+		for _, o := range found {
+			if replacePointer(tree, o.pointer, map[string]interface{}{"$ref": "#/components/schemas/" + escapePointer(name)}) {
+				replaced[o.pointer] = true
+			}
+		}
+	}
+}
+
+// inlineSchemaName names the component extracted from an inline schema
+// after its title, or the property holding it
+func inlineSchemaName(pointer string, s *Schema) string {
+	if s.Title != "" {
+		return exportedName(s.Title)
+	}
+	tokens := strings.Split(pointer, "/")
+	for i := len(tokens) - 1; i > 0; i-- {
+		if tokens[i-1] == "properties" {
+			return exportedName(unescapePointer(tokens[i]))
+		}
+	}
+	return "InlineSchema"
+}
+
+// replacePointer replaces the value at a pointer in a decoded JSON value,
+// reporting whether the pointer exists
+func replacePointer(tree interface{}, pointer string, value interface{}) bool {
+	i := strings.LastIndex(pointer, "/")
+	if i < 0 {
+		return false
+	}
+	parent, ok := pointerValue(tree, pointer[:i])
+	if !ok {
+		return false
+	}
+	token := unescapePointer(pointer[i+1:])
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := p[token]; ok {
+			p[token] = value
+			return true
+		}
+	case []interface{}:
+		if j, err := strconv.Atoi(token); err == nil && j >= 0 && j < len(p) {
+			p[j] = value
+			return true
+		}
+	}
+	return false
+}
+
+// walkObjects calls fn for every object in a decoded JSON value along with
+// its pointer, parents first. Members fn removes aren't visited.
+func walkObjects(pointer string, value interface{}, fn func(pointer string, object map[string]interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		fn(pointer, v)
+		for _, key := range sortedKeys(v) {
+			walkObjects(pointer+"/"+escapePointer(key), v[key], fn)
+		}
+	case []interface{}:
+		for i, child := range v {
+			walkObjects(fmt.Sprintf("%s/%d", pointer, i), child, fn)
+		}
+	}
+}
+
+// namesMembers reports whether the object at a pointer maps names to values,
+// like schema properties, rather than holding keywords such as "description"
+func namesMembers(pointer string) bool {
+	last := pointer[strings.LastIndex(pointer, "/")+1:]
+	return slices.Contains([]string{"properties", "patternProperties", "dependentSchemas", "$defs", "schemas"}, last)
+}
+
+// isResponsePointer reports whether the object at a pointer is a response
+func isResponsePointer(pointer string) bool {
+	tokens := strings.Split(pointer, "/")
+	return len(tokens) >= 2 && tokens[len(tokens)-2] == "responses"
+}
+
+// hasAncestor reports whether a pointer is nested under one of pointers
+func hasAncestor(pointer string, pointers map[string]bool) bool {
+	for i := strings.LastIndex(pointer, "/"); i > 0; i = strings.LastIndex(pointer[:i], "/") {
+		if pointers[pointer[:i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// largest returns the n largest contributors, biggest first
+func largest(contributors []SizeContributor, n int) []SizeContributor {
+	sort.SliceStable(contributors, func(i, j int) bool {
+		if contributors[i].Bytes != contributors[j].Bytes {
+			return contributors[i].Bytes > contributors[j].Bytes
+		}
+		return contributors[i].Pointer < contributors[j].Pointer
+	})
+	if len(contributors) > n {
+		contributors = contributors[:n]
+	}
+	return contributors
+}
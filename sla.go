@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExtensionSLA is the specification extension holding an operation's SLA
+const ExtensionSLA = "x-sla"
+
+// SLA documents the service level objectives of an operation
+type SLA struct {
+	P50Ms        int     `json:"p50_ms,omitempty"`
+	P99Ms        int     `json:"p99_ms,omitempty"`
+	Availability float64 `json:"availability,omitempty"`
+	RateLimit    string  `json:"rate_limit,omitempty"`
+}
+
+// WithSLA documents the operation's service level objectives in the x-sla extension
+func (o Operation) WithSLA(sla SLA) Operation {
+	return o.WithExtension(ExtensionSLA, sla)
+}
+
+// SLA returns the service level objectives documented on the operation
+func (o Operation) SLA() (SLA, bool) {
+	var sla SLA
+	ok := decodeExtension(o.Extensions[ExtensionSLA], &sla)
+	return sla, ok
+}
+
+// SLAEntry is a single row of an SLA report
+type SLAEntry struct {
+	Path        string `json:"path"`
+	Method      string `json:"method"`
+	OperationID string `json:"operationId,omitempty"`
+	SLA         SLA    `json:"sla"`
+}
+
+// SLAReport aggregates the SLAs documented across a document
+type SLAReport struct {
+	Entries []SLAEntry `json:"entries"`
+	// Undocumented lists operations without an SLA as "METHOD path"
+	Undocumented []string `json:"undocumented,omitempty"`
+}
+
+// SLAReport collects the SLAs of every operation in the document
+func (d *Document) SLAReport() SLAReport {
+	var report SLAReport
+	d.walkOperations(func(path, method string, op *Operation) {
+		sla, ok := op.SLA()
+		if !ok {
+			report.Undocumented = append(report.Undocumented, method+" "+path)
+			return
+		}
+		report.Entries = append(report.Entries, SLAEntry{
+			Path:        path,
+			Method:      method,
+			OperationID: op.OperationID,
+			SLA:         sla,
+		})
+	})
+	return report
+}
+
+// Markdown renders the report as a Markdown table
+func (r SLAReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString("| Operation | Method | Path | p50 (ms) | p99 (ms) | Availability | Rate limit |\n")
+	b.WriteString("|---|---|---|---|---|---|---|\n")
+	for _, e := range r.Entries {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			e.OperationID, e.Method, e.Path,
+			formatOptional(e.SLA.P50Ms), formatOptional(e.SLA.P99Ms),
+			formatOptional(e.SLA.Availability), e.SLA.RateLimit)
+	}
+	if len(r.Undocumented) > 0 {
+		b.WriteString("\nOperations without an SLA:\n\n")
+		for _, op := range r.Undocumented {
+			fmt.Fprintf(&b, "- %s\n", op)
+		}
+	}
+	return b.String()
+}
+
+// formatOptional renders zero values as empty table cells
+func formatOptional[T int | float64](v T) string {
+	if v == 0 {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,117 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ResponseError reports why a response doesn't conform to its operation
+type ResponseError struct {
+	Errors []ValidationError
+}
+
+// Error implements the error interface
+func (e *ResponseError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "response does not match the operation: " + strings.Join(messages, "; ")
+}
+
+// SpecWriter wraps an http.ResponseWriter and checks every response written
+// through it against the responses documented for an operation, catching
+// undocumented status codes, media types and payload shapes during development.
+type SpecWriter struct {
+	http.ResponseWriter
+	doc         *Document
+	operationID string
+
+	// OnViolation, when set, is called with a *ResponseError for undocumented
+	// responses, which are then written anyway. When nil, such writes fail and
+	// nothing is sent.
+	OnViolation func(err error)
+}
+
+// NewSpecWriter creates a writer enforcing the responses of the given operation
+func NewSpecWriter(doc *Document, operationID string, w http.ResponseWriter) *SpecWriter {
+	return &SpecWriter{
+		ResponseWriter: w,
+		doc:            doc,
+		operationID:    operationID,
+	}
+}
+
+// WriteJSON encodes v as JSON and writes it with the given status code. The
+// content type is the JSON media type documented for that status, such as
+// application/problem+json; when none is documented the check fails.
+func (w *SpecWriter) WriteJSON(code int, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.WriteContent(code, w.jsonMediaType(code), body)
+}
+
+// jsonMediaType returns the JSON media type documented for a status,
+// preferring application/json, or application/json when there is none
+func (w *SpecWriter) jsonMediaType(code int) string {
+	_, _, op := w.doc.FindOperation(w.operationID)
+	if op == nil {
+		return "application/json"
+	}
+	_, response, ok := op.ResponseFor(code)
+	if !ok {
+		return "application/json"
+	}
+	content := w.doc.resolveResponse(response).Content
+	if _, ok := content["application/json"]; ok {
+		return "application/json"
+	}
+	for _, name := range sortedKeys(content) {
+		if isJSONMediaType(name) && !strings.Contains(name, "*") {
+			return name
+		}
+	}
+	return "application/json"
+}
+
+// WriteContent writes a response body with the given status code and content type
+func (w *SpecWriter) WriteContent(code int, contentType string, body []byte) error {
+	header := w.Header().Clone()
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	if err := w.check(code, header, body); err != nil {
+		return err
+	}
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(code)
+	_, err := w.ResponseWriter.Write(body)
+	return err
+}
+
+// WriteNoContent writes a response without a body
+func (w *SpecWriter) WriteNoContent(code int) error {
+	if err := w.check(code, w.Header(), nil); err != nil {
+		return err
+	}
+	w.WriteHeader(code)
+	return nil
+}
+
+func (w *SpecWriter) check(code int, header http.Header, body []byte) error {
+	errs := w.doc.CheckResponse(w.operationID, code, header, body)
+	if !HasErrors(errs) {
+		return nil
+	}
+	err := &ResponseError{Errors: errs}
+	if w.OnViolation == nil {
+		return err
+	}
+	w.OnViolation(err)
+	return nil
+}
@@ -0,0 +1,87 @@
+package openapi
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSpecWriter(t *testing.T) {
+	doc := petDocument()
+
+	rec := httptest.NewRecorder()
+	w := NewSpecWriter(doc, "getPet", rec)
+	if err := w.WriteJSON(200, map[string]interface{}{"id": 1, "name": "Rex"}); err != nil {
+		t.Fatalf("Expected documented response to be written, got %v", err)
+	}
+	if rec.Code != 200 || rec.Body.String() != `{"id":1,"name":"Rex"}` || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected the response passed through unchanged, got %d %q %q", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	if err := NewSpecWriter(doc, "getPet", rec).WriteNoContent(404); err != nil || rec.Code != 404 {
+		t.Errorf("Expected documented 404 to be written, got %v and %d", err, rec.Code)
+	}
+
+	tests := []struct {
+		name  string
+		write func(w *SpecWriter) error
+	}{
+		{"undocumented status", func(w *SpecWriter) error { return w.WriteNoContent(500) }},
+		{"undocumented content type", func(w *SpecWriter) error {
+			return w.WriteContent(200, "text/plain", []byte("Rex"))
+		}},
+		{"invalid payload", func(w *SpecWriter) error { return w.WriteJSON(200, map[string]interface{}{"id": 1}) }},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		err := tt.write(NewSpecWriter(doc, "getPet", rec))
+		var responseErr *ResponseError
+		if !errors.As(err, &responseErr) || len(responseErr.Errors) == 0 {
+			t.Errorf("%s: Expected a *ResponseError, got %v", tt.name, err)
+		}
+		if rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != "" {
+			t.Errorf("%s: Expected nothing written, got %q %q", tt.name, rec.Header().Get("Content-Type"), rec.Body.String())
+		}
+
+		rec = httptest.NewRecorder()
+		w := NewSpecWriter(doc, "getPet", rec)
+		var reported []error
+		w.OnViolation = func(err error) { reported = append(reported, err) }
+		if err := tt.write(w); err != nil {
+			t.Errorf("%s: Expected the write to succeed with OnViolation, got %v", tt.name, err)
+		}
+		if len(reported) != 1 {
+			t.Errorf("%s: Expected 1 reported violation, got %v", tt.name, reported)
+		}
+		if rec.Code == 200 && rec.Body.Len() == 0 {
+			t.Errorf("%s: Expected the response written anyway", tt.name)
+		}
+	}
+}
+
+func TestSpecWriterJSONMediaType(t *testing.T) {
+	doc := petDocument()
+	doc.AddOperation("/pets", "POST", NewOperation("createPet", "Create pet", "").
+		WithResponse("400", "Invalid pet", NewResponse("Invalid pet").
+			WithContent("application/problem+json", NewJSONMediaType(&Schema{Type: Types{"object"}, Properties: map[string]*Schema{"title": StringSchema("")}}))).
+		WithResponse("415", "Unsupported", NewResponse("Unsupported").
+			WithContent("text/plain", NewJSONMediaType(StringSchema("")))))
+
+	rec := httptest.NewRecorder()
+	if err := NewSpecWriter(doc, "createPet", rec).WriteJSON(400, map[string]string{"title": "Invalid pet"}); err != nil {
+		t.Fatalf("Expected the problem response to be written, got %v", err)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Expected 'application/problem+json', got '%s'", ct)
+	}
+
+	rec = httptest.NewRecorder()
+	var responseErr *ResponseError
+	if err := NewSpecWriter(doc, "createPet", rec).WriteJSON(415, "Unsupported"); !errors.As(err, &responseErr) {
+		t.Errorf("Expected a *ResponseError without a documented JSON media type, got %v", err)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("Expected nothing written, got %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,297 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SQLOptions configures SQLTables and ToSQL
+type SQLOptions struct {
+	// Dialect is "postgres" (the default), "mysql" or "sqlite"
+	Dialect string
+	// Schemas limits the export to these component schemas; defaults to every
+	// object schema
+	Schemas []string
+}
+
+// SQLTable is the table a component object schema maps to
+type SQLTable struct {
+	// Schema is the component schema name
+	Schema  string      `json:"schema"`
+	Name    string      `json:"name"`
+	Columns []SQLColumn `json:"columns"`
+	// Notes lists what didn't map cleanly to the table
+	Notes []string `json:"notes,omitempty"`
+}
+
+// SQLColumn is the column a schema property maps to
+type SQLColumn struct {
+	Property string `json:"property"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	NotNull  bool   `json:"notNull,omitempty"`
+	// PrimaryKey is set on the column of the "id" property
+	PrimaryKey bool `json:"primaryKey,omitempty"`
+	// Default is the SQL literal of the schema default
+	Default string `json:"default,omitempty"`
+	// Check is the constraint derived from an enum
+	Check string `json:"check,omitempty"`
+	// References names the table of a property referencing another exported schema
+	References string `json:"references,omitempty"`
+}
+
+// SQLTables maps component object schemas to SQL tables, as a best-effort
+// starting point for teams prototyping storage from their API models.
+// Properties become snake_case columns typed after their type and format,
+// required properties are NOT NULL unless nullable, enums become CHECK
+// constraints and an "id" property becomes the primary key. A property
+// referencing another exported schema with an id becomes a foreign key
+// column; other nested objects and arrays are stored as JSON and noted.
+// Tables are ordered so that referenced tables come first.
+func (d *Document) SQLTables(opts SQLOptions) []SQLTable {
+	if d.Components == nil {
+		return nil
+	}
+	names := opts.Schemas
+	if names == nil {
+		for _, name := range sortedKeys(d.Components.Schemas) {
+			if s := d.Components.Schemas[name]; s != nil && s.Ref == "" && (s.Type.Is("object") || len(s.Properties) > 0 || len(s.AllOf) > 0) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var tables []SQLTable
+	done := make(map[string]bool)
+	var visit func(name string, path []string)
+	visit = func(name string, path []string) {
+		if done[name] || slices.Contains(path, name) {
+			return
+		}
+		s := d.Components.Schemas[name]
+		if s == nil {
+			return
+		}
+		properties, required := d.sqlProperties(s)
+		for _, prop := range sortedKeys(properties) {
+			if target := d.sqlReference(properties[prop], names); target != "" {
+				visit(target, append(path, name))
+			}
+		}
+		done[name] = true
+		tables = append(tables, d.sqlTable(name, properties, required, names, opts))
+	}
+	for _, name := range names {
+		visit(name, nil)
+	}
+	return tables
+}
+
+// ToSQL renders the tables of SQLTables as CREATE TABLE statements, with the
+// notes of the mapping as comments
+func (d *Document) ToSQL(opts SQLOptions) ([]byte, error) {
+	switch opts.Dialect {
+	case "", "postgres", "mysql", "sqlite":
+	default:
+		return nil, fmt.Errorf("openapi: unsupported SQL dialect %q", opts.Dialect)
+	}
+	quote := `"`
+	if opts.Dialect == "mysql" {
+		quote = "`"
+	}
+	identifier := func(name string) string {
+		return quote + name + quote
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "-- Tables for the schemas of %s %s.\n", d.Info.Title, d.Info.Version)
+	buf.WriteString("-- Best-effort mapping for prototyping; review before using as a migration.\n")
+	for _, table := range d.SQLTables(opts) {
+		fmt.Fprintf(&buf, "\n-- %s\n", table.Schema)
+		for _, note := range table.Notes {
+			fmt.Fprintf(&buf, "-- Note: %s\n", note)
+		}
+		fmt.Fprintf(&buf, "CREATE TABLE %s (\n", identifier(table.Name))
+		var lines []string
+		for _, column := range table.Columns {
+			line := "  " + identifier(column.Name) + " " + column.Type
+			if column.NotNull {
+				line += " NOT NULL"
+			}
+			if column.PrimaryKey {
+				line += " PRIMARY KEY"
+			}
+			if column.Default != "" {
+				line += " DEFAULT " + column.Default
+			}
+			if column.Check != "" {
+				line += " CHECK (" + column.Check + ")"
+			}
+			if column.References != "" {
+				line += fmt.Sprintf(" REFERENCES %s (%s)", identifier(column.References), identifier("id"))
+			}
+			lines = append(lines, line)
+		}
+		buf.WriteString(strings.Join(lines, ",\n"))
+		buf.WriteString("\n);\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// sqlTable maps one object schema
+func (d *Document) sqlTable(name string, properties map[string]*Schema, required []string, exported []string, opts SQLOptions) SQLTable {
+	table := SQLTable{Schema: name, Name: protoFieldName(name)}
+	if _, ok := properties["id"]; !ok {
+		table.Notes = append(table.Notes, "no id property, so the table has no primary key")
+	}
+	props := sortedKeys(properties)
+	if i := slices.Index(props, "id"); i > 0 {
+		props = append([]string{"id"}, slices.Delete(props, i, i+1)...)
+	}
+	for _, prop := range props {
+		s := d.resolveSchema(properties[prop])
+		column := SQLColumn{
+			Property: prop,
+			Name:     protoFieldName(prop),
+			NotNull:  slices.Contains(required, prop) && (s == nil || !s.AllowsNull()),
+		}
+		if target := d.sqlReference(properties[prop], exported); target != "" {
+			idType, _ := d.sqlType(d.Components.Schemas[target].Properties["id"], opts.Dialect)
+			column.Name += "_id"
+			column.Type = idType
+			column.References = protoFieldName(target)
+			table.Columns = append(table.Columns, column)
+			continue
+		}
+
+		var note string
+		column.Type, note = d.sqlType(properties[prop], opts.Dialect)
+		if note != "" {
+			table.Notes = append(table.Notes, fmt.Sprintf("%s %s", prop, note))
+		}
+		column.PrimaryKey = prop == "id"
+		if s != nil {
+			column.Default = sqlLiteral(s.Default)
+			var values []string
+			for _, value := range s.Enum {
+				if literal := sqlLiteral(value); literal != "" {
+					values = append(values, literal)
+				}
+			}
+			if len(values) > 0 {
+				column.Check = fmt.Sprintf("%s IN (%s)", column.Name, strings.Join(values, ", "))
+			}
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	return table
+}
+
+// sqlProperties collects the properties and required names of a schema,
+// including those of its allOf members
+func (d *Document) sqlProperties(s *Schema) (map[string]*Schema, []string) {
+	properties := make(map[string]*Schema)
+	var required []string
+	var collect func(s *Schema, depth int)
+	collect = func(s *Schema, depth int) {
+		s = d.resolveSchema(s)
+		if s == nil || depth > 16 {
+			return
+		}
+		for _, member := range s.AllOf {
+			collect(member, depth+1)
+		}
+		for name, prop := range s.Properties {
+			properties[name] = prop
+		}
+		required = append(required, s.Required...)
+	}
+	collect(s, 0)
+	return properties, required
+}
+
+// sqlReference returns the exported schema a property references when it has
+// an id property to point a foreign key at
+func (d *Document) sqlReference(s *Schema, exported []string) string {
+	if s == nil {
+		return ""
+	}
+	name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+	if !ok {
+		return ""
+	}
+	name = unescapePointer(name)
+	target := d.Components.Schemas[name]
+	if !slices.Contains(exported, name) || target == nil || target.Properties["id"] == nil {
+		return ""
+	}
+	return name
+}
+
+// sqlType maps a property schema to a column type, with a note when the
+// mapping loses information
+func (d *Document) sqlType(s *Schema, dialect string) (string, string) {
+	s = d.resolveSchema(s)
+	if s == nil {
+		return "TEXT", "has an unresolved schema and is stored as text"
+	}
+	pick := func(postgres, mysql, sqlite string) string {
+		switch dialect {
+		case "mysql":
+			return mysql
+		case "sqlite":
+			return sqlite
+		}
+		return postgres
+	}
+	switch s.Type.Primary() {
+	case "integer":
+		if s.Format == "int32" {
+			return "INTEGER", ""
+		}
+		return "BIGINT", ""
+	case "number":
+		if s.Format == "float" {
+			return "REAL", ""
+		}
+		return pick("DOUBLE PRECISION", "DOUBLE", "REAL"), ""
+	case "boolean":
+		return pick("BOOLEAN", "BOOLEAN", "INTEGER"), ""
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return pick("TIMESTAMP WITH TIME ZONE", "DATETIME", "TEXT"), ""
+		case "date":
+			return pick("DATE", "DATE", "TEXT"), ""
+		case "uuid":
+			return pick("UUID", "CHAR(36)", "TEXT"), ""
+		case "byte", "binary":
+			return pick("BYTEA", "BLOB", "BLOB"), ""
+		}
+		if s.MaxLength != nil && dialect != "sqlite" {
+			return fmt.Sprintf("VARCHAR(%d)", *s.MaxLength), ""
+		}
+		return "TEXT", ""
+	case "array":
+		return pick("JSONB", "JSON", "TEXT"), "is an array and is stored as JSON"
+	}
+	return pick("JSONB", "JSON", "TEXT"), "is not a scalar and is stored as JSON"
+}
+
+// sqlLiteral renders a scalar as a SQL literal, or "" for other values
+func sqlLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int32, int64, float32, float64:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
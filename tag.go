@@ -1,8 +1,30 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // Tag represents a tag object
 type Tag struct {
-	Name         string        `json:"name"`
-	Description  string        `json:"description,omitempty"`
-	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	ExternalDocs *ExternalDocs          `json:"externalDocs,omitempty"`
+	Extensions   map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling to include specification extensions
+func (t Tag) MarshalJSON() ([]byte, error) {
+	type tag Tag
+	return marshalWithExtensions(tag(t), t.Extensions)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to collect specification extensions
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	type tag Tag
+	if err := json.Unmarshal(data, (*tag)(t)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data)
+	t.Extensions = ext
+	return err
 }
@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// durationPattern matches ISO 8601 durations, e.g. "P1Y2M", "P3W" or "PT1.5S"
+var durationPattern = regexp.MustCompile(`^P([0-9]+Y)?([0-9]+M)?([0-9]+W)?([0-9]+D)?(T([0-9]+H)?([0-9]+M)?([0-9]+([.,][0-9]+)?S)?)?$`)
+
+// validDuration reports whether a value is an ISO 8601 duration with at
+// least one component
+func validDuration(value string) bool {
+	return durationPattern.MatchString(value) && value != "P" && !strings.HasSuffix(value, "T")
+}
+
+// IntervalSchema creates an interval object with a required start and an
+// optional end, both of the given format ("date-time" when empty, or
+// "date"). The interval is half-open: start is included, end excluded, and
+// end must not be before start, as a cross-field constraint; a missing end
+// leaves the interval open.
+func IntervalSchema(format string) *Schema {
+	if format == "" {
+		format = "date-time"
+	}
+	startExample, endExample := "2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z"
+	if format == "date" {
+		startExample, endExample = "2024-01-01", "2024-01-02"
+	}
+	start := StringSchema(format).
+		WithDescription("Start of the interval, included").
+		WithExample(startExample)
+	end := StringSchema(format).
+		WithDescription("End of the interval, excluded; must not be before start. Omitted for open intervals").
+		WithExample(endExample)
+	schema := NewObjectSchema().
+		WithDescription("Half-open interval from start to end").
+		WithRequiredProperty("start", &start).
+		WithProperty("end", &end).
+		WithFieldComparison("end", ">=", "start")
+	return &schema
+}
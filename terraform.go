@@ -0,0 +1,219 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TerraformTarget selects the managed API gateway a Terraform export targets
+type TerraformTarget string
+
+const (
+	// TerraformAWS targets AWS API Gateway REST APIs imported from the document body
+	TerraformAWS TerraformTarget = "aws"
+	// TerraformGCP targets GCP API Gateway. Check that the gateway accepts the
+	// document's OpenAPI version; it historically required Swagger 2.0.
+	TerraformGCP TerraformTarget = "gcp"
+)
+
+// TerraformOptions configures ToTerraform
+type TerraformOptions struct {
+	Target TerraformTarget
+	// Name is used for the API and, sanitized, for resource names; defaults to the document title
+	Name string
+	// StageName is the AWS stage or GCP gateway name; defaults to "prod"
+	StageName string
+	// Project is the GCP project ID; required for TerraformGCP
+	Project string
+	// Region is the GCP gateway region; defaults to "us-central1"
+	Region string
+	// Integrations adds an HTTP proxy x-amazon-apigateway-integration to every
+	// operation, forwarding to the upstream resolved as in GatewayRoutes. Server
+	// variables in upstream URLs become ${stageVariables.name} references.
+	Integrations bool
+}
+
+var terraformIdentifier = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// ToTerraform renders the document as a Terraform JSON configuration (a
+// .tf.json file, also understood by OpenTofu) that creates the API on a managed
+// gateway with the document inlined as its definition. For AWS, server variable
+// defaults become stage variables.
+func (d *Document) ToTerraform(opts TerraformOptions) ([]byte, error) {
+	if opts.Name == "" {
+		opts.Name = d.Info.Title
+	}
+	if opts.StageName == "" {
+		opts.StageName = "prod"
+	}
+	if opts.Region == "" {
+		opts.Region = "us-central1"
+	}
+	id := strings.Trim(terraformIdentifier.ReplaceAllString(strings.ToLower(opts.Name), "_"), "_")
+	if id == "" {
+		id = "api"
+	}
+
+	spec, err := d.terraformBody(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var config map[string]interface{}
+	switch opts.Target {
+	case TerraformAWS:
+		variables := make(map[string]string)
+		for _, server := range d.Servers {
+			for name, variable := range server.Variables {
+				variables[name] = variable.Default
+			}
+		}
+		stage := map[string]interface{}{
+			"rest_api_id":   "${aws_api_gateway_rest_api." + id + ".id}",
+			"deployment_id": "${aws_api_gateway_deployment." + id + ".id}",
+			"stage_name":    opts.StageName,
+		}
+		if len(variables) > 0 {
+			stage["variables"] = variables
+		}
+		config = map[string]interface{}{
+			"resource": map[string]interface{}{
+				"aws_api_gateway_rest_api": map[string]interface{}{
+					id: map[string]interface{}{
+						"name": opts.Name,
+						"body": "${local." + id + "_openapi}",
+					},
+				},
+				"aws_api_gateway_deployment": map[string]interface{}{
+					id: map[string]interface{}{
+						"rest_api_id": "${aws_api_gateway_rest_api." + id + ".id}",
+						"triggers": map[string]string{
+							"redeployment": "${sha1(aws_api_gateway_rest_api." + id + ".body)}",
+						},
+						"lifecycle": map[string]bool{"create_before_destroy": true},
+					},
+				},
+				"aws_api_gateway_stage": map[string]interface{}{id: stage},
+			},
+		}
+	case TerraformGCP:
+		if opts.Project == "" {
+			return nil, fmt.Errorf("openapi: a GCP project is required")
+		}
+		apiID := strings.ReplaceAll(id, "_", "-")
+		config = map[string]interface{}{
+			"resource": map[string]interface{}{
+				"google_api_gateway_api": map[string]interface{}{
+					id: map[string]interface{}{
+						"provider": "google-beta",
+						"project":  opts.Project,
+						"api_id":   apiID,
+					},
+				},
+				"google_api_gateway_api_config": map[string]interface{}{
+					id: map[string]interface{}{
+						"provider":             "google-beta",
+						"project":              opts.Project,
+						"api":                  "${google_api_gateway_api." + id + ".api_id}",
+						"api_config_id_prefix": apiID + "-",
+						"openapi_documents": []interface{}{map[string]interface{}{
+							"document": map[string]interface{}{
+								"path":     "openapi.json",
+								"contents": "${base64encode(local." + id + "_openapi)}",
+							},
+						}},
+						"lifecycle": map[string]bool{"create_before_destroy": true},
+					},
+				},
+				"google_api_gateway_gateway": map[string]interface{}{
+					id: map[string]interface{}{
+						"provider":   "google-beta",
+						"project":    opts.Project,
+						"region":     opts.Region,
+						"api_config": "${google_api_gateway_api_config." + id + ".id}",
+						"gateway_id": apiID + "-" + opts.StageName,
+					},
+				},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("openapi: unknown Terraform target %q", opts.Target)
+	}
+	config["locals"] = map[string]string{id + "_openapi": spec}
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// terraformBody renders the document for inlining into a Terraform string,
+// adding gateway integrations when requested and escaping template sequences
+func (d *Document) terraformBody(opts TerraformOptions) (string, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Target == TerraformAWS && opts.Integrations {
+		routes, err := d.GatewayRoutes()
+		if err != nil {
+			return "", err
+		}
+		var spec map[string]interface{}
+		if err := json.Unmarshal(data, &spec); err != nil {
+			return "", err
+		}
+		paths, _ := spec["paths"].(map[string]interface{})
+		for _, route := range routes {
+			item, _ := paths[route.Path].(map[string]interface{})
+			for _, method := range route.Methods {
+				op, ok := item[strings.ToLower(method)].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				uri := strings.TrimSuffix(route.Upstream.String(), "/") + route.Path
+				integration := map[string]interface{}{
+					"type":                "http_proxy",
+					"httpMethod":          method,
+					"uri":                 d.stageVariableURI(uri),
+					"passthroughBehavior": "when_no_match",
+				}
+				// Path variables of the upstream URI must be mapped from the method request
+				if params := templateVariables(route.Path); len(params) > 0 {
+					mapping := make(map[string]string, len(params))
+					for _, name := range params {
+						mapping["integration.request.path."+name] = "method.request.path." + name
+					}
+					integration["requestParameters"] = mapping
+				}
+				op["x-amazon-apigateway-integration"] = integration
+			}
+		}
+		if data, err = json.Marshal(spec); err != nil {
+			return "", err
+		}
+	}
+
+	// Terraform interprets ${ and %{ inside strings as template sequences
+	escaped := strings.NewReplacer("${", "$${", "%{", "%%{").Replace(string(data))
+	return escaped, nil
+}
+
+// stageVariableURI rewrites the expanded defaults of server variables back into
+// AWS stage variable references, so the stage controls the upstream
+func (d *Document) stageVariableURI(uri string) string {
+	for _, server := range d.Servers {
+		if len(server.Variables) == 0 {
+			continue
+		}
+		expanded := server.Expand(nil)
+		if !strings.HasPrefix(uri, expanded) {
+			continue
+		}
+		templated := server.URL
+		for name := range server.Variables {
+			templated = strings.ReplaceAll(templated, "{"+name+"}", "${stageVariables."+name+"}")
+		}
+		return templated + strings.TrimPrefix(uri, expanded)
+	}
+	return uri
+}
@@ -0,0 +1,137 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	byteSliceType = reflect.TypeOf([]byte{})
+)
+
+// SchemaOf returns the schema of a Go type, registering the schemas of named
+// struct types as components of the document and referencing them
+func SchemaOf[T any](d *Document) *Schema {
+	return d.SchemaFor(reflect.TypeFor[T]())
+}
+
+// SchemaFor returns the schema of a Go type. Named struct types are registered
+// as component schemas, named after the type, and referenced. Struct fields
+// follow encoding/json: their names come from json tags, fields tagged "-" are
+// skipped, embedded structs are flattened and fields without omitempty that
+// aren't pointers are required. Pointers are nullable and time.Time is a
+// date-time string. Component schemas record their Go type in x-go-source.
+func (d *Document) SchemaFor(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+	s := d.schemaFor(t)
+	if nullable && s.Ref == "" {
+		s.Nullable = true
+	}
+	return s
+}
+
+func (d *Document) schemaFor(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return DateTimeSchema()
+	case rawJSONType:
+		return &Schema{}
+	case byteSliceType:
+		return StringSchema("byte")
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return NewBooleanSchema()
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Int, reflect.Uint:
+		return Int32Schema()
+	case reflect.Int64, reflect.Uint32, reflect.Uint64:
+		return Int64Schema()
+	case reflect.Float32:
+		return FloatSchema()
+	case reflect.Float64:
+		return DoubleSchema()
+	case reflect.String:
+		return NewStringSchema()
+	case reflect.Slice, reflect.Array:
+		return NewArraySchema(d.SchemaFor(t.Elem()))
+	case reflect.Map:
+		schema := NewObjectSchema()
+		schema.AdditionalProperties = &AdditionalProperties{Schema: d.SchemaFor(t.Elem())}
+		return schema
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		name := exportedName(t.Name())
+		ref := &Schema{Ref: "#/components/schemas/" + escapePointer(name)}
+		components := d.AddComponents()
+		if components.Schemas == nil {
+			components.Schemas = make(map[string]*Schema)
+		}
+		if _, exists := components.Schemas[name]; !exists {
+			// Register a placeholder first so recursive types terminate
+			components.Schemas[name] = &Schema{}
+			schema := d.structSchema(t)
+			*components.Schemas[name] = schema.WithExtension(ExtensionGoSource, GoSource{
+				Package:    t.PkgPath(),
+				Type:       t.Name(),
+				TypeHash:   typeHash(t),
+				SchemaHash: schemaHash(schema),
+			})
+		}
+		return ref
+	}
+	return &Schema{}
+}
+
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	schema := NewObjectSchema()
+	schema.Properties = make(map[string]*Schema)
+	d.collectStructFields(t, schema, false)
+	return schema
+}
+
+func (d *Document) collectStructFields(t reflect.Type, schema *Schema, skipParameters bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				d.collectStructFields(embedded, schema, skipParameters)
+				continue
+			}
+		}
+		if !field.IsExported() || (skipParameters && isParameterField(field)) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := d.SchemaFor(field.Type)
+		if description := field.Tag.Get("description"); description != "" && prop.Ref == "" {
+			prop.Description = description
+		}
+		schema.Properties[name] = prop
+		if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
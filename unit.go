@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ExtensionUnit is the schema extension holding the unit of a numeric value
+const ExtensionUnit = "x-unit"
+
+// UnitKind is the quantity a unit measures
+type UnitKind string
+
+const (
+	UnitDuration UnitKind = "duration"
+	UnitSize     UnitKind = "size"
+	// UnitCurrency marks amounts of money in an ISO 4217 currency
+	UnitCurrency UnitKind = "currency"
+)
+
+// Unit is the unit of measurement of a numeric value
+type Unit struct {
+	// Name is the unit, e.g. "milliseconds", "bytes" or "EUR"
+	Name string   `json:"name"`
+	Kind UnitKind `json:"kind,omitempty"`
+}
+
+// unitKinds maps well-known unit names to the quantity they measure
+var unitKinds = map[string]UnitKind{
+	"nanoseconds": UnitDuration, "microseconds": UnitDuration, "milliseconds": UnitDuration,
+	"seconds": UnitDuration, "minutes": UnitDuration, "hours": UnitDuration, "days": UnitDuration,
+	"bits": UnitSize, "bytes": UnitSize, "kilobytes": UnitSize, "kibibytes": UnitSize,
+	"megabytes": UnitSize, "mebibytes": UnitSize, "gigabytes": UnitSize, "gibibytes": UnitSize,
+}
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// DefaultUnitPatterns are the field names UnitProfile requires a unit on
+var DefaultUnitPatterns = []string{"*_ms", "*Ms", "*_seconds", "*Seconds", "*_bytes", "*Bytes", "*_size", "*Size", "amount", "*_amount", "*Amount"}
+
+func init() {
+	RegisterKeyword(Keyword{Name: ExtensionUnit, Check: checkUnit})
+}
+
+// NewUnit returns a unit, recognizing the kind of well-known units and ISO
+// 4217 currency codes
+func NewUnit(name string) Unit {
+	unit := Unit{Name: name, Kind: unitKinds[name]}
+	if currencyCode.MatchString(name) {
+		unit.Kind = UnitCurrency
+	}
+	return unit
+}
+
+// WithUnit sets the unit of the schema's values, such as "milliseconds",
+// "bytes" or an ISO 4217 currency code, and appends it to the description
+func (s Schema) WithUnit(name string) Schema {
+	suffix := "(" + name + ")"
+	switch {
+	case s.Description == "":
+		s.Description = suffix
+	case !strings.HasSuffix(s.Description, suffix):
+		s.Description += " " + suffix
+	}
+	return s.WithExtension(ExtensionUnit, NewUnit(name))
+}
+
+// Unit returns the unit of the schema's values
+func (s *Schema) Unit() (Unit, bool) {
+	var unit Unit
+	ok := decodeExtension(s.Extensions[ExtensionUnit], &unit) && unit.Name != ""
+	return unit, ok
+}
+
+// checkUnit checks the value of an x-unit extension
+func checkUnit(value interface{}) error {
+	var unit Unit
+	if !decodeExtension(value, &unit) || unit.Name == "" {
+		return errors.New("expected an object with a name")
+	}
+	if unit.Kind == UnitCurrency && !currencyCode.MatchString(unit.Name) {
+		return errors.New("currency " + unit.Name + " is not an ISO 4217 code")
+	}
+	return nil
+}
+
+// UnitProfile requires a unit on numeric properties and parameters whose
+// name matches one of its patterns, as rule "units/required". Patterns use
+// path.Match syntax; nil patterns use DefaultUnitPatterns.
+type UnitProfile struct {
+	Patterns []string
+}
+
+// Name returns "units"
+func (UnitProfile) Name() string {
+	return "units"
+}
+
+// Validate reports numeric fields matching the patterns without a unit
+func (p UnitProfile) Validate(d *Document) []ValidationError {
+	patterns := p.Patterns
+	if patterns == nil {
+		patterns = DefaultUnitPatterns
+	}
+	matches := func(name string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var errs []ValidationError
+	check := func(pointer, name string, s *Schema) {
+		if s == nil || !matches(name) {
+			return
+		}
+		if _, ok := s.Unit(); ok {
+			return
+		}
+		if resolved := d.resolveSchema(s); resolved != nil {
+			if _, ok := resolved.Unit(); ok {
+				return
+			}
+			if resolved.Type.Is("integer") || resolved.Type.Is("number") {
+				errs = append(errs, ValidationError{Path: pointer, Message: name + " has no unit", Severity: SeverityError, Rule: "units/required"})
+			}
+		}
+	}
+	d.walkSchemas(func(pointer string, s *Schema) {
+		for _, name := range sortedKeys(s.Properties) {
+			check(pointer+"/properties/"+escapePointer(name), name, s.Properties[name])
+		}
+	})
+	d.walkParameters(func(pointer string, param Parameter) {
+		check(pointer+"/schema", param.Name, param.Schema)
+	})
+	return errs
+}
@@ -0,0 +1,223 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Severity indicates how serious a validation finding is
+type Severity string
+
+const (
+	// SeverityError marks a violation of the OpenAPI specification
+	SeverityError Severity = "error"
+	// SeverityWarning marks a construct that is legal but likely a mistake
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationError describes a problem found in a document
+type ValidationError struct {
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity"`
+	// Rule names the profile rule reporting the finding, if any
+	Rule string `json:"rule,omitempty"`
+}
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.Path, e.Message, e.Severity)
+}
+
+// Validate checks the document for specification violations and common mistakes.
+// Each finding carries a JSON pointer to the offending location.
+func (d *Document) Validate() []ValidationError {
+	v := &validator{doc: d}
+	v.validateRequiredFields()
+	v.validateDialect()
+	v.validatePathParameters()
+	v.validateOperationIDs()
+	v.validateWebhooks()
+	v.validateSecurityRequirements()
+	d.walkMediaTypes(v.validateMediaType)
+	v.validateHeaders()
+	v.validateExamples()
+	v.validateKeywords()
+	return v.errs
+}
+
+// HasErrors reports whether any of the findings has error severity
+func HasErrors(errs []ValidationError) bool {
+	for _, err := range errs {
+		if err.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// validator accumulates findings while walking a document
+type validator struct {
+	doc  *Document
+	errs []ValidationError
+}
+
+func (v *validator) errorf(pointer, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{Path: pointer, Message: fmt.Sprintf(format, args...), Severity: SeverityError})
+}
+
+func (v *validator) warnf(pointer, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{Path: pointer, Message: fmt.Sprintf(format, args...), Severity: SeverityWarning})
+}
+
+// resolveSchema follows local component references until it reaches a concrete schema.
+// It returns nil when a reference cannot be resolved.
+func (d *Document) resolveSchema(s *Schema) *Schema {
+	seen := map[string]bool{}
+	for s != nil && s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		if !ok || seen[name] || d.Components == nil {
+			return nil
+		}
+		seen[name] = true
+		s = d.Components.Schemas[unescapePointer(name)]
+	}
+	return s
+}
+
+// validateRequiredFields checks the fields the specification requires
+func (v *validator) validateRequiredFields() {
+	if v.doc.OpenAPI == "" {
+		v.errorf("/openapi", "openapi version is required")
+	}
+	if v.doc.Info.Title == "" {
+		v.errorf("/info/title", "info title is required")
+	}
+	if v.doc.Info.Version == "" {
+		v.errorf("/info/version", "info version is required")
+	}
+	if license := v.doc.Info.License; license != nil && license.Identifier != "" && license.URL != "" {
+		v.errorf("/info/license", "license identifier and url are mutually exclusive")
+	}
+	if isOpenAPI31(v.doc.OpenAPI) && len(v.doc.Paths) == 0 && len(v.doc.Webhooks) == 0 && len(v.doc.componentKeys()) == 0 {
+		v.errorf("", "document must have paths, webhooks or components")
+	}
+	v.doc.walkResponses(func(pointer string, r Response) {
+		if r.Ref == nil && r.Description == "" {
+			v.errorf(pointer+"/description", "response description is required")
+		}
+	})
+}
+
+// validatePathParameters checks that every variable of a path template has a
+// required path parameter and that every path parameter appears in the template.
+// Findings on path item parameters are reported once for all its operations.
+func (v *validator) validatePathParameters() {
+	reported := make(map[string]bool)
+	report := func(pointer, format string, args ...interface{}) {
+		key := pointer + " " + fmt.Sprintf(format, args...)
+		if !reported[key] {
+			reported[key] = true
+			v.errorf(pointer, format, args...)
+		}
+	}
+	v.doc.walkOperations(func(path, method string, op *Operation) {
+		pathPointer := "/paths/" + escapePointer(path)
+		variables := templateVariables(path)
+
+		// Operation parameters override those of the path item, as in OperationParameters
+		var names []string
+		pointers := make(map[string]string)
+		params := make(map[string]Parameter)
+		collect := func(base string, list []Parameter) {
+			for i, p := range list {
+				p = v.doc.resolveParameter(p)
+				if p.In != "path" {
+					continue
+				}
+				if _, ok := params[p.Name]; !ok {
+					names = append(names, p.Name)
+				}
+				pointers[p.Name] = base + "/parameters/" + strconv.Itoa(i)
+				params[p.Name] = p
+			}
+		}
+		collect(pathPointer, v.doc.Paths[path].Parameters)
+		collect(operationPointer(path, method), op.Parameters)
+
+		for _, name := range names {
+			switch {
+			case !slices.Contains(variables, name):
+				report(pointers[name], "path parameter %q does not appear in the path template", name)
+			case !params[name].Required:
+				report(pointers[name], "path parameter %q must be required", name)
+			}
+		}
+		for _, name := range variables {
+			if _, ok := params[name]; !ok {
+				report(pathPointer, "path variable {%s} has no matching path parameter", name)
+			}
+		}
+	})
+}
+
+// validateOperationIDs checks that operationIds are unique across paths and webhooks
+func (v *validator) validateOperationIDs() {
+	seen := make(map[string]string)
+	check := func(pointer string, op *Operation) {
+		if op.OperationID == "" {
+			return
+		}
+		if first, ok := seen[op.OperationID]; ok {
+			v.errorf(pointer+"/operationId", "operationId %q is already used by %s", op.OperationID, first)
+			return
+		}
+		seen[op.OperationID] = pointer
+	}
+	v.doc.walkOperations(func(path, method string, op *Operation) {
+		check(operationPointer(path, method), op)
+	})
+	v.doc.walkWebhooks(func(name, method string, op *Operation) {
+		check(webhookPointer(name, method), op)
+	})
+}
+
+// validateWebhooks checks that webhooks are only used by OpenAPI 3.1 documents
+// and that every webhook operation describes its responses
+func (v *validator) validateWebhooks() {
+	if len(v.doc.Webhooks) > 0 && !isOpenAPI31(v.doc.OpenAPI) {
+		v.errorf("/webhooks", "webhooks require OpenAPI 3.1, document uses %s", v.doc.OpenAPI)
+	}
+	v.doc.walkWebhooks(func(name, method string, op *Operation) {
+		if len(op.Responses) == 0 {
+			v.warnf(webhookPointer(name, method)+"/responses", "webhook %s %s does not describe the responses consumers should send", method, name)
+		}
+	})
+}
+
+// validateSecurityRequirements checks that security requirements reference
+// declared security schemes
+func (v *validator) validateSecurityRequirements() {
+	check := func(pointer string, requirements []SecurityRequirement) {
+		for i, requirement := range requirements {
+			for _, name := range sortedKeys(requirement) {
+				var declared bool
+				if v.doc.Components != nil {
+					_, declared = v.doc.Components.SecuritySchemes[name]
+				}
+				if !declared {
+					v.errorf(fmt.Sprintf("%s/%d/%s", pointer, i, escapePointer(name)), "security scheme %q is not declared", name)
+				}
+			}
+		}
+	}
+	check("/security", v.doc.Security)
+	v.doc.walkOperations(func(path, method string, op *Operation) {
+		check(operationPointer(path, method)+"/security", op.Security)
+	})
+	v.doc.walkWebhooks(func(name, method string, op *Operation) {
+		check(webhookPointer(name, method)+"/security", op.Security)
+	})
+}
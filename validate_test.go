@@ -0,0 +1,603 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func findValidationError(errs []ValidationError, path string) *ValidationError {
+	for i := range errs {
+		if errs[i].Path == path {
+			return &errs[i]
+		}
+	}
+	return nil
+}
+
+func TestValidateEncoding(t *testing.T) {
+	doc := NewDocument("Test API", "1.0.0")
+	upload := NewObjectSchema().
+		WithProperty("file", StringSchema("binary")).
+		WithProperty("meta", NewObjectSchema())
+
+	op := NewOperation("upload", "Upload", "").
+		WithRequestBody("Upload", true, map[string]MediaType{
+			"multipart/form-data": NewMediaType().
+				WithSchema(&upload).
+				WithEncoding("file", NewEncoding().WithContentType("image/png")).
+				WithEncoding("missing", NewEncoding()).
+				WithEncoding("meta", NewEncoding().WithStyle("matrix")),
+			"application/json": NewMediaType().
+				WithSchema(&upload).
+				WithEncoding("file", NewEncoding()),
+		}).
+		WithNoContentResponse()
+	doc.AddOperation("/upload", "POST", op)
+
+	errs := doc.Validate()
+	base := "/paths/~1upload/post/requestBody/content/"
+
+	if err := findValidationError(errs, base+"multipart~1form-data/encoding/missing"); err == nil || err.Severity != SeverityError {
+		t.Errorf("Expected error for unknown encoding key, got %v", errs)
+	}
+
+	if err := findValidationError(errs, base+"multipart~1form-data/encoding/meta/style"); err == nil || err.Severity != SeverityError {
+		t.Errorf("Expected error for illegal style, got %v", errs)
+	}
+
+	if err := findValidationError(errs, base+"multipart~1form-data/encoding/file"); err != nil {
+		t.Errorf("Expected no error for valid encoding, got %v", err)
+	}
+
+	if err := findValidationError(errs, base+"application~1json/encoding"); err == nil || err.Severity != SeverityWarning {
+		t.Errorf("Expected warning for encoding on JSON media type, got %v", errs)
+	}
+}
+
+func TestDefaultEncodingContentType(t *testing.T) {
+	if ct := DefaultEncodingContentType(NewObjectSchema()); ct != "application/json" {
+		t.Errorf("Expected 'application/json', got '%s'", ct)
+	}
+
+	if ct := DefaultEncodingContentType(StringSchema("binary")); ct != "application/octet-stream" {
+		t.Errorf("Expected 'application/octet-stream', got '%s'", ct)
+	}
+
+	if ct := DefaultEncodingContentType(NewArraySchema(Int32Schema())); ct != "text/plain" {
+		t.Errorf("Expected 'text/plain', got '%s'", ct)
+	}
+}
+
+func TestValidateResponseHeaders(t *testing.T) {
+	doc := NewDocument("Test API", "1.0.0")
+	op := NewOperation("getPet", "Get pet", "").
+		WithResponse("200", "OK", NewResponse("OK").
+			WithHeader("Content-Type", NewHeader().WithSchema(StringSchema(""))).
+			WithHeader("X-Request-ID", NewHeader().WithSchema(UUIDSchema())).
+			WithHeader("Authorization", NewHeader().WithSchema(StringSchema(""))))
+	doc.AddOperation("/pets", "GET", op)
+
+	errs := doc.Validate()
+
+	if err := findValidationError(errs, "/paths/~1pets/get/responses/200/headers/Content-Type"); err == nil {
+		t.Errorf("Expected warning for Content-Type response header, got %v", errs)
+	}
+
+	if err := findValidationError(errs, "/paths/~1pets/get/responses/200/headers/X-Request-ID"); err != nil {
+		t.Errorf("Expected no finding for X-Request-ID, got %v", err)
+	}
+
+	if err := findValidationError(errs, "/paths/~1pets/get/responses/200/headers/Authorization"); err == nil || err.Severity != SeverityWarning {
+		t.Errorf("Expected Authorization response header warning, got %v", err)
+	}
+}
+
+func TestVerifyConsumerContract(t *testing.T) {
+	user := Schema{
+		Type:     Types{"object"},
+		Required: []string{"id"},
+		Properties: map[string]*Schema{
+			"id":   {Type: Types{"integer"}},
+			"name": {Type: Types{"string"}},
+		},
+	}
+	provider := NewDocument("Provider", "1.0.0")
+	provider.AddOperation("/users/{id}", "GET", NewOperation("getUser", "", "").
+		WithPathParameter("id", "", StringSchema("")).
+		WithJSONResponse("200", "OK", &user))
+
+	consumer := NewDocument("Consumer", "1.0.0")
+	consumer.AddOperation("/users/{userId}", "GET", NewOperation("getUser", "", "").
+		WithPathParameter("userId", "", StringSchema("")).
+		WithJSONResponse("200", "OK", &Schema{
+			Type:     Types{"object"},
+			Required: []string{"id", "name"},
+			Properties: map[string]*Schema{
+				"id":    {Type: Types{"number"}},
+				"name":  {Type: Types{"string"}},
+				"email": {Type: Types{"string"}},
+			},
+		}))
+
+	errs := VerifyConsumerContract(provider, consumer)
+
+	base := "/paths/~1users~1{userId}/get"
+	if findValidationError(errs, base+"/responses/200/content/application~1json/schema/properties/email") == nil {
+		t.Errorf("Expected missing field error, got %v", errs)
+	}
+	if findValidationError(errs, base+"/responses/200/content/application~1json/schema/properties/name") == nil {
+		t.Errorf("Expected optional field error, got %v", errs)
+	}
+	if findValidationError(errs, base+"/responses/200/content/application~1json/schema/properties/id") != nil {
+		t.Errorf("Expected integer to satisfy number, got %v", errs)
+	}
+	if findValidationError(errs, base+"/parameters/0") == nil {
+		t.Errorf("Expected unknown parameter error, got %v", errs)
+	}
+}
+
+func TestValidateProfiles(t *testing.T) {
+	doc := NewDocument("Patient API", "1.0.0")
+	outcome := map[string]MediaType{FHIRMediaType: {Schema: &Schema{Ref: "#/components/schemas/OperationOutcome"}}}
+	doc.AddOperation("/Patient/{id}", "GET", NewOperation("readPatient", "", "").
+		WithParameter(NewPathParameter("id", "", StringSchema(""))).
+		WithResponse("200", "Patient", Response{Description: "Patient", Content: map[string]MediaType{"application/json": {}}}).
+		WithResponse("404", "Not found", Response{Description: "Not found", Content: outcome}))
+	doc.AddOperation("/patients", "GET", NewOperation("listPatients", "", "").
+		WithResponse("400", "Bad request", Response{Description: "Bad request"}))
+
+	errs := doc.ValidateProfiles(FHIRProfile{})
+	expected := map[string]string{
+		"fhir/resource-paths":       "/paths/~1patients",
+		"fhir/media-type":           "/paths/~1Patient~1{id}/get/responses/200/content",
+		"fhir/operation-outcome":    "/paths/~1patients/get/responses/400",
+		"fhir/capability-statement": "/paths",
+	}
+	if len(errs) != len(expected) {
+		t.Errorf("Expected %d findings, got %v", len(expected), errs)
+	}
+	for _, err := range errs {
+		if expected[err.Rule] != err.Path {
+			t.Errorf("Expected %s at %s, got %s", err.Rule, expected[err.Rule], err.Path)
+		}
+	}
+}
+
+func TestRuleSet(t *testing.T) {
+	rules, err := LoadRuleSet([]byte(`
+name: acme
+rules:
+  - name: operation-id-casing
+    given: operations
+    field: operationId
+    required: true
+    casing: camel
+  - name: no-trailing-slash
+    description: paths must not end with a slash
+    given: paths
+    field: "@key"
+    notPattern: ".+/$"
+    severity: error
+  - name: property-casing
+    given: properties
+    field: "@key"
+    casing: camel
+`))
+	if err != nil {
+		t.Fatalf("Error loading rule set: %v", err)
+	}
+
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddSchema("Pet", NewObjectSchema().WithProperty("pet_name", StringSchema("")))
+	doc.AddOperation("/pets/", "GET", NewOperation("ListPets", "", ""))
+
+	errs := doc.ValidateProfiles(rules)
+	expected := map[string]string{
+		"acme/operation-id-casing": "/paths/~1pets~1/get",
+		"acme/no-trailing-slash":   "/paths/~1pets~1",
+		"acme/property-casing":     "/components/schemas/Pet/properties/pet_name",
+	}
+	if len(errs) != len(expected) {
+		t.Errorf("Expected %d findings, got %v", len(expected), errs)
+	}
+	for _, err := range errs {
+		if expected[err.Rule] != err.Path {
+			t.Errorf("Expected %s at %s, got %s", err.Rule, expected[err.Rule], err.Path)
+		}
+	}
+	if errs[1].Severity != SeverityError || errs[1].Message != `paths must not end with a slash: name "/pets/" must not match .+/$` {
+		t.Errorf("Expected an error with the rule description, got %v", errs[1])
+	}
+
+	if _, err := LoadRuleSet([]byte("rules:\n  - name: x\n    given: nowhere\n")); err == nil {
+		t.Error("Expected an error for an unknown selector")
+	}
+}
+
+func TestCustomKeywords(t *testing.T) {
+	RegisterKeyword(Keyword{
+		Name: "unit",
+		Check: func(value interface{}) error {
+			if _, ok := value.(string); !ok {
+				return errors.New("must be a string")
+			}
+			return nil
+		},
+	})
+	RegisterKeyword(Keyword{
+		Name: "x-multiple-of-ten",
+		Validate: func(value, instance interface{}, s *Schema) error {
+			if n, ok := instance.(float64); ok && value == true && int(n)%10 != 0 {
+				return fmt.Errorf("%v is not a multiple of ten", n)
+			}
+			return nil
+		},
+	})
+	defer UnregisterKeyword("unit")
+	defer UnregisterKeyword("x-multiple-of-ten")
+
+	var s Schema
+	if err := json.Unmarshal([]byte(`{"type":"integer","unit":"cm","x-multiple-of-ten":true}`), &s); err != nil {
+		t.Fatalf("Failed to unmarshal schema: %v", err)
+	}
+	if unit, _ := s.Keyword("unit"); unit != "cm" {
+		t.Errorf("Expected the unit keyword to be kept, got %v", unit)
+	}
+	data, _ := json.Marshal(s)
+	if !strings.Contains(string(data), `"unit":"cm"`) {
+		t.Errorf("Expected the unit keyword to be marshaled, got %s", data)
+	}
+
+	doc := NewDocument("Test", "1.0.0")
+	if errs := doc.ValidateValue(&s, 25.0); len(errs) != 1 || errs[0].Rule != "x-multiple-of-ten" {
+		t.Errorf("Expected a x-multiple-of-ten violation, got %v", errs)
+	}
+	if errs := doc.ValidateValue(&s, 30.0); len(errs) != 0 {
+		t.Errorf("Expected no violations, got %v", errs)
+	}
+
+	length := Int32Schema().WithKeyword("unit", 3)
+	doc.AddSchema("Length", length)
+	errs := doc.Validate()
+	if len(errs) != 1 || errs[0].Path != "/components/schemas/Length/unit" {
+		t.Errorf("Expected an invalid unit finding, got %v", errs)
+	}
+}
+
+func TestUnits(t *testing.T) {
+	timeout := Int32Schema().WithDescription("Request timeout").WithUnit("milliseconds")
+	if timeout.Description != "Request timeout (milliseconds)" {
+		t.Errorf("Expected the unit in the description, got %q", timeout.Description)
+	}
+	if unit, ok := timeout.Unit(); !ok || unit.Kind != UnitDuration {
+		t.Errorf("Expected a duration unit, got %+v", unit)
+	}
+	if unit := NewUnit("EUR"); unit.Kind != UnitCurrency {
+		t.Errorf("Expected EUR to be a currency, got %+v", unit)
+	}
+
+	order := NewObjectSchema()
+	*order = order.WithProperty("timeout_ms", &timeout).
+		WithProperty("size_bytes", Int32Schema()).
+		WithProperty("amount", DoubleSchema()).
+		WithProperty("label_bytes", StringSchema(""))
+	doc := NewDocument("Test", "1.0.0")
+	doc.AddSchema("Order", *order)
+
+	errs := doc.ValidateProfiles(UnitProfile{})
+	if len(errs) != 2 || errs[0].Path != "/components/schemas/Order/properties/amount" || errs[1].Rule != "units/required" {
+		t.Errorf("Expected amount and size_bytes to require a unit, got %v", errs)
+	}
+	if errs := doc.ValidateProfiles(UnitProfile{Patterns: []string{"amount"}}); len(errs) != 1 {
+		t.Errorf("Expected only amount to require a unit, got %v", errs)
+	}
+
+	doc.Components.Schemas["Order"].Properties["amount"].Extensions = map[string]interface{}{ExtensionUnit: "EUR"}
+	if errs := doc.Validate(); len(errs) != 1 || errs[0].Path != "/components/schemas/Order/properties/amount/x-unit" {
+		t.Errorf("Expected a malformed unit finding, got %v", errs)
+	}
+}
+
+func TestMoney(t *testing.T) {
+	doc := NewDocument("Test", "1.0.0")
+	doc.AddSchema("Price", *MoneySchema("EUR", "USD"))
+	if errs := doc.ValidateJSON(doc.Components.Schemas["Price"], []byte(`{"amount":"12.50","currency":"EUR"}`)); len(errs) != 0 {
+		t.Errorf("Expected a valid amount, got %v", errs)
+	}
+	if errs := doc.ValidateJSON(doc.Components.Schemas["Price"], []byte(`{"amount":12.5,"currency":"GBP"}`)); len(errs) != 2 {
+		t.Errorf("Expected an invalid amount and currency, got %v", errs)
+	}
+
+	order := NewObjectSchema()
+	*order = order.WithProperty("unitPrice", DoubleSchema()).
+		WithProperty("total", &Schema{Ref: "#/components/schemas/Price"}).
+		WithProperty("priceless", DoubleSchema())
+	doc.AddSchema("Order", *order)
+	errs := doc.ValidateProfiles(MoneyProfile{})
+	if len(errs) != 1 || errs[0].Path != "/components/schemas/Order/properties/unitPrice" || errs[0].Severity != SeverityWarning {
+		t.Errorf("Expected a warning on unitPrice, got %v", errs)
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	contact := NewObjectSchema().
+		WithProperty("email", EmailSchema()).
+		WithProperty("phone", StringSchema("")).
+		WithProperty("country", StringSchema("")).
+		WithProperty("startDate", DateSchema()).
+		WithProperty("endDate", DateSchema()).
+		WithExactlyOneOf("email", "phone").
+		WithDependentRequired("phone", "country").
+		WithFieldComparison("endDate", ">=", "startDate")
+
+	if len(contact.Constraints()) != 3 || contact.DependentRequired["phone"][0] != "country" {
+		t.Errorf("Expected 3 constraints and a dependentRequired entry, got %+v", contact.Constraints())
+	}
+	if !strings.Contains(contact.Description, "Exactly one of email, phone must be set.") ||
+		!strings.Contains(contact.Description, "endDate must be after, equal to or greater than startDate.") {
+		t.Errorf("Expected the constraints in the description, got %q", contact.Description)
+	}
+
+	doc := NewDocument("Test", "1.0.0")
+	tests := map[string]int{
+		`{"email":"a@example.com"}`:                              0,
+		`{"email":"a@example.com","phone":"123","country":"FR"}`: 1,
+		`{"phone":"123"}`:                                        1,
+		`{}`:                                                     1,
+		`{"email":"a@example.com","startDate":"2024-03-01","endDate":"2024-02-01"}`: 1,
+		`{"email":"a@example.com","startDate":"2024-03-01","endDate":"2024-03-01"}`: 0,
+	}
+	for input, expected := range tests {
+		if errs := doc.ValidateJSON(&contact, []byte(input)); len(errs) != expected {
+			t.Errorf("Expected %d errors for %s, got %v", expected, input, errs)
+		}
+	}
+
+	doc.AddSchema("Contact", contact.WithConstraint(Constraint{Kind: ConstraintCompare, Fields: []string{"a"}, Operator: "~", Description: "Broken."}))
+	if errs := doc.Validate(); len(errs) != 1 || errs[0].Path != "/components/schemas/Contact/x-constraints" {
+		t.Errorf("Expected a malformed constraint finding, got %v", errs)
+	}
+}
+
+func TestValidateStructure(t *testing.T) {
+	doc := NewDocument("", "1.0.0")
+	doc.AddOperation("/pets/{petId}", "GET", NewOperation("getPet", "", "").
+		WithParameter(NewQueryParameter("limit", "", false, Int32Schema())).
+		WithResponse("200", "", Response{}))
+	doc.AddOperation("/pets", "GET", NewOperation("getPet", "", "").
+		WithParameter(Parameter{Name: "owner", In: "path", Schema: StringSchema("")}).
+		WithResponse("200", "Pets", Response{}))
+	doc.AddSecurityRequirement(SecurityRequirement{"apiKey": {}})
+
+	errs := doc.Validate()
+	expected := map[string]string{
+		"/info/title": "info title is required",
+		"/paths/~1pets~1{petId}/get/responses/200/description": "response description is required",
+		"/paths/~1pets~1{petId}":                               "path variable {petId} has no matching path parameter",
+		"/paths/~1pets/get/parameters/0":                       `path parameter "owner" does not appear in the path template`,
+		"/paths/~1pets~1{petId}/get/operationId":               `operationId "getPet" is already used by /paths/~1pets/get`,
+		"/security/0/apiKey":                                   `security scheme "apiKey" is not declared`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for path, message := range expected {
+		if err := findValidationError(errs, path); err == nil || err.Message != message {
+			t.Errorf("Expected %q at %s, got %v", message, path, err)
+		}
+	}
+
+	doc = NewDocument("Test", "1.0.0")
+	doc.AddOperation("/pets/{petId}", "GET", NewOperation("getPet", "", "").WithResponse("200", "Pet", Response{}))
+	doc.AddOperation("/pets/{petId}", "DELETE", NewOperation("deletePet", "", "").WithResponse("204", "Deleted", Response{}))
+	item := doc.Paths["/pets/{petId}"]
+	item.Parameters = []Parameter{{Name: "petId", In: "path", Schema: Int64Schema()}}
+	doc.Paths["/pets/{petId}"] = item
+	errs = doc.Validate()
+	if len(errs) != 1 || errs[0].Path != "/paths/~1pets~1{petId}/parameters/0" || errs[0].Message != `path parameter "petId" must be required` {
+		t.Errorf("Expected one finding on the path item parameter, got %v", errs)
+	}
+}
+
+func TestNullabilityReport(t *testing.T) {
+	nickname := StringSchema("").WithNullable(true)
+	notes := StringSchema("").WithNullable(true)
+	vaccinated := NewBooleanSchema()
+	pet := NewObjectSchema().
+		WithRequiredProperty("name", StringSchema("")).
+		WithRequiredProperty("nickname", &nickname).
+		WithProperty("notes", &notes).
+		WithProperty("vaccinated", vaccinated).
+		WithProperty("neutered", &Schema{Type: Types{"boolean"}, Default: false})
+	doc := NewDocument("Test", "1.0.0")
+	doc.AddSchema("Pet", pet)
+
+	report := doc.NullabilityReport()
+	expected := map[string]Nullability{
+		"name":       NullabilityRequired,
+		"nickname":   NullabilityRequiredNullable,
+		"notes":      NullabilityOptionalNullable,
+		"vaccinated": NullabilityOptional,
+		"neutered":   NullabilityOptional,
+	}
+	if len(report.Fields) != len(expected) {
+		t.Fatalf("Expected %d fields, got %v", len(expected), report.Fields)
+	}
+	for _, field := range report.Fields {
+		if field.Nullability != expected[field.Property] {
+			t.Errorf("Expected %s to be %s, got %s", field.Property, expected[field.Property], field.Nullability)
+		}
+	}
+
+	errs := doc.ValidateProfiles(NullabilityProfile{})
+	if len(errs) != 2 || errs[0].Rule != "nullability/required-nullable-string" || errs[1].Rule != "nullability/optional-boolean-default" {
+		t.Errorf("Expected warnings on nickname and vaccinated, got %v", errs)
+	}
+	if !strings.Contains(report.Markdown(), "| nickname | required-nullable |") {
+		t.Errorf("Expected nickname in the Markdown report, got %s", report.Markdown())
+	}
+}
+
+func TestPathParameters(t *testing.T) {
+	doc := NewDocument("Test", "1.0.0")
+	doc.AddOperation("/users/{userId}", "GET", NewOperation("getUser", "", "").
+		WithPathParameter("userId", "The user", StringSchema("")))
+	doc.AddOperation("/users/{userId}/orders/{filter}", "GET", NewOperation("listOrders", "", "").
+		WithPathParameter("userId", "", Int64Schema()).
+		WithPathParameter("filter", "", NewObjectSchema()))
+	doc.AddOperation("/teams/{teamId}", "GET", NewOperation("getTeam", "", "").
+		WithParameter(Parameter{Name: "teamId", In: "path", Required: true}))
+
+	errs := doc.ValidateProfiles(PathParameterProfile{})
+	rules := []string{"path-parameters/schema", "path-parameters/consistent", "path-parameters/scalar"}
+	if len(errs) != len(rules) {
+		t.Fatalf("Expected %d findings, got %v", len(rules), errs)
+	}
+	for i, rule := range rules {
+		if errs[i].Rule != rule {
+			t.Errorf("Expected finding %d to be %s, got %v", i, rule, errs[i])
+		}
+	}
+
+	doc.ApplyIDPolicy(IDPolicy{Default: IDInt64})
+	_, _, op := doc.FindOperation("getUser")
+	if s := op.Parameters[0].Schema; s.Format != "int64" || s.Description != "" {
+		t.Errorf("Expected userId to become an int64, got %+v", s)
+	}
+	if errs := doc.ValidateProfiles(PathParameterProfile{}); len(errs) != 1 || errs[0].Rule != "path-parameters/scalar" {
+		t.Errorf("Expected only the object filter to remain, got %v", errs)
+	}
+
+	scaffolded := NewOperation("getOrder", "", "").WithIDPathParameters("/orders/{orderId}/{slug}", IDPolicy{Overrides: map[string]IDKind{"slug": IDString}})
+	if len(scaffolded.Parameters) != 2 || scaffolded.Parameters[0].Schema.Format != "uuid" || !scaffolded.Parameters[1].Schema.Type.Is("string") {
+		t.Errorf("Expected uuid and string parameters, got %+v", scaffolded.Parameters)
+	}
+}
+
+func TestHeaderProfile(t *testing.T) {
+	requestID := NewHeader().WithSchema(UUIDSchema())
+	doc := NewDocument("Test", "1.0.0")
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithResponse("200", "Pets", Response{Headers: map[string]Header{"x-request-id": requestID, "RateLimit-Limit": requestID}}).
+		WithResponse("404", "Not found", Response{}).
+		WithResponse("429", "Too many requests", Response{Headers: map[string]Header{"X-Request-ID": requestID}}))
+	doc.AddOperation("/pets", "POST", NewOperation("createPet", "", "").
+		WithResponse("201", "Created", Response{}))
+
+	profile := HeaderProfile{Rules: []HeaderRule{
+		{Header: "X-Request-ID"},
+		{Header: "RateLimit-*", Codes: []string{"2XX", "429"}},
+	}}
+	errs := doc.ValidateProfiles(profile)
+	expected := []string{
+		"GET /pets response 404 does not document header X-Request-ID",
+		"GET /pets response 429 does not document header RateLimit-*",
+		"POST /pets response 201 does not document header X-Request-ID",
+		"POST /pets response 201 does not document header RateLimit-*",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d gaps, got %v", len(expected), errs)
+	}
+	for i, message := range expected {
+		if errs[i].Message != message || errs[i].Rule != "headers/required" {
+			t.Errorf("Expected %q, got %v", message, errs[i])
+		}
+	}
+}
+
+func TestValidateWebhooks(t *testing.T) {
+	doc := NewDocument("Events", "1.0.0")
+	doc.AddWebhook("petCreated", "POST", NewOperation("petCreated", "Pet created", "").
+		WithResponse("200", "Received", Response{}))
+	doc.AddWebhook("petCreated", "PUT", NewOperation("petReplaced", "Pet replaced", ""))
+	if item := doc.Webhooks["petCreated"]; item.Post == nil || item.Put == nil {
+		t.Fatalf("Expected POST and PUT on the webhook, got %+v", item)
+	}
+
+	errs := doc.Validate()
+	if err := findValidationError(errs, "/webhooks/petCreated/put/responses"); err == nil || err.Severity != SeverityWarning {
+		t.Errorf("Expected a warning for the webhook without responses, got %v", errs)
+	}
+	if err := findValidationError(errs, "/webhooks/petCreated/post/responses"); err != nil {
+		t.Errorf("Expected no finding for the webhook with responses, got %v", err)
+	}
+
+	op := doc.Webhooks["petCreated"].Post
+	op.Security = []SecurityRequirement{{"signature": {}}}
+	doc.OpenAPI = "3.0.3"
+	errs = doc.Validate()
+	if findValidationError(errs, "/webhooks") == nil {
+		t.Errorf("Expected an error for webhooks in an OpenAPI 3.0 document, got %v", errs)
+	}
+	if findValidationError(errs, "/webhooks/petCreated/post/security/0/signature") == nil {
+		t.Errorf("Expected an error for the undeclared webhook security scheme, got %v", errs)
+	}
+
+	data, _ := json.Marshal(doc)
+	decoded, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("Error decoding document: %v", err)
+	}
+	if decoded.Webhooks["petCreated"].Put == nil {
+		t.Errorf("Expected the webhook to round-trip, got %+v", decoded.Webhooks)
+	}
+}
+
+func TestInfoSummaryAndLicenseIdentifier(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0").WithSummary("Pets as a service").WithLicenseSPDX("MIT")
+	data, _ := json.Marshal(doc.Info)
+	if !strings.Contains(string(data), `"summary":"Pets as a service"`) || !strings.Contains(string(data), `"license":{"name":"MIT","identifier":"MIT"}`) {
+		t.Errorf("Expected summary and license identifier, got %s", data)
+	}
+	if errs := doc.Validate(); findValidationError(errs, "/info/license") != nil {
+		t.Errorf("Expected no license error, got %v", errs)
+	}
+
+	doc.Info.License.URL = "https://opensource.org/licenses/MIT"
+	if errs := doc.Validate(); findValidationError(errs, "/info/license") == nil {
+		t.Errorf("Expected an error for a license with identifier and url, got %v", errs)
+	}
+}
+
+func TestIdempotencyAnalysis(t *testing.T) {
+	doc := NewDocument("Pet API", "1.0.0")
+	doc.AddOperation("/pets", "GET", NewOperation("listPets", "", "").
+		WithJSONRequestBody("Filter", false, NewObjectSchema()))
+	doc.AddOperation("/pets", "POST", NewOperation("createPet", "", ""))
+	doc.AddOperation("/pets/search", "POST", NewOperation("searchPets", "", "").WithIdempotency(IdempotencySafe))
+	doc.AddOperation("/pets/{petId}", "DELETE", NewOperation("deletePet", "", "").
+		WithResponse("200", "Deleted", Response{}))
+
+	classes := make(map[string]OperationIdempotency)
+	for _, entry := range doc.IdempotencyAnalysis() {
+		classes[entry.OperationID] = entry
+	}
+	for id, expected := range map[string]Idempotency{"listPets": IdempotencySafe, "createPet": IdempotencyNonIdempotent, "searchPets": IdempotencySafe, "deletePet": IdempotencyIdempotent} {
+		if classes[id].Idempotency != expected {
+			t.Errorf("Expected %s to be %s, got %+v", id, expected, classes[id])
+		}
+	}
+	if !classes["searchPets"].Overridden || classes["createPet"].Overridden {
+		t.Errorf("Expected only searchPets to be overridden, got %+v", classes)
+	}
+	if !*doc.EffectiveClientGuidance("/pets/search", "POST", doc.Paths["/pets/search"].Post).RetrySafe {
+		t.Error("Expected the safe search to be retry-safe")
+	}
+
+	rules := make(map[string]string)
+	for _, err := range (IdempotencyProfile{}).Validate(doc) {
+		rules[err.Rule] = err.Path
+	}
+	if rules["idempotency/get-request-body"] != "/paths/~1pets/get/requestBody" {
+		t.Errorf("Expected a warning for the GET request body, got %v", rules)
+	}
+	if rules["idempotency/delete-response"] != "/paths/~1pets~1{petId}/delete/responses/200" {
+		t.Errorf("Expected a warning for the bodiless 200, got %v", rules)
+	}
+	if len(rules) != 2 {
+		t.Errorf("Expected 2 rules to fire, got %v", rules)
+	}
+}
@@ -0,0 +1,415 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ValidateValue checks a decoded JSON value (as produced by encoding/json into an
+// interface{}) against a schema, resolving references against the document.
+// Findings carry JSON pointers into the value rather than into the document.
+func (d *Document) ValidateValue(s *Schema, value interface{}) []ValidationError {
+	v := &valueValidator{doc: d}
+	v.validate("", s, value, 0)
+	return v.errs
+}
+
+// ValidateJSON decodes data and validates it against a schema
+func (d *Document) ValidateJSON(s *Schema, data []byte) []ValidationError {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return []ValidationError{{Path: "", Message: fmt.Sprintf("invalid JSON: %v", err), Severity: SeverityError}}
+	}
+	return d.ValidateValue(s, value)
+}
+
+// valueValidator accumulates findings while validating a value against a schema
+type valueValidator struct {
+	doc  *Document
+	errs []ValidationError
+}
+
+func (v *valueValidator) errorf(pointer, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{Path: pointer, Message: fmt.Sprintf(format, args...), Severity: SeverityError})
+}
+
+// matches reports whether value validates against s without recording findings
+func (v *valueValidator) matches(pointer string, s *Schema, value interface{}, depth int) bool {
+	sub := &valueValidator{doc: v.doc}
+	sub.validate(pointer, s, value, depth)
+	return len(sub.errs) == 0
+}
+
+func (v *valueValidator) validate(pointer string, s *Schema, value interface{}, depth int) {
+	if s == nil {
+		return
+	}
+	if depth > 64 {
+		v.errorf(pointer, "schema nesting too deep")
+		return
+	}
+	if s.Ref != "" {
+		resolved := v.doc.resolveSchema(s)
+		if resolved == nil {
+			v.errorf(pointer, "unresolved reference %q", s.Ref)
+			return
+		}
+		s = resolved
+	}
+
+	for _, sub := range s.AllOf {
+		v.validate(pointer, sub, value, depth+1)
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if v.matches(pointer, sub, value, depth+1) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.errorf(pointer, "value does not match any schema in anyOf")
+		}
+	}
+	if len(s.OneOf) > 0 {
+		count := 0
+		for _, sub := range s.OneOf {
+			if v.matches(pointer, sub, value, depth+1) {
+				count++
+			}
+		}
+		if count != 1 {
+			v.errorf(pointer, "value matches %d schemas in oneOf, expected exactly 1", count)
+		}
+	}
+	if s.Not != nil && v.matches(pointer, s.Not, value, depth+1) {
+		v.errorf(pointer, "value must not match the schema in not")
+	}
+	v.validateKeywords(pointer, s, value)
+	v.validateApplicators(pointer, s, value, depth)
+
+	if value == nil {
+		if !s.AllowsNull() && len(s.Type) > 0 {
+			v.errorf(pointer, "value must not be null")
+		}
+		return
+	}
+
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		v.errorf(pointer, "value %v is not one of the allowed enum values", value)
+	}
+
+	// Of several types, the one the value has is checked further
+	typ := s.Type.Primary()
+	if len(s.Type) > 1 {
+		if typ = matchingType(s.Type, value); typ == "" {
+			v.errorf(pointer, "expected %s, got %s", s.Type, jsonTypeName(value))
+			return
+		}
+	}
+	switch typ {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			v.errorf(pointer, "expected string, got %s", jsonTypeName(value))
+			return
+		}
+		v.validateString(pointer, s, str)
+	case "integer", "number":
+		num, ok := toFloat(value)
+		if !ok {
+			v.errorf(pointer, "expected %s, got %s", typ, jsonTypeName(value))
+			return
+		}
+		if typ == "integer" && num != math.Trunc(num) {
+			v.errorf(pointer, "expected integer, got %v", num)
+		}
+		v.validateNumber(pointer, s, num)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.errorf(pointer, "expected boolean, got %s", jsonTypeName(value))
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			v.errorf(pointer, "expected array, got %s", jsonTypeName(value))
+			return
+		}
+		v.validateArray(pointer, s, items, depth)
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			v.errorf(pointer, "expected object, got %s", jsonTypeName(value))
+			return
+		}
+		v.validateObject(pointer, s, obj, depth)
+	default:
+		if obj, ok := value.(map[string]interface{}); ok {
+			v.validateObject(pointer, s, obj, depth)
+		}
+	}
+}
+
+func (v *valueValidator) validateString(pointer string, s *Schema, str string) {
+	length := utf8.RuneCountInString(str)
+	if s.MinLength != nil && length < *s.MinLength {
+		v.errorf(pointer, "string is shorter than minLength %d", *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		v.errorf(pointer, "string is longer than maxLength %d", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(str) {
+			v.errorf(pointer, "string does not match pattern %q", s.Pattern)
+		}
+	}
+	if !validFormat(s.Format, str) {
+		v.errorf(pointer, "string is not a valid %s", s.Format)
+	}
+}
+
+func (v *valueValidator) validateNumber(pointer string, s *Schema, num float64) {
+	if min, exclusive := s.LowerBound(); min != nil {
+		if exclusive && num <= *min {
+			v.errorf(pointer, "value must be greater than %v", *min)
+		} else if num < *min {
+			v.errorf(pointer, "value must be at least %v", *min)
+		}
+	}
+	if max, exclusive := s.UpperBound(); max != nil {
+		if exclusive && num >= *max {
+			v.errorf(pointer, "value must be less than %v", *max)
+		} else if num > *max {
+			v.errorf(pointer, "value must be at most %v", *max)
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		if q := num / *s.MultipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.errorf(pointer, "value must be a multiple of %v", *s.MultipleOf)
+		}
+	}
+}
+
+func (v *valueValidator) validateArray(pointer string, s *Schema, items []interface{}, depth int) {
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		v.errorf(pointer, "array has fewer than %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		v.errorf(pointer, "array has more than %d items", *s.MaxItems)
+	}
+	if s.UniqueItems {
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				if reflect.DeepEqual(items[i], items[j]) {
+					v.errorf(pointer, "array items %d and %d are not unique", i, j)
+				}
+			}
+		}
+	}
+	for i := len(s.PrefixItems); i < len(items); i++ {
+		v.validate(pointer+"/"+strconv.Itoa(i), s.Items, items[i], depth+1)
+	}
+	v.validateContains(pointer, s, items, depth)
+}
+
+func (v *valueValidator) validateObject(pointer string, s *Schema, obj map[string]interface{}, depth int) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			v.errorf(pointer, "missing required property %q", name)
+		}
+	}
+	for _, name := range sortedKeys(s.DependentRequired) {
+		if _, ok := obj[name]; !ok {
+			continue
+		}
+		for _, dependent := range s.DependentRequired[name] {
+			if _, ok := obj[dependent]; !ok {
+				v.errorf(pointer, "property %q is required when %q is present", dependent, name)
+			}
+		}
+	}
+	if s.MinProperties != nil && len(obj) < *s.MinProperties {
+		v.errorf(pointer, "object has fewer than %d properties", *s.MinProperties)
+	}
+	if s.MaxProperties != nil && len(obj) > *s.MaxProperties {
+		v.errorf(pointer, "object has more than %d properties", *s.MaxProperties)
+	}
+
+	matched := v.validatePatternProperties(pointer, s, obj, depth)
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propPointer := pointer + "/" + escapePointer(name)
+		if prop, ok := s.Properties[name]; ok {
+			v.validate(propPointer, prop, obj[name], depth+1)
+			continue
+		}
+		if matched[name] {
+			continue
+		}
+		if ap := s.AdditionalProperties; ap != nil {
+			v.validateAdditional(propPointer, "additional", ap, name, obj[name], depth)
+		} else if up := s.UnevaluatedProperties; up != nil && !v.evaluatedByAllOf(s, name) {
+			v.validateAdditional(propPointer, "unevaluated", up, name, obj[name], depth)
+		}
+	}
+}
+
+// validateAdditional checks a property against additionalProperties or
+// unevaluatedProperties, named by kind
+func (v *valueValidator) validateAdditional(pointer, kind string, ap *AdditionalProperties, name string, value interface{}, depth int) {
+	if ap.Schema != nil {
+		v.validate(pointer, ap.Schema, value, depth+1)
+	} else if ap.Bool != nil && !*ap.Bool {
+		v.errorf(pointer, "%s property %q is not allowed", kind, name)
+	}
+}
+
+// evaluatedByAllOf reports whether an allOf member of s declares a property,
+// which unevaluatedProperties then leaves alone
+func (v *valueValidator) evaluatedByAllOf(s *Schema, name string) bool {
+	for _, sub := range s.AllOf {
+		if sub = v.doc.resolveSchema(sub); sub == nil {
+			continue
+		}
+		if _, ok := sub.Properties[name]; ok || v.evaluatedByAllOf(sub, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// validFormat checks the well-known string formats; unknown formats always pass
+func validFormat(format, value string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05.999999999Z07:00", value)
+		return err == nil
+	case "duration":
+		return validDuration(value)
+	case "email":
+		_, err := mail.ParseAddress(value)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "decimal":
+		return decimalPattern.MatchString(value)
+	case "uri", "url":
+		u, err := url.Parse(value)
+		return err == nil && u.IsAbs()
+	case "ipv4":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil
+	case "ipv6":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() == nil
+	}
+	return true
+}
+
+// toFloat converts the numeric representations produced by JSON decoding
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// containsValue reports whether value is equal to one of the candidates,
+// comparing numbers by value regardless of their Go type
+func containsValue(candidates []interface{}, value interface{}) bool {
+	num, isNum := toFloat(value)
+	for _, c := range candidates {
+		if isNum {
+			if n, ok := toFloat(c); ok && n == num {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(c, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingType returns the first of types a decoded value has, or an empty
+// string when it has none of them
+func matchingType(types Types, value interface{}) string {
+	for _, t := range types {
+		num, isNum := toFloat(value)
+		var ok bool
+		switch t {
+		case "string":
+			_, ok = value.(string)
+		case "integer":
+			ok = isNum && num == math.Trunc(num)
+		case "number":
+			ok = isNum
+		case "boolean":
+			_, ok = value.(bool)
+		case "array":
+			_, ok = value.([]interface{})
+		case "object":
+			_, ok = value.(map[string]interface{})
+		}
+		if ok {
+			return t
+		}
+	}
+	return ""
+}
+
+// jsonTypeName names the JSON type of a decoded value for error messages
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if _, ok := toFloat(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func petDocument() *Document {
+	doc := NewDocument("Pet API", "1.0.0")
+	status := StringSchema("").WithEnum("available", "sold")
+	name := StringSchema("").WithMinLength(3)
+	doc.AddSchema("Pet", NewObjectSchema().
+		WithRequiredProperty("id", Int64Schema()).
+		WithRequiredProperty("name", &name).
+		WithProperty("status", &status).
+		WithProperty("tags", NewArraySchema(StringSchema(""))))
+
+	op := NewOperation("getPet", "Get pet", "").
+		WithPathParameter("petId", "Pet ID", Int64Schema()).
+		WithOkResponse("Pet found", &Schema{Ref: "#/components/schemas/Pet"}).
+		WithNotFoundResponse("Pet not found")
+	doc.AddOperation("/pets/{petId}", "GET", op)
+	return doc
+}
+
+func TestSampleValueValidates(t *testing.T) {
+	doc := petDocument()
+	pet := &Schema{Ref: "#/components/schemas/Pet"}
+
+	sample := doc.SampleValue(pet)
+	obj, ok := sample.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected object sample, got %T", sample)
+	}
+
+	if obj["status"] != "available" {
+		t.Errorf("Expected first enum value 'available', got '%v'", obj["status"])
+	}
+
+	if errs := doc.ValidateValue(pet, sample); len(errs) != 0 {
+		t.Errorf("Expected sample to validate, got %v", errs)
+	}
+}
+
+func TestTemporalSchemas(t *testing.T) {
+	doc := NewDocument("Test", "1.0.0")
+	for _, s := range []*Schema{TimeSchema(), DurationSchema(), IntervalSchema(""), IntervalSchema("date")} {
+		sample := doc.SampleValue(s)
+		if errs := doc.ValidateValue(s, sample); len(errs) != 0 {
+			t.Errorf("Expected sample %v to validate, got %v", sample, errs)
+		}
+	}
+
+	for value, valid := range map[string]bool{"P1Y2M10DT2H30M": true, "PT0.5S": true, "P3W": true, "P": false, "PT": false, "1H": false} {
+		if errs := doc.ValidateValue(DurationSchema(), value); (len(errs) == 0) != valid {
+			t.Errorf("Expected duration %q valid=%v, got %v", value, valid, errs)
+		}
+	}
+	if errs := doc.ValidateValue(TimeSchema(), "25:00"); len(errs) != 1 {
+		t.Errorf("Expected an invalid time, got %v", errs)
+	}
+
+	interval := doc.SampleValue(IntervalSchema("")).(map[string]interface{})
+	if interval["start"] != "2024-01-01T00:00:00Z" || interval["end"] != "2024-01-02T00:00:00Z" {
+		t.Errorf("Expected an interval ending after its start, got %v", interval)
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	doc := petDocument()
+	pet := &Schema{Ref: "#/components/schemas/Pet"}
+
+	errs := doc.ValidateJSON(pet, []byte(`{"id": 1.5, "name": "Al", "status": "lost", "tags": [1]}`))
+	expected := []string{"/id", "/name", "/status", "/tags/0"}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for _, path := range expected {
+		if findValidationError(errs, path) == nil {
+			t.Errorf("Expected error at '%s', got %v", path, errs)
+		}
+	}
+}
+
+func TestJSONSchemaKeywords(t *testing.T) {
+	doc := NewDocument("Test", "1.0.0")
+	shortName := StringSchema("").WithMaxLength(8)
+	zipCode := StringSchema("").WithPattern(`^[0-9]{5}$`)
+	kind := StringSchema("").WithConst("parcel").WithComment("fixed discriminator").WithExamples("parcel")
+	point := NewArraySchema(Int32Schema()).
+		WithPrefixItems(StringSchema(""), Int32Schema()).
+		WithContains(&Schema{Const: 0}).WithMaxContains(1)
+	labels := NewObjectSchema().
+		WithPatternProperty("^x-", StringSchema("")).
+		WithPropertyNames(&shortName).
+		WithUnevaluatedProperties(false)
+	shipping := NewObjectSchema().
+		WithProperty("country", StringSchema("")).
+		WithProperty("postalCode", StringSchema("")).
+		WithIf(&Schema{Properties: map[string]*Schema{"country": {Const: "US"}}}).
+		WithThen(&Schema{Properties: map[string]*Schema{"postalCode": &zipCode}}).
+		WithDependentSchema("postalCode", &Schema{Required: []string{"country"}})
+	schema := NewObjectSchema().
+		WithProperty("point", &point).
+		WithProperty("labels", &labels).
+		WithProperty("shipping", &shipping).
+		WithProperty("kind", &kind).
+		WithDef("Code", StringSchema(""))
+
+	errs := doc.ValidateJSON(&schema, []byte(`{
+		"point": [1, 0, 0, 3],
+		"labels": {"x-team": 1, "owner": "me", "x-very-long": "v"},
+		"shipping": {"country": "US", "postalCode": "ABC"},
+		"kind": "letter"
+	}`))
+	expected := []string{"/point", "/point/0", "/labels", "/labels/owner", "/labels/x-team", "/shipping/postalCode", "/kind"}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for _, path := range expected {
+		if findValidationError(errs, path) == nil {
+			t.Errorf("Expected error at '%s', got %v", path, errs)
+		}
+	}
+	if errs := doc.ValidateJSON(&schema, []byte(`{"point": ["a", 0, 1], "shipping": {"country": "FR", "postalCode": "ABC"}, "kind": "parcel"}`)); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Error marshaling schema: %v", err)
+	}
+	var decoded Schema
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Error unmarshaling schema: %v", err)
+	}
+	if decoded.Defs["Code"] == nil || len(decoded.Properties["point"].PrefixItems) != 2 || decoded.Properties["shipping"].If == nil ||
+		decoded.Properties["labels"].UnevaluatedProperties == nil || decoded.Properties["kind"].Comment != "fixed discriminator" {
+		t.Errorf("Expected the 2020-12 keywords to round-trip, got %s", data)
+	}
+}
+
+func TestTypes(t *testing.T) {
+	var s Schema
+	if err := json.Unmarshal([]byte(`{"type": ["string", "null"]}`), &s); err != nil {
+		t.Fatalf("Error unmarshaling schema: %v", err)
+	}
+	if s.Type.Primary() != "string" || !s.AllowsNull() || s.Nullable {
+		t.Errorf("Expected a nullable string type, got %v", s.Type)
+	}
+	data, _ := json.Marshal(s)
+	if string(data) != `{"type":["string","null"]}` {
+		t.Errorf("Expected the type list to round-trip, got %s", data)
+	}
+	data, _ = json.Marshal(NewIntegerSchema())
+	if string(data) != `{"type":"integer"}` {
+		t.Errorf("Expected a single type to marshal as a string, got %s", data)
+	}
+
+	doc := NewDocument("Test", "1.0.0")
+	nullable := NullableStringSchema()
+	if errs := doc.ValidateJSON(nullable, []byte(`null`)); len(errs) != 0 {
+		t.Errorf("Expected null to be accepted, got %v", errs)
+	}
+	if errs := doc.ValidateJSON(nullable, []byte(`1`)); len(errs) != 1 || errs[0].Message != "expected string or null, got number" {
+		t.Errorf("Expected a type error, got %v", errs)
+	}
+	count := NewIntegerSchema().AddType("null")
+	if errs := doc.ValidateJSON(&count, []byte(`null`)); len(errs) != 0 {
+		t.Errorf("Expected AddType(\"null\") to accept null, got %v", errs)
+	}
+	id := Schema{Type: Types{"string", "integer"}}.WithMinimum(1)
+	for input, expected := range map[string]int{`"a"`: 0, `2`: 0, `0`: 1, `1.5`: 1, `true`: 1} {
+		if errs := doc.ValidateJSON(&id, []byte(input)); len(errs) != expected {
+			t.Errorf("Expected %d errors for %s, got %v", expected, input, errs)
+		}
+	}
+}
+
+func TestExclusiveBounds(t *testing.T) {
+	doc := NewDocument("Test", "1.0.0")
+	price := DoubleSchema().WithExclusiveMinimum(0)
+	doc.AddSchema("Price", price)
+	if errs := doc.ValidateValue(&price, 0); len(errs) != 1 || errs[0].Message != "value must be greater than 0" {
+		t.Errorf("Expected 0 to be rejected, got %v", errs)
+	}
+	if errs := doc.ValidateValue(&price, 0.5); len(errs) != 0 {
+		t.Errorf("Expected 0.5 to be accepted, got %v", errs)
+	}
+
+	data, _ := json.Marshal(doc)
+	if !strings.Contains(string(data), `"Price":{"exclusiveMinimum":0,"type":"number","format":"double"}`) {
+		t.Errorf("Expected the 3.1 numeric form, got %s", data)
+	}
+	doc.OpenAPI = "3.0.3"
+	data, _ = json.Marshal(doc)
+	if !strings.Contains(string(data), `"Price":{"minimum":0,"exclusiveMinimum":true,"type":"number","format":"double"}`) {
+		t.Errorf("Expected the 3.0 flag form, got %s", data)
+	}
+	if doc.Components.Schemas["Price"].ExclusiveMinimum.Value == nil {
+		t.Error("Expected marshaling to leave the document untouched")
+	}
+
+	var legacy Schema
+	if err := json.Unmarshal([]byte(`{"type": "integer", "maximum": 10, "exclusiveMaximum": true}`), &legacy); err != nil {
+		t.Fatalf("Error unmarshaling schema: %v", err)
+	}
+	if max, exclusive := legacy.UpperBound(); max == nil || *max != 10 || !exclusive {
+		t.Errorf("Expected an exclusive maximum of 10, got %+v", legacy)
+	}
+	doc.OpenAPI = "3.1.0"
+	doc.AddSchema("Legacy", legacy)
+	data, _ = json.Marshal(doc)
+	if !strings.Contains(string(data), `"Legacy":{"exclusiveMaximum":10,"type":"integer"}`) {
+		t.Errorf("Expected the flag form to be converted, got %s", data)
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	doc := petDocument()
+	header := http.Header{"Content-Type": []string{"application/json; charset=utf-8"}}
+
+	if errs := doc.CheckResponse("getPet", 200, header, []byte(`{"id": 1, "name": "Rex"}`)); len(errs) != 0 {
+		t.Errorf("Expected conforming response, got %v", errs)
+	}
+
+	if errs := doc.CheckResponse("getPet", 200, header, []byte(`{"id": 1}`)); len(errs) != 1 {
+		t.Errorf("Expected 1 error for missing property, got %v", errs)
+	}
+
+	if errs := doc.CheckResponse("getPet", 500, header, nil); len(errs) != 1 {
+		t.Errorf("Expected 1 error for undocumented status, got %v", errs)
+	}
+}
+
+func TestEnumValues(t *testing.T) {
+	schema := Schema{Type: Types{"string"}}.WithEnumValues(
+		NewEnumValue("active", "StatusActive", "Account in use"),
+		NewEnumValue("legacy", "StatusLegacy", "").WithDeprecated(),
+	)
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Error marshaling schema: %v", err)
+	}
+	var decoded Schema
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Error unmarshaling schema: %v", err)
+	}
+
+	values := decoded.EnumValues()
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 enum values, got %d", len(values))
+	}
+	if values[0].Name != "StatusActive" || values[0].Description != "Account in use" || values[0].Deprecated {
+		t.Errorf("Expected active value metadata, got %+v", values[0])
+	}
+	if !values[1].Deprecated {
+		t.Errorf("Expected legacy value to be deprecated, got %+v", values[1])
+	}
+}
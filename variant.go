@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Extensions recording the API versions an element is available in
+const (
+	ExtensionIntroducedIn = "x-introduced-in"
+	ExtensionRemovedIn    = "x-removed-in"
+)
+
+// WithVersions records the API versions the operation is available in: from
+// introduced on and before removed. Either may be empty for an open range.
+func (o Operation) WithVersions(introduced, removed string) Operation {
+	if introduced != "" {
+		o = o.WithExtension(ExtensionIntroducedIn, introduced)
+	}
+	if removed != "" {
+		o = o.WithExtension(ExtensionRemovedIn, removed)
+	}
+	return o
+}
+
+// WithVersions records the API versions the parameter is available in
+func (p Parameter) WithVersions(introduced, removed string) Parameter {
+	if introduced != "" {
+		p = p.WithExtension(ExtensionIntroducedIn, introduced)
+	}
+	if removed != "" {
+		p = p.WithExtension(ExtensionRemovedIn, removed)
+	}
+	return p
+}
+
+// WithVersions records the API versions the schema is available in, as a
+// component schema or as a property
+func (s Schema) WithVersions(introduced, removed string) Schema {
+	if introduced != "" {
+		s = s.WithExtension(ExtensionIntroducedIn, introduced)
+	}
+	if removed != "" {
+		s = s.WithExtension(ExtensionRemovedIn, removed)
+	}
+	return s
+}
+
+// ForVersion materializes the variant of the document for one API version:
+// operations, parameters, component schemas and properties introduced after
+// it or removed in or before it are left out, the version extensions are
+// dropped and the info version is set to the version. Versions are compared
+// with CompareVersions, so dates ("2023-10-01") and dotted numbers ("1.10")
+// both work. The document itself is left untouched.
+func (d *Document) ForVersion(version string) (*Document, error) {
+	variant, err := d.Clone()
+	if err != nil {
+		return nil, err
+	}
+	variant.filterElements(func(ext map[string]interface{}) bool {
+		var introduced, removed string
+		decodeExtension(ext[ExtensionIntroducedIn], &introduced)
+		decodeExtension(ext[ExtensionRemovedIn], &removed)
+		return (introduced == "" || CompareVersions(introduced, version) <= 0) &&
+			(removed == "" || CompareVersions(version, removed) < 0)
+	})
+	variant.stripExtensions(ExtensionIntroducedIn, ExtensionRemovedIn)
+	variant.Info.Version = version
+	return variant, nil
+}
+
+// CompareVersions compares API versions segment by segment, splitting them on
+// dots, dashes and underscores. Numeric segments compare as numbers and other
+// segments as strings. It returns -1, 0 or 1 as a is before, equal to or after b.
+func CompareVersions(a, b string) int {
+	split := func(v string) []string {
+		return strings.FieldsFunc(strings.TrimPrefix(v, "v"), func(r rune) bool {
+			return r == '.' || r == '-' || r == '_'
+		})
+	}
+	as, bs := split(a), split(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		// Missing segments count as zero, so "1" equals "1.0"
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,91 @@
+package openapi
+
+import "slices"
+
+// Version is an OpenAPI version a document can be written in
+type Version string
+
+const (
+	// V30 writes OpenAPI 3.0.3: nullable, boolean exclusive bounds and a
+	// single schema example
+	V30 Version = "3.0.3"
+	// V31 writes OpenAPI 3.1.0: "null" types, numeric exclusive bounds and
+	// schema examples lists
+	V31 Version = "3.1.0"
+)
+
+// SetVersion sets the OpenAPI version the document is written in. Schemas
+// may be built in the form of either version: when marshaling, nullable and
+// "null" types, exclusive bounds and schema example and examples are
+// converted to the form of this version, on a copy of the document.
+func (d *Document) SetVersion(version Version) *Document {
+	if !d.mutable("SetVersion") {
+		return d
+	}
+	d.OpenAPI = string(version)
+	return d
+}
+
+// hasForeignSchemaForms reports whether a schema of the document uses a
+// keyword in the form of another OpenAPI version
+func (d *Document) hasForeignSchemaForms() bool {
+	if d.hasForeignExclusiveBounds() {
+		return true
+	}
+	v31 := isOpenAPI31(d.OpenAPI)
+	foreign := false
+	d.walkSchemas(func(_ string, s *Schema) {
+		if foreignNullable(s, v31) || foreignExamples(s, v31) {
+			foreign = true
+		}
+	})
+	return foreign
+}
+
+// convertSchemaForms rewrites the schemas of the document in the form of its
+// OpenAPI version
+func (d *Document) convertSchemaForms() {
+	d.convertExclusiveBounds()
+	v31 := isOpenAPI31(d.OpenAPI)
+	d.walkSchemas(func(_ string, s *Schema) {
+		if foreignNullable(s, v31) {
+			if v31 {
+				s.Type = append(s.Type, "null")
+				s.Nullable = false
+			} else {
+				s.Type = slices.DeleteFunc(s.Type, func(t string) bool { return t == "null" })
+				s.Nullable = true
+			}
+		}
+		if foreignExamples(s, v31) {
+			if v31 {
+				s.Examples = []interface{}{s.Example}
+				s.Example = nil
+			} else {
+				if s.Example == nil {
+					s.Example = s.Examples[0]
+				}
+				s.Examples = nil
+			}
+		}
+	})
+}
+
+// foreignNullable reports whether a schema accepts null in the form of the
+// other OpenAPI version. Untyped nullable schemas are left alone, as are type
+// lists OpenAPI 3.0 can't express.
+func foreignNullable(s *Schema, v31 bool) bool {
+	if v31 {
+		return s.Nullable && len(s.Type) > 0 && !s.Type.Is("null")
+	}
+	return s.Type.Is("null") && len(s.Type) == 2
+}
+
+// foreignExamples reports whether a schema has examples in the form of the
+// other OpenAPI version: a single example in 3.1, an examples list in 3.0
+func foreignExamples(s *Schema, v31 bool) bool {
+	if v31 {
+		return s.Example != nil && len(s.Examples) == 0
+	}
+	return len(s.Examples) > 0
+}
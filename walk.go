@@ -0,0 +1,310 @@
+package openapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// httpMethods lists the operation methods of a PathItem in canonical order
+var httpMethods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// Operation returns the operation registered for the given HTTP method, or nil
+func (p *PathItem) Operation(method string) *Operation {
+	switch strings.ToUpper(method) {
+	case "GET":
+		return p.Get
+	case "PUT":
+		return p.Put
+	case "POST":
+		return p.Post
+	case "DELETE":
+		return p.Delete
+	case "OPTIONS":
+		return p.Options
+	case "HEAD":
+		return p.Head
+	case "PATCH":
+		return p.Patch
+	case "TRACE":
+		return p.Trace
+	}
+	return nil
+}
+
+// SetOperation registers an operation for the given HTTP method
+func (p *PathItem) SetOperation(method string, operation *Operation) {
+	switch strings.ToUpper(method) {
+	case "GET":
+		p.Get = operation
+	case "PUT":
+		p.Put = operation
+	case "POST":
+		p.Post = operation
+	case "DELETE":
+		p.Delete = operation
+	case "OPTIONS":
+		p.Options = operation
+	case "HEAD":
+		p.Head = operation
+	case "PATCH":
+		p.Patch = operation
+	case "TRACE":
+		p.Trace = operation
+	}
+}
+
+// WalkOperations calls fn for every operation in the document's paths,
+// ordered by path and then by method
+func (d *Document) WalkOperations(fn func(path, method string, op *Operation)) {
+	d.walkOperations(fn)
+}
+
+// WalkSchemas calls fn for every schema of the document and every schema
+// nested in them, parents first, along with its JSON pointer. References
+// aren't followed.
+func (d *Document) WalkSchemas(fn func(pointer string, s *Schema)) {
+	d.walkSchemas(fn)
+}
+
+// walkOperations calls fn for every operation in the document's paths,
+// ordered by path and then by method
+func (d *Document) walkOperations(fn func(path, method string, op *Operation)) {
+	for _, path := range sortedKeys(d.Paths) {
+		item := d.Paths[path]
+		for _, method := range httpMethods {
+			if op := item.Operation(method); op != nil {
+				fn(path, method, op)
+			}
+		}
+	}
+}
+
+// walkWebhooks calls fn for every webhook operation, ordered by webhook name
+// and then by method
+func (d *Document) walkWebhooks(fn func(name, method string, op *Operation)) {
+	for _, name := range sortedKeys(d.Webhooks) {
+		item := d.Webhooks[name]
+		for _, method := range httpMethods {
+			if op := item.Operation(method); op != nil {
+				fn(name, method, op)
+			}
+		}
+	}
+}
+
+// walkMediaTypes calls fn for every media type reachable from request bodies,
+// responses, parameters and headers, along with the JSON pointer of the media type
+// and a flag telling whether it belongs to a request body
+func (d *Document) walkMediaTypes(fn func(pointer, name string, mt MediaType, requestBody bool)) {
+	content := func(base string, c map[string]MediaType, requestBody bool) {
+		for _, name := range sortedKeys(c) {
+			fn(base+"/content/"+escapePointer(name), name, c[name], requestBody)
+		}
+	}
+	headers := func(base string, h map[string]Header) {
+		for _, name := range sortedKeys(h) {
+			content(base+"/headers/"+escapePointer(name), h[name].Content, false)
+		}
+	}
+
+	d.walkOperations(func(path, method string, op *Operation) {
+		base := operationPointer(path, method)
+		for i, param := range op.Parameters {
+			content(base+"/parameters/"+strconv.Itoa(i), param.Content, false)
+		}
+		if op.RequestBody != nil {
+			content(base+"/requestBody", op.RequestBody.Content, true)
+		}
+	})
+	d.walkResponses(func(pointer string, r Response) {
+		headers(pointer, r.Headers)
+		content(pointer, r.Content, false)
+	})
+
+	if d.Components == nil {
+		return
+	}
+	for _, name := range sortedKeys(d.Components.RequestBodies) {
+		content("/components/requestBodies/"+escapePointer(name), d.Components.RequestBodies[name].Content, true)
+	}
+	for _, name := range sortedKeys(d.Components.Parameters) {
+		content("/components/parameters/"+escapePointer(name), d.Components.Parameters[name].Content, false)
+	}
+	headers("/components", d.Components.Headers)
+}
+
+// walkResponses calls fn for every response of every operation and for every
+// response component, along with the JSON pointer of the response
+func (d *Document) walkResponses(fn func(pointer string, r Response)) {
+	d.walkOperations(func(path, method string, op *Operation) {
+		base := operationPointer(path, method) + "/responses/"
+		for _, code := range sortedKeys(op.Responses) {
+			fn(base+escapePointer(code), op.Responses[code])
+		}
+	})
+	if d.Components == nil {
+		return
+	}
+	for _, name := range sortedKeys(d.Components.Responses) {
+		fn("/components/responses/"+escapePointer(name), d.Components.Responses[name])
+	}
+}
+
+// walkParameters calls fn for every parameter declared on path items, operations
+// and in components, along with the JSON pointer of the parameter
+func (d *Document) walkParameters(fn func(pointer string, p Parameter)) {
+	for _, path := range sortedKeys(d.Paths) {
+		for i, p := range d.Paths[path].Parameters {
+			fn("/paths/"+escapePointer(path)+"/parameters/"+strconv.Itoa(i), p)
+		}
+	}
+	d.walkOperations(func(path, method string, op *Operation) {
+		for i, p := range op.Parameters {
+			fn(operationPointer(path, method)+"/parameters/"+strconv.Itoa(i), p)
+		}
+	})
+	if d.Components != nil {
+		for _, name := range sortedKeys(d.Components.Parameters) {
+			fn("/components/parameters/"+escapePointer(name), d.Components.Parameters[name])
+		}
+	}
+}
+
+// walkHeaders calls fn for every header declared on responses, encodings and
+// in components, along with the JSON pointer of the header
+func (d *Document) walkHeaders(fn func(pointer, name string, h Header)) {
+	headers := func(base string, h map[string]Header) {
+		for _, name := range sortedKeys(h) {
+			fn(base+"/headers/"+escapePointer(name), name, h[name])
+		}
+	}
+	d.walkResponses(func(pointer string, r Response) {
+		headers(pointer, r.Headers)
+	})
+	d.walkMediaTypes(func(pointer, _ string, mt MediaType, _ bool) {
+		for _, prop := range sortedKeys(mt.Encoding) {
+			headers(pointer+"/encoding/"+escapePointer(prop), mt.Encoding[prop].Headers)
+		}
+	})
+	if d.Components != nil {
+		headers("/components", d.Components.Headers)
+	}
+}
+
+// walkExamples calls fn for every Example object in the document, along with
+// its JSON pointer
+func (d *Document) walkExamples(fn func(pointer string, ex Example)) {
+	examples := func(base string, e map[string]Example) {
+		for _, name := range sortedKeys(e) {
+			fn(base+"/examples/"+escapePointer(name), e[name])
+		}
+	}
+	d.walkParameters(func(pointer string, p Parameter) {
+		examples(pointer, p.Examples)
+	})
+	d.walkHeaders(func(pointer, _ string, h Header) {
+		examples(pointer, h.Examples)
+	})
+	d.walkMediaTypes(func(pointer, _ string, mt MediaType, _ bool) {
+		examples(pointer, mt.Examples)
+	})
+	if d.Components != nil {
+		examples("/components", d.Components.Examples)
+	}
+}
+
+// walkSchemas calls fn for every schema of parameters, headers, media types
+// and components and for every schema nested in them, parents first, along
+// with its JSON pointer. References aren't followed.
+func (d *Document) walkSchemas(fn func(pointer string, s *Schema)) {
+	var walk func(pointer string, s *Schema)
+	walk = func(pointer string, s *Schema) {
+		if s == nil {
+			return
+		}
+		fn(pointer, s)
+		for _, name := range sortedKeys(s.Properties) {
+			walk(pointer+"/properties/"+escapePointer(name), s.Properties[name])
+		}
+		walk(pointer+"/items", s.Items)
+		if s.AdditionalProperties != nil {
+			walk(pointer+"/additionalProperties", s.AdditionalProperties.Schema)
+		}
+		for i, child := range s.AllOf {
+			walk(pointer+"/allOf/"+strconv.Itoa(i), child)
+		}
+		for i, child := range s.AnyOf {
+			walk(pointer+"/anyOf/"+strconv.Itoa(i), child)
+		}
+		for i, child := range s.OneOf {
+			walk(pointer+"/oneOf/"+strconv.Itoa(i), child)
+		}
+		walk(pointer+"/not", s.Not)
+		for i, child := range s.PrefixItems {
+			walk(pointer+"/prefixItems/"+strconv.Itoa(i), child)
+		}
+		walk(pointer+"/contains", s.Contains)
+		for _, pattern := range sortedKeys(s.PatternProperties) {
+			walk(pointer+"/patternProperties/"+escapePointer(pattern), s.PatternProperties[pattern])
+		}
+		walk(pointer+"/propertyNames", s.PropertyNames)
+		if s.UnevaluatedProperties != nil {
+			walk(pointer+"/unevaluatedProperties", s.UnevaluatedProperties.Schema)
+		}
+		walk(pointer+"/if", s.If)
+		walk(pointer+"/then", s.Then)
+		walk(pointer+"/else", s.Else)
+		for _, name := range sortedKeys(s.DependentSchemas) {
+			walk(pointer+"/dependentSchemas/"+escapePointer(name), s.DependentSchemas[name])
+		}
+		for _, name := range sortedKeys(s.Defs) {
+			walk(pointer+"/$defs/"+escapePointer(name), s.Defs[name])
+		}
+	}
+
+	d.walkParameters(func(pointer string, p Parameter) {
+		walk(pointer+"/schema", p.Schema)
+	})
+	d.walkHeaders(func(pointer, _ string, h Header) {
+		walk(pointer+"/schema", h.Schema)
+	})
+	d.walkMediaTypes(func(pointer, _ string, mt MediaType, _ bool) {
+		walk(pointer+"/schema", mt.Schema)
+	})
+	if d.Components != nil {
+		for _, name := range sortedKeys(d.Components.Schemas) {
+			walk("/components/schemas/"+escapePointer(name), d.Components.Schemas[name])
+		}
+	}
+}
+
+// operationPointer returns the JSON pointer of the operation at path and method
+func operationPointer(path, method string) string {
+	return "/paths/" + escapePointer(path) + "/" + strings.ToLower(method)
+}
+
+// webhookPointer returns the JSON pointer of a webhook operation
+func webhookPointer(name, method string) string {
+	return "/webhooks/" + escapePointer(name) + "/" + strings.ToLower(method)
+}
+
+// escapePointer escapes a single JSON pointer reference token (RFC 6901)
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// unescapePointer reverses escapePointer
+func unescapePointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
+
+// sortedKeys returns the keys of a string-keyed map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
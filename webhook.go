@@ -0,0 +1,254 @@
+package openapi
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ExtensionCodeSamples is the operation extension listing code samples, as
+// rendered by Redoc and other documentation tools
+const ExtensionCodeSamples = "x-codeSamples"
+
+// CodeSample is a code snippet shown with an operation's documentation
+type CodeSample struct {
+	Lang   string `json:"lang"`
+	Label  string `json:"label,omitempty"`
+	Source string `json:"source"`
+}
+
+// WithCodeSample adds a code sample to the operation documentation
+func (o Operation) WithCodeSample(sample CodeSample) Operation {
+	return o.WithExtension(ExtensionCodeSamples, append(o.CodeSamples(), sample))
+}
+
+// CodeSamples returns the code samples of the operation
+func (o *Operation) CodeSamples() []CodeSample {
+	var samples []CodeSample
+	decodeExtension(o.Extensions[ExtensionCodeSamples], &samples)
+	return samples
+}
+
+// WebhookSignature describes how webhook payloads are signed: an HMAC-SHA256
+// of the raw body, hex encoded, sent in a request header. When a timestamp
+// header is used, the signed content is the timestamp, a dot and the body.
+type WebhookSignature struct {
+	Header string
+	// Prefix is prepended to the hex digest, e.g. "sha256="
+	Prefix          string
+	TimestampHeader string
+	// SampleSecret and SampleTimestamp are used to sign the example payloads
+	// shown in the documentation
+	SampleSecret    string
+	SampleTimestamp string
+}
+
+// NewWebhookSignature creates a signature scheme sending "sha256=<hex>" in the given header
+func NewWebhookSignature(header string) WebhookSignature {
+	return WebhookSignature{
+		Header:          header,
+		Prefix:          "sha256=",
+		SampleSecret:    "whsec_example",
+		SampleTimestamp: "1700000000",
+	}
+}
+
+// WithTimestamp signs a timestamp, sent in the given header, along with the body
+func (s WebhookSignature) WithTimestamp(header string) WebhookSignature {
+	s.TimestampHeader = header
+	return s
+}
+
+// Sign computes the signature header value of a payload
+func (s WebhookSignature) Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	if s.TimestampHeader != "" {
+		mac.Write([]byte(timestamp + "."))
+	}
+	mac.Write(body)
+	return s.Prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value in constant time
+func (s WebhookSignature) Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(s.Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// WithWebhookSignature documents the signature scheme on every webhook: the
+// signature and timestamp headers are added with an example signature of the
+// sample payload, the description gains a verification section and Go, Node.js
+// and Python verification snippets are added as x-codeSamples. Headers and
+// samples that already exist are kept, so the call is idempotent.
+func (d *Document) WithWebhookSignature(sig WebhookSignature) *Document {
+	if !d.mutable("WithWebhookSignature") {
+		return d
+	}
+	d.ownWebhooks()
+	d.walkWebhooks(func(name, method string, op *Operation) {
+		body := d.webhookPayload(op)
+		if !hasParameter(op.Parameters, "header", sig.Header) {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:        sig.Header,
+				In:          "header",
+				Required:    true,
+				Description: fmt.Sprintf("HMAC-SHA256 of the %s, hex encoded and prefixed with %q", signedContent(sig), sig.Prefix),
+				Schema:      StringSchema(""),
+				Example:     sig.Sign(sig.SampleSecret, sig.SampleTimestamp, body),
+			})
+		}
+		if sig.TimestampHeader != "" && !hasParameter(op.Parameters, "header", sig.TimestampHeader) {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:        sig.TimestampHeader,
+				In:          "header",
+				Required:    true,
+				Description: "Unix time the payload was signed at; reject stale deliveries to prevent replays",
+				Schema:      StringSchema(""),
+				Example:     sig.SampleTimestamp,
+			})
+		}
+
+		section := "Verifying signatures"
+		if !strings.Contains(op.Description, section) {
+			if op.Description != "" {
+				op.Description += "\n\n"
+			}
+			op.Description += fmt.Sprintf("### %s\n\nCompute the HMAC-SHA256 of the %s using your webhook secret "+
+				"and compare it, in constant time, with the `%s` header before trusting the payload.",
+				section, signedContent(sig), sig.Header)
+		}
+
+		existing := op.CodeSamples()
+		for _, sample := range webhookCodeSamples(sig) {
+			found := false
+			for _, e := range existing {
+				found = found || (e.Lang == sample.Lang && e.Label == sample.Label)
+			}
+			if !found {
+				existing = append(existing, sample)
+			}
+		}
+		if op.Extensions == nil {
+			op.Extensions = make(map[string]interface{})
+		}
+		op.Extensions[ExtensionCodeSamples] = existing
+	})
+	return d
+}
+
+// WebhookMarkdown renders documentation sections for the document's webhooks:
+// description, example payload, headers and code samples
+func (d *Document) WebhookMarkdown() string {
+	var b strings.Builder
+	d.walkWebhooks(func(name, method string, op *Operation) {
+		fmt.Fprintf(&b, "## %s\n\n`%s` request sent to your endpoint", name, method)
+		if op.Summary != "" {
+			fmt.Fprintf(&b, ": %s", op.Summary)
+		}
+		b.WriteString("\n\n")
+		if op.Description != "" {
+			b.WriteString(op.Description + "\n\n")
+		}
+
+		if payload := d.webhookPayload(op); payload != nil {
+			var pretty bytes.Buffer
+			json.Indent(&pretty, payload, "", "  ")
+			fmt.Fprintf(&b, "### Payload\n\n```json\n%s\n```\n\n", pretty.String())
+		}
+
+		var headers []Parameter
+		for _, p := range d.OperationParameters("", op) {
+			if p.In == "header" {
+				headers = append(headers, p)
+			}
+		}
+		if len(headers) > 0 {
+			b.WriteString("### Headers\n\n| Name | Required | Description | Example |\n|---|---|---|---|\n")
+			for _, h := range headers {
+				example := ""
+				if h.Example != nil {
+					example = fmt.Sprintf("`%v`", h.Example)
+				}
+				fmt.Fprintf(&b, "| %s | %t | %s | %s |\n", h.Name, h.Required, h.Description, example)
+			}
+			b.WriteString("\n")
+		}
+
+		for _, sample := range op.CodeSamples() {
+			label := sample.Label
+			if label == "" {
+				label = sample.Lang
+			}
+			fmt.Fprintf(&b, "#### %s\n\n```%s\n%s\n```\n\n", label, strings.ToLower(sample.Lang), sample.Source)
+		}
+	})
+	return b.String()
+}
+
+// webhookPayload returns the sample JSON payload of a webhook, or nil
+func (d *Document) webhookPayload(op *Operation) []byte {
+	if op.RequestBody == nil {
+		return nil
+	}
+	for _, name := range sortedKeys(op.RequestBody.Content) {
+		if isJSONMediaType(name) {
+			data, err := json.Marshal(d.SampleValue(op.RequestBody.Content[name].Schema))
+			if err == nil {
+				return data
+			}
+		}
+	}
+	return nil
+}
+
+func hasParameter(params []Parameter, in, name string) bool {
+	for _, p := range params {
+		if p.In == in && strings.EqualFold(p.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func signedContent(sig WebhookSignature) string {
+	if sig.TimestampHeader != "" {
+		return fmt.Sprintf("`%s` header value, a dot and the raw request body", sig.TimestampHeader)
+	}
+	return "raw request body"
+}
+
+// webhookCodeSamples renders signature verification snippets
+func webhookCodeSamples(sig WebhookSignature) []CodeSample {
+	goSigned, nodeSigned, pySigned := "body", "rawBody", "body"
+	goTimestamp, nodeTimestamp := "", ""
+	if sig.TimestampHeader != "" {
+		goTimestamp = fmt.Sprintf("mac.Write([]byte(r.Header.Get(%q) + \".\"))\n", sig.TimestampHeader)
+		nodeTimestamp = fmt.Sprintf("  .update(req.headers[%q] + \".\")\n", strings.ToLower(sig.TimestampHeader))
+		pySigned = fmt.Sprintf("request.headers[%q].encode() + b\".\" + body", sig.TimestampHeader)
+	}
+
+	return []CodeSample{
+		{Lang: "Go", Label: "Verify signature (Go)", Source: fmt.Sprintf(
+			"mac := hmac.New(sha256.New, []byte(secret))\n%smac.Write(%s)\n"+
+				"expected := %q + hex.EncodeToString(mac.Sum(nil))\n"+
+				"if !hmac.Equal([]byte(expected), []byte(r.Header.Get(%q))) {\n"+
+				"\thttp.Error(w, \"invalid signature\", http.StatusUnauthorized)\n\treturn\n}",
+			goTimestamp, goSigned, sig.Prefix, sig.Header)},
+		{Lang: "JavaScript", Label: "Verify signature (Node.js)", Source: fmt.Sprintf(
+			"const crypto = require(\"crypto\");\n"+
+				"const expected = %q + crypto.createHmac(\"sha256\", secret)\n%s  .update(%s)\n  .digest(\"hex\");\n"+
+				"const received = Buffer.from(req.headers[%q] || \"\");\n"+
+				"if (received.length !== expected.length || !crypto.timingSafeEqual(Buffer.from(expected), received)) {\n"+
+				"  return res.status(401).send(\"invalid signature\");\n}",
+			sig.Prefix, nodeTimestamp, nodeSigned, strings.ToLower(sig.Header))},
+		{Lang: "Python", Label: "Verify signature (Python)", Source: fmt.Sprintf(
+			"import hashlib, hmac\n\n"+
+				"expected = %q + hmac.new(secret.encode(), %s, hashlib.sha256).hexdigest()\n"+
+				"if not hmac.compare_digest(expected, request.headers.get(%q, \"\")):\n"+
+				"    abort(401)",
+			sig.Prefix, pySigned, sig.Header)},
+	}
+}
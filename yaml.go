@@ -0,0 +1,133 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"gopkg.in/yaml.v3"
+)
+
+// marshalYAML renders v as block-style YAML. The value is marshaled to JSON
+// first so that JSON tags and custom MarshalJSON methods (extensions, unions)
+// apply, and the key order of the JSON output is preserved.
+func marshalYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlToJSON converts a YAML document to JSON. Mapping keys become strings,
+// so that unquoted response codes such as 200 are kept, timestamps and other
+// tagged scalars are read as strings, and aliases and merge keys are expanded.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 {
+		return nil, errors.New("empty document")
+	}
+	value, err := yamlValue(node.Content[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// yamlValue converts a node to the value encoding/json would decode
+func yamlValue(node *yaml.Node, depth int) (interface{}, error) {
+	if depth > 512 {
+		return nil, fmt.Errorf("line %d: nesting too deep", node.Line)
+	}
+	switch node.Kind {
+	case yaml.AliasNode:
+		return yamlValue(node.Alias, depth+1)
+	case yaml.MappingNode:
+		obj := make(map[string]interface{}, len(node.Content)/2)
+		var merged []map[string]interface{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind == yaml.AliasNode {
+				key = key.Alias
+			}
+			v, err := yamlValue(value, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if key.Tag == "!!merge" {
+				switch m := v.(type) {
+				case map[string]interface{}:
+					merged = append(merged, m)
+				case []interface{}:
+					for _, item := range m {
+						if mm, ok := item.(map[string]interface{}); ok {
+							merged = append(merged, mm)
+						}
+					}
+				}
+				continue
+			}
+			obj[key.Value] = v
+		}
+		// Explicit keys win over merged ones, and earlier merged mappings over later ones
+		for _, m := range merged {
+			for k, v := range m {
+				if _, ok := obj[k]; !ok {
+					obj[k] = v
+				}
+			}
+		}
+		return obj, nil
+	case yaml.SequenceNode:
+		items := make([]interface{}, len(node.Content))
+		for i, child := range node.Content {
+			v, err := yamlValue(child, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = v
+		}
+		return items, nil
+	}
+
+	switch node.Tag {
+	case "!!null":
+		return nil, nil
+	case "!!bool", "!!int":
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case "!!float":
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return nil, err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("line %d: %s has no JSON representation", node.Line, node.Value)
+		}
+		return f, nil
+	}
+	return node.Value, nil
+}